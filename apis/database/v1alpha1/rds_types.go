@@ -20,6 +20,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
 )
 
 // SQL database engines.
@@ -77,9 +79,18 @@ type RDSInstanceParameters struct {
 
 	// EngineVersion indicates the database engine version.
 	// MySQL：5.5/5.6/5.7/8.0
-	// PostgreSQL：9.4/10.0/11.0/12.0
+	// PostgreSQL：9.4/10.0/11.0/12.0/13.0
+	// Increasing the version of an existing instance upgrades it if
+	// AllowMajorVersionUpgrade is true. Versions cannot be downgraded.
 	EngineVersion string `json:"engineVersion"`
 
+	// AllowMajorVersionUpgrade allows the engine version of the instance to
+	// be upgraded when EngineVersion is increased. Upgrades make the
+	// instance unavailable for a while; PostgreSQL instances are upgraded by
+	// cloning them to a new instance that takes over their endpoints.
+	// +optional
+	AllowMajorVersionUpgrade bool `json:"allowMajorVersionUpgrade,omitempty"`
+
 	// DBInstanceClass is the machine class of the instance, e.g. "rds.pg.s1.small"
 	DBInstanceClass string `json:"dbInstanceClass"`
 
@@ -87,10 +98,99 @@ type RDSInstanceParameters struct {
 	// Increments by 5GB.
 	// For "rds.pg.s1.small", the range is 20-600 (GB).
 	// See https://help.aliyun.com/document_detail/26312.html
+	// Changing the class or storage of an instance resizes it.
 	DBInstanceStorageInGB int `json:"dbInstanceStorageInGB"`
 
-	// SecurityIPList is the IP whitelist for RDS instances
-	SecurityIPList string `json:"securityIPList"`
+	// SecurityIPList is the comma-separated IP whitelist of the default
+	// whitelist group of the instance, unless SecurityIPGroups includes the
+	// default group.
+	// +optional
+	SecurityIPList string `json:"securityIPList,omitempty"`
+
+	// SecurityIPGroups are named IP whitelist groups of the instance. The
+	// listed groups are kept in sync with their IPs; groups that are not
+	// listed, other than those reserved by cloud services, are cleared to
+	// allow no access.
+	// +optional
+	SecurityIPGroups []SecurityIPGroup `json:"securityIPGroups,omitempty"`
+
+	// InstanceNetworkType is the network type of the instance: VPC or
+	// Classic. Instances are created in a VPC if unset.
+	// +immutable
+	// +optional
+	// +kubebuilder:validation:Enum=VPC;Classic
+	InstanceNetworkType string `json:"instanceNetworkType,omitempty"`
+
+	// VPCID is the VPC the instance is created in. Required for VPC
+	// instances.
+	// +immutable
+	// +optional
+	VPCID string `json:"vpcId,omitempty"`
+
+	// VSwitchID is the vSwitch the instance is created in. Required for VPC
+	// instances.
+	// +immutable
+	// +optional
+	VSwitchID string `json:"vSwitchId,omitempty"`
+
+	// ZoneID is the zone the instance is created in, which must be the zone
+	// of its vSwitch.
+	// +immutable
+	// +optional
+	ZoneID string `json:"zoneId,omitempty"`
+
+	// PrivateIPAddress is the intranet IP address of the instance within its
+	// vSwitch. Assigned automatically if unset.
+	// +immutable
+	// +optional
+	PrivateIPAddress string `json:"privateIpAddress,omitempty"`
+
+	// PubliclyAccessible allocates a public endpoint for the instance if
+	// true, and releases it if false. The public endpoint is left alone if
+	// unset.
+	// +optional
+	PubliclyAccessible *bool `json:"publiclyAccessible,omitempty"`
+
+	// PayType is the billing method of the instance: Postpaid
+	// (pay-as-you-go) or Prepaid (subscription). Instances are created
+	// Postpaid if unset. Setting it to Prepaid converts Postpaid instances to
+	// subscriptions; Prepaid instances cannot be converted back.
+	// +optional
+	// +kubebuilder:validation:Enum=Postpaid;Prepaid
+	PayType string `json:"payType,omitempty"`
+
+	// Period is the unit of the subscription of Prepaid instances: Year or
+	// Month. Month if unset.
+	// +optional
+	// +kubebuilder:validation:Enum=Year;Month
+	Period string `json:"period,omitempty"`
+
+	// UsedTime is the length of the subscription of Prepaid instances in
+	// Periods, i.e. 1 to 5 years or 1 to 9 months. 1 if unset.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	UsedTime int `json:"usedTime,omitempty"`
+
+	// AutoRenew renews the subscription of Prepaid instances by one Period
+	// each time it expires.
+	// +optional
+	AutoRenew bool `json:"autoRenew,omitempty"`
+
+	// BackupPolicy configures the backups of the instance. The backup policy
+	// is left alone if unset.
+	// +optional
+	BackupPolicy *BackupPolicy `json:"backupPolicy,omitempty"`
+
+	// Parameters of the engine of the instance by name, e.g.
+	// max_connections. Parameters that are not set are left alone.
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// AllowRestart lets changes to Parameters that only take effect once the
+	// instance restarts restart it. Such changes are pending a restart
+	// otherwise.
+	// +optional
+	AllowRestart bool `json:"allowRestart,omitempty"`
 
 	// MasterUsername is the name for the master user.
 	// MySQL
@@ -110,6 +210,147 @@ type RDSInstanceParameters struct {
 	MasterUsername string `json:"masterUsername"`
 }
 
+// Instance network types.
+const (
+	InstanceNetworkTypeVPC     = "VPC"
+	InstanceNetworkTypeClassic = "Classic"
+)
+
+// Pay types of instances.
+const (
+	PayTypePostpaid = "Postpaid"
+	PayTypePrepaid  = "Prepaid"
+)
+
+// Subscription periods of Prepaid instances.
+const (
+	PeriodYear  = "Year"
+	PeriodMonth = "Month"
+)
+
+// A BackupPolicy configures the backups of an RDS instance. Unset fields are
+// left alone.
+type BackupPolicy struct {
+	// BackupRetentionPeriod is the number of days data backups are kept.
+	// +optional
+	// +kubebuilder:validation:Minimum=7
+	// +kubebuilder:validation:Maximum=730
+	BackupRetentionPeriod int `json:"backupRetentionPeriod,omitempty"`
+
+	// PreferredBackupTime is the hour in which data backups are made, in
+	// UTC and the format HH:00Z-HH:00Z, e.g. 02:00Z-03:00Z.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):00Z-([01][0-9]|2[0-3]):00Z$`
+	PreferredBackupTime string `json:"preferredBackupTime,omitempty"`
+
+	// PreferredBackupPeriod are the days of the week data backups are made
+	// on, e.g. Monday. At least two days.
+	// +optional
+	// +kubebuilder:validation:MinItems=2
+	PreferredBackupPeriod []string `json:"preferredBackupPeriod,omitempty"`
+
+	// EnableBackupLog enables log backups if true, and disables them if
+	// false.
+	// +optional
+	EnableBackupLog *bool `json:"enableBackupLog,omitempty"`
+
+	// LogBackupRetentionPeriod is the number of days log backups are kept,
+	// which cannot exceed BackupRetentionPeriod.
+	// +optional
+	// +kubebuilder:validation:Minimum=7
+	// +kubebuilder:validation:Maximum=730
+	LogBackupRetentionPeriod int `json:"logBackupRetentionPeriod,omitempty"`
+
+	// LocalLogRetentionHours is the number of hours logs are kept on the
+	// instance.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=168
+	LocalLogRetentionHours int `json:"localLogRetentionHours,omitempty"`
+
+	// CrossRegionBackup copies the backups of the instance to another
+	// region.
+	// +optional
+	CrossRegionBackup *CrossRegionBackup `json:"crossRegionBackup,omitempty"`
+}
+
+// A CrossRegionBackup copies the backups of an RDS instance to another
+// region.
+type CrossRegionBackup struct {
+	// Region the backups are copied to, e.g. cn-shanghai. Cross-region
+	// backup is disabled if empty.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// RetentionDays is the number of days the copies are kept.
+	// +optional
+	// +kubebuilder:validation:Minimum=7
+	// +kubebuilder:validation:Maximum=1825
+	RetentionDays int `json:"retentionDays,omitempty"`
+
+	// LogBackupEnabled copies log backups as well as data backups.
+	// +optional
+	LogBackupEnabled bool `json:"logBackupEnabled,omitempty"`
+}
+
+// DefaultSecurityIPGroup is the name of the whitelist group that the
+// SecurityIPList of an instance is applied to.
+const DefaultSecurityIPGroup = "default"
+
+// Security IP types.
+const (
+	SecurityIPTypeIPv4 = "IPv4"
+	SecurityIPTypeIPv6 = "IPv6"
+)
+
+// A SecurityIPGroup is a named IP whitelist group of an RDS instance.
+type SecurityIPGroup struct {
+	// Name of the group, e.g. default.
+	Name string `json:"name"`
+
+	// IPs are the IP addresses or CIDR blocks that may access the instance.
+	// +optional
+	IPs []string `json:"ips,omitempty"`
+
+	// IPsFrom are keys of ConfigMaps whose values list further IP addresses
+	// or CIDR blocks, separated by commas or whitespace, e.g. the egress IPs
+	// of the nodes of a cluster.
+	// +optional
+	IPsFrom []ConfigMapKeySelector `json:"ipsFrom,omitempty"`
+
+	// IPType is the type of the IPs of the group. IPv4 by default.
+	// +optional
+	// +kubebuilder:validation:Enum=IPv4;IPv6
+	IPType string `json:"ipType,omitempty"`
+}
+
+// A ConfigMapKeySelector selects a key of a ConfigMap.
+type ConfigMapKeySelector struct {
+	// Name of the ConfigMap.
+	Name string `json:"name"`
+
+	// Namespace of the ConfigMap.
+	Namespace string `json:"namespace"`
+
+	// Key of the ConfigMap data.
+	Key string `json:"key"`
+}
+
+// A SecurityIPGroupObservation is the observed state of an IP whitelist
+// group of an RDS instance.
+type SecurityIPGroupObservation struct {
+	// Name of the group.
+	Name string `json:"name"`
+
+	// IPs are the IP addresses or CIDR blocks of the group.
+	// +optional
+	IPs []string `json:"ips,omitempty"`
+
+	// IPType is the type of the IPs of the group.
+	// +optional
+	IPType string `json:"ipType,omitempty"`
+}
+
 // RDS instance states.
 const (
 	// The instance is healthy and available
@@ -118,8 +359,57 @@ const (
 	RDSInstanceStateCreating = "Creating"
 	// The instance is being deleted.
 	RDSInstanceStateDeleting = "Deleting"
+	// The class or storage of the instance is being changed. The instance
+	// remains accessible, except for a brief switchover at the end.
+	RDSInstanceStateClassChanging = "DBInstanceClassChanging"
+	// The engine version of the instance is being upgraded.
+	RDSInstanceStateEngineVersionUpgrading = "EngineVersionUpgrading"
 )
 
+// Engine upgrade states.
+const (
+	// The pre-check of the upgrade is running.
+	EngineUpgradePrechecking = "Prechecking"
+	// The pre-check of the upgrade passed, so the upgrade can be requested.
+	EngineUpgradePrecheckPassed = "PrecheckPassed"
+	// The pre-check of the upgrade failed. See the message for why.
+	EngineUpgradePrecheckFailed = "PrecheckFailed"
+	// The upgrade was requested and has not completed yet.
+	EngineUpgradeUpgrading = "Upgrading"
+	// The clone of the upgraded instance took over, so the upgraded
+	// instance is released.
+	EngineUpgradeReleasingSource = "ReleasingSource"
+)
+
+// An EngineUpgrade is an upgrade of the engine version of an RDS instance.
+type EngineUpgrade struct {
+	// TargetEngineVersion is the engine version the instance is upgraded to.
+	TargetEngineVersion string `json:"targetEngineVersion"`
+
+	// State of the upgrade.
+	State string `json:"state"`
+
+	// PrecheckTaskID is the ID of the pre-check task of the upgrade, if the
+	// engine has one.
+	// +optional
+	PrecheckTaskID string `json:"precheckTaskID,omitempty"`
+
+	// TargetDBInstanceID is the ID of the instance that takes over from the
+	// upgraded one, if the engine is upgraded by cloning the instance.
+	// +optional
+	TargetDBInstanceID string `json:"targetDBInstanceID,omitempty"`
+
+	// SourceDBInstanceID is the ID of the upgraded instance, which is
+	// released once its clone took over, if the engine is upgraded by
+	// cloning the instance.
+	// +optional
+	SourceDBInstanceID string `json:"sourceDBInstanceID,omitempty"`
+
+	// Message describes why the pre-check failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // RDSInstanceObservation is the representation of the current state that is observed.
 type RDSInstanceObservation struct {
 	// DBInstanceStatus specifies the current state of this database.
@@ -128,8 +418,93 @@ type RDSInstanceObservation struct {
 	// DBInstanceID specifies the DB instance ID.
 	DBInstanceID string `json:"dbInstanceID"`
 
+	// EngineVersion is the current engine version of the instance.
+	// +optional
+	EngineVersion string `json:"engineVersion,omitempty"`
+
+	// EngineUpgrade is the upgrade of the engine version of the instance
+	// that is in progress, if any.
+	// +optional
+	EngineUpgrade *EngineUpgrade `json:"engineUpgrade,omitempty"`
+
+	// DBInstanceClass is the current machine class of the instance.
+	// +optional
+	DBInstanceClass string `json:"dbInstanceClass,omitempty"`
+
+	// DBInstanceStorageInGB is the current size of the storage in GB.
+	// +optional
+	DBInstanceStorageInGB int `json:"dbInstanceStorageInGB,omitempty"`
+
+	// PayType is the billing method of the instance.
+	// +optional
+	PayType string `json:"payType,omitempty"`
+
+	// ExpireTime is when the subscription of a Prepaid instance expires.
+	// +optional
+	ExpireTime string `json:"expireTime,omitempty"`
+
+	// AutoRenew is true if the subscription of a Prepaid instance is renewed
+	// automatically.
+	// +optional
+	AutoRenew bool `json:"autoRenew,omitempty"`
+
+	// BackupPolicy is the backup policy of the instance. Only observed if
+	// the spec of the instance sets one.
+	// +optional
+	BackupPolicy *BackupPolicy `json:"backupPolicy,omitempty"`
+
+	// Parameters are the configured values of the parameters of the engine
+	// that the spec of the instance sets.
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// PendingRestartParameters are the parameters of the engine that the
+	// spec of the instance sets, whose configured values take effect once
+	// the instance restarts.
+	// +optional
+	PendingRestartParameters []string `json:"pendingRestartParameters,omitempty"`
+
+	// InstanceNetworkType is the network type of the instance.
+	// +optional
+	InstanceNetworkType string `json:"instanceNetworkType,omitempty"`
+
+	// ZoneID is the zone of the instance.
+	// +optional
+	ZoneID string `json:"zoneID,omitempty"`
+
+	// VPCID is the VPC of the instance, if it is in one.
+	// +optional
+	VPCID string `json:"vpcID,omitempty"`
+
+	// VSwitchID is the vSwitch of the instance, if it is in a VPC.
+	// +optional
+	VSwitchID string `json:"vSwitchID,omitempty"`
+
+	// Endpoint is the intranet endpoint of the instance.
+	// +optional
+	Endpoint *Endpoint `json:"endpoint,omitempty"`
+
+	// PublicEndpoint is the public endpoint of the instance, if it has one.
+	// +optional
+	PublicEndpoint *Endpoint `json:"publicEndpoint,omitempty"`
+
+	// SecurityIPGroups are the IP whitelist groups of the instance, except
+	// for those hidden groups that are managed by other cloud services.
+	// +optional
+	SecurityIPGroups []SecurityIPGroupObservation `json:"securityIPGroups,omitempty"`
+
 	// AccountReady specifies whether the initial user account (username + password) is ready
 	AccountReady bool `json:"accountReady"`
+
+	// CostEstimate is the estimated cost of the instance. Only recorded if
+	// cost estimation is enabled by its ProviderConfig.
+	// +optional
+	CostEstimate *aliv1alpha1.CostEstimate `json:"costEstimate,omitempty"`
+
+	// PendingOperation is the change of the instance that was requested but has
+	// not completed yet, if any.
+	// +optional
+	PendingOperation *aliv1alpha1.PendingOperation `json:"pendingOperation,omitempty"`
 }
 
 // Endpoint is the database endpoint
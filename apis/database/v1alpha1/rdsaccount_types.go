@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// +kubebuilder:object:root=true
+
+// RDSAccountList contains a list of RDSAccount
+type RDSAccountList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RDSAccount `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+
+// An RDSAccount is a managed resource that represents an account of an RDS
+// instance. The account is named by its external name.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.atProvider.accountStatus"
+// +kubebuilder:printcolumn:name="TYPE",type="string",JSONPath=".status.atProvider.accountType"
+// +kubebuilder:printcolumn:name="INSTANCE",type="string",JSONPath=".status.atProvider.dbInstanceID"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,alibaba}
+type RDSAccount struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RDSAccountSpec   `json:"spec"`
+	Status RDSAccountStatus `json:"status,omitempty"`
+}
+
+// An RDSAccountSpec defines the desired state of an RDSAccount.
+type RDSAccountSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       RDSAccountParameters `json:"forProvider"`
+}
+
+// An RDSAccountStatus represents the observed state of an RDSAccount.
+type RDSAccountStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          RDSAccountObservation `json:"atProvider,omitempty"`
+}
+
+// RDS account types.
+const (
+	AccountTypeNormal = "Normal"
+	AccountTypeSuper  = "Super"
+)
+
+// RDSAccountParameters define the desired state of an account of an RDS
+// instance.
+type RDSAccountParameters struct {
+	// DBInstanceID is the ID of the RDS instance the account is created in.
+	// +immutable
+	// +optional
+	// +crossplane:generate:reference:type=RDSInstance
+	// +crossplane:generate:reference:extractor=RDSInstanceID()
+	DBInstanceID string `json:"dbInstanceId,omitempty"`
+
+	// DBInstanceIDRef references the RDSInstance the account is created in.
+	// +immutable
+	// +optional
+	DBInstanceIDRef *xpv1.Reference `json:"dbInstanceIdRef,omitempty"`
+
+	// DBInstanceIDSelector selects the RDSInstance the account is created
+	// in, unless DBInstanceID or DBInstanceIDRef is set.
+	// +optional
+	DBInstanceIDSelector *xpv1.Selector `json:"dbInstanceIdSelector,omitempty"`
+
+	// AccountType is the type of the account: Normal or Super. Defaults to
+	// Normal.
+	// +immutable
+	// +optional
+	// +kubebuilder:validation:Enum=Normal;Super
+	AccountType string `json:"accountType,omitempty"`
+
+	// Description of the account.
+	// +immutable
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// PasswordSecretRef references the key of a Secret that holds the
+	// password of the account. The account is reset to the password whenever
+	// it changes. A password is generated if unset.
+	// +optional
+	PasswordSecretRef *xpv1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
+	// Privileges of the account on the databases of its instance. Ignored
+	// for Super accounts, which have all privileges.
+	// +optional
+	Privileges []AccountPrivilege `json:"privileges,omitempty"`
+}
+
+// An AccountPrivilege grants an account a privilege on a database.
+type AccountPrivilege struct {
+	// Database is the name of the database.
+	Database string `json:"database"`
+
+	// Privilege of the account on the database. DBOwner is only supported
+	// by PostgreSQL and SQL Server.
+	// +kubebuilder:validation:Enum=ReadWrite;ReadOnly;DDLOnly;DMLOnly;DBOwner
+	Privilege string `json:"privilege"`
+}
+
+// RDS account states.
+const (
+	RDSAccountStateAvailable   = "Available"
+	RDSAccountStateUnavailable = "Unavailable"
+)
+
+// RDSAccountObservation is the representation of the current state that is
+// observed.
+type RDSAccountObservation struct {
+	// AccountStatus specifies the current state of the account.
+	AccountStatus string `json:"accountStatus,omitempty"`
+
+	// DBInstanceID is the ID of the RDS instance of the account.
+	DBInstanceID string `json:"dbInstanceID,omitempty"`
+
+	// AccountType is the type of the account.
+	// +optional
+	AccountType string `json:"accountType,omitempty"`
+
+	// Privileges of the account on the databases of its instance.
+	// +optional
+	Privileges []AccountPrivilege `json:"privileges,omitempty"`
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// +kubebuilder:object:root=true
+
+// RDSDatabaseList contains a list of RDSDatabase
+type RDSDatabaseList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RDSDatabase `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+
+// An RDSDatabase is a managed resource that represents a database of an RDS
+// instance. The database is named by its external name.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.atProvider.dbStatus"
+// +kubebuilder:printcolumn:name="INSTANCE",type="string",JSONPath=".status.atProvider.dbInstanceID"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,alibaba}
+type RDSDatabase struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RDSDatabaseSpec   `json:"spec"`
+	Status RDSDatabaseStatus `json:"status,omitempty"`
+}
+
+// An RDSDatabaseSpec defines the desired state of an RDSDatabase.
+type RDSDatabaseSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       RDSDatabaseParameters `json:"forProvider"`
+}
+
+// An RDSDatabaseStatus represents the observed state of an RDSDatabase.
+type RDSDatabaseStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          RDSDatabaseObservation `json:"atProvider,omitempty"`
+}
+
+// RDSDatabaseParameters define the desired state of a database of an RDS
+// instance.
+type RDSDatabaseParameters struct {
+	// DBInstanceID is the ID of the RDS instance the database is created in.
+	// +immutable
+	// +optional
+	// +crossplane:generate:reference:type=RDSInstance
+	// +crossplane:generate:reference:extractor=RDSInstanceID()
+	DBInstanceID string `json:"dbInstanceId,omitempty"`
+
+	// DBInstanceIDRef references the RDSInstance the database is created in.
+	// +immutable
+	// +optional
+	DBInstanceIDRef *xpv1.Reference `json:"dbInstanceIdRef,omitempty"`
+
+	// DBInstanceIDSelector selects the RDSInstance the database is created
+	// in, unless DBInstanceID or DBInstanceIDRef is set.
+	// +optional
+	DBInstanceIDSelector *xpv1.Selector `json:"dbInstanceIdSelector,omitempty"`
+
+	// CharacterSetName is the character set of the database, e.g. utf8 or
+	// utf8mb4 for MySQL, or UTF8 for PostgreSQL.
+	// +immutable
+	CharacterSetName string `json:"characterSetName"`
+
+	// Description of the database.
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// RDS database states.
+const (
+	RDSDatabaseStateCreating = "Creating"
+	RDSDatabaseStateRunning  = "Running"
+	RDSDatabaseStateDeleting = "Deleting"
+)
+
+// RDSDatabaseObservation is the representation of the current state that is
+// observed.
+type RDSDatabaseObservation struct {
+	// DBStatus specifies the current state of the database.
+	DBStatus string `json:"dbStatus,omitempty"`
+
+	// DBInstanceID is the ID of the RDS instance of the database.
+	DBInstanceID string `json:"dbInstanceID,omitempty"`
+
+	// Engine is the database engine of the instance.
+	// +optional
+	Engine string `json:"engine,omitempty"`
+
+	// CharacterSetName is the character set of the database.
+	// +optional
+	CharacterSetName string `json:"characterSetName,omitempty"`
+}
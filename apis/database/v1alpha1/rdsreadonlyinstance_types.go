@@ -0,0 +1,152 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// +kubebuilder:object:root=true
+
+// RDSReadOnlyInstanceList contains a list of RDSReadOnlyInstance
+type RDSReadOnlyInstanceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RDSReadOnlyInstance `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+
+// An RDSReadOnlyInstance is a managed resource that represents a read-only
+// replica of an RDS instance.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.atProvider.dbInstanceStatus"
+// +kubebuilder:printcolumn:name="PRIMARY",type="string",JSONPath=".status.atProvider.primaryDBInstanceID"
+// +kubebuilder:printcolumn:name="DELAY",type="integer",JSONPath=".status.atProvider.replicationDelaySeconds"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,alibaba}
+type RDSReadOnlyInstance struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RDSReadOnlyInstanceSpec   `json:"spec"`
+	Status RDSReadOnlyInstanceStatus `json:"status,omitempty"`
+}
+
+// An RDSReadOnlyInstanceSpec defines the desired state of an
+// RDSReadOnlyInstance.
+type RDSReadOnlyInstanceSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       RDSReadOnlyInstanceParameters `json:"forProvider"`
+}
+
+// An RDSReadOnlyInstanceStatus represents the observed state of an
+// RDSReadOnlyInstance.
+type RDSReadOnlyInstanceStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          RDSReadOnlyInstanceObservation `json:"atProvider,omitempty"`
+}
+
+// RDSReadOnlyInstanceParameters define the desired state of a read-only
+// replica of an RDS instance. Replicas run the engine version of their
+// primary instance.
+type RDSReadOnlyInstanceParameters struct {
+	// PrimaryDBInstanceID is the ID of the RDS instance the replica
+	// replicates.
+	// +immutable
+	// +optional
+	// +crossplane:generate:reference:type=RDSInstance
+	// +crossplane:generate:reference:extractor=RDSInstanceID()
+	PrimaryDBInstanceID string `json:"primaryDbInstanceId,omitempty"`
+
+	// PrimaryDBInstanceIDRef references the RDSInstance the replica
+	// replicates.
+	// +immutable
+	// +optional
+	PrimaryDBInstanceIDRef *xpv1.Reference `json:"primaryDbInstanceIdRef,omitempty"`
+
+	// PrimaryDBInstanceIDSelector selects the RDSInstance the replica
+	// replicates, unless PrimaryDBInstanceID or PrimaryDBInstanceIDRef is
+	// set.
+	// +optional
+	PrimaryDBInstanceIDSelector *xpv1.Selector `json:"primaryDbInstanceIdSelector,omitempty"`
+
+	// DBInstanceClass is the machine class of the replica, e.g.
+	// "rds.mysql.s2.large". Changing the class of a replica resizes it.
+	DBInstanceClass string `json:"dbInstanceClass"`
+
+	// DBInstanceStorageInGB indicates the size of the storage in GB, which
+	// must be at least that of the primary instance. Increments by 5GB.
+	// Changing the storage of a replica resizes it.
+	DBInstanceStorageInGB int `json:"dbInstanceStorageInGB"`
+
+	// ZoneID is the zone the replica is created in. The zone of the primary
+	// instance if unset.
+	// +immutable
+	// +optional
+	ZoneID string `json:"zoneId,omitempty"`
+
+	// VSwitchID is the vSwitch the replica is created in, which must be in
+	// its zone and the VPC of the primary instance. The vSwitch of the
+	// primary instance if unset.
+	// +immutable
+	// +optional
+	VSwitchID string `json:"vSwitchId,omitempty"`
+}
+
+// RDSReadOnlyInstanceObservation is the representation of the current state
+// that is observed.
+type RDSReadOnlyInstanceObservation struct {
+	// DBInstanceStatus specifies the current state of the replica.
+	DBInstanceStatus string `json:"dbInstanceStatus,omitempty"`
+
+	// DBInstanceID specifies the ID of the replica.
+	DBInstanceID string `json:"dbInstanceID,omitempty"`
+
+	// PrimaryDBInstanceID is the ID of the instance the replica replicates.
+	// +optional
+	PrimaryDBInstanceID string `json:"primaryDBInstanceID,omitempty"`
+
+	// EngineVersion is the current engine version of the replica.
+	// +optional
+	EngineVersion string `json:"engineVersion,omitempty"`
+
+	// DBInstanceClass is the current machine class of the replica.
+	// +optional
+	DBInstanceClass string `json:"dbInstanceClass,omitempty"`
+
+	// DBInstanceStorageInGB is the current size of the storage in GB.
+	// +optional
+	DBInstanceStorageInGB int `json:"dbInstanceStorageInGB,omitempty"`
+
+	// ZoneID is the zone of the replica.
+	// +optional
+	ZoneID string `json:"zoneID,omitempty"`
+
+	// ReplicationDelaySeconds is how many seconds the data of the replica
+	// lags behind that of its primary instance.
+	// +optional
+	ReplicationDelaySeconds int `json:"replicationDelaySeconds,omitempty"`
+
+	// Endpoint is the intranet endpoint of the replica.
+	// +optional
+	Endpoint *Endpoint `json:"endpoint,omitempty"`
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reference"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The methods in this file are written the way angryjet generates them from
+// the +crossplane:generate:reference markers of the referencing fields. The
+// pinned crossplane-tools predates reference generation; replace this file
+// with zz_generated.resolvers.go once it is bumped.
+
+// RDSInstanceID extracts the observed ID of an RDSInstance.
+func RDSInstanceID() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		cr, ok := mg.(*RDSInstance)
+		if !ok {
+			return ""
+		}
+		return cr.Status.AtProvider.DBInstanceID
+	}
+}
+
+// ResolveReferences of this RDSAccount.
+func (mg *RDSAccount) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.DBInstanceID,
+		Extract:      RDSInstanceID(),
+		Reference:    mg.Spec.ForProvider.DBInstanceIDRef,
+		Selector:     mg.Spec.ForProvider.DBInstanceIDSelector,
+		To: reference.To{
+			List:    &RDSInstanceList{},
+			Managed: &RDSInstance{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.DBInstanceID")
+	}
+	mg.Spec.ForProvider.DBInstanceID = rsp.ResolvedValue
+	mg.Spec.ForProvider.DBInstanceIDRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this RDSDatabase.
+func (mg *RDSDatabase) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.DBInstanceID,
+		Extract:      RDSInstanceID(),
+		Reference:    mg.Spec.ForProvider.DBInstanceIDRef,
+		Selector:     mg.Spec.ForProvider.DBInstanceIDSelector,
+		To: reference.To{
+			List:    &RDSInstanceList{},
+			Managed: &RDSInstance{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.DBInstanceID")
+	}
+	mg.Spec.ForProvider.DBInstanceID = rsp.ResolvedValue
+	mg.Spec.ForProvider.DBInstanceIDRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this RDSReadOnlyInstance.
+func (mg *RDSReadOnlyInstance) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.PrimaryDBInstanceID,
+		Extract:      RDSInstanceID(),
+		Reference:    mg.Spec.ForProvider.PrimaryDBInstanceIDRef,
+		Selector:     mg.Spec.ForProvider.PrimaryDBInstanceIDSelector,
+		To: reference.To{
+			List:    &RDSInstanceList{},
+			Managed: &RDSInstance{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.PrimaryDBInstanceID")
+	}
+	mg.Spec.ForProvider.PrimaryDBInstanceID = rsp.ResolvedValue
+	mg.Spec.ForProvider.PrimaryDBInstanceIDRef = rsp.ResolvedReference
+
+	return nil
+}
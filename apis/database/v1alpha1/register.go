@@ -45,6 +45,33 @@ var (
 	RDSInstanceGroupVersionKind = SchemeGroupVersion.WithKind(RDSInstanceKind)
 )
 
+// RDSDatabase type metadata.
+var (
+	RDSDatabaseKind             = reflect.TypeOf(RDSDatabase{}).Name()
+	RDSDatabaseGroupKind        = schema.GroupKind{Group: Group, Kind: RDSDatabaseKind}.String()
+	RDSDatabaseKindAPIVersion   = RDSDatabaseKind + "." + SchemeGroupVersion.String()
+	RDSDatabaseGroupVersionKind = SchemeGroupVersion.WithKind(RDSDatabaseKind)
+)
+
+// RDSAccount type metadata.
+var (
+	RDSAccountKind             = reflect.TypeOf(RDSAccount{}).Name()
+	RDSAccountGroupKind        = schema.GroupKind{Group: Group, Kind: RDSAccountKind}.String()
+	RDSAccountKindAPIVersion   = RDSAccountKind + "." + SchemeGroupVersion.String()
+	RDSAccountGroupVersionKind = SchemeGroupVersion.WithKind(RDSAccountKind)
+)
+
+// RDSReadOnlyInstance type metadata.
+var (
+	RDSReadOnlyInstanceKind             = reflect.TypeOf(RDSReadOnlyInstance{}).Name()
+	RDSReadOnlyInstanceGroupKind        = schema.GroupKind{Group: Group, Kind: RDSReadOnlyInstanceKind}.String()
+	RDSReadOnlyInstanceKindAPIVersion   = RDSReadOnlyInstanceKind + "." + SchemeGroupVersion.String()
+	RDSReadOnlyInstanceGroupVersionKind = SchemeGroupVersion.WithKind(RDSReadOnlyInstanceKind)
+)
+
 func init() {
 	SchemeBuilder.Register(&RDSInstance{}, &RDSInstanceList{})
+	SchemeBuilder.Register(&RDSDatabase{}, &RDSDatabaseList{})
+	SchemeBuilder.Register(&RDSAccount{}, &RDSAccountList{})
+	SchemeBuilder.Register(&RDSReadOnlyInstance{}, &RDSReadOnlyInstanceList{})
 }
@@ -21,9 +21,86 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountPrivilege) DeepCopyInto(out *AccountPrivilege) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountPrivilege.
+func (in *AccountPrivilege) DeepCopy() *AccountPrivilege {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountPrivilege)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupPolicy) DeepCopyInto(out *BackupPolicy) {
+	*out = *in
+	if in.PreferredBackupPeriod != nil {
+		in, out := &in.PreferredBackupPeriod, &out.PreferredBackupPeriod
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EnableBackupLog != nil {
+		in, out := &in.EnableBackupLog, &out.EnableBackupLog
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CrossRegionBackup != nil {
+		in, out := &in.CrossRegionBackup, &out.CrossRegionBackup
+		*out = new(CrossRegionBackup)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupPolicy.
+func (in *BackupPolicy) DeepCopy() *BackupPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapKeySelector) DeepCopyInto(out *ConfigMapKeySelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapKeySelector.
+func (in *ConfigMapKeySelector) DeepCopy() *ConfigMapKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrossRegionBackup) DeepCopyInto(out *CrossRegionBackup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrossRegionBackup.
+func (in *CrossRegionBackup) DeepCopy() *CrossRegionBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(CrossRegionBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Endpoint) DeepCopyInto(out *Endpoint) {
 	*out = *in
@@ -39,6 +116,302 @@ func (in *Endpoint) DeepCopy() *Endpoint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EngineUpgrade) DeepCopyInto(out *EngineUpgrade) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EngineUpgrade.
+func (in *EngineUpgrade) DeepCopy() *EngineUpgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(EngineUpgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSAccount) DeepCopyInto(out *RDSAccount) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSAccount.
+func (in *RDSAccount) DeepCopy() *RDSAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RDSAccount) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSAccountList) DeepCopyInto(out *RDSAccountList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RDSAccount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSAccountList.
+func (in *RDSAccountList) DeepCopy() *RDSAccountList {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSAccountList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RDSAccountList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSAccountObservation) DeepCopyInto(out *RDSAccountObservation) {
+	*out = *in
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]AccountPrivilege, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSAccountObservation.
+func (in *RDSAccountObservation) DeepCopy() *RDSAccountObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSAccountObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSAccountParameters) DeepCopyInto(out *RDSAccountParameters) {
+	*out = *in
+	if in.DBInstanceIDRef != nil {
+		in, out := &in.DBInstanceIDRef, &out.DBInstanceIDRef
+		*out = new(v1.Reference)
+		**out = **in
+	}
+	if in.DBInstanceIDSelector != nil {
+		in, out := &in.DBInstanceIDSelector, &out.DBInstanceIDSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(v1.SecretKeySelector)
+		**out = **in
+	}
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]AccountPrivilege, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSAccountParameters.
+func (in *RDSAccountParameters) DeepCopy() *RDSAccountParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSAccountParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSAccountSpec) DeepCopyInto(out *RDSAccountSpec) {
+	*out = *in
+	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSAccountSpec.
+func (in *RDSAccountSpec) DeepCopy() *RDSAccountSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSAccountSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSAccountStatus) DeepCopyInto(out *RDSAccountStatus) {
+	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSAccountStatus.
+func (in *RDSAccountStatus) DeepCopy() *RDSAccountStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSAccountStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSDatabase) DeepCopyInto(out *RDSDatabase) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSDatabase.
+func (in *RDSDatabase) DeepCopy() *RDSDatabase {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSDatabase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RDSDatabase) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSDatabaseList) DeepCopyInto(out *RDSDatabaseList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RDSDatabase, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSDatabaseList.
+func (in *RDSDatabaseList) DeepCopy() *RDSDatabaseList {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSDatabaseList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RDSDatabaseList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSDatabaseObservation) DeepCopyInto(out *RDSDatabaseObservation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSDatabaseObservation.
+func (in *RDSDatabaseObservation) DeepCopy() *RDSDatabaseObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSDatabaseObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSDatabaseParameters) DeepCopyInto(out *RDSDatabaseParameters) {
+	*out = *in
+	if in.DBInstanceIDRef != nil {
+		in, out := &in.DBInstanceIDRef, &out.DBInstanceIDRef
+		*out = new(v1.Reference)
+		**out = **in
+	}
+	if in.DBInstanceIDSelector != nil {
+		in, out := &in.DBInstanceIDSelector, &out.DBInstanceIDSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSDatabaseParameters.
+func (in *RDSDatabaseParameters) DeepCopy() *RDSDatabaseParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSDatabaseParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSDatabaseSpec) DeepCopyInto(out *RDSDatabaseSpec) {
+	*out = *in
+	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSDatabaseSpec.
+func (in *RDSDatabaseSpec) DeepCopy() *RDSDatabaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSDatabaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSDatabaseStatus) DeepCopyInto(out *RDSDatabaseStatus) {
+	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	out.AtProvider = in.AtProvider
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSDatabaseStatus.
+func (in *RDSDatabaseStatus) DeepCopy() *RDSDatabaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSDatabaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RDSInstance) DeepCopyInto(out *RDSInstance) {
 	*out = *in
@@ -101,6 +474,55 @@ func (in *RDSInstanceList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RDSInstanceObservation) DeepCopyInto(out *RDSInstanceObservation) {
 	*out = *in
+	if in.EngineUpgrade != nil {
+		in, out := &in.EngineUpgrade, &out.EngineUpgrade
+		*out = new(EngineUpgrade)
+		**out = **in
+	}
+	if in.BackupPolicy != nil {
+		in, out := &in.BackupPolicy, &out.BackupPolicy
+		*out = new(BackupPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PendingRestartParameters != nil {
+		in, out := &in.PendingRestartParameters, &out.PendingRestartParameters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Endpoint != nil {
+		in, out := &in.Endpoint, &out.Endpoint
+		*out = new(Endpoint)
+		**out = **in
+	}
+	if in.PublicEndpoint != nil {
+		in, out := &in.PublicEndpoint, &out.PublicEndpoint
+		*out = new(Endpoint)
+		**out = **in
+	}
+	if in.SecurityIPGroups != nil {
+		in, out := &in.SecurityIPGroups, &out.SecurityIPGroups
+		*out = make([]SecurityIPGroupObservation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CostEstimate != nil {
+		in, out := &in.CostEstimate, &out.CostEstimate
+		*out = new(apisv1alpha1.CostEstimate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PendingOperation != nil {
+		in, out := &in.PendingOperation, &out.PendingOperation
+		*out = new(apisv1alpha1.PendingOperation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSInstanceObservation.
@@ -116,6 +538,30 @@ func (in *RDSInstanceObservation) DeepCopy() *RDSInstanceObservation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RDSInstanceParameters) DeepCopyInto(out *RDSInstanceParameters) {
 	*out = *in
+	if in.SecurityIPGroups != nil {
+		in, out := &in.SecurityIPGroups, &out.SecurityIPGroups
+		*out = make([]SecurityIPGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PubliclyAccessible != nil {
+		in, out := &in.PubliclyAccessible, &out.PubliclyAccessible
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BackupPolicy != nil {
+		in, out := &in.BackupPolicy, &out.BackupPolicy
+		*out = new(BackupPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSInstanceParameters.
@@ -132,7 +578,7 @@ func (in *RDSInstanceParameters) DeepCopy() *RDSInstanceParameters {
 func (in *RDSInstanceSpec) DeepCopyInto(out *RDSInstanceSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
-	out.ForProvider = in.ForProvider
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSInstanceSpec.
@@ -149,7 +595,7 @@ func (in *RDSInstanceSpec) DeepCopy() *RDSInstanceSpec {
 func (in *RDSInstanceStatus) DeepCopyInto(out *RDSInstanceStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
-	out.AtProvider = in.AtProvider
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSInstanceStatus.
@@ -161,3 +607,186 @@ func (in *RDSInstanceStatus) DeepCopy() *RDSInstanceStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSReadOnlyInstance) DeepCopyInto(out *RDSReadOnlyInstance) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSReadOnlyInstance.
+func (in *RDSReadOnlyInstance) DeepCopy() *RDSReadOnlyInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSReadOnlyInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RDSReadOnlyInstance) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSReadOnlyInstanceList) DeepCopyInto(out *RDSReadOnlyInstanceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RDSReadOnlyInstance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSReadOnlyInstanceList.
+func (in *RDSReadOnlyInstanceList) DeepCopy() *RDSReadOnlyInstanceList {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSReadOnlyInstanceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RDSReadOnlyInstanceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSReadOnlyInstanceObservation) DeepCopyInto(out *RDSReadOnlyInstanceObservation) {
+	*out = *in
+	if in.Endpoint != nil {
+		in, out := &in.Endpoint, &out.Endpoint
+		*out = new(Endpoint)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSReadOnlyInstanceObservation.
+func (in *RDSReadOnlyInstanceObservation) DeepCopy() *RDSReadOnlyInstanceObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSReadOnlyInstanceObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSReadOnlyInstanceParameters) DeepCopyInto(out *RDSReadOnlyInstanceParameters) {
+	*out = *in
+	if in.PrimaryDBInstanceIDRef != nil {
+		in, out := &in.PrimaryDBInstanceIDRef, &out.PrimaryDBInstanceIDRef
+		*out = new(v1.Reference)
+		**out = **in
+	}
+	if in.PrimaryDBInstanceIDSelector != nil {
+		in, out := &in.PrimaryDBInstanceIDSelector, &out.PrimaryDBInstanceIDSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSReadOnlyInstanceParameters.
+func (in *RDSReadOnlyInstanceParameters) DeepCopy() *RDSReadOnlyInstanceParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSReadOnlyInstanceParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSReadOnlyInstanceSpec) DeepCopyInto(out *RDSReadOnlyInstanceSpec) {
+	*out = *in
+	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSReadOnlyInstanceSpec.
+func (in *RDSReadOnlyInstanceSpec) DeepCopy() *RDSReadOnlyInstanceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSReadOnlyInstanceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSReadOnlyInstanceStatus) DeepCopyInto(out *RDSReadOnlyInstanceStatus) {
+	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSReadOnlyInstanceStatus.
+func (in *RDSReadOnlyInstanceStatus) DeepCopy() *RDSReadOnlyInstanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSReadOnlyInstanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityIPGroup) DeepCopyInto(out *SecurityIPGroup) {
+	*out = *in
+	if in.IPs != nil {
+		in, out := &in.IPs, &out.IPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPsFrom != nil {
+		in, out := &in.IPsFrom, &out.IPsFrom
+		*out = make([]ConfigMapKeySelector, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityIPGroup.
+func (in *SecurityIPGroup) DeepCopy() *SecurityIPGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityIPGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityIPGroupObservation) DeepCopyInto(out *SecurityIPGroupObservation) {
+	*out = *in
+	if in.IPs != nil {
+		in, out := &in.IPs, &out.IPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityIPGroupObservation.
+func (in *SecurityIPGroupObservation) DeepCopy() *SecurityIPGroupObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityIPGroupObservation)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -22,6 +22,9 @@ limitations under the License.
 // Remove existing CRDs
 //go:generate rm -rf ../package/crds
 
+// Generate API types and SDK conversions from sdkgen.yaml files
+//go:generate go run -tags generate ../cmd/sdkgen --header-file=../hack/boilerplate.go.txt ./...
+
 // Generate deepcopy methodsets and CRD manifests
 //go:generate go run -tags generate sigs.k8s.io/controller-tools/cmd/controller-gen object:headerFile=../hack/boilerplate.go.txt paths=./... crd:trivialVersions=true,crdVersions=v1 output:artifacts:config=../package/crds
 
@@ -19,6 +19,8 @@ package v1alpha1
 import (
 	runtimev1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
 )
 
 // +kubebuilder:object:root=true
@@ -73,4 +75,9 @@ type NASFileSystemParameter struct {
 type NASFileSystemObservation struct {
 	FileSystemID      string `json:"fileSystemID,omitempty"`
 	MountTargetDomain string `json:"mountTargetDomain,omitempty"`
+
+	// PendingOperation is the change of the file system that was requested but has
+	// not completed yet, if any.
+	// +optional
+	PendingOperation *aliv1alpha1.PendingOperation `json:"pendingOperation,omitempty"`
 }
@@ -21,6 +21,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	apisv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -86,6 +87,11 @@ func (in *NASFileSystemList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NASFileSystemObservation) DeepCopyInto(out *NASFileSystemObservation) {
 	*out = *in
+	if in.PendingOperation != nil {
+		in, out := &in.PendingOperation, &out.PendingOperation
+		*out = new(apisv1alpha1.PendingOperation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NASFileSystemObservation.
@@ -164,7 +170,7 @@ func (in *NASFileSystemSpec) DeepCopy() *NASFileSystemSpec {
 func (in *NASFileSystemStatus) DeepCopyInto(out *NASFileSystemStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
-	out.AtProvider = in.AtProvider
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NASFileSystemStatus.
@@ -19,6 +19,8 @@ package v1alpha1
 import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
 )
 
 // +kubebuilder:object:root=true
@@ -138,6 +140,16 @@ type RedisInstanceObservation struct {
 
 	// ConnectionReady specifies whether the network connect is ready
 	ConnectionReady bool `json:"connectionReady"`
+
+	// CostEstimate is the estimated cost of the instance. Only recorded if
+	// cost estimation is enabled by its ProviderConfig.
+	// +optional
+	CostEstimate *aliv1alpha1.CostEstimate `json:"costEstimate,omitempty"`
+
+	// PendingOperation is the change of the instance that was requested but has
+	// not completed yet, if any.
+	// +optional
+	PendingOperation *aliv1alpha1.PendingOperation `json:"pendingOperation,omitempty"`
 }
 
 // Endpoint is the redis endpoint
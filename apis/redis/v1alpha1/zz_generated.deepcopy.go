@@ -21,6 +21,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	apisv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -101,6 +102,16 @@ func (in *RedisInstanceList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RedisInstanceObservation) DeepCopyInto(out *RedisInstanceObservation) {
 	*out = *in
+	if in.CostEstimate != nil {
+		in, out := &in.CostEstimate, &out.CostEstimate
+		*out = new(apisv1alpha1.CostEstimate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PendingOperation != nil {
+		in, out := &in.PendingOperation, &out.PendingOperation
+		*out = new(apisv1alpha1.PendingOperation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisInstanceObservation.
@@ -149,7 +160,7 @@ func (in *RedisInstanceSpec) DeepCopy() *RedisInstanceSpec {
 func (in *RedisInstanceStatus) DeepCopyInto(out *RedisInstanceStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
-	out.AtProvider = in.AtProvider
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisInstanceStatus.
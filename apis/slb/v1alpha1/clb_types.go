@@ -19,6 +19,8 @@ package v1alpha1
 import (
 	runtimev1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
 )
 
 // +kubebuilder:object:root=true
@@ -49,7 +51,7 @@ type CLB struct {
 // CLBSpec defines the desired state of CLB
 type CLBSpec struct {
 	runtimev1.ResourceSpec `json:",inline"`
-	ForProvider            CLBParameter `json:"forProvider"`
+	ForProvider            CLBParameters `json:"forProvider"`
 }
 
 // CLBStatus defines the observed state of CLB
@@ -58,79 +60,17 @@ type CLBStatus struct {
 	AtProvider               CLBObservation `json:"atProvider,omitempty"`
 }
 
-// CLBParameter is the isolated place to store files
-type CLBParameter struct {
-	// Region is the ID of the region where you want to create the SLB instance.
-	Region *string `json:"region"`
-	// AddressType is the type of IP address that the SLB instance uses to provide services. Valid values:
-	// internet: After an Internet-facing SLB instance is created, the system assigns a public IP address to the SLB instance.
-	// Then, the SLB instance can forward requests from the Internet.
-	// intranet: After an internal-facing SLB instance is created, the system assigns a private IP address to the SLB instance.
-	// Then, the SLB instance can forward only internal requests.
-	AddressType *string `json:"addressType,omitempty"`
-
-	// Address is the IP address
-	Address *string `json:"address,omitempty"`
-
-	// Bandwidth is the maximum bandwidth value of the listener. Unit: Mbit/s.
-	// Valid values: -1 and 1 to 5120.
-	// -1: For a pay-by-data-transfer Internet-facing SLB instance, you can set the value to -1. This indicates that
-	// the bandwidth is unlimited.
-	// 1 to 5120: For a pay-by-bandwidth Internet-facing SLB instance, you can specify a bandwidth cap for each listener.
-	// The sum of bandwidth limit values of all listeners cannot exceed the maximum bandwidth value of the SLB instance.
-	Bandwidth *int32 `json:"bandwidth,omitempty"`
-
-	// InternetChargeType is the metering method of the Internet-facing SLB instance. Valid values:
-	// paybytraffic (default): pay-by-data-transfer
-	// +kubebuilder:default:=paybytraffic
-	InternetChargeType *string `json:"internetChargeType,omitempty"`
-
-	// VpcID is the ID of the virtual private cloud (VPC) to which the SLB instance belongs.
-	VpcID *string `json:"vpcId,omitempty"`
-
-	// VSwitchID is the ID of the vSwitch to which the SLB instance is attached.
-	// To create an SLB instance that is deployed in a VPC, you must set this parameter. If you specify this parameter,
-	// the value of the AddressType parameter is set to intranet by default.
-	VSwitchID *string `json:"vSwitchId,omitempty"`
-
-	// LoadBalancerSpec is the specification of the SLB instance.
-	// The types of SLB instance that you can create vary by region.
-	// +kubebuilder:validation:Enum:=slb.s1.small;slb.s2.small;slb.s2.medium;slb.s3.small;slb.s3.medium;slb.s3.large
-	LoadBalancerSpec *string `json:"loadBalancerSpec,omitempty"`
-
-	// ClientToken that is used to ensure the idempotence of the request. You can use the client to generate the value,
-	// but you must ensure that it is unique among different requests. The token can contain only ASCII characters and
-	// cannot exceed 64 characters in length.
-	ClientToken *string `json:"clientToken,omitempty"`
-
-	OwnerID                      *int64  `json:"ownerId,omitempty"`
-	ResourceOwnerAccount         *string `json:"resourceOwnerAccount,omitempty"`
-	ResourceOwnerID              *int64  `json:"resourceOwnerId,omitempty"`
-	OwnerAccount                 *string `json:"ownerAccount,omitempty"`
-	MasterZoneID                 *string `json:"masterZoneId,omitempty"`
-	SlaveZoneID                  *string `json:"slaveZoneId,omitempty"`
-	ResourceGroupID              *string `json:"resourceGroupId,omitempty"`
-	PayType                      *string `json:"payType,omitempty"`
-	PricingCycle                 *string `json:"pricingCycle,omitempty"`
-	Duration                     *int32  `json:"duration,omitempty"`
-	AutoPay                      *bool   `json:"autoPay,omitempty"`
-	AddressIPVersion             *string `json:"addressIPVersion,omitempty"`
-	DeleteProtection             *string `json:"deleteProtection,omitempty"`
-	ModificationProtectionStatus *string `json:"modificationProtectionStatus,omitempty"`
-	ModificationProtectionReason *string `json:"modificationProtectionReason,omitempty"`
-}
-
 // CLBObservation is the representation of the current state that is observed.
 type CLBObservation struct {
-	LoadBalancerID               *string `json:"loadBalancerID,omitempty"`
-	CreateTime                   *string `json:"CreateTime,omitempty"`
-	NetworkType                  *string `json:"NetworkType,omitempty"`
-	MasterZoneID                 *string `json:"MasterZoneId,omitempty"`
-	ModificationProtectionReason *string `json:"ModificationProtectionReason,omitempty"`
-	ModificationProtectionStatus *string `json:"ModificationProtectionStatus,omitempty"`
-	LoadBalancerStatus           *string `json:"LoadBalancerStatus,omitempty"`
-	ResourceGroupID              *string `json:"ResourceGroupId,omitempty"`
-	DeleteProtection             *string `json:"DeleteProtection,omitempty"`
-	// Though `Address` is one of the Parameter, but if the parameter it's not set, it still can be generated.
-	Address *string `json:"address,omitempty"`
+	LoadBalancerObservation `json:",inline"`
+
+	// CostEstimate is the estimated cost of the load balancer. Only recorded if
+	// cost estimation is enabled by its ProviderConfig.
+	// +optional
+	CostEstimate *aliv1alpha1.CostEstimate `json:"costEstimate,omitempty"`
+
+	// PendingOperation is the change of the load balancer that was requested but has
+	// not completed yet, if any.
+	// +optional
+	PendingOperation *aliv1alpha1.PendingOperation `json:"pendingOperation,omitempty"`
 }
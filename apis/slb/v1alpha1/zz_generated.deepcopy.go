@@ -21,6 +21,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	apisv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -86,55 +87,16 @@ func (in *CLBList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CLBObservation) DeepCopyInto(out *CLBObservation) {
 	*out = *in
-	if in.LoadBalancerID != nil {
-		in, out := &in.LoadBalancerID, &out.LoadBalancerID
-		*out = new(string)
-		**out = **in
-	}
-	if in.CreateTime != nil {
-		in, out := &in.CreateTime, &out.CreateTime
-		*out = new(string)
-		**out = **in
-	}
-	if in.NetworkType != nil {
-		in, out := &in.NetworkType, &out.NetworkType
-		*out = new(string)
-		**out = **in
-	}
-	if in.MasterZoneID != nil {
-		in, out := &in.MasterZoneID, &out.MasterZoneID
-		*out = new(string)
-		**out = **in
-	}
-	if in.ModificationProtectionReason != nil {
-		in, out := &in.ModificationProtectionReason, &out.ModificationProtectionReason
-		*out = new(string)
-		**out = **in
-	}
-	if in.ModificationProtectionStatus != nil {
-		in, out := &in.ModificationProtectionStatus, &out.ModificationProtectionStatus
-		*out = new(string)
-		**out = **in
-	}
-	if in.LoadBalancerStatus != nil {
-		in, out := &in.LoadBalancerStatus, &out.LoadBalancerStatus
-		*out = new(string)
-		**out = **in
-	}
-	if in.ResourceGroupID != nil {
-		in, out := &in.ResourceGroupID, &out.ResourceGroupID
-		*out = new(string)
-		**out = **in
-	}
-	if in.DeleteProtection != nil {
-		in, out := &in.DeleteProtection, &out.DeleteProtection
-		*out = new(string)
-		**out = **in
+	in.LoadBalancerObservation.DeepCopyInto(&out.LoadBalancerObservation)
+	if in.CostEstimate != nil {
+		in, out := &in.CostEstimate, &out.CostEstimate
+		*out = new(apisv1alpha1.CostEstimate)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.Address != nil {
-		in, out := &in.Address, &out.Address
-		*out = new(string)
-		**out = **in
+	if in.PendingOperation != nil {
+		in, out := &in.PendingOperation, &out.PendingOperation
+		*out = new(apisv1alpha1.PendingOperation)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -149,8 +111,23 @@ func (in *CLBObservation) DeepCopy() *CLBObservation {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CLBParameter) DeepCopyInto(out *CLBParameter) {
+func (in *CLBParameters) DeepCopyInto(out *CLBParameters) {
 	*out = *in
+	if in.OwnerID != nil {
+		in, out := &in.OwnerID, &out.OwnerID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ResourceOwnerAccount != nil {
+		in, out := &in.ResourceOwnerAccount, &out.ResourceOwnerAccount
+		*out = new(string)
+		**out = **in
+	}
+	if in.ResourceOwnerID != nil {
+		in, out := &in.ResourceOwnerID, &out.ResourceOwnerID
+		*out = new(int64)
+		**out = **in
+	}
 	if in.Region != nil {
 		in, out := &in.Region, &out.Region
 		*out = new(string)
@@ -161,8 +138,8 @@ func (in *CLBParameter) DeepCopyInto(out *CLBParameter) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.Address != nil {
-		in, out := &in.Address, &out.Address
+	if in.InternetChargeType != nil {
+		in, out := &in.InternetChargeType, &out.InternetChargeType
 		*out = new(string)
 		**out = **in
 	}
@@ -171,8 +148,8 @@ func (in *CLBParameter) DeepCopyInto(out *CLBParameter) {
 		*out = new(int32)
 		**out = **in
 	}
-	if in.InternetChargeType != nil {
-		in, out := &in.InternetChargeType, &out.InternetChargeType
+	if in.ClientToken != nil {
+		in, out := &in.ClientToken, &out.ClientToken
 		*out = new(string)
 		**out = **in
 	}
@@ -186,31 +163,6 @@ func (in *CLBParameter) DeepCopyInto(out *CLBParameter) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.LoadBalancerSpec != nil {
-		in, out := &in.LoadBalancerSpec, &out.LoadBalancerSpec
-		*out = new(string)
-		**out = **in
-	}
-	if in.ClientToken != nil {
-		in, out := &in.ClientToken, &out.ClientToken
-		*out = new(string)
-		**out = **in
-	}
-	if in.OwnerID != nil {
-		in, out := &in.OwnerID, &out.OwnerID
-		*out = new(int64)
-		**out = **in
-	}
-	if in.ResourceOwnerAccount != nil {
-		in, out := &in.ResourceOwnerAccount, &out.ResourceOwnerAccount
-		*out = new(string)
-		**out = **in
-	}
-	if in.ResourceOwnerID != nil {
-		in, out := &in.ResourceOwnerID, &out.ResourceOwnerID
-		*out = new(int64)
-		**out = **in
-	}
 	if in.OwnerAccount != nil {
 		in, out := &in.OwnerAccount, &out.OwnerAccount
 		*out = new(string)
@@ -226,6 +178,11 @@ func (in *CLBParameter) DeepCopyInto(out *CLBParameter) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.LoadBalancerSpec != nil {
+		in, out := &in.LoadBalancerSpec, &out.LoadBalancerSpec
+		*out = new(string)
+		**out = **in
+	}
 	if in.ResourceGroupID != nil {
 		in, out := &in.ResourceGroupID, &out.ResourceGroupID
 		*out = new(string)
@@ -256,6 +213,11 @@ func (in *CLBParameter) DeepCopyInto(out *CLBParameter) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Address != nil {
+		in, out := &in.Address, &out.Address
+		*out = new(string)
+		**out = **in
+	}
 	if in.DeleteProtection != nil {
 		in, out := &in.DeleteProtection, &out.DeleteProtection
 		*out = new(string)
@@ -273,12 +235,12 @@ func (in *CLBParameter) DeepCopyInto(out *CLBParameter) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CLBParameter.
-func (in *CLBParameter) DeepCopy() *CLBParameter {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CLBParameters.
+func (in *CLBParameters) DeepCopy() *CLBParameters {
 	if in == nil {
 		return nil
 	}
-	out := new(CLBParameter)
+	out := new(CLBParameters)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -316,3 +278,68 @@ func (in *CLBStatus) DeepCopy() *CLBStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerObservation) DeepCopyInto(out *LoadBalancerObservation) {
+	*out = *in
+	if in.LoadBalancerID != nil {
+		in, out := &in.LoadBalancerID, &out.LoadBalancerID
+		*out = new(string)
+		**out = **in
+	}
+	if in.CreateTime != nil {
+		in, out := &in.CreateTime, &out.CreateTime
+		*out = new(string)
+		**out = **in
+	}
+	if in.NetworkType != nil {
+		in, out := &in.NetworkType, &out.NetworkType
+		*out = new(string)
+		**out = **in
+	}
+	if in.Address != nil {
+		in, out := &in.Address, &out.Address
+		*out = new(string)
+		**out = **in
+	}
+	if in.MasterZoneID != nil {
+		in, out := &in.MasterZoneID, &out.MasterZoneID
+		*out = new(string)
+		**out = **in
+	}
+	if in.ModificationProtectionReason != nil {
+		in, out := &in.ModificationProtectionReason, &out.ModificationProtectionReason
+		*out = new(string)
+		**out = **in
+	}
+	if in.ModificationProtectionStatus != nil {
+		in, out := &in.ModificationProtectionStatus, &out.ModificationProtectionStatus
+		*out = new(string)
+		**out = **in
+	}
+	if in.LoadBalancerStatus != nil {
+		in, out := &in.LoadBalancerStatus, &out.LoadBalancerStatus
+		*out = new(string)
+		**out = **in
+	}
+	if in.ResourceGroupID != nil {
+		in, out := &in.ResourceGroupID, &out.ResourceGroupID
+		*out = new(string)
+		**out = **in
+	}
+	if in.DeleteProtection != nil {
+		in, out := &in.DeleteProtection, &out.DeleteProtection
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerObservation.
+func (in *LoadBalancerObservation) DeepCopy() *LoadBalancerObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerObservation)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by sdkgen. DO NOT EDIT.
+
+package v1alpha1
+
+// CLBParameters are the configurable fields of a CLB.
+type CLBParameters struct {
+	// +optional
+	OwnerID *int64 `json:"ownerId,omitempty"`
+
+	// +optional
+	ResourceOwnerAccount *string `json:"resourceOwnerAccount,omitempty"`
+
+	// +optional
+	ResourceOwnerID *int64 `json:"resourceOwnerId,omitempty"`
+
+	// Region is the ID of the region where you want to create the SLB instance.
+	// +kubebuilder:validation:Required
+	Region *string `json:"region"`
+
+	// AddressType is the type of IP address that the SLB instance uses to provide services. Valid values:
+	// internet: After an Internet-facing SLB instance is created, the system assigns a public IP address to the SLB instance.
+	// Then, the SLB instance can forward requests from the Internet.
+	// intranet: After an internal-facing SLB instance is created, the system assigns a private IP address to the SLB instance.
+	// Then, the SLB instance can forward only internal requests.
+	// +optional
+	AddressType *string `json:"addressType,omitempty"`
+
+	// InternetChargeType is the metering method of the Internet-facing SLB instance. Valid values:
+	// paybytraffic (default): pay-by-data-transfer
+	// +kubebuilder:default:=paybytraffic
+	// +optional
+	InternetChargeType *string `json:"internetChargeType,omitempty"`
+
+	// Bandwidth is the maximum bandwidth value of the listener. Unit: Mbit/s.
+	// Valid values: -1 and 1 to 5120.
+	// -1: For a pay-by-data-transfer Internet-facing SLB instance, you can set the value to -1. This indicates that
+	// the bandwidth is unlimited.
+	// 1 to 5120: For a pay-by-bandwidth Internet-facing SLB instance, you can specify a bandwidth cap for each listener.
+	// The sum of bandwidth limit values of all listeners cannot exceed the maximum bandwidth value of the SLB instance.
+	// +optional
+	Bandwidth *int32 `json:"bandwidth,omitempty"`
+
+	// ClientToken that is used to ensure the idempotence of the request. You can use the client to generate the value,
+	// but you must ensure that it is unique among different requests. The token can contain only ASCII characters and
+	// cannot exceed 64 characters in length.
+	// +optional
+	ClientToken *string `json:"clientToken,omitempty"`
+
+	// VpcID is the ID of the virtual private cloud (VPC) to which the SLB instance belongs.
+	// +optional
+	VpcID *string `json:"vpcId,omitempty"`
+
+	// VSwitchID is the ID of the vSwitch to which the SLB instance is attached.
+	// To create an SLB instance that is deployed in a VPC, you must set this parameter. If you specify this parameter,
+	// the value of the AddressType parameter is set to intranet by default.
+	// +optional
+	VSwitchID *string `json:"vSwitchId,omitempty"`
+
+	// +optional
+	OwnerAccount *string `json:"ownerAccount,omitempty"`
+
+	// +optional
+	MasterZoneID *string `json:"masterZoneId,omitempty"`
+
+	// +optional
+	SlaveZoneID *string `json:"slaveZoneId,omitempty"`
+
+	// LoadBalancerSpec is the specification of the SLB instance.
+	// The types of SLB instance that you can create vary by region.
+	// +kubebuilder:validation:Enum:=slb.s1.small;slb.s2.small;slb.s2.medium;slb.s3.small;slb.s3.medium;slb.s3.large
+	// +optional
+	LoadBalancerSpec *string `json:"loadBalancerSpec,omitempty"`
+
+	// +optional
+	ResourceGroupID *string `json:"resourceGroupId,omitempty"`
+
+	// +optional
+	PayType *string `json:"payType,omitempty"`
+
+	// +optional
+	PricingCycle *string `json:"pricingCycle,omitempty"`
+
+	// +optional
+	Duration *int32 `json:"duration,omitempty"`
+
+	// +optional
+	AutoPay *bool `json:"autoPay,omitempty"`
+
+	// +optional
+	AddressIPVersion *string `json:"addressIPVersion,omitempty"`
+
+	// Address is the IP address
+	// +optional
+	Address *string `json:"address,omitempty"`
+
+	// +optional
+	DeleteProtection *string `json:"deleteProtection,omitempty"`
+
+	// +optional
+	ModificationProtectionStatus *string `json:"modificationProtectionStatus,omitempty"`
+
+	// +optional
+	ModificationProtectionReason *string `json:"modificationProtectionReason,omitempty"`
+}
+
+// LoadBalancerObservation are the observable fields of a CLB.
+type LoadBalancerObservation struct {
+	// +optional
+	LoadBalancerID *string `json:"loadBalancerId,omitempty"`
+
+	// +optional
+	CreateTime *string `json:"createTime,omitempty"`
+
+	// +optional
+	NetworkType *string `json:"networkType,omitempty"`
+
+	// Address of the load balancer, which is observed even if the spec does not set it.
+	// +optional
+	Address *string `json:"address,omitempty"`
+
+	// +optional
+	MasterZoneID *string `json:"masterZoneId,omitempty"`
+
+	// +optional
+	ModificationProtectionReason *string `json:"modificationProtectionReason,omitempty"`
+
+	// +optional
+	ModificationProtectionStatus *string `json:"modificationProtectionStatus,omitempty"`
+
+	// +optional
+	LoadBalancerStatus *string `json:"loadBalancerStatus,omitempty"`
+
+	// +optional
+	ResourceGroupID *string `json:"resourceGroupId,omitempty"`
+
+	// +optional
+	DeleteProtection *string `json:"deleteProtection,omitempty"`
+}
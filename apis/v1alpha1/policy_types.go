@@ -0,0 +1,98 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// A PolicyOperator compares the value of a parameter with the values of a
+// PolicyRule.
+type PolicyOperator string
+
+// Policy operators.
+const (
+	// PolicyOperatorIn requires the parameter to be one of the values.
+	PolicyOperatorIn PolicyOperator = "In"
+
+	// PolicyOperatorNotIn requires the parameter not to be any of the values.
+	PolicyOperatorNotIn PolicyOperator = "NotIn"
+
+	// PolicyOperatorMax requires the numeric parameter to be at most the
+	// single value.
+	PolicyOperatorMax PolicyOperator = "Max"
+
+	// PolicyOperatorMin requires the numeric parameter to be at least the
+	// single value.
+	PolicyOperatorMin PolicyOperator = "Min"
+)
+
+// A PolicyRule constrains one parameter of a kind of managed resource. Rules
+// only constrain parameters that are set.
+type PolicyRule struct {
+	// Name of the rule, reported with violations.
+	Name string `json:"name"`
+
+	// Kind of the managed resources the rule applies to, e.g. RDSInstance.
+	Kind string `json:"kind"`
+
+	// Field is the dot separated path of the constrained parameter within
+	// spec.forProvider, or within spec for kinds without spec.forProvider,
+	// e.g. instanceClass.
+	Field string `json:"field"`
+
+	// Operator comparing the parameter with the values.
+	// +kubebuilder:validation:Enum=In;NotIn;Max;Min
+	Operator PolicyOperator `json:"operator"`
+
+	// Values the parameter is compared with. Max and Min take a single
+	// numeric value.
+	Values []string `json:"values"`
+
+	// Message explaining the rule, reported with violations.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// A ProvisioningPolicySpec defines the rules of a ProvisioningPolicy.
+type ProvisioningPolicySpec struct {
+	// Rules the parameters of managed resources must satisfy.
+	Rules []PolicyRule `json:"rules"`
+}
+
+// +kubebuilder:object:root=true
+
+// A ProvisioningPolicy constrains the parameters of managed resources. Managed
+// resources violating a rule are rejected by the validating webhook, and are
+// neither created nor updated by their controller.
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,alibaba}
+type ProvisioningPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ProvisioningPolicySpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// ProvisioningPolicyList contains a list of ProvisioningPolicy
+type ProvisioningPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProvisioningPolicy `json:"items"`
+}
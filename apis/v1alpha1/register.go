@@ -66,7 +66,16 @@ var (
 	ProviderConfigUsageListGroupVersionKind = SchemeGroupVersion.WithKind(ProviderConfigUsageListKind)
 )
 
+// ProvisioningPolicy type metadata.
+var (
+	ProvisioningPolicyKind             = reflect.TypeOf(ProvisioningPolicy{}).Name()
+	ProvisioningPolicyGroupKind        = schema.GroupKind{Group: Group, Kind: ProvisioningPolicyKind}.String()
+	ProvisioningPolicyKindAPIVersion   = ProvisioningPolicyKind + "." + SchemeGroupVersion.String()
+	ProvisioningPolicyGroupVersionKind = SchemeGroupVersion.WithKind(ProvisioningPolicyKind)
+)
+
 func init() {
 	SchemeBuilder.Register(&ProviderConfig{}, &ProviderConfigList{})
 	SchemeBuilder.Register(&ProviderConfigUsage{}, &ProviderConfigUsageList{})
+	SchemeBuilder.Register(&ProvisioningPolicy{}, &ProvisioningPolicyList{})
 }
@@ -60,6 +60,46 @@ type ProviderConfigSpec struct {
 	// Region for managed resources created using this Alibaba Cloud provider,
 	// e.g. "cn-hangzhou".
 	Region string `json:"region"`
+
+	// DryRun makes the managed resources using this ProviderConfig report the
+	// cloud API calls they would make, instead of making them.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// CostEstimation records the estimated cost of the managed resources
+	// using this ProviderConfig in their status, using the BSS pricing APIs.
+	// +optional
+	CostEstimation bool `json:"costEstimation,omitempty"`
+
+	// OperationTimeout bounds each observation, creation, update and
+	// deletion of the managed resources using this ProviderConfig, including
+	// the cloud API calls it makes, e.g. 30s. It cannot exceed the reconcile
+	// timeout of the provider.
+	// +optional
+	OperationTimeout *metav1.Duration `json:"operationTimeout,omitempty"`
+
+	// Policy restricts which managed resources may use this ProviderConfig.
+	// All managed resources may use it if unset. Policies are enforced on the
+	// managed resources Crossplane composes for claims, whose labels are set
+	// by their composition; anyone who may create managed resources directly
+	// may set any labels, so restrict that with RBAC.
+	// +optional
+	Policy *ProviderConfigPolicy `json:"policy,omitempty"`
+}
+
+// A ProviderConfigPolicy restricts which managed resources may use a
+// ProviderConfig and which secrets it may read. Managed resources must satisfy
+// all of the rules that are set.
+type ProviderConfigPolicy struct {
+	// Selector selects the managed resources that may use the ProviderConfig
+	// by their labels.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// AllowedSecretNamespaces are the namespaces the ProviderConfig may read
+	// credentials secrets from.
+	// +optional
+	AllowedSecretNamespaces []string `json:"allowedSecretNamespaces,omitempty"`
 }
 
 // A ProviderConfigStatus represents the status of a ProviderConfig.
@@ -115,3 +155,46 @@ type ProviderConfigUsageList struct {
 	metav1.ListMeta `json:"metadata,omitempty"`
 	Items           []ProviderConfigUsage `json:"items"`
 }
+
+// A CostEstimate is the estimated cost of a cloud resource, as quoted by the
+// BSS pricing APIs for its parameters.
+type CostEstimate struct {
+	// Amount charged per period, e.g. "0.42".
+	Amount string `json:"amount"`
+
+	// Currency of the amount, e.g. "CNY".
+	Currency string `json:"currency"`
+
+	// Period the amount is charged for: Hour for pay-as-you-go resources and
+	// Month for subscription resources.
+	Period string `json:"period"`
+
+	// ParametersHash identifies the parameters the cost was estimated for.
+	ParametersHash string `json:"parametersHash"`
+
+	// LastEstimateTime is when the cost was estimated.
+	LastEstimateTime metav1.Time `json:"lastEstimateTime"`
+}
+
+// A PendingOperation is an asynchronous change of a cloud resource that was
+// requested but has not been observed to complete yet.
+type PendingOperation struct {
+	// Operation that was requested, e.g. Create or Update.
+	Operation string `json:"operation"`
+
+	// RequestedAt is when the operation was requested.
+	RequestedAt metav1.Time `json:"requestedAt"`
+
+	// SpecHash identifies the parameters the operation applies.
+	SpecHash string `json:"specHash"`
+
+	// Started is true once the cloud resource was observed applying the
+	// operation.
+	// +optional
+	Started bool `json:"started,omitempty"`
+
+	// TaskID identifies the operation in the cloud, e.g. the ID of its
+	// order, if the cloud API returned one.
+	// +optional
+	TaskID string `json:"taskID,omitempty"`
+}
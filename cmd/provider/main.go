@@ -17,27 +17,73 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"gopkg.in/alecthomas/kingpin.v2"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 
 	"github.com/crossplane/provider-alibaba/apis"
+	"github.com/crossplane/provider-alibaba/pkg/clients/audit"
+	"github.com/crossplane/provider-alibaba/pkg/clients/sls"
 	"github.com/crossplane/provider-alibaba/pkg/controller"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
+	"github.com/crossplane/provider-alibaba/pkg/discovery"
+	"github.com/crossplane/provider-alibaba/pkg/plan"
+	"github.com/crossplane/provider-alibaba/pkg/policy"
+	"github.com/crossplane/provider-alibaba/pkg/util"
 )
 
 func main() {
 	var (
-		app            = kingpin.New(filepath.Base(os.Args[0]), "Alibaba Cloud support for Crossplane.").DefaultEnvars()
-		debug          = app.Flag("debug", "Run with debug logging.").Short('d').Bool()
-		syncPeriod     = app.Flag("sync", "Controller manager sync period such as 300ms, 1.5h, or 2h45m").Short('s').Default("1h").Duration()
-		leaderElection = app.Flag("leader-election", "Use leader election for the conroller manager.").Short('l').Default("false").OverrideDefaultFromEnvar("LEADER_ELECTION").Bool()
+		app   = kingpin.New(filepath.Base(os.Args[0]), "Alibaba Cloud support for Crossplane.").DefaultEnvars()
+		debug = app.Flag("debug", "Run with debug logging.").Short('d').Bool()
+
+		startCmd       = app.Command("start", "Start the Alibaba Cloud controllers.").Default()
+		syncPeriod     = startCmd.Flag("sync", "Controller manager sync period such as 300ms, 1.5h, or 2h45m").Short('s').Default("1h").Duration()
+		leaderElection = startCmd.Flag("leader-election", "Use leader election for the conroller manager.").Short('l').Default("false").OverrideDefaultFromEnvar("LEADER_ELECTION").Bool()
+		pollInterval   = startCmd.Flag("poll-interval", "How often up to date managed resources are observed, such as 1m or 10m.").Default("1m").Duration()
+		terminalWait   = startCmd.Flag("terminal-error-wait", "How long managed resources that failed with an error requiring a spec change wait before they are reconciled again, unless their spec changes.").Default("30m").Duration()
+		throttledWait  = startCmd.Flag("throttled-wait", "How long managed resources whose cloud API calls were throttled wait before they are reconciled again.").Default("2m").Duration()
+		reconcileTime  = startCmd.Flag("reconcile-timeout", "How long the cloud API calls made while reconciling a managed resource may take. ProviderConfigs may set a shorter operationTimeout.").Default("1m").Duration()
+		maxReconciles  = startCmd.Flag("max-concurrent-reconciles", "Number of managed resources of each kind reconciled concurrently.").Default("1").Int()
+		kindReconciles = startCmd.Flag("max-concurrent-reconciles-per-kind", "Override max-concurrent-reconciles for a kind or service, as KIND=N, e.g. RDSInstance=5 or sls=2. May be repeated.").StringMap()
+		apiQPS         = startCmd.Flag("api-qps", "Maximum queries per second of the Kubernetes API client. The client default is used if zero.").Default("0").Float32()
+		apiBurst       = startCmd.Flag("api-burst", "Maximum burst of queries of the Kubernetes API client. The client default is used if zero.").Default("0").Int()
+		controllers    = startCmd.Flag("controllers", "Only start the controllers of this kind or service, e.g. RDSInstance or sls. May be repeated. Defaults to all.").Strings()
+		disabled       = startCmd.Flag("disable-controllers", "Do not start the controllers of this kind or service. May be repeated.").Strings()
+		enableWebhook  = startCmd.Flag("webhook", "Serve the webhook validating managed resources against ProvisioningPolicies.").Default("false").Bool()
+		webhookPort    = startCmd.Flag("webhook-port", "Port the webhook is served on.").Default("9443").Int()
+		webhookCertDir = startCmd.Flag("webhook-cert-dir", "Directory containing the tls.crt and tls.key of the webhook.").Default("/tmp/k8s-webhook-server/serving-certs").String()
+		eventsAddr     = startCmd.Flag("events-address", "Address cloud change events delivered by EventBridge are received on, such as :8090. Events are not received if empty.").String()
+		eventsToken    = startCmd.Flag("events-token", "Token EventBridge deliveries must carry as a bearer token or token query parameter. Required if events-address is set.").String()
+		auditLog       = startCmd.Flag("audit-log", "File the cloud API calls that create, update or delete cloud resources are recorded to as JSON lines, or - for stdout. Calls are not recorded to a file if empty.").String()
+		auditLogstore  = startCmd.Flag("audit-logstore", "SLS logstore the cloud API calls that create, update or delete cloud resources are shipped to, as PROJECT/LOGSTORE. Calls are not shipped if empty.").String()
+		auditConfig    = startCmd.Flag("audit-provider-config", "ProviderConfig whose credentials and region are used to ship calls to the audit logstore.").Default("default").String()
+
+		discoverCmd    = app.Command("discover", "Generate managed resource manifests for existing Alibaba Cloud resources.")
+		providerConfig = discoverCmd.Flag("provider-config", "ProviderConfig whose credentials are used and which the manifests reference.").Default("default").String()
+		region         = discoverCmd.Flag("region", "Region to discover resources in. Defaults to the region of the ProviderConfig.").String()
+		kinds          = discoverCmd.Flag("kind", "Kind of managed resources to discover, e.g. RDSInstance. May be repeated. Defaults to all kinds.").Strings()
+		namePrefix     = discoverCmd.Flag("name-prefix", "Only discover resources whose name starts with this prefix.").String()
+		tags           = discoverCmd.Flag("tag", "Only discover resources with this tag, as key=value. May be repeated.").StringMap()
+		deletionPolicy = discoverCmd.Flag("deletion-policy", "Deletion policy of the generated managed resources.").Default(string(xpv1.DeletionOrphan)).Enum(string(xpv1.DeletionOrphan), string(xpv1.DeletionDelete))
+		output         = discoverCmd.Flag("output", "File to write the manifests to. Defaults to stdout.").Short('o').String()
+
+		planCmd = app.Command("plan", "Show the cloud changes pending for managed resources in dry run mode.")
 	)
-	kingpin.MustParse(app.Parse(os.Args[1:]))
+	cmd := kingpin.MustParse(app.Parse(os.Args[1:]))
 
 	zl := zap.New(zap.UseDevMode(*debug))
 	log := logging.NewLogrLogger(zl.WithName("provider-alibaba"))
@@ -48,19 +94,129 @@ func main() {
 		ctrl.SetLogger(zl)
 	}
 
-	log.Debug("Starting", "sync-period", syncPeriod.String())
-
 	cfg, err := ctrl.GetConfig()
 	kingpin.FatalIfError(err, "Cannot get API server rest config")
 
+	switch cmd {
+	case discoverCmd.FullCommand():
+		_, kube := newClient(cfg)
+		mgs, err := discovery.Discover(context.Background(), kube, controller.Discoverable(), discovery.Options{
+			ProviderConfig: *providerConfig,
+			Region:         *region,
+			Kinds:          *kinds,
+			NamePrefix:     *namePrefix,
+			Tags:           *tags,
+			DeletionPolicy: xpv1.DeletionPolicy(*deletionPolicy),
+		})
+		kingpin.FatalIfError(err, "Cannot discover Alibaba Cloud resources")
+
+		w := os.Stdout
+		if *output != "" {
+			w, err = os.Create(*output)
+			kingpin.FatalIfError(err, "Cannot create output file")
+			defer w.Close() //nolint:errcheck
+		}
+		kingpin.FatalIfError(discovery.WriteYAML(w, mgs), "Cannot write managed resources")
+		return
+	case planCmd.FullCommand():
+		s, kube := newClient(cfg)
+		changes, err := plan.List(context.Background(), kube, s)
+		kingpin.FatalIfError(err, "Cannot list pending changes")
+		kingpin.FatalIfError(plan.Write(os.Stdout, changes), "Cannot write pending changes")
+		return
+	}
+
+	log.Debug("Starting", "sync-period", syncPeriod.String(), "poll-interval", pollInterval.String())
+
+	perKind := make(map[string]int, len(*kindReconciles))
+	for k, v := range *kindReconciles {
+		n, err := strconv.Atoi(v)
+		kingpin.FatalIfError(err, "Cannot parse max concurrent reconciles of %s", k)
+		perKind[k] = n
+	}
+
+	cfg.QPS = *apiQPS
+	cfg.Burst = *apiBurst
+
 	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
 		LeaderElection:   *leaderElection,
 		LeaderElectionID: "crossplane-leader-election-provider-alibaba",
 		SyncPeriod:       syncPeriod,
+		Port:             *webhookPort,
+		CertDir:          *webhookCertDir,
 	})
 	kingpin.FatalIfError(err, "Cannot create controller manager")
 
 	kingpin.FatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add Alibaba Cloud APIs to scheme")
-	kingpin.FatalIfError(controller.Setup(mgr, log), "Cannot setup Alibaba Cloud controllers")
+
+	var events *adapter.Receiver
+	if *eventsAddr != "" {
+		events, err = adapter.NewReceiver(mgr.GetClient(), *eventsAddr, *eventsToken, log)
+		kingpin.FatalIfError(err, "Cannot create cloud change event receiver")
+		kingpin.FatalIfError(mgr.Add(events), "Cannot add cloud change event receiver")
+	}
+	var sinks []audit.Sink
+	switch {
+	case *auditLog == "-":
+		sinks = append(sinks, audit.NewWriterSink(os.Stdout))
+	case *auditLog != "":
+		f, err := os.OpenFile(*auditLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		kingpin.FatalIfError(err, "Cannot open audit log")
+		defer f.Close() //nolint:errcheck
+		sinks = append(sinks, audit.NewWriterSink(f))
+	}
+	if *auditLogstore != "" {
+		sinks = append(sinks, newLogstoreSink(cfg, *auditLogstore, *auditConfig))
+	}
+	var auditSink audit.Sink
+	if len(sinks) > 0 {
+		auditSink = audit.NewLoggingSink(audit.NewMultiSink(sinks...), log)
+	}
+
+	kingpin.FatalIfError(controller.Setup(mgr, log, controller.Options{
+		Options: adapter.Options{
+			PollInterval:            *pollInterval,
+			MaxConcurrentReconciles: *maxReconciles,
+			TerminalErrorWait:       *terminalWait,
+			ThrottledWait:           *throttledWait,
+			ReconcileTimeout:        *reconcileTime,
+			Events:                  events,
+			Audit:                   auditSink,
+		},
+		MaxConcurrentReconcilesPerKind: perKind,
+		Enabled:                        *controllers,
+		Disabled:                       *disabled,
+	}), "Cannot setup Alibaba Cloud controllers")
+	if *enableWebhook {
+		policy.RegisterWebhook(mgr.GetWebhookServer(), mgr.GetClient())
+	}
 	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 }
+
+// newLogstoreSink returns an audit sink that ships records to the supplied
+// PROJECT/LOGSTORE using the credentials and region of the supplied
+// ProviderConfig, which are read once on startup.
+func newLogstoreSink(cfg *rest.Config, logstore, providerConfig string) audit.Sink {
+	p := strings.SplitN(logstore, "/", 2)
+	if len(p) != 2 || p[0] == "" || p[1] == "" {
+		kingpin.Fatalf("Audit logstore %q is not of the form PROJECT/LOGSTORE", logstore)
+	}
+	_, kube := newClient(cfg)
+	pc, err := util.GetProviderConfig(context.Background(), kube, providerConfig)
+	kingpin.FatalIfError(err, "Cannot get audit ProviderConfig")
+	creds, err := util.GetCredentials(context.Background(), kube, pc.Spec.Credentials.SecretRef, pc.Spec.Region)
+	kingpin.FatalIfError(err, "Cannot get audit credentials")
+	c := sls.NewClient(creds.AccessKeyID, creds.AccessKeySecret, creds.SecurityToken, creds.Region)
+	return audit.NewLogstoreSink(c.Client, p[0], p[1])
+}
+
+// newClient returns a Kubernetes client that knows the Alibaba Cloud APIs,
+// and its scheme.
+func newClient(cfg *rest.Config) (*runtime.Scheme, client.Client) {
+	s := runtime.NewScheme()
+	kingpin.FatalIfError(clientgoscheme.AddToScheme(s), "Cannot add Kubernetes APIs to scheme")
+	kingpin.FatalIfError(apis.AddToScheme(s), "Cannot add Alibaba Cloud APIs to scheme")
+	kube, err := client.New(cfg, client.Options{Scheme: s})
+	kingpin.FatalIfError(err, "Cannot create Kubernetes client")
+	return s, kube
+}
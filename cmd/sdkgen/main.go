@@ -0,0 +1,79 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/crossplane/provider-alibaba/pkg/sdkgen"
+)
+
+func main() {
+	var (
+		app        = kingpin.New(filepath.Base(os.Args[0]), "Generates managed resource types from Alibaba Cloud SDK structs.").DefaultEnvars()
+		headerFile = app.Flag("header-file", "The contents of this file will be added to the top of all generated files.").ExistingFile()
+		paths      = app.Arg("paths", "Directories containing "+sdkgen.ConfigFileName+" files. A trailing /... searches recursively.").Default("./...").Strings()
+	)
+	kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	header := ""
+	if *headerFile != "" {
+		h, err := ioutil.ReadFile(*headerFile)
+		kingpin.FatalIfError(err, "cannot read header file %s", *headerFile)
+		header = string(h)
+	}
+
+	g := sdkgen.NewGenerator(header)
+	for _, p := range *paths {
+		configs, err := find(p)
+		kingpin.FatalIfError(err, "cannot find %s files in %s", sdkgen.ConfigFileName, p)
+		for _, c := range configs {
+			kingpin.FatalIfError(g.Generate(c), "cannot generate code for %s", c)
+		}
+	}
+}
+
+// find returns the configuration files in the supplied path.
+func find(path string) ([]string, error) {
+	if !strings.HasSuffix(path, "/...") {
+		c := filepath.Join(path, sdkgen.ConfigFileName)
+		if _, err := os.Stat(c); err != nil {
+			if os.IsNotExist(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return []string{c}, nil
+	}
+
+	var configs []string
+	err := filepath.Walk(strings.TrimSuffix(path, "/..."), func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && info.Name() == sdkgen.ConfigFileName {
+			configs = append(configs, p)
+		}
+		return nil
+	})
+	return configs, err
+}
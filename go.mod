@@ -7,6 +7,7 @@ require (
 	github.com/alibabacloud-go/nas-20170626/v2 v2.0.1
 	github.com/alibabacloud-go/slb-20140515/v2 v2.0.1
 	github.com/alibabacloud-go/tea v1.1.15
+	github.com/alibabacloud-go/tea-utils v1.3.9
 	github.com/aliyun/alibaba-cloud-sdk-go v1.61.109
 	github.com/aliyun/aliyun-log-go-sdk v0.1.19
 	github.com/aliyun/aliyun-oss-go-sdk v2.1.6+incompatible
@@ -15,11 +16,14 @@ require (
 	github.com/crossplane/crossplane-tools v0.0.0-20201201125637-9ddc70edfd0d
 	github.com/google/go-cmp v0.5.2
 	github.com/pkg/errors v0.9.1
+	github.com/prometheus/client_golang v1.3.0
 	github.com/satori/go.uuid v1.2.0 // indirect
 	gopkg.in/alecthomas/kingpin.v2 v2.2.6
 	k8s.io/api v0.18.6
 	k8s.io/apimachinery v0.18.6
+	k8s.io/client-go v0.18.6
 	k8s.io/utils v0.0.0-20200603063816-c1c6865ac451
 	sigs.k8s.io/controller-runtime v0.6.2
 	sigs.k8s.io/controller-tools v0.3.0
+	sigs.k8s.io/yaml v1.2.0
 )
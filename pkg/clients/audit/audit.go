@@ -0,0 +1,239 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit records the cloud API calls that create, update or delete
+// cloud resources, so that every change made by the provider can be traced
+// back to the managed resource that requested it.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+)
+
+// Results of audited calls.
+const (
+	ResultSuccess = "Success"
+	ResultFailure = "Failure"
+)
+
+// redacted replaces the values of sensitive request parameters.
+const redacted = "REDACTED"
+
+// omittedParameters are the common parameters of Alibaba Cloud API requests,
+// which carry no information about the change but the credentials used.
+var omittedParameters = map[string]bool{
+	"AccessKeyId":      true,
+	"Action":           true,
+	"Format":           true,
+	"SecurityToken":    true,
+	"Signature":        true,
+	"SignatureMethod":  true,
+	"SignatureNonce":   true,
+	"SignatureType":    true,
+	"SignatureVersion": true,
+	"Timestamp":        true,
+	"Version":          true,
+}
+
+// sensitiveParameters are the substrings of the lower case names of request
+// parameters whose values are redacted, e.g. AccountPassword.
+var sensitiveParameters = []string{"password", "secret", "accesskey", "securitytoken"}
+
+// A Resource identifies the managed resource a call was made for.
+type Resource struct {
+	APIVersion     string `json:"apiVersion"`
+	Kind           string `json:"kind"`
+	Name           string `json:"name"`
+	ProviderConfig string `json:"providerConfig,omitempty"`
+}
+
+// A Record of a cloud API call that created, updated or deleted a cloud
+// resource.
+type Record struct {
+	Time       time.Time              `json:"time"`
+	Resource   Resource               `json:"resource"`
+	Service    string                 `json:"service"`
+	Action     string                 `json:"action"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	RequestID  string                 `json:"requestId,omitempty"`
+	Result     string                 `json:"result"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+// A Sink writes audit records.
+type Sink interface {
+	Write(r Record) error
+}
+
+type auditKey struct{}
+
+type auditor struct {
+	sink     Sink
+	resource Resource
+}
+
+// WithResource returns a context that records the calls made with it for the
+// supplied managed resource to the supplied sink.
+func WithResource(ctx context.Context, s Sink, r Resource) context.Context {
+	return context.WithValue(ctx, auditKey{}, auditor{sink: s, resource: r})
+}
+
+// Call records a call of the supplied action of the supplied service, e.g.
+// CreateFileSystem of nas, made with the supplied context. The parameters of
+// the call are converted to JSON, so they may be a request of a Tea based
+// SDK or a map. The request ID is read from the error if empty. Calls made
+// with contexts that carry no sink are not recorded.
+func Call(ctx context.Context, service, action string, parameters interface{}, requestID string, err error) {
+	a, ok := ctx.Value(auditKey{}).(auditor)
+	if !ok {
+		return
+	}
+	r := Record{
+		Time:       time.Now().UTC(),
+		Resource:   a.resource,
+		Service:    service,
+		Action:     action,
+		Parameters: sanitize(parameters),
+		RequestID:  requestID,
+		Result:     ResultSuccess,
+	}
+	if err != nil {
+		r.Result = ResultFailure
+		r.Error = err.Error()
+		if r.RequestID == "" {
+			r.RequestID = clients.ErrorRequestID(err)
+		}
+	}
+	_ = a.sink.Write(r)
+}
+
+// Request records a call made with the supplied request of the Alibaba Cloud
+// SDK. It must be called after the request was sent, once the SDK has filled
+// in its parameters.
+func Request(ctx context.Context, service string, req requests.AcsRequest, requestID string, err error) {
+	parameters := map[string]string{}
+	for k, v := range req.GetQueryParams() {
+		parameters[k] = v
+	}
+	for k, v := range req.GetFormParams() {
+		parameters[k] = v
+	}
+	Call(ctx, service, req.GetActionName(), parameters, requestID, err)
+}
+
+// sanitize returns the supplied parameters as a map without the common
+// parameters of API requests and with the values of sensitive parameters
+// redacted.
+func sanitize(parameters interface{}) map[string]interface{} {
+	if parameters == nil {
+		return nil
+	}
+	b, err := json.Marshal(parameters)
+	if err != nil {
+		return nil
+	}
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil
+	}
+	for k, v := range m {
+		switch {
+		case omittedParameters[k], v == nil:
+			delete(m, k)
+		case sensitive(k):
+			m[k] = redacted
+		}
+	}
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+func sensitive(name string) bool {
+	name = strings.ToLower(name)
+	for _, s := range sensitiveParameters {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewWriterSink returns a Sink that writes records to the supplied writer, e.g.
+// a file or stdout, as JSON lines.
+func NewWriterSink(w io.Writer) Sink {
+	return &writerSink{enc: json.NewEncoder(w)}
+}
+
+type writerSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func (s *writerSink) Write(r Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(r)
+}
+
+// NewMultiSink returns a Sink that writes records to all of the supplied
+// sinks. It returns the first error returned by any of them.
+func NewMultiSink(sinks ...Sink) Sink {
+	return multiSink(sinks)
+}
+
+type multiSink []Sink
+
+func (s multiSink) Write(r Record) error {
+	var first error
+	for _, sink := range s {
+		if err := sink.Write(r); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// NewLoggingSink returns a Sink that writes records to the supplied sink and
+// logs the records it cannot write, so that they are not lost silently.
+func NewLoggingSink(s Sink, l logging.Logger) Sink {
+	return &loggingSink{sink: s, log: l}
+}
+
+type loggingSink struct {
+	sink Sink
+	log  logging.Logger
+}
+
+func (s *loggingSink) Write(r Record) error {
+	err := s.sink.Write(r)
+	if err != nil {
+		s.log.Info("Cannot write audit record", "error", err,
+			"kind", r.Resource.Kind, "name", r.Resource.Name, "action", r.Action, "requestId", r.RequestID, "result", r.Result)
+	}
+	return err
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alibabacloud-go/tea/tea"
+	sdkerrors "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	alirds "github.com/aliyun/alibaba-cloud-sdk-go/services/rds"
+	sdk "github.com/aliyun/aliyun-log-go-sdk"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+)
+
+type fakeSink struct {
+	records []Record
+}
+
+func (s *fakeSink) Write(r Record) error {
+	s.records = append(s.records, r)
+	return nil
+}
+
+var bucket = Resource{APIVersion: "oss.alibaba.crossplane.io/v1alpha1", Kind: "Bucket", Name: "cool", ProviderConfig: "default"}
+
+func TestCall(t *testing.T) {
+	errBoom := sdkerrors.NewServerError(http.StatusBadRequest, `{"Code": "InvalidAccountPassword.Malformed", "RequestId": "rds-2"}`, "")
+
+	type args struct {
+		action     string
+		parameters interface{}
+		requestID  string
+		err        error
+	}
+
+	cases := map[string]struct {
+		reason string
+		audit  bool
+		args   args
+		want   []Record
+	}{
+		"NotAudited": {
+			reason: "Calls made with contexts that carry no sink should not be recorded",
+			args:   args{action: "DeleteBucket"},
+		},
+		"Success": {
+			reason: "Successful calls should be recorded with their sanitized parameters and request ID",
+			audit:  true,
+			args: args{
+				action:     "CreateAccount",
+				parameters: map[string]interface{}{"AccountName": "cool", "AccountPassword": "hunter2", "AccessKeyId": "ak", "Signature": "sig", "Port": 3306},
+				requestID:  "rds-1",
+			},
+			want: []Record{{
+				Resource:   bucket,
+				Service:    "rds",
+				Action:     "CreateAccount",
+				Parameters: map[string]interface{}{"AccountName": "cool", "AccountPassword": redacted, "Port": float64(3306)},
+				RequestID:  "rds-1",
+				Result:     ResultSuccess,
+			}},
+		},
+		"Failure": {
+			reason: "Failed calls should be recorded with their error and the request ID of the error",
+			audit:  true,
+			args: args{
+				action: "CreateAccount",
+				err:    errors.Wrap(errBoom, "cannot create account"),
+			},
+			want: []Record{{
+				Resource:  bucket,
+				Service:   "rds",
+				Action:    "CreateAccount",
+				RequestID: "rds-2",
+				Result:    ResultFailure,
+				Error:     errors.Wrap(errBoom, "cannot create account").Error(),
+			}},
+		},
+		"TeaRequest": {
+			reason: "Requests of the Tea based SDKs should be recorded by their JSON field names without unset fields",
+			audit:  true,
+			args: args{
+				action:     "CreateFileSystem",
+				parameters: &struct{ StorageType, VpcId *string }{StorageType: tea.String("Performance")},
+			},
+			want: []Record{{
+				Resource:   bucket,
+				Service:    "rds",
+				Action:     "CreateFileSystem",
+				Parameters: map[string]interface{}{"StorageType": "Performance"},
+				Result:     ResultSuccess,
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &fakeSink{}
+			ctx := context.Background()
+			if tc.audit {
+				ctx = WithResource(ctx, s, bucket)
+			}
+			Call(ctx, "rds", tc.args.action, tc.args.parameters, tc.args.requestID, tc.args.err)
+			if diff := cmp.Diff(tc.want, s.records, cmpopts.IgnoreFields(Record{}, "Time")); diff != "" {
+				t.Errorf("\n%s\nCall(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRequest(t *testing.T) {
+	r := alirds.CreateDeleteDBInstanceRequest()
+	r.QueryParams["DBInstanceId"] = "rm-cool"
+	r.QueryParams["SecurityToken"] = "sts"
+	r.FormParams["ClientToken"] = "cool"
+
+	s := &fakeSink{}
+	Request(WithResource(context.Background(), s, bucket), "rds", r, "rds-1", nil)
+
+	want := []Record{{
+		Resource:   bucket,
+		Service:    "rds",
+		Action:     "DeleteDBInstance",
+		Parameters: map[string]interface{}{"DBInstanceId": "rm-cool", "ClientToken": "cool"},
+		RequestID:  "rds-1",
+		Result:     ResultSuccess,
+	}}
+	if diff := cmp.Diff(want, s.records, cmpopts.IgnoreFields(Record{}, "Time")); diff != "" {
+		t.Errorf("Request(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestWriterSink(t *testing.T) {
+	b := &bytes.Buffer{}
+	s := NewWriterSink(b)
+	r := Record{
+		Time:      time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC),
+		Resource:  bucket,
+		Service:   "oss",
+		Action:    "DeleteBucket",
+		RequestID: "oss-1",
+		Result:    ResultSuccess,
+	}
+	for i := 0; i < 2; i++ {
+		if err := s.Write(r); err != nil {
+			t.Fatalf("s.Write(...): %s", err)
+		}
+	}
+
+	line := `{"time":"2021-06-01T00:00:00Z","resource":{"apiVersion":"oss.alibaba.crossplane.io/v1alpha1","kind":"Bucket","name":"cool","providerConfig":"default"},"service":"oss","action":"DeleteBucket","requestId":"oss-1","result":"Success"}` + "\n"
+	if diff := cmp.Diff(line+line, b.String()); diff != "" {
+		t.Errorf("s.Write(...): -want, +got:\n%s", diff)
+	}
+}
+
+type fakeLogClient struct {
+	sdk.ClientInterface
+
+	project  string
+	logstore string
+	group    *sdk.LogGroup
+}
+
+func (c *fakeLogClient) PutLogs(project, logstore string, lg *sdk.LogGroup) error {
+	c.project, c.logstore, c.group = project, logstore, lg
+	return nil
+}
+
+func TestLogstoreSink(t *testing.T) {
+	c := &fakeLogClient{}
+	s := NewLogstoreSink(c, "audit", "calls")
+	err := s.Write(Record{
+		Time:       time.Unix(1622505600, 0),
+		Resource:   bucket,
+		Service:    "oss",
+		Action:     "PutBucketAcl",
+		Parameters: map[string]interface{}{"ACL": "private"},
+		Result:     ResultSuccess,
+	})
+	if err != nil {
+		t.Fatalf("s.Write(...): %s", err)
+	}
+
+	ts := uint32(1622505600)
+	want := &sdk.LogGroup{
+		Topic: tea.String(topic),
+		Logs: []*sdk.Log{{
+			Time: &ts,
+			Contents: []*sdk.LogContent{
+				{Key: tea.String("apiVersion"), Value: tea.String("oss.alibaba.crossplane.io/v1alpha1")},
+				{Key: tea.String("kind"), Value: tea.String("Bucket")},
+				{Key: tea.String("name"), Value: tea.String("cool")},
+				{Key: tea.String("providerConfig"), Value: tea.String("default")},
+				{Key: tea.String("service"), Value: tea.String("oss")},
+				{Key: tea.String("action"), Value: tea.String("PutBucketAcl")},
+				{Key: tea.String("parameters"), Value: tea.String(`{"ACL":"private"}`)},
+				{Key: tea.String("result"), Value: tea.String(ResultSuccess)},
+			},
+		}},
+	}
+	if diff := cmp.Diff([]string{"audit", "calls"}, []string{c.project, c.logstore}); diff != "" {
+		t.Errorf("s.Write(...): -want logstore, +got logstore:\n%s", diff)
+	}
+	if diff := cmp.Diff(want, c.group); diff != "" {
+		t.Errorf("s.Write(...): -want, +got:\n%s", diff)
+	}
+}
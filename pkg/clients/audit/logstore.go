@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+
+	"github.com/alibabacloud-go/tea/tea"
+	sdk "github.com/aliyun/aliyun-log-go-sdk"
+	"github.com/pkg/errors"
+)
+
+const (
+	errPutLogs = "cannot put audit record to logstore"
+
+	// topic of the log groups audit records are shipped in.
+	topic = "crossplane-audit"
+)
+
+// NewLogstoreSink returns a Sink that ships records to the supplied logstore
+// of the supplied SLS project. Each record is a log whose contents are the
+// fields of the record; structured fields are JSON encoded.
+func NewLogstoreSink(c sdk.ClientInterface, project, logstore string) Sink {
+	return &logstoreSink{client: c, project: project, logstore: logstore}
+}
+
+type logstoreSink struct {
+	client   sdk.ClientInterface
+	project  string
+	logstore string
+}
+
+func (s *logstoreSink) Write(r Record) error {
+	lg := &sdk.LogGroup{
+		Topic: tea.String(topic),
+		Logs:  []*sdk.Log{logOf(r)},
+	}
+	return errors.Wrap(s.client.PutLogs(s.project, s.logstore, lg), errPutLogs)
+}
+
+// logOf returns the SLS log of the supplied record.
+func logOf(r Record) *sdk.Log {
+	t := uint32(r.Time.Unix())
+	contents := map[string]string{
+		"apiVersion":     r.Resource.APIVersion,
+		"kind":           r.Resource.Kind,
+		"name":           r.Resource.Name,
+		"providerConfig": r.Resource.ProviderConfig,
+		"service":        r.Service,
+		"action":         r.Action,
+		"requestId":      r.RequestID,
+		"result":         r.Result,
+		"error":          r.Error,
+	}
+	if len(r.Parameters) > 0 {
+		b, _ := json.Marshal(r.Parameters)
+		contents["parameters"] = string(b)
+	}
+
+	l := &sdk.Log{Time: &t}
+	for _, k := range []string{"apiVersion", "kind", "name", "providerConfig", "service", "action", "parameters", "requestId", "result", "error"} {
+		if contents[k] == "" {
+			continue
+		}
+		l.Contents = append(l.Contents, &sdk.LogContent{Key: tea.String(k), Value: tea.String(contents[k])})
+	}
+	return l
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	errFmtNotAvailableInRegion  = "%s %q is not available in any zone of %s"
+	errFmtNotAvailableInZone    = "%s %q is not available in %s; available zones: %s"
+	errFmtNotAvailableInAnyZone = "%s %q is not available in %s or any other zone of %s"
+)
+
+// An Availability maps the IDs of the zones of a region to the offerings
+// available in them, e.g. instance classes. Zones that are sold out have no
+// offerings.
+type Availability map[string][]string
+
+// Zones returns the sorted IDs of the zones the supplied offering is available
+// in.
+func (a Availability) Zones(offering string) []string {
+	var zones []string
+	for zone, offerings := range a {
+		for _, o := range offerings {
+			if o == offering {
+				zones = append(zones, zone)
+				break
+			}
+		}
+	}
+	sort.Strings(zones)
+	return zones
+}
+
+// Check returns a terminal error if the supplied offering is not available in
+// the supplied zone of the supplied region, or in any zone of the region if
+// the zone is empty. The error describes the offering as the supplied kind of
+// offering, e.g. "RDS MySQL 8.0 instance class", and lists the zones it is
+// available in instead.
+func (a Availability) Check(kind, offering, region, zone string) error {
+	if offering == "" {
+		return nil
+	}
+	zones := a.Zones(offering)
+	switch {
+	case zone == "" && len(zones) == 0:
+		return NewTerminalErrorf(errFmtNotAvailableInRegion, kind, offering, region)
+	case zone == "":
+		return nil
+	case len(zones) == 0:
+		return NewTerminalErrorf(errFmtNotAvailableInAnyZone, kind, offering, zone, region)
+	}
+	for _, z := range zones {
+		if z == zone {
+			return nil
+		}
+	}
+	return NewTerminalErrorf(errFmtNotAvailableInZone, kind, offering, zone, strings.Join(zones, ", "))
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAvailabilityCheck(t *testing.T) {
+	a := Availability{
+		"cn-hangzhou-h": {"small"},
+		"cn-hangzhou-i": {"small", "large"},
+		"cn-hangzhou-j": nil,
+	}
+
+	type args struct {
+		offering string
+		zone     string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   string
+	}{
+		"AvailableInRegion": {
+			reason: "Offerings available in some zone should pass if no zone is requested",
+			args:   args{offering: "large"},
+		},
+		"AvailableInZone": {
+			reason: "Offerings available in the requested zone should pass",
+			args:   args{offering: "small", zone: "cn-hangzhou-h"},
+		},
+		"NotAvailableInZone": {
+			reason: "Offerings not available in the requested zone should list the zones they are available in",
+			args:   args{offering: "large", zone: "cn-hangzhou-j"},
+			want:   `class "large" is not available in cn-hangzhou-j; available zones: cn-hangzhou-i`,
+		},
+		"NotAvailableInAnyZone": {
+			reason: "Offerings not available in any zone should fail even if a zone is requested",
+			args:   args{offering: "huge", zone: "cn-hangzhou-h"},
+			want:   `class "huge" is not available in cn-hangzhou-h or any other zone of cn-hangzhou`,
+		},
+		"NotAvailableInRegion": {
+			reason: "Offerings not available in any zone should fail",
+			args:   args{offering: "huge"},
+			want:   `class "huge" is not available in any zone of cn-hangzhou`,
+		},
+		"NoOffering": {
+			reason: "Unset offerings should be left to the cloud API to validate",
+			args:   args{zone: "cn-hangzhou-h"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := a.Check("class", tc.args.offering, "cn-hangzhou", tc.args.zone)
+			got := ""
+			if err != nil {
+				got = err.Error()
+				if !IsTerminalError(err) {
+					t.Errorf("\n%s\na.Check(...): want terminal error, got %v\n", tc.reason, err)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\na.Check(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
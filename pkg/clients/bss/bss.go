@@ -0,0 +1,188 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bss estimates the cost of cloud resources using the pricing APIs of
+// the Alibaba Cloud Business Support System.
+package bss
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	alibss "github.com/aliyun/alibaba-cloud-sdk-go/services/bssopenapi"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+)
+
+const (
+	httpsScheme = "https"
+
+	// PeriodHour is the period pay-as-you-go prices are charged for.
+	PeriodHour = "Hour"
+	// PeriodMonth is the period subscription prices are charged for.
+	PeriodMonth = "Month"
+
+	subscriptionTypePayAsYouGo   = "PayAsYouGo"
+	subscriptionTypeSubscription = "Subscription"
+	orderTypeNew                 = "NewOrder"
+
+	errFmtPriceFailed = "%s: %s"
+)
+
+// Client defines BSS pricing operations.
+type Client interface {
+	GetPrice(ctx context.Context, r PriceRequest) (*Price, error)
+}
+
+// A Module is a billable component of a product, e.g. the instance class or
+// the storage of an RDS instance.
+type Module struct {
+	// Code of the module, e.g. DBInstanceClass.
+	Code string
+
+	// Config of the module as comma separated Key:Value pairs, e.g.
+	// DBInstanceClass:rds.mysql.s1.small.
+	Config string
+}
+
+// A PriceRequest describes the cloud resource whose price is requested.
+type PriceRequest struct {
+	// ProductCode of the product, e.g. rds.
+	ProductCode string
+
+	// ProductType of the product. Optional.
+	ProductType string
+
+	// Region of the cloud resource.
+	Region string
+
+	// Subscription is true for subscription (PrePaid) resources, whose
+	// monthly price is requested. The hourly price of pay-as-you-go
+	// (PostPaid) resources is requested otherwise.
+	Subscription bool
+
+	// Modules of the cloud resource.
+	Modules []Module
+}
+
+// A Price is the price of a cloud resource for one period.
+type Price struct {
+	Amount   float64
+	Currency string
+
+	// Period is PeriodHour or PeriodMonth.
+	Period string
+}
+
+// Config returns the Config of a Module from the supplied Key:Value pairs, in
+// the supplied order.
+func Config(kv ...string) string {
+	pairs := make([]string, 0, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		pairs = append(pairs, kv[i]+":"+kv[i+1])
+	}
+	return strings.Join(pairs, ",")
+}
+
+type client struct {
+	bssCli *alibss.Client
+}
+
+// NewClient creates new BSS client
+func NewClient(ctx context.Context, accessKeyID, accessKeySecret, securityToken, region string) (Client, error) {
+	var (
+		bssCli *alibss.Client
+		err    error
+	)
+	if securityToken != "" {
+		bssCli, err = alibss.NewClientWithStsToken(region, accessKeyID, accessKeySecret, securityToken)
+	} else {
+		bssCli, err = alibss.NewClientWithAccessKey(region, accessKeyID, accessKeySecret)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &client{bssCli: bssCli}, nil
+}
+
+func (c *client) GetPrice(ctx context.Context, r PriceRequest) (*Price, error) {
+	if r.Subscription {
+		return c.getSubscriptionPrice(ctx, r)
+	}
+	return c.getPayAsYouGoPrice(ctx, r)
+}
+
+func (c *client) getPayAsYouGoPrice(ctx context.Context, r PriceRequest) (*Price, error) {
+	request := alibss.CreateGetPayAsYouGoPriceRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return nil, err
+	}
+	request.ProductCode = r.ProductCode
+	request.ProductType = r.ProductType
+	request.Region = r.Region
+	request.SubscriptionType = subscriptionTypePayAsYouGo
+	modules := make([]alibss.GetPayAsYouGoPriceModuleList, len(r.Modules))
+	for i, m := range r.Modules {
+		modules[i] = alibss.GetPayAsYouGoPriceModuleList{ModuleCode: m.Code, Config: m.Config, PriceType: PeriodHour}
+	}
+	request.ModuleList = &modules
+
+	response, err := c.bssCli.GetPayAsYouGoPrice(request)
+	if err != nil {
+		return nil, err
+	}
+	if !response.Success {
+		return nil, errors.Errorf(errFmtPriceFailed, response.Code, response.Message)
+	}
+	p := &Price{Currency: response.Data.Currency, Period: PeriodHour}
+	for _, m := range response.Data.ModuleDetails.ModuleDetail {
+		p.Amount += m.CostAfterDiscount
+	}
+	return p, nil
+}
+
+func (c *client) getSubscriptionPrice(ctx context.Context, r PriceRequest) (*Price, error) {
+	request := alibss.CreateGetSubscriptionPriceRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return nil, err
+	}
+	request.ProductCode = r.ProductCode
+	request.ProductType = r.ProductType
+	request.Region = r.Region
+	request.SubscriptionType = subscriptionTypeSubscription
+	request.OrderType = orderTypeNew
+	request.Quantity = requests.NewInteger(1)
+	request.ServicePeriodQuantity = requests.NewInteger(1)
+	request.ServicePeriodUnit = PeriodMonth
+	modules := make([]alibss.GetSubscriptionPriceModuleList, len(r.Modules))
+	for i, m := range r.Modules {
+		modules[i] = alibss.GetSubscriptionPriceModuleList{ModuleCode: m.Code, Config: m.Config}
+	}
+	request.ModuleList = &modules
+
+	response, err := c.bssCli.GetSubscriptionPrice(request)
+	if err != nil {
+		return nil, err
+	}
+	if !response.Success {
+		return nil, errors.Errorf(errFmtPriceFailed, response.Code, response.Message)
+	}
+	return &Price{Amount: response.Data.TradePrice, Currency: response.Data.Currency, Period: PeriodMonth}, nil
+}
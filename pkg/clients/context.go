@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"time"
+
+	util "github.com/alibabacloud-go/tea-utils/service"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+)
+
+// DefaultTimeout is the timeout of cloud API requests made with a context that
+// has no deadline.
+const DefaultTimeout = 60 * time.Second
+
+// Timeout returns the time remaining until the deadline of the supplied
+// context, or DefaultTimeout if it has none. It returns the error of the
+// context if it is already done.
+func Timeout(ctx context.Context) (time.Duration, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	d, ok := ctx.Deadline()
+	if !ok {
+		return DefaultTimeout, nil
+	}
+	t := time.Until(d)
+	if t <= 0 {
+		return 0, context.DeadlineExceeded
+	}
+	return t, nil
+}
+
+// WithTimeout sets the connect and read timeouts of the supplied request of
+// the Alibaba Cloud SDK to the time remaining until the deadline of the
+// supplied context. It returns the error of the context if it is already
+// done.
+func WithTimeout(ctx context.Context, r requests.AcsRequest) error {
+	t, err := Timeout(ctx)
+	if err != nil {
+		return err
+	}
+	r.SetConnectTimeout(t)
+	r.SetReadTimeout(t)
+	return nil
+}
+
+// RuntimeOptions returns the runtime options of a request of the Tea based
+// Alibaba Cloud SDKs, whose connect and read timeouts are the time remaining
+// until the deadline of the supplied context. It returns the error of the
+// context if it is already done.
+func RuntimeOptions(ctx context.Context) (*util.RuntimeOptions, error) {
+	t, err := Timeout(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ms := int(t / time.Millisecond)
+	return &util.RuntimeOptions{ConnectTimeout: &ms, ReadTimeout: &ms}, nil
+}
+
+// Do calls the supplied function, which makes cloud API requests using an SDK
+// that does not support contexts, and returns its error. It returns the error
+// of the supplied context instead if the context is done first. The function
+// keeps running until the requests it makes time out.
+func Do(ctx context.Context, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	errc := make(chan error, 1)
+	go func() { errc <- fn() }()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
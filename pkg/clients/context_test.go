@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/rds"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWithTimeout(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	bounded, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	type want struct {
+		min time.Duration
+		max time.Duration
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		ctx    context.Context
+		want   want
+	}{
+		"NoDeadline": {
+			reason: "Requests made with a context without deadline should use the default timeout",
+			ctx:    context.Background(),
+			want:   want{min: DefaultTimeout, max: DefaultTimeout},
+		},
+		"Deadline": {
+			reason: "Requests should time out at the deadline of their context",
+			ctx:    bounded,
+			want:   want{min: time.Hour - time.Minute, max: time.Hour},
+		},
+		"Cancelled": {
+			reason: "Requests made with a cancelled context should not be made",
+			ctx:    cancelled,
+			want:   want{err: context.Canceled},
+		},
+		"Expired": {
+			reason: "Requests made with a context whose deadline passed should not be made",
+			ctx:    expired,
+			want:   want{err: context.DeadlineExceeded},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := rds.CreateDescribeDBInstancesRequest()
+			err := WithTimeout(tc.ctx, r)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nWithTimeout(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if got := r.GetReadTimeout(); got < tc.want.min || got > tc.want.max {
+				t.Errorf("\n%s\nWithTimeout(...): want read timeout within [%s, %s], got %s\n", tc.reason, tc.want.min, tc.want.max, got)
+			}
+			if r.GetConnectTimeout() != r.GetReadTimeout() {
+				t.Errorf("\n%s\nWithTimeout(...): want connect timeout %s, got %s\n", tc.reason, r.GetReadTimeout(), r.GetConnectTimeout())
+			}
+		})
+	}
+}
+
+func TestDo(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	block := make(chan struct{})
+	defer close(block)
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- Do(ctx, func() error {
+			<-block
+			return nil
+		})
+	}()
+	cancel()
+
+	if diff := cmp.Diff(context.Canceled, <-errc, test.EquateErrors()); diff != "" {
+		t.Errorf("Do(...): -want error, +got error:\n%s", diff)
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/alibabacloud-go/tea/tea"
+	sdkerrors "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	sls "github.com/aliyun/aliyun-log-go-sdk"
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/pkg/errors"
+)
+
+// An ErrorClass determines how an error returned by a cloud client is
+// retried.
+type ErrorClass string
+
+// Error classes.
+const (
+	// ErrorRetryable errors may succeed if retried, e.g. network errors.
+	ErrorRetryable ErrorClass = "Retryable"
+
+	// ErrorThrottled errors were caused by exceeding an API rate limit, and
+	// should be retried after backing off.
+	ErrorThrottled ErrorClass = "Throttled"
+
+	// ErrorTerminal errors will not succeed until the spec of the managed
+	// resource changes, e.g. invalid parameters.
+	ErrorTerminal ErrorClass = "Terminal"
+)
+
+// terminalCodePrefixes are the prefixes of the error codes of Alibaba Cloud
+// APIs that reject the parameters of a request.
+var terminalCodePrefixes = []string{"Invalid", "Missing", "Malformed", "Unsupported", "IncorrectDBInstanceType"}
+
+// retryableCodePrefixes are the prefixes of the error codes that match
+// terminalCodePrefixes but are not caused by the spec of a managed resource,
+// e.g. expired credentials that are rotated outside of it.
+var retryableCodePrefixes = []string{"InvalidAccessKeyId", "InvalidSecurityToken"}
+
+// notFoundIDCodeSuffix is the suffix of the error codes of Alibaba Cloud APIs
+// that cannot find the resource with the supplied ID, which may not exist yet
+// or have been deleted since it was observed.
+const notFoundIDCodeSuffix = "Id.NotFound"
+
+// throttledCodePrefixes are the prefixes of the error codes of Alibaba Cloud
+// APIs that reject requests exceeding a rate limit.
+var throttledCodePrefixes = []string{"Throttling", "ServiceUnavailable.Throttling", "QpsLimitExceeded", "RequestTooFrequent"}
+
+type terminal struct {
+	error
+}
+
+func (t terminal) Cause() error {
+	return t.error
+}
+
+// NewTerminalError returns an error with the supplied message that will not
+// succeed until the spec of the managed resource changes.
+func NewTerminalError(message string) error {
+	return terminal{errors.New(message)}
+}
+
+// NewTerminalErrorf returns an error formatted according to the supplied
+// format specifier that will not succeed until the spec of the managed
+// resource changes.
+func NewTerminalErrorf(format string, args ...interface{}) error {
+	return terminal{errors.Errorf(format, args...)}
+}
+
+// Terminal marks the supplied error as one that will not succeed until the
+// spec of the managed resource changes. It returns nil if err is nil.
+func Terminal(err error) error {
+	if err == nil {
+		return nil
+	}
+	return terminal{err}
+}
+
+// ClassifyError returns the class of the supplied error, which may have been
+// wrapped. Errors are retryable unless they are known to be terminal or
+// throttled.
+func ClassifyError(err error) ErrorClass {
+	for e := err; e != nil; {
+		if _, ok := e.(terminal); ok {
+			return ErrorTerminal
+		}
+		c, ok := e.(interface{ Cause() error })
+		if !ok {
+			break
+		}
+		e = c.Cause()
+	}
+
+	code, status := errorCode(errors.Cause(err))
+	switch {
+	case status == http.StatusTooManyRequests || hasPrefix(code, throttledCodePrefixes):
+		return ErrorThrottled
+	case hasPrefix(code, retryableCodePrefixes) || strings.HasSuffix(code, notFoundIDCodeSuffix):
+		return ErrorRetryable
+	case hasPrefix(code, terminalCodePrefixes):
+		return ErrorTerminal
+	}
+	return ErrorRetryable
+}
+
+// IsTerminalError returns true if the supplied error will not succeed until
+// the spec of the managed resource changes.
+func IsTerminalError(err error) bool {
+	return err != nil && ClassifyError(err) == ErrorTerminal
+}
+
+// IsThrottledError returns true if the supplied error was caused by exceeding
+// an API rate limit.
+func IsThrottledError(err error) bool {
+	return err != nil && ClassifyError(err) == ErrorThrottled
+}
+
+// errorCode returns the error code and HTTP status of the errors returned by
+// the Alibaba Cloud SDKs. The status is zero if unknown.
+func errorCode(err error) (string, int) {
+	switch e := err.(type) {
+	case *sdkerrors.ServerError:
+		return e.ErrorCode(), e.HttpStatus()
+	case *tea.SDKError:
+		return tea.StringValue(e.Code), 0
+	case oss.ServiceError:
+		return e.Code, e.StatusCode
+	case *oss.ServiceError:
+		return e.Code, e.StatusCode
+	case *sls.Error:
+		return e.Code, int(e.HTTPCode)
+	}
+	return "", 0
+}
+
+// ErrorRequestID returns the ID of the cloud API request that returned the
+// supplied error, which may have been wrapped, or an empty string if it is
+// not an error returned by an Alibaba Cloud SDK.
+func ErrorRequestID(err error) string {
+	switch e := errors.Cause(err).(type) {
+	case *sdkerrors.ServerError:
+		return e.RequestId()
+	case *tea.SDKError:
+		// The Tea based SDKs return the response body as the data of
+		// their errors.
+		data := struct {
+			RequestID string `json:"RequestId"`
+		}{}
+		_ = json.Unmarshal([]byte(tea.StringValue(e.Data)), &data)
+		return data.RequestID
+	case oss.ServiceError:
+		return e.RequestID
+	case *oss.ServiceError:
+		return e.RequestID
+	case *sls.Error:
+		return e.RequestID
+	}
+	return ""
+}
+
+func hasPrefix(code string, prefixes []string) bool {
+	if code == "" {
+		return false
+	}
+	for _, p := range prefixes {
+		if strings.HasPrefix(code, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/alibabacloud-go/tea/tea"
+	sdkerrors "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	sls "github.com/aliyun/aliyun-log-go-sdk"
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestClassifyError(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   ErrorClass
+	}{
+		"Unknown": {
+			reason: "Errors of unknown origin are retryable",
+			err:    errors.New("connection reset by peer"),
+			want:   ErrorRetryable,
+		},
+		"MarkedTerminal": {
+			reason: "Errors marked as terminal are terminal, even when wrapped",
+			err:    errors.Wrap(NewTerminalErrorf("bucket ACL %s is invalid", "public"), "cannot create bucket"),
+			want:   ErrorTerminal,
+		},
+		"InvalidParameter": {
+			reason: "Server errors rejecting the parameters of a request are terminal",
+			err:    errors.Wrap(sdkerrors.NewServerError(http.StatusBadRequest, `{"Code": "InvalidDBInstanceClass.NotFound"}`, ""), "cannot create RDS instance"),
+			want:   ErrorTerminal,
+		},
+		"InstanceNotFound": {
+			reason: "Server errors of resources that cannot be found by ID are retryable, since they may not exist yet",
+			err:    sdkerrors.NewServerError(http.StatusNotFound, `{"Code": "InvalidDBInstanceId.NotFound"}`, ""),
+			want:   ErrorRetryable,
+		},
+		"InvalidAccessKeyId": {
+			reason: "Server errors rejecting credentials are retryable, since credentials are not part of the spec",
+			err:    sdkerrors.NewServerError(http.StatusNotFound, `{"Code": "InvalidAccessKeyId.NotFound"}`, ""),
+			want:   ErrorRetryable,
+		},
+		"ExpiredSecurityToken": {
+			reason: "Server errors rejecting expired security tokens are retryable",
+			err:    &tea.SDKError{Code: tea.String("InvalidSecurityToken.Expired")},
+			want:   ErrorRetryable,
+		},
+		"Throttling": {
+			reason: "Server errors rejecting requests exceeding a rate limit are throttled",
+			err:    sdkerrors.NewServerError(http.StatusBadRequest, `{"Code": "Throttling.User"}`, ""),
+			want:   ErrorThrottled,
+		},
+		"TooManyRequests": {
+			reason: "Errors with HTTP status 429 are throttled",
+			err:    &sls.Error{HTTPCode: http.StatusTooManyRequests, Code: "ExceedQuota"},
+			want:   ErrorThrottled,
+		},
+		"InternalError": {
+			reason: "Server errors of the cloud API are retryable",
+			err:    oss.ServiceError{Code: "InternalError", StatusCode: http.StatusInternalServerError},
+			want:   ErrorRetryable,
+		},
+		"TeaInvalidParameter": {
+			reason: "Errors of the tea SDKs rejecting the parameters of a request are terminal",
+			err:    &tea.SDKError{Code: tea.String("InvalidParameter")},
+			want:   ErrorTerminal,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ClassifyError(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nClassifyError(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestErrorRequestID(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   string
+	}{
+		"Unknown": {
+			reason: "Errors of unknown origin have no request ID",
+			err:    errors.New("connection reset by peer"),
+		},
+		"ServerError": {
+			reason: "The request ID of server errors should be returned, even when wrapped",
+			err:    errors.Wrap(sdkerrors.NewServerError(http.StatusBadRequest, `{"Code": "InvalidDBInstanceClass.NotFound", "RequestId": "rds-1"}`, ""), "cannot create RDS instance"),
+			want:   "rds-1",
+		},
+		"Tea": {
+			reason: "The request ID of tea SDK errors should be read from their data",
+			err:    &tea.SDKError{Code: tea.String("InvalidParameter"), Data: tea.String(`{"RequestId": "nas-1"}`)},
+			want:   "nas-1",
+		},
+		"OSS": {
+			reason: "The request ID of OSS service errors should be returned",
+			err:    oss.ServiceError{Code: "InternalError", RequestID: "oss-1"},
+			want:   "oss-1",
+		},
+		"SLS": {
+			reason: "The request ID of SLS errors should be returned",
+			err:    &sls.Error{Code: "ProjectAlreadyExist", RequestID: "sls-1"},
+			want:   "sls-1",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ErrorRequestID(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nErrorRequestID(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
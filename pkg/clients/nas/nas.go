@@ -18,6 +18,7 @@ package nas
 
 import (
 	"context"
+	"strings"
 
 	openapi "github.com/alibabacloud-go/darabonba-openapi/client"
 	sdk "github.com/alibabacloud-go/nas-20170626/v2/client"
@@ -25,10 +26,24 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/crossplane/provider-alibaba/apis/nas/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/clients/audit"
 )
 
 // ErrCodeNoSuchNASFileSystem is the error code "NoSuchNASFileSystem" returned by SDK
 const (
+	// QuotaProductCode is the Quota Center product code of NAS.
+	QuotaProductCode = "nas"
+
+	// FileSystemTypeStandard is the type of General-purpose file systems.
+	FileSystemTypeStandard = "standard"
+
+	// auditService is the service NAS API calls are audited as.
+	auditService = "nas"
+
+	storageTypePerformance = "Performance"
+	storageTypeCapacity    = "Capacity"
+
 	errFailedToCreateNASClient = "failed to crate NAS client"
 	errCodeFileSystemNotExist  = "InvalidFileSystem.NotFound"
 	errMountTargetNotExisted   = "InvalidMountTarget.NotFound"
@@ -36,13 +51,14 @@ const (
 
 // ClientInterface create a client inferface
 type ClientInterface interface {
-	DescribeFileSystems(fileSystemID, fileSystemType, vpcID *string) (*sdk.DescribeFileSystemsResponse, error)
-	CreateFileSystem(fs v1alpha1.NASFileSystemParameter) (*sdk.CreateFileSystemResponse, error)
-	DeleteFileSystem(fileSystemID string) error
-
-	DescribeMountTargets(fileSystemID, mountTargetDomain *string) (*sdk.DescribeMountTargetsResponse, error)
-	CreateMountTarget(fs v1alpha1.NASMountTargetParameter) (*sdk.CreateMountTargetResponse, error)
-	DeleteMountTarget(fileSystemID, mountTargetDomain *string) error
+	DescribeFileSystems(ctx context.Context, fileSystemID, fileSystemType, vpcID *string) (*sdk.DescribeFileSystemsResponse, error)
+	CreateFileSystem(ctx context.Context, fs v1alpha1.NASFileSystemParameter) (*sdk.CreateFileSystemResponse, error)
+	DeleteFileSystem(ctx context.Context, fileSystemID string) error
+	DescribeZones(ctx context.Context, region string) (clients.Availability, error)
+
+	DescribeMountTargets(ctx context.Context, fileSystemID, mountTargetDomain *string) (*sdk.DescribeMountTargetsResponse, error)
+	CreateMountTarget(ctx context.Context, fs v1alpha1.NASMountTargetParameter) (*sdk.CreateMountTargetResponse, error)
+	DeleteMountTarget(ctx context.Context, fileSystemID, mountTargetDomain *string) error
 }
 
 // SDKClient is the SDK client for NASFileSystem
@@ -68,7 +84,7 @@ func NewClient(ctx context.Context, endpoint string, accessKeyID string, accessK
 // -------------------------------- FileSystem ----------------------------------------------------
 
 // DescribeFileSystems describes NAS FileSystem
-func (c *SDKClient) DescribeFileSystems(fileSystemID, fileSystemType, vpcID *string) (*sdk.DescribeFileSystemsResponse, error) {
+func (c *SDKClient) DescribeFileSystems(ctx context.Context, fileSystemID, fileSystemType, vpcID *string) (*sdk.DescribeFileSystemsResponse, error) {
 	describeFileSystemsRequest := &sdk.DescribeFileSystemsRequest{}
 	if fileSystemID != nil {
 		describeFileSystemsRequest.FileSystemId = tea.String(*fileSystemID)
@@ -79,7 +95,11 @@ func (c *SDKClient) DescribeFileSystems(fileSystemID, fileSystemType, vpcID *str
 	if vpcID != nil {
 		describeFileSystemsRequest.VpcId = tea.String(*vpcID)
 	}
-	fs, err := c.Client.DescribeFileSystems(describeFileSystemsRequest)
+	runtime, err := clients.RuntimeOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fs, err := c.Client.DescribeFileSystemsWithOptions(describeFileSystemsRequest, runtime)
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +107,7 @@ func (c *SDKClient) DescribeFileSystems(fileSystemID, fileSystemType, vpcID *str
 }
 
 // CreateFileSystem creates NASFileSystem
-func (c *SDKClient) CreateFileSystem(fs v1alpha1.NASFileSystemParameter) (*sdk.CreateFileSystemResponse, error) {
+func (c *SDKClient) CreateFileSystem(ctx context.Context, fs v1alpha1.NASFileSystemParameter) (*sdk.CreateFileSystemResponse, error) {
 	createFileSystemRequest := &sdk.CreateFileSystemRequest{
 		FileSystemType: fs.FileSystemType,
 		ChargeType:     fs.ChargeType,
@@ -96,19 +116,76 @@ func (c *SDKClient) CreateFileSystem(fs v1alpha1.NASFileSystemParameter) (*sdk.C
 		StorageType:    fs.StorageType,
 		ProtocolType:   fs.ProtocolType,
 	}
-	res, err := c.Client.CreateFileSystem(createFileSystemRequest)
+	runtime, err := clients.RuntimeOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.Client.CreateFileSystemWithOptions(createFileSystemRequest, runtime)
+	var requestID *string
+	if err == nil {
+		requestID = res.Body.RequestId
+	}
+	audit.Call(ctx, auditService, "CreateFileSystem", createFileSystemRequest, tea.StringValue(requestID), err)
 	return res, err
 }
 
 // DeleteFileSystem deletes NASFileSystem
-func (c *SDKClient) DeleteFileSystem(fileSystemID string) error {
+func (c *SDKClient) DeleteFileSystem(ctx context.Context, fileSystemID string) error {
 	deleteFileSystemRequest := &sdk.DeleteFileSystemRequest{
 		FileSystemId: tea.String(fileSystemID),
 	}
-	_, err := c.Client.DeleteFileSystem(deleteFileSystemRequest)
+	runtime, err := clients.RuntimeOptions(ctx)
+	if err != nil {
+		return err
+	}
+	res, err := c.Client.DeleteFileSystemWithOptions(deleteFileSystemRequest, runtime)
+	var requestID *string
+	if err == nil {
+		requestID = res.Body.RequestId
+	}
+	audit.Call(ctx, auditService, "DeleteFileSystem", deleteFileSystemRequest, tea.StringValue(requestID), err)
 	return err
 }
 
+// DescribeZones returns the storage types and protocols of General-purpose
+// file systems available in each zone of the supplied region, as returned by
+// Offering.
+func (c *SDKClient) DescribeZones(ctx context.Context, region string) (clients.Availability, error) {
+	runtime, err := clients.RuntimeOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.Client.DescribeZonesWithOptions(&sdk.DescribeZonesRequest{RegionId: tea.String(region)}, runtime)
+	if err != nil {
+		return nil, err
+	}
+	a := clients.Availability{}
+	if res.Body == nil || res.Body.Zones == nil {
+		return a, nil
+	}
+	for _, z := range res.Body.Zones.Zone {
+		id := tea.StringValue(z.ZoneId)
+		a[id] = nil
+		if z.Performance != nil {
+			for _, p := range z.Performance.Protocol {
+				a[id] = append(a[id], Offering(storageTypePerformance, tea.StringValue(p)))
+			}
+		}
+		if z.Capacity != nil {
+			for _, p := range z.Capacity.Protocol {
+				a[id] = append(a[id], Offering(storageTypeCapacity, tea.StringValue(p)))
+			}
+		}
+	}
+	return a, nil
+}
+
+// Offering returns the offering of General-purpose file systems of the
+// supplied storage type and protocol, e.g. Performance/NFS.
+func Offering(storageType, protocol string) string {
+	return storageType + "/" + strings.ToUpper(protocol)
+}
+
 // GenerateObservation generates NASFileSystemObservation from fileSystem information
 // When vpcID and vSwitchID are set, descriptionResponse.Body.FileSystems.FileSystem becomes 0, so we need to set fileSystemID
 // first, not from descriptionResponse
@@ -142,7 +219,7 @@ func IsUpdateToDate(cr *v1alpha1.NASFileSystem, fsResponse *sdk.DescribeFileSyst
 	return false
 }
 
-// IsNotFoundError helper function to test for SLS project not found error
+// IsNotFoundError helper function to test for NAS file system not found error
 func IsNotFoundError(err error) bool {
 	if err == nil {
 		return false
@@ -156,7 +233,7 @@ func IsNotFoundError(err error) bool {
 // -------------------------------- MountTarget ----------------------------------------------------
 
 // DescribeMountTargets describes NAS MountTarget
-func (c *SDKClient) DescribeMountTargets(fileSystemID, mountTargetDomain *string) (*sdk.DescribeMountTargetsResponse, error) {
+func (c *SDKClient) DescribeMountTargets(ctx context.Context, fileSystemID, mountTargetDomain *string) (*sdk.DescribeMountTargetsResponse, error) {
 	describeMountTargetsRequest := &sdk.DescribeMountTargetsRequest{}
 	if fileSystemID != nil {
 		describeMountTargetsRequest.FileSystemId = tea.String(*fileSystemID)
@@ -164,7 +241,11 @@ func (c *SDKClient) DescribeMountTargets(fileSystemID, mountTargetDomain *string
 	if mountTargetDomain != nil {
 		describeMountTargetsRequest.MountTargetDomain = tea.String(*mountTargetDomain)
 	}
-	fs, err := c.Client.DescribeMountTargets(describeMountTargetsRequest)
+	runtime, err := clients.RuntimeOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fs, err := c.Client.DescribeMountTargetsWithOptions(describeMountTargetsRequest, runtime)
 	if err != nil {
 		return nil, err
 	}
@@ -172,7 +253,7 @@ func (c *SDKClient) DescribeMountTargets(fileSystemID, mountTargetDomain *string
 }
 
 // CreateMountTarget creates NASMountTarget
-func (c *SDKClient) CreateMountTarget(fs v1alpha1.NASMountTargetParameter) (*sdk.CreateMountTargetResponse, error) {
+func (c *SDKClient) CreateMountTarget(ctx context.Context, fs v1alpha1.NASMountTargetParameter) (*sdk.CreateMountTargetResponse, error) {
 	createMountTargetRequest := &sdk.CreateMountTargetRequest{
 		FileSystemId:    fs.FileSystemID,
 		AccessGroupName: fs.AccessGroupName,
@@ -181,17 +262,35 @@ func (c *SDKClient) CreateMountTarget(fs v1alpha1.NASMountTargetParameter) (*sdk
 		VSwitchId:       fs.VSwitchID,
 		SecurityGroupId: fs.SecurityGroupID,
 	}
-	res, err := c.Client.CreateMountTarget(createMountTargetRequest)
+	runtime, err := clients.RuntimeOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.Client.CreateMountTargetWithOptions(createMountTargetRequest, runtime)
+	var requestID *string
+	if err == nil {
+		requestID = res.Body.RequestId
+	}
+	audit.Call(ctx, auditService, "CreateMountTarget", createMountTargetRequest, tea.StringValue(requestID), err)
 	return res, err
 }
 
 // DeleteMountTarget deletes NASMountTarget
-func (c *SDKClient) DeleteMountTarget(fileSystemID, mountTargetDomain *string) error {
+func (c *SDKClient) DeleteMountTarget(ctx context.Context, fileSystemID, mountTargetDomain *string) error {
 	deleteMountTargetRequest := &sdk.DeleteMountTargetRequest{
 		FileSystemId:      fileSystemID,
 		MountTargetDomain: mountTargetDomain,
 	}
-	_, err := c.Client.DeleteMountTarget(deleteMountTargetRequest)
+	runtime, err := clients.RuntimeOptions(ctx)
+	if err != nil {
+		return err
+	}
+	res, err := c.Client.DeleteMountTargetWithOptions(deleteMountTargetRequest, runtime)
+	var requestID *string
+	if err == nil {
+		requestID = res.Body.RequestId
+	}
+	audit.Call(ctx, auditService, "DeleteMountTarget", deleteMountTargetRequest, tea.StringValue(requestID), err)
 	return err
 }
 
@@ -228,7 +327,7 @@ func IsMountTargetUpdateToDate(cr *v1alpha1.NASMountTarget, mountTargetResponse
 	return true
 }
 
-// IsMountTargetNotFoundError helper function to test for SLS project not found error
+// IsMountTargetNotFoundError helper function to test for NAS mount target not found error
 func IsMountTargetNotFoundError(err error) bool {
 	if err == nil {
 		return false
@@ -23,17 +23,27 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/crossplane/provider-alibaba/apis/oss/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/clients/audit"
 )
 
 // ErrCodeNoSuchBucket is the error code "NoSuchBucket" returned by SDK
 const ErrCodeNoSuchBucket = "NoSuchBucket"
 
+// listMaxKeys is the maximum page size of ListBuckets.
+const listMaxKeys = 1000
+
+// auditService is the service OSS API calls are audited as.
+const auditService = "oss"
+
 // ClientInterface will help fakeOSSClient in unit tests
 type ClientInterface interface {
-	Describe(name string) (*sdk.GetBucketInfoResult, error)
-	Create(name string, bucket v1alpha1.BucketParameter) error
-	Update(name string, aclStr string) error
-	Delete(name string) error
+	Describe(ctx context.Context, name string) (*sdk.GetBucketInfoResult, error)
+	List(ctx context.Context, prefix string) ([]sdk.BucketProperties, error)
+	DescribeTags(ctx context.Context, name string) (map[string]string, error)
+	Create(ctx context.Context, name string, bucket v1alpha1.BucketParameter) error
+	Update(ctx context.Context, name string, aclStr string) error
+	Delete(ctx context.Context, name string) error
 }
 
 // SDKClient is the SDK client for Bucket
@@ -59,16 +69,60 @@ func NewClient(ctx context.Context, endpoint string, accessKeyID string, accessK
 }
 
 // Describe describes OSS bucket
-func (c *SDKClient) Describe(name string) (*sdk.GetBucketInfoResult, error) {
-	bucketInfoResult, err := c.Client.GetBucketInfo(name)
+func (c *SDKClient) Describe(ctx context.Context, name string) (*sdk.GetBucketInfoResult, error) {
+	var bucketInfoResult sdk.GetBucketInfoResult
+	err := clients.Do(ctx, func() (err error) {
+		bucketInfoResult, err = c.Client.GetBucketInfo(name)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 	return &bucketInfoResult, nil
 }
 
+// List lists the OSS buckets whose names start with prefix in all regions
+func (c *SDKClient) List(ctx context.Context, prefix string) ([]sdk.BucketProperties, error) {
+	var (
+		buckets []sdk.BucketProperties
+		marker  string
+	)
+	for {
+		var res sdk.ListBucketsResult
+		err := clients.Do(ctx, func() (err error) {
+			res, err = c.Client.ListBuckets(sdk.Prefix(prefix), sdk.Marker(marker), sdk.MaxKeys(listMaxKeys))
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, res.Buckets...)
+		if !res.IsTruncated {
+			return buckets, nil
+		}
+		marker = res.NextMarker
+	}
+}
+
+// DescribeTags describes the tags of OSS bucket
+func (c *SDKClient) DescribeTags(ctx context.Context, name string) (map[string]string, error) {
+	var res sdk.GetBucketTaggingResult
+	err := clients.Do(ctx, func() (err error) {
+		res, err = c.Client.GetBucketTagging(name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	tags := make(map[string]string, len(res.Tags))
+	for _, t := range res.Tags {
+		tags[t.Key] = t.Value
+	}
+	return tags, nil
+}
+
 // Create creates Bucket bucket
-func (c *SDKClient) Create(name string, bucket v1alpha1.BucketParameter) error {
+func (c *SDKClient) Create(ctx context.Context, name string, bucket v1alpha1.BucketParameter) error {
 	var options []sdk.Option
 	var (
 		acl                sdk.ACLType
@@ -98,24 +152,40 @@ func (c *SDKClient) Create(name string, bucket v1alpha1.BucketParameter) error {
 	}
 	options = append(options, sdk.RedundancyType(dataRedundancyType))
 
-	if err := c.Client.CreateBucket(name, options...); err != nil {
-		return err
+	// Calls are audited once they return, even if the context is done first.
+	parameters := map[string]string{
+		"BucketName":         name,
+		"ACL":                string(acl),
+		"StorageClass":       string(storageClass),
+		"DataRedundancyType": string(dataRedundancyType),
 	}
-	return nil
+	return clients.Do(ctx, func() error {
+		err := c.Client.CreateBucket(name, options...)
+		audit.Call(ctx, auditService, "PutBucket", parameters, "", err)
+		return err
+	})
 }
 
 // Update sets bucket acl
-func (c *SDKClient) Update(name string, aclStr string) error {
+func (c *SDKClient) Update(ctx context.Context, name string, aclStr string) error {
 	acl, err := ValidateOSSAcl(aclStr)
 	if err != nil {
 		return err
 	}
-	return c.Client.SetBucketACL(name, acl)
+	return clients.Do(ctx, func() error {
+		err := c.Client.SetBucketACL(name, acl)
+		audit.Call(ctx, auditService, "PutBucketAcl", map[string]string{"BucketName": name, "ACL": string(acl)}, "", err)
+		return err
+	})
 }
 
 // Delete deletes OSS Bucket
-func (c *SDKClient) Delete(name string) error {
-	return c.Client.DeleteBucket(name)
+func (c *SDKClient) Delete(ctx context.Context, name string) error {
+	return clients.Do(ctx, func() error {
+		err := c.Client.DeleteBucket(name)
+		audit.Call(ctx, auditService, "DeleteBucket", map[string]string{"BucketName": name}, "", err)
+		return err
+	})
 }
 
 // IsNotFoundError checks whether the error is an NotFound error
@@ -127,6 +197,15 @@ func IsNotFoundError(err error) bool {
 	return ok && e.Code == ErrCodeNoSuchBucket
 }
 
+// GenerateParameters generates BucketParameter from bucket information
+func GenerateParameters(r sdk.GetBucketInfoResult) v1alpha1.BucketParameter {
+	return v1alpha1.BucketParameter{
+		ACL:                r.BucketInfo.ACL,
+		StorageClass:       r.BucketInfo.StorageClass,
+		DataRedundancyType: r.BucketInfo.RedundancyType,
+	}
+}
+
 // GenerateObservation generates BucketObservation from bucket information
 func GenerateObservation(r sdk.GetBucketInfoResult) v1alpha1.BucketObservation {
 	return v1alpha1.BucketObservation{
@@ -146,7 +225,7 @@ func ValidateOSSAcl(aclStr string) (sdk.ACLType, error) {
 	case string(sdk.ACLPrivate), "":
 		acl = sdk.ACLPrivate
 	default:
-		err := errors.Errorf("bucket ACL %s is invalid. The ACL could only be public-read-write, public-read, and private", aclStr)
+		err := clients.NewTerminalErrorf("bucket ACL %s is invalid. The ACL could only be public-read-write, public-read, and private", aclStr)
 		return "", err
 	}
 	return acl, nil
@@ -164,7 +243,7 @@ func validateOSSStorageClass(storageClassStr string) (sdk.StorageClassType, erro
 	case string(sdk.StorageColdArchive):
 		storageClass = sdk.StorageColdArchive
 	default:
-		err := errors.Errorf("bucket StorageClass %s is invalid. It only supports could be Standard, IA, Archive, and ColdArchive", storageClassStr)
+		err := clients.NewTerminalErrorf("bucket StorageClass %s is invalid. It only supports could be Standard, IA, Archive, and ColdArchive", storageClassStr)
 		return "", err
 	}
 	return storageClass, nil
@@ -178,7 +257,7 @@ func validateOSSDataRedundancyType(dataRedundancyTypeStr string) (sdk.DataRedund
 	case string(sdk.RedundancyZRS):
 		dataRedundancyType = sdk.RedundancyZRS
 	default:
-		return "", errors.Errorf("bucket DataRedundancyType %s is invalid. It only supports could be LRS and ZRS", dataRedundancyType)
+		return "", clients.NewTerminalErrorf("bucket DataRedundancyType %s is invalid. It only supports could be LRS and ZRS", dataRedundancyType)
 	}
 	return dataRedundancyType, nil
 }
@@ -0,0 +1,178 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package quotas reads the quotas of the account from the Alibaba Cloud Quota
+// Center.
+package quotas
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+)
+
+const (
+	httpsScheme = "https"
+
+	// The Quota Center API is not part of the SDK, so it is called using
+	// common requests.
+	domain               = "quotas.aliyuncs.com"
+	version              = "2020-05-10"
+	apiListProductQuotas = "ListProductQuotas"
+	listPageSize         = 200
+	dimensionRegionID    = "regionId"
+	paramProductCode     = "ProductCode"
+	paramMaxResults      = "MaxResults"
+	paramNextToken       = "NextToken"
+)
+
+// Client defines Quota Center operations.
+type Client interface {
+	ListProductQuotas(ctx context.Context, productCode string) ([]Quota, error)
+}
+
+// A Quota limits the usage of a product by the account.
+type Quota struct {
+	// Code of the quota.
+	Code string
+
+	// Name of the quota.
+	Name string
+
+	// Limit and Usage of the quota.
+	Limit float64
+	Usage float64
+
+	// Dimensions the quota applies to, e.g. regionId.
+	Dimensions map[string]string
+}
+
+// Exhausted returns true if the usage of the quota reached its limit.
+func (q Quota) Exhausted() bool {
+	return q.Limit > 0 && q.Usage >= q.Limit
+}
+
+// Exhausted returns the exhausted quotas of the supplied quotas that limit the
+// usage of the whole account in the supplied region, i.e. that have no
+// dimensions other than the region, such as the number of instances.
+func Exhausted(quotas []Quota, region string) []Quota {
+	var exhausted []Quota
+	for _, q := range quotas {
+		if !q.Exhausted() || !regional(q, region) {
+			continue
+		}
+		exhausted = append(exhausted, q)
+	}
+	return exhausted
+}
+
+func regional(q Quota, region string) bool {
+	for k, v := range q.Dimensions {
+		if k != dimensionRegionID || v != region {
+			return false
+		}
+	}
+	return true
+}
+
+type quota struct {
+	QuotaActionCode string            `json:"QuotaActionCode"`
+	QuotaName       string            `json:"QuotaName"`
+	TotalQuota      float64           `json:"TotalQuota"`
+	TotalUsage      float64           `json:"TotalUsage"`
+	Dimensions      map[string]string `json:"Dimensions"`
+}
+
+type listProductQuotasResponse struct {
+	Quotas    []quota `json:"Quotas"`
+	NextToken string  `json:"NextToken"`
+}
+
+type client struct {
+	sdkCli *sdk.Client
+}
+
+// NewClient creates new Quota Center client
+func NewClient(ctx context.Context, accessKeyID, accessKeySecret, securityToken, region string) (Client, error) {
+	var (
+		sdkCli *sdk.Client
+		err    error
+	)
+	if securityToken != "" {
+		sdkCli, err = sdk.NewClientWithStsToken(region, accessKeyID, accessKeySecret, securityToken)
+	} else {
+		sdkCli, err = sdk.NewClientWithAccessKey(region, accessKeyID, accessKeySecret)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &client{sdkCli: sdkCli}, nil
+}
+
+// ListProductQuotas returns the quotas of the product with the supplied code,
+// e.g. rds.
+func (c *client) ListProductQuotas(ctx context.Context, productCode string) ([]Quota, error) {
+	var (
+		quotas []Quota
+		next   string
+	)
+	for {
+		t, err := clients.Timeout(ctx)
+		if err != nil {
+			return nil, err
+		}
+		request := requests.NewCommonRequest()
+		request.Method = requests.POST
+		request.Scheme = httpsScheme
+		request.Domain = domain
+		request.Version = version
+		request.ApiName = apiListProductQuotas
+		request.SetConnectTimeout(t)
+		request.SetReadTimeout(t)
+		request.QueryParams[paramProductCode] = productCode
+		request.QueryParams[paramMaxResults] = strconv.Itoa(listPageSize)
+		if next != "" {
+			request.QueryParams[paramNextToken] = next
+		}
+
+		response, err := c.sdkCli.ProcessCommonRequest(request)
+		if err != nil {
+			return nil, err
+		}
+		page := &listProductQuotasResponse{}
+		if err := json.Unmarshal(response.GetHttpContentBytes(), page); err != nil {
+			return nil, err
+		}
+		for _, q := range page.Quotas {
+			quotas = append(quotas, Quota{
+				Code:       q.QuotaActionCode,
+				Name:       q.QuotaName,
+				Limit:      q.TotalQuota,
+				Usage:      q.TotalUsage,
+				Dimensions: q.Dimensions,
+			})
+		}
+		next = page.NextToken
+		if next == "" || len(page.Quotas) == 0 {
+			return quotas, nil
+		}
+	}
+}
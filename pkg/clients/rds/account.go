@@ -0,0 +1,247 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	alirds "github.com/aliyun/alibaba-cloud-sdk-go/services/rds"
+
+	"github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/clients/audit"
+)
+
+// ErrAccountNotFound indicates that the account does not exist.
+var ErrAccountNotFound = errors.New("AccountNotFound")
+
+// privilegeSeparator separates the databases and privileges of the privilege
+// APIs.
+const privilegeSeparator = ","
+
+// Account defines the information of an account of an instance.
+type Account struct {
+	Name         string
+	DBInstanceID string
+	Status       string
+	Type         string
+	Description  string
+	Privileges   []v1alpha1.AccountPrivilege
+
+	// Endpoint and PublicEndpoint of the instance of the account.
+	Endpoint       *v1alpha1.Endpoint
+	PublicEndpoint *v1alpha1.Endpoint
+}
+
+// DescribeAccount returns the account with the supplied name of the instance
+// with the supplied ID, along with the endpoints of the instance.
+func (c *client) DescribeAccount(ctx context.Context, id, name string) (*Account, error) {
+	request := alirds.CreateDescribeAccountsRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return nil, err
+	}
+
+	request.DBInstanceId = id
+	request.AccountName = name
+
+	response, err := c.rdsCli.DescribeAccounts(request)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range response.Accounts.DBInstanceAccount {
+		if a.AccountName != name {
+			continue
+		}
+		account := &Account{
+			Name:         a.AccountName,
+			DBInstanceID: a.DBInstanceId,
+			Status:       a.AccountStatus,
+			Type:         a.AccountType,
+			Description:  a.AccountDescription,
+		}
+		for _, p := range a.DatabasePrivileges.DatabasePrivilege {
+			account.Privileges = append(account.Privileges, v1alpha1.AccountPrivilege{Database: p.DBName, Privilege: p.AccountPrivilege})
+		}
+		account.Endpoint, account.PublicEndpoint, err = c.describeEndpoints(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return account, nil
+	}
+	return nil, ErrAccountNotFound
+}
+
+// CreateAccount creates an account with the supplied name and password in the
+// instance with the supplied ID. The account is of the type and description
+// of the supplied parameters, if any.
+func (c *client) CreateAccount(ctx context.Context, id, user, pw string, p *v1alpha1.RDSAccountParameters) error {
+	request := alirds.CreateCreateAccountRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return err
+	}
+	request.DBInstanceId = id
+	request.AccountName = user
+	request.AccountPassword = pw
+	if p != nil {
+		request.AccountType = p.AccountType
+		request.AccountDescription = p.Description
+	}
+
+	resp, err := c.rdsCli.CreateAccount(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
+	return err
+}
+
+// GrantAccountPrivilege grants the account with the supplied name of the
+// instance with the supplied ID the supplied privileges.
+func (c *client) GrantAccountPrivilege(ctx context.Context, id, name string, privileges []v1alpha1.AccountPrivilege) error {
+	request := alirds.CreateGrantAccountPrivilegeRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return err
+	}
+
+	dbs := make([]string, len(privileges))
+	privs := make([]string, len(privileges))
+	for i, p := range privileges {
+		dbs[i], privs[i] = p.Database, p.Privilege
+	}
+	request.DBInstanceId = id
+	request.AccountName = name
+	request.DBName = strings.Join(dbs, privilegeSeparator)
+	request.AccountPrivilege = strings.Join(privs, privilegeSeparator)
+
+	resp, err := c.rdsCli.GrantAccountPrivilege(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
+	return err
+}
+
+// RevokeAccountPrivilege revokes the privileges of the account with the
+// supplied name of the instance with the supplied ID on the supplied
+// databases.
+func (c *client) RevokeAccountPrivilege(ctx context.Context, id, name string, databases []string) error {
+	request := alirds.CreateRevokeAccountPrivilegeRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return err
+	}
+
+	request.DBInstanceId = id
+	request.AccountName = name
+	request.DBName = strings.Join(databases, privilegeSeparator)
+
+	resp, err := c.rdsCli.RevokeAccountPrivilege(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
+	return err
+}
+
+// ResetAccountPassword resets the password of the account with the supplied
+// name of the instance with the supplied ID.
+func (c *client) ResetAccountPassword(ctx context.Context, id, name, pw string) error {
+	request := alirds.CreateResetAccountPasswordRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return err
+	}
+
+	request.DBInstanceId = id
+	request.AccountName = name
+	request.AccountPassword = pw
+
+	resp, err := c.rdsCli.ResetAccountPassword(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
+	return err
+}
+
+// DeleteAccount deletes the account with the supplied name of the instance
+// with the supplied ID.
+func (c *client) DeleteAccount(ctx context.Context, id, name string) error {
+	request := alirds.CreateDeleteAccountRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return err
+	}
+
+	request.DBInstanceId = id
+	request.AccountName = name
+
+	resp, err := c.rdsCli.DeleteAccount(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
+	return err
+}
+
+// GenerateAccountObservation is used to produce
+// v1alpha1.RDSAccountObservation from rds.Account.
+func GenerateAccountObservation(a *Account) v1alpha1.RDSAccountObservation {
+	return v1alpha1.RDSAccountObservation{
+		AccountStatus: a.Status,
+		DBInstanceID:  a.DBInstanceID,
+		AccountType:   a.Type,
+		Privileges:    a.Privileges,
+	}
+}
+
+// DiffPrivileges returns the desired privileges that are not observed, and
+// the databases whose observed privileges have to be revoked because they are
+// not, or not as, desired. Changed privileges are both revoked and granted.
+func DiffPrivileges(desired, observed []v1alpha1.AccountPrivilege) ([]v1alpha1.AccountPrivilege, []string) {
+	want := make(map[string]string, len(desired))
+	for _, priv := range desired {
+		want[priv.Database] = priv.Privilege
+	}
+	got := make(map[string]string, len(observed))
+	for _, priv := range observed {
+		got[priv.Database] = priv.Privilege
+	}
+
+	var grant []v1alpha1.AccountPrivilege
+	for _, priv := range desired {
+		if o, ok := got[priv.Database]; !ok || o != priv.Privilege {
+			grant = append(grant, priv)
+		}
+	}
+	var revoke []string
+	for db, o := range got {
+		if d, ok := want[db]; !ok || d != o {
+			revoke = append(revoke, db)
+		}
+	}
+	sort.Strings(revoke)
+	return grant, revoke
+}
+
+// IsAccountUpToDate returns true if the supplied account has the privileges
+// of the supplied parameters. The privileges of Super accounts, which have all
+// privileges, are not compared.
+func IsAccountUpToDate(p *v1alpha1.RDSAccountParameters, a *Account) bool {
+	if a.Type == v1alpha1.AccountTypeSuper {
+		return true
+	}
+	grant, revoke := DiffPrivileges(p.Privileges, a.Privileges)
+	return len(grant) == 0 && len(revoke) == 0
+}
+
+// IsAccountNotFound returns true if the supplied error indicates that an
+// account, or its instance, does not exist.
+func IsAccountNotFound(err error) bool {
+	return errors.Is(err, ErrAccountNotFound) || IsErrorNotFound(err)
+}
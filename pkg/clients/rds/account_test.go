@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rds
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
+)
+
+func TestDiffPrivileges(t *testing.T) {
+	type want struct {
+		grant  []v1alpha1.AccountPrivilege
+		revoke []string
+	}
+
+	cases := map[string]struct {
+		reason   string
+		desired  []v1alpha1.AccountPrivilege
+		observed []v1alpha1.AccountPrivilege
+		want     want
+	}{
+		"UpToDate": {
+			reason:   "Observed privileges that are desired should be left alone",
+			desired:  []v1alpha1.AccountPrivilege{{Database: "app", Privilege: "ReadWrite"}},
+			observed: []v1alpha1.AccountPrivilege{{Database: "app", Privilege: "ReadWrite"}},
+		},
+		"Grant": {
+			reason:  "Desired privileges that are not observed should be granted",
+			desired: []v1alpha1.AccountPrivilege{{Database: "app", Privilege: "ReadWrite"}, {Database: "log", Privilege: "ReadOnly"}},
+			want:    want{grant: []v1alpha1.AccountPrivilege{{Database: "app", Privilege: "ReadWrite"}, {Database: "log", Privilege: "ReadOnly"}}},
+		},
+		"Revoke": {
+			reason:   "Observed privileges that are not desired should be revoked",
+			observed: []v1alpha1.AccountPrivilege{{Database: "log", Privilege: "ReadOnly"}, {Database: "app", Privilege: "ReadWrite"}},
+			want:     want{revoke: []string{"app", "log"}},
+		},
+		"Change": {
+			reason:   "Changed privileges should be revoked and granted",
+			desired:  []v1alpha1.AccountPrivilege{{Database: "app", Privilege: "ReadOnly"}},
+			observed: []v1alpha1.AccountPrivilege{{Database: "app", Privilege: "ReadWrite"}},
+			want:     want{grant: []v1alpha1.AccountPrivilege{{Database: "app", Privilege: "ReadOnly"}}, revoke: []string{"app"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			grant, revoke := DiffPrivileges(tc.desired, tc.observed)
+			if diff := cmp.Diff(tc.want.grant, grant); diff != "" {
+				t.Errorf("\n%s\nDiffPrivileges(...): -want grant, +got grant:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.revoke, revoke); diff != "" {
+				t.Errorf("\n%s\nDiffPrivileges(...): -want revoke, +got revoke:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIsAccountUpToDate(t *testing.T) {
+	privileges := []v1alpha1.AccountPrivilege{{Database: "app", Privilege: "ReadWrite"}}
+
+	cases := map[string]struct {
+		reason string
+		p      v1alpha1.RDSAccountParameters
+		a      Account
+		want   bool
+	}{
+		"UpToDate": {
+			reason: "Accounts with the desired privileges should be up to date",
+			p:      v1alpha1.RDSAccountParameters{Privileges: privileges},
+			a:      Account{Type: v1alpha1.AccountTypeNormal, Privileges: privileges},
+			want:   true,
+		},
+		"PrivilegesChanged": {
+			reason: "Accounts without the desired privileges should not be up to date",
+			p:      v1alpha1.RDSAccountParameters{Privileges: privileges},
+			a:      Account{Type: v1alpha1.AccountTypeNormal},
+		},
+		"Super": {
+			reason: "The privileges of Super accounts should not be compared",
+			p:      v1alpha1.RDSAccountParameters{Privileges: privileges},
+			a:      Account{Type: v1alpha1.AccountTypeSuper},
+			want:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsAccountUpToDate(&tc.p, &tc.a)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIsAccountUpToDate(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rds
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	alirds "github.com/aliyun/alibaba-cloud-sdk-go/services/rds"
+
+	"github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/clients/audit"
+)
+
+const (
+	// Log backup states of the backup policy APIs.
+	backupLogEnable = "Enable"
+	backupLogTrue   = "True"
+	backupLogFalse  = "False"
+
+	// Cross-region backup states of the cross-region backup policy APIs,
+	// which are described as Enable and modified as 1 or 0.
+	crossBackupEnable   = "Enable"
+	crossBackupEnabled  = "1"
+	crossBackupDisabled = "0"
+
+	// crossBackupTypeDefault copies backups as they are made.
+	crossBackupTypeDefault = "1"
+
+	// crossBackupRetentTypeDays keeps copies for a number of days.
+	crossBackupRetentTypeDays = 1
+
+	// defaultCrossBackupRetentionDays is the number of days copies are kept
+	// if unset.
+	defaultCrossBackupRetentionDays = 7
+
+	// backupPeriodSeparator separates the days of the week of backup
+	// periods.
+	backupPeriodSeparator = ","
+)
+
+// DescribeBackupPolicy returns the backup policy of the instance with the
+// supplied ID. Its cross-region backup is only described if crossRegion is
+// true.
+func (c *client) DescribeBackupPolicy(ctx context.Context, id string, crossRegion bool) (*v1alpha1.BackupPolicy, error) {
+	request := alirds.CreateDescribeBackupPolicyRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return nil, err
+	}
+
+	request.DBInstanceId = id
+
+	response, err := c.rdsCli.DescribeBackupPolicy(request)
+	if err != nil {
+		return nil, err
+	}
+	backupLog := response.BackupLog == backupLogEnable || response.EnableBackupLog == backupLogTrue || response.EnableBackupLog == crossBackupEnabled
+	bp := &v1alpha1.BackupPolicy{
+		BackupRetentionPeriod:    response.BackupRetentionPeriod,
+		PreferredBackupTime:      response.PreferredBackupTime,
+		PreferredBackupPeriod:    splitBackupPeriod(response.PreferredBackupPeriod),
+		EnableBackupLog:          &backupLog,
+		LogBackupRetentionPeriod: response.LogBackupRetentionPeriod,
+		LocalLogRetentionHours:   response.LocalLogRetentionHours,
+	}
+	if crossRegion {
+		if bp.CrossRegionBackup, err = c.describeCrossRegionBackup(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return bp, nil
+}
+
+// describeCrossRegionBackup returns the cross-region backup of the instance
+// with the supplied ID, whose region is empty if it is disabled.
+func (c *client) describeCrossRegionBackup(ctx context.Context, id string) (*v1alpha1.CrossRegionBackup, error) {
+	request := alirds.CreateDescribeInstanceCrossBackupPolicyRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return nil, err
+	}
+
+	request.DBInstanceId = id
+
+	response, err := c.rdsCli.DescribeInstanceCrossBackupPolicy(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.BackupEnabled != crossBackupEnable {
+		return &v1alpha1.CrossRegionBackup{}, nil
+	}
+	return &v1alpha1.CrossRegionBackup{
+		Region:           response.CrossBackupRegion,
+		RetentionDays:    response.Retention,
+		LogBackupEnabled: response.LogBackupEnabled == crossBackupEnable,
+	}, nil
+}
+
+// ModifyBackupPolicy changes the backup policy of the instance with the
+// supplied ID to the supplied one, except for its cross-region backup. Unset
+// fields are not changed.
+func (c *client) ModifyBackupPolicy(ctx context.Context, id string, bp *v1alpha1.BackupPolicy) error {
+	request := alirds.CreateModifyBackupPolicyRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return err
+	}
+
+	request.DBInstanceId = id
+	if bp.BackupRetentionPeriod != 0 {
+		request.BackupRetentionPeriod = strconv.Itoa(bp.BackupRetentionPeriod)
+	}
+	request.PreferredBackupTime = bp.PreferredBackupTime
+	request.PreferredBackupPeriod = strings.Join(bp.PreferredBackupPeriod, backupPeriodSeparator)
+	if bp.EnableBackupLog != nil {
+		request.EnableBackupLog = backupLogFalse
+		if *bp.EnableBackupLog {
+			request.EnableBackupLog = backupLogTrue
+		}
+	}
+	if bp.LogBackupRetentionPeriod != 0 {
+		request.LogBackupRetentionPeriod = strconv.Itoa(bp.LogBackupRetentionPeriod)
+	}
+	if bp.LocalLogRetentionHours != 0 {
+		request.LocalLogRetentionHours = strconv.Itoa(bp.LocalLogRetentionHours)
+	}
+
+	resp, err := c.rdsCli.ModifyBackupPolicy(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
+	return err
+}
+
+// ModifyCrossRegionBackup enables the supplied cross-region backup of the
+// instance with the supplied ID, or disables it if its region is empty.
+func (c *client) ModifyCrossRegionBackup(ctx context.Context, id string, cb *v1alpha1.CrossRegionBackup) error {
+	request := alirds.CreateModifyInstanceCrossBackupPolicyRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return err
+	}
+
+	request.DBInstanceId = id
+	request.BackupEnabled = crossBackupDisabled
+	if cb.Region != "" {
+		retention := cb.RetentionDays
+		if retention == 0 {
+			retention = defaultCrossBackupRetentionDays
+		}
+		request.BackupEnabled = crossBackupEnabled
+		request.CrossBackupRegion = cb.Region
+		request.CrossBackupType = crossBackupTypeDefault
+		request.RetentType = requests.NewInteger(crossBackupRetentTypeDays)
+		request.Retention = requests.NewInteger(retention)
+		request.LogBackupEnabled = crossBackupDisabled
+		if cb.LogBackupEnabled {
+			request.LogBackupEnabled = crossBackupEnabled
+		}
+	}
+
+	resp, err := c.rdsCli.ModifyInstanceCrossBackupPolicy(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
+	return err
+}
+
+// splitBackupPeriod splits the supplied comma-separated days of the week.
+func splitBackupPeriod(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, backupPeriodSeparator)
+}
+
+// IsBackupPolicyUpToDate returns true if the supplied observed backup policy
+// has all fields that are set in the supplied desired one, except for its
+// cross-region backup.
+func IsBackupPolicyUpToDate(desired, observed *v1alpha1.BackupPolicy) bool {
+	if desired == nil {
+		return true
+	}
+	if observed == nil {
+		return false
+	}
+	switch {
+	case desired.BackupRetentionPeriod != 0 && desired.BackupRetentionPeriod != observed.BackupRetentionPeriod:
+		return false
+	case desired.PreferredBackupTime != "" && desired.PreferredBackupTime != observed.PreferredBackupTime:
+		return false
+	case len(desired.PreferredBackupPeriod) != 0 && !sameDays(desired.PreferredBackupPeriod, observed.PreferredBackupPeriod):
+		return false
+	case desired.EnableBackupLog != nil && (observed.EnableBackupLog == nil || *desired.EnableBackupLog != *observed.EnableBackupLog):
+		return false
+	case desired.LogBackupRetentionPeriod != 0 && desired.LogBackupRetentionPeriod != observed.LogBackupRetentionPeriod:
+		return false
+	case desired.LocalLogRetentionHours != 0 && desired.LocalLogRetentionHours != observed.LocalLogRetentionHours:
+		return false
+	}
+	return true
+}
+
+// IsCrossRegionBackupUpToDate returns true if the cross-region backup of the
+// supplied observed backup policy is that of the supplied desired one, or if
+// the desired one sets none.
+func IsCrossRegionBackupUpToDate(desired, observed *v1alpha1.BackupPolicy) bool {
+	if desired == nil || desired.CrossRegionBackup == nil {
+		return true
+	}
+	if observed == nil || observed.CrossRegionBackup == nil {
+		return false
+	}
+	d, o := desired.CrossRegionBackup, observed.CrossRegionBackup
+	if d.Region == "" || o.Region == "" {
+		return d.Region == o.Region
+	}
+	retention := d.RetentionDays
+	if retention == 0 {
+		retention = defaultCrossBackupRetentionDays
+	}
+	return d.Region == o.Region && retention == o.RetentionDays && d.LogBackupEnabled == o.LogBackupEnabled
+}
+
+// sameDays returns true if the supplied days of the week are the same,
+// regardless of their order.
+func sameDays(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sa := append([]string{}, a...)
+	sb := append([]string{}, b...)
+	sort.Strings(sa)
+	sort.Strings(sb)
+	for i := range sa {
+		if sa[i] != sb[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rds
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
+)
+
+func TestIsBackupPolicyUpToDate(t *testing.T) {
+	enabled, disabled := true, false
+	observed := &v1alpha1.BackupPolicy{
+		BackupRetentionPeriod:    7,
+		PreferredBackupTime:      "02:00Z-03:00Z",
+		PreferredBackupPeriod:    []string{"Monday", "Thursday"},
+		EnableBackupLog:          &enabled,
+		LogBackupRetentionPeriod: 7,
+		LocalLogRetentionHours:   18,
+	}
+
+	cases := map[string]struct {
+		reason   string
+		desired  *v1alpha1.BackupPolicy
+		observed *v1alpha1.BackupPolicy
+		want     bool
+	}{
+		"NoPolicy": {
+			reason:   "Instances whose spec sets no backup policy should be up to date",
+			observed: observed,
+			want:     true,
+		},
+		"NotObserved": {
+			reason:  "Backup policies that were not observed should not be up to date",
+			desired: &v1alpha1.BackupPolicy{BackupRetentionPeriod: 7},
+			want:    false,
+		},
+		"UnsetFields": {
+			reason:   "Fields that are not set in the spec should not be compared",
+			desired:  &v1alpha1.BackupPolicy{BackupRetentionPeriod: 7},
+			observed: observed,
+			want:     true,
+		},
+		"Period": {
+			reason:   "Backup periods should be compared regardless of the order of their days",
+			desired:  &v1alpha1.BackupPolicy{PreferredBackupPeriod: []string{"Thursday", "Monday"}},
+			observed: observed,
+			want:     true,
+		},
+		"PeriodChanged": {
+			reason:   "Backup periods with different days should not be up to date",
+			desired:  &v1alpha1.BackupPolicy{PreferredBackupPeriod: []string{"Monday", "Friday"}},
+			observed: observed,
+			want:     false,
+		},
+		"BackupLogDisabled": {
+			reason:   "Log backups that should be disabled should not be up to date while enabled",
+			desired:  &v1alpha1.BackupPolicy{EnableBackupLog: &disabled},
+			observed: observed,
+			want:     false,
+		},
+		"RetentionChanged": {
+			reason:   "Backup policies with a different retention period should not be up to date",
+			desired:  &v1alpha1.BackupPolicy{BackupRetentionPeriod: 30, PreferredBackupTime: "02:00Z-03:00Z"},
+			observed: observed,
+			want:     false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsBackupPolicyUpToDate(tc.desired, tc.observed)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIsBackupPolicyUpToDate(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIsCrossRegionBackupUpToDate(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		desired  *v1alpha1.CrossRegionBackup
+		observed *v1alpha1.CrossRegionBackup
+		want     bool
+	}{
+		"NoCrossRegionBackup": {
+			reason: "Instances whose spec sets no cross-region backup should be up to date",
+			want:   true,
+		},
+		"NotObserved": {
+			reason:  "Cross-region backups that were not observed should not be up to date",
+			desired: &v1alpha1.CrossRegionBackup{Region: "cn-shanghai"},
+			want:    false,
+		},
+		"Disabled": {
+			reason:   "Disabled cross-region backups should be up to date if the spec disables them",
+			desired:  &v1alpha1.CrossRegionBackup{},
+			observed: &v1alpha1.CrossRegionBackup{},
+			want:     true,
+		},
+		"Enable": {
+			reason:   "Disabled cross-region backups should not be up to date if the spec enables them",
+			desired:  &v1alpha1.CrossRegionBackup{Region: "cn-shanghai"},
+			observed: &v1alpha1.CrossRegionBackup{},
+			want:     false,
+		},
+		"Disable": {
+			reason:   "Enabled cross-region backups should not be up to date if the spec disables them",
+			desired:  &v1alpha1.CrossRegionBackup{},
+			observed: &v1alpha1.CrossRegionBackup{Region: "cn-shanghai", RetentionDays: 7},
+			want:     false,
+		},
+		"DefaultRetention": {
+			reason:   "Cross-region backups should be kept for 7 days if the spec does not say",
+			desired:  &v1alpha1.CrossRegionBackup{Region: "cn-shanghai"},
+			observed: &v1alpha1.CrossRegionBackup{Region: "cn-shanghai", RetentionDays: 7},
+			want:     true,
+		},
+		"RegionChanged": {
+			reason:   "Cross-region backups to a different region should not be up to date",
+			desired:  &v1alpha1.CrossRegionBackup{Region: "cn-shenzhen", RetentionDays: 7},
+			observed: &v1alpha1.CrossRegionBackup{Region: "cn-shanghai", RetentionDays: 7},
+			want:     false,
+		},
+		"LogBackupChanged": {
+			reason:   "Cross-region backups whose log backups differ from the spec should not be up to date",
+			desired:  &v1alpha1.CrossRegionBackup{Region: "cn-shanghai", LogBackupEnabled: true},
+			observed: &v1alpha1.CrossRegionBackup{Region: "cn-shanghai", RetentionDays: 7},
+			want:     false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			desired := &v1alpha1.BackupPolicy{CrossRegionBackup: tc.desired}
+			observed := &v1alpha1.BackupPolicy{CrossRegionBackup: tc.observed}
+			got := IsCrossRegionBackupUpToDate(desired, observed)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIsCrossRegionBackupUpToDate(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
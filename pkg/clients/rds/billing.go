@@ -0,0 +1,166 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rds
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	alirds "github.com/aliyun/alibaba-cloud-sdk-go/services/rds"
+
+	"github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/clients/audit"
+)
+
+const (
+	// The RDS API action that converts the pay type of instances is not
+	// part of the SDK, so it is called as a common request.
+	apiTransformPayType = "TransformDBInstancePayType"
+
+	// Auto-renewal states of the auto-renewal attribute API.
+	autoRenewTrue  = "True"
+	autoRenewFalse = "False"
+
+	// defaultUsedTime is the length of subscriptions whose length is unset.
+	defaultUsedTime = 1
+
+	// monthsPerYear is the auto-renewal duration of yearly subscriptions,
+	// which is set in months.
+	monthsPerYear = 12
+)
+
+// A Subscription of a Prepaid instance.
+type Subscription struct {
+	// Period is the unit of UsedTime: Year or Month.
+	Period string
+
+	// UsedTime is the length of the subscription in Periods.
+	UsedTime int
+
+	// AutoRenew renews the subscription by one Period each time it expires.
+	AutoRenew bool
+}
+
+type transformPayTypeResponse struct {
+	OrderID json.Number `json:"OrderId"`
+}
+
+// TransformDBInstancePayType converts the Postpaid instance with the supplied
+// ID to a Prepaid instance with the supplied subscription, and returns the ID
+// of the order that converted it.
+func (c *client) TransformDBInstancePayType(ctx context.Context, id string, s Subscription) (string, error) {
+	rsp := &transformPayTypeResponse{}
+	err := c.call(ctx, apiTransformPayType, map[string]string{
+		"DBInstanceId": id,
+		"PayType":      v1alpha1.PayTypePrepaid,
+		"Period":       s.Period,
+		"UsedTime":     strconv.Itoa(s.UsedTime),
+		"AutoRenew":    strconv.FormatBool(s.AutoRenew),
+	}, true, rsp)
+	return rsp.OrderID.String(), err
+}
+
+// ModifyAutoRenewal enables or disables the auto-renewal of the subscription
+// of the Prepaid instance with the supplied ID.
+func (c *client) ModifyAutoRenewal(ctx context.Context, id string, s Subscription) error {
+	request := alirds.CreateModifyInstanceAutoRenewalAttributeRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return err
+	}
+
+	request.DBInstanceId = id
+	request.AutoRenew = autoRenewFalse
+	if s.AutoRenew {
+		request.AutoRenew = autoRenewTrue
+		request.Duration = strconv.Itoa(renewalMonths(s.Period))
+	}
+
+	resp, err := c.rdsCli.ModifyInstanceAutoRenewalAttribute(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
+	return err
+}
+
+// describeAutoRenewal returns true if the subscription of the Prepaid
+// instance with the supplied ID is renewed automatically.
+func (c *client) describeAutoRenewal(ctx context.Context, id string) (bool, error) {
+	request := alirds.CreateDescribeInstanceAutoRenewalAttributeRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return false, err
+	}
+
+	request.DBInstanceId = id
+
+	response, err := c.rdsCli.DescribeInstanceAutoRenewalAttribute(request)
+	if err != nil {
+		return false, err
+	}
+	for _, i := range response.Items.Item {
+		if i.DBInstanceId == id {
+			return i.AutoRenew == autoRenewTrue, nil
+		}
+	}
+	return false, nil
+}
+
+// renewalMonths returns the number of months a subscription of the supplied
+// period is renewed by.
+func renewalMonths(period string) int {
+	if period == v1alpha1.PeriodYear {
+		return monthsPerYear
+	}
+	return 1
+}
+
+// PayType returns the pay type of instances with the supplied parameters,
+// which is Postpaid unless set.
+func PayType(p *v1alpha1.RDSInstanceParameters) string {
+	if p.PayType == "" {
+		return v1alpha1.PayTypePostpaid
+	}
+	return p.PayType
+}
+
+// MakeSubscription returns the subscription of Prepaid instances with the
+// supplied parameters, which is one month unless set.
+func MakeSubscription(p *v1alpha1.RDSInstanceParameters) Subscription {
+	s := Subscription{Period: p.Period, UsedTime: p.UsedTime, AutoRenew: p.AutoRenew}
+	if s.Period == "" {
+		s.Period = v1alpha1.PeriodMonth
+	}
+	if s.UsedTime == 0 {
+		s.UsedTime = defaultUsedTime
+	}
+	return s
+}
+
+// IsBillingUpToDate returns true if an instance with the supplied observation
+// has the pay type of the supplied parameters and, if Prepaid, renews its
+// subscription as they ask. Instances whose parameters set no pay type are
+// left alone.
+func IsBillingUpToDate(p *v1alpha1.RDSInstanceParameters, o *v1alpha1.RDSInstanceObservation) bool {
+	if p.PayType == "" {
+		return true
+	}
+	if p.PayType != o.PayType {
+		return false
+	}
+	return p.PayType != v1alpha1.PayTypePrepaid || p.AutoRenew == o.AutoRenew
+}
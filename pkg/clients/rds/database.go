@@ -0,0 +1,168 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rds
+
+import (
+	"context"
+	"errors"
+
+	sdkerrors "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	alirds "github.com/aliyun/alibaba-cloud-sdk-go/services/rds"
+
+	"github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/clients/audit"
+)
+
+var (
+	// ErrDatabaseNotFound indicates that the database does not exist.
+	ErrDatabaseNotFound = errors.New("DatabaseNotFound")
+	// ErrCodeDatabaseNotFound is the error code of ServerErrors of databases
+	// that do not exist.
+	ErrCodeDatabaseNotFound = "InvalidDBName.NotFound"
+)
+
+// Database defines the information of a database of an instance.
+type Database struct {
+	Name             string
+	DBInstanceID     string
+	Engine           string
+	Status           string
+	CharacterSetName string
+	Description      string
+
+	// Endpoint and PublicEndpoint of the instance of the database.
+	Endpoint       *v1alpha1.Endpoint
+	PublicEndpoint *v1alpha1.Endpoint
+}
+
+// DescribeDatabase returns the database with the supplied name of the
+// instance with the supplied ID, along with the endpoints of the instance.
+func (c *client) DescribeDatabase(ctx context.Context, id, name string) (*Database, error) {
+	request := alirds.CreateDescribeDatabasesRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return nil, err
+	}
+
+	request.DBInstanceId = id
+	request.DBName = name
+
+	response, err := c.rdsCli.DescribeDatabases(request)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range response.Databases.Database {
+		if d.DBName != name {
+			continue
+		}
+		db := &Database{
+			Name:             d.DBName,
+			DBInstanceID:     d.DBInstanceId,
+			Engine:           d.Engine,
+			Status:           d.DBStatus,
+			CharacterSetName: d.CharacterSetName,
+			Description:      d.DBDescription,
+		}
+		db.Endpoint, db.PublicEndpoint, err = c.describeEndpoints(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	}
+	return nil, ErrDatabaseNotFound
+}
+
+// CreateDatabase creates a database with the supplied name and parameters in
+// the instance with the supplied ID.
+func (c *client) CreateDatabase(ctx context.Context, id, name string, p *v1alpha1.RDSDatabaseParameters) error {
+	request := alirds.CreateCreateDatabaseRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return err
+	}
+
+	request.DBInstanceId = id
+	request.DBName = name
+	request.CharacterSetName = p.CharacterSetName
+	request.DBDescription = p.Description
+
+	resp, err := c.rdsCli.CreateDatabase(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
+	return err
+}
+
+// ModifyDatabaseDescription changes the description of the database with the
+// supplied name of the instance with the supplied ID.
+func (c *client) ModifyDatabaseDescription(ctx context.Context, id, name, description string) error {
+	request := alirds.CreateModifyDBDescriptionRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return err
+	}
+
+	request.DBInstanceId = id
+	request.DBName = name
+	request.DBDescription = description
+
+	resp, err := c.rdsCli.ModifyDBDescription(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
+	return err
+}
+
+// DeleteDatabase deletes the database with the supplied name of the instance
+// with the supplied ID.
+func (c *client) DeleteDatabase(ctx context.Context, id, name string) error {
+	request := alirds.CreateDeleteDatabaseRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return err
+	}
+
+	request.DBInstanceId = id
+	request.DBName = name
+
+	resp, err := c.rdsCli.DeleteDatabase(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
+	return err
+}
+
+// GenerateDatabaseObservation is used to produce
+// v1alpha1.RDSDatabaseObservation from rds.Database.
+func GenerateDatabaseObservation(db *Database) v1alpha1.RDSDatabaseObservation {
+	return v1alpha1.RDSDatabaseObservation{
+		DBStatus:         db.Status,
+		DBInstanceID:     db.DBInstanceID,
+		Engine:           db.Engine,
+		CharacterSetName: db.CharacterSetName,
+	}
+}
+
+// IsDatabaseUpToDate returns true if the description of the supplied
+// database is that of the supplied parameters.
+func IsDatabaseUpToDate(p *v1alpha1.RDSDatabaseParameters, db *Database) bool {
+	return p.Description == db.Description
+}
+
+// IsDatabaseNotFound returns true if the supplied error indicates that a
+// database, or its instance, does not exist.
+func IsDatabaseNotFound(err error) bool {
+	if e, ok := err.(*sdkerrors.ServerError); ok && e.ErrorCode() == ErrCodeDatabaseNotFound {
+		return true
+	}
+	return errors.Is(err, ErrDatabaseNotFound) || IsErrorNotFound(err)
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rds
+
+import (
+	"context"
+
+	alirds "github.com/aliyun/alibaba-cloud-sdk-go/services/rds"
+
+	"github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/clients/audit"
+)
+
+const (
+	// netTypeIntranet creates instances with an intranet endpoint only.
+	netTypeIntranet = "Intranet"
+
+	// IP types of the endpoints of instances. Intranet endpoints are Private
+	// in VPCs and Inner on the classic network.
+	ipTypePublic  = "Public"
+	ipTypePrivate = "Private"
+	ipTypeInner   = "Inner"
+
+	// publicConnectionSuffix is appended to the ID of an instance to form
+	// the prefix of its public endpoint.
+	publicConnectionSuffix = "-public"
+)
+
+// describeEndpoints returns the intranet and public endpoint of the instance
+// with the supplied ID. Either is nil if the instance has none.
+func (c *client) describeEndpoints(ctx context.Context, id string) (*v1alpha1.Endpoint, *v1alpha1.Endpoint, error) {
+	request := alirds.CreateDescribeDBInstanceNetInfoRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return nil, nil, err
+	}
+
+	request.DBInstanceId = id
+
+	response, err := c.rdsCli.DescribeDBInstanceNetInfo(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	var intranet, public *v1alpha1.Endpoint
+	for _, n := range response.DBInstanceNetInfos.DBInstanceNetInfo {
+		e := &v1alpha1.Endpoint{Address: n.ConnectionString, Port: n.Port}
+		switch n.IPType {
+		case ipTypePrivate, ipTypeInner:
+			if intranet == nil {
+				intranet = e
+			}
+		case ipTypePublic:
+			public = e
+		}
+	}
+	return intranet, public, nil
+}
+
+// AllocatePublicConnection allocates a public endpoint with the supplied port
+// for the instance with the supplied ID.
+func (c *client) AllocatePublicConnection(ctx context.Context, id, port string) error {
+	request := alirds.CreateAllocateInstancePublicConnectionRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return err
+	}
+
+	request.DBInstanceId = id
+	request.ConnectionStringPrefix = id + publicConnectionSuffix
+	request.Port = port
+
+	resp, err := c.rdsCli.AllocateInstancePublicConnection(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
+	return err
+}
+
+// ReleasePublicConnection releases the supplied public endpoint of the
+// instance with the supplied ID.
+func (c *client) ReleasePublicConnection(ctx context.Context, id, address string) error {
+	request := alirds.CreateReleaseInstancePublicConnectionRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return err
+	}
+
+	request.DBInstanceId = id
+	request.CurrentConnectionString = address
+
+	resp, err := c.rdsCli.ReleaseInstancePublicConnection(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
+	return err
+}
+
+// DefaultPort returns the default port of the supplied engine.
+func DefaultPort(engine string) string {
+	if engine == v1alpha1.PostgresqlEngine {
+		return "5432"
+	}
+	return "3306"
+}
+
+// NetworkType returns the network type of the supplied parameters, which is
+// VPC if unset.
+func NetworkType(p *v1alpha1.RDSInstanceParameters) string {
+	if p.InstanceNetworkType == "" {
+		return v1alpha1.InstanceNetworkTypeVPC
+	}
+	return p.InstanceNetworkType
+}
+
+// IsPublicConnectionUpToDate returns true if the supplied instance has a
+// public endpoint exactly if the supplied parameters ask for one, or if they
+// do not ask either way.
+func IsPublicConnectionUpToDate(p *v1alpha1.RDSInstanceParameters, public *v1alpha1.Endpoint) bool {
+	return p.PubliclyAccessible == nil || *p.PubliclyAccessible == (public != nil)
+}
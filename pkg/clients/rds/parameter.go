@@ -0,0 +1,173 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rds
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	alirds "github.com/aliyun/alibaba-cloud-sdk-go/services/rds"
+
+	"github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/clients/audit"
+)
+
+// forceRestartTrue indicates that a parameter of a parameter template only
+// takes effect once the instance restarts.
+const forceRestartTrue = "true"
+
+// DescribeParameters returns the configured values of the parameters with the
+// supplied names of the instance with the supplied ID, and the names of those
+// whose configured values differ from their running ones, which are pending a
+// restart.
+func (c *client) DescribeParameters(ctx context.Context, id string, names []string) (map[string]string, []string, error) {
+	request := alirds.CreateDescribeParametersRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return nil, nil, err
+	}
+
+	request.DBInstanceId = id
+
+	response, err := c.rdsCli.DescribeParameters(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	configured := make(map[string]string, len(response.ConfigParameters.DBInstanceParameter))
+	for _, p := range response.ConfigParameters.DBInstanceParameter {
+		configured[p.ParameterName] = p.ParameterValue
+	}
+	running := make(map[string]string, len(response.RunningParameters.DBInstanceParameter))
+	for _, p := range response.RunningParameters.DBInstanceParameter {
+		running[p.ParameterName] = p.ParameterValue
+	}
+
+	params := make(map[string]string, len(names))
+	var pending []string
+	for _, name := range names {
+		v, ok := configured[name]
+		if !ok {
+			v, ok = running[name]
+		}
+		if !ok {
+			continue
+		}
+		params[name] = v
+		if r, ok := running[name]; ok && r != v {
+			pending = append(pending, name)
+		}
+	}
+	sort.Strings(pending)
+	return params, pending, nil
+}
+
+// DescribeParameterTemplates returns whether each parameter that can be
+// modified for the supplied engine version only takes effect once the
+// instance restarts.
+func (c *client) DescribeParameterTemplates(ctx context.Context, engine, engineVersion string) (map[string]bool, error) {
+	request := alirds.CreateDescribeParameterTemplatesRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return nil, err
+	}
+
+	request.Engine = engine
+	request.EngineVersion = engineVersion
+
+	response, err := c.rdsCli.DescribeParameterTemplates(request)
+	if err != nil {
+		return nil, err
+	}
+	restart := make(map[string]bool, len(response.Parameters.TemplateRecord))
+	for _, r := range response.Parameters.TemplateRecord {
+		restart[r.ParameterName] = r.ForceRestart == forceRestartTrue
+	}
+	return restart, nil
+}
+
+// ModifyParameters changes the supplied parameters of the instance with the
+// supplied ID, and restarts the instance for them to take effect if
+// forceRestart is true.
+func (c *client) ModifyParameters(ctx context.Context, id string, params map[string]string, forceRestart bool) error {
+	request := alirds.CreateModifyParameterRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	request.DBInstanceId = id
+	request.Parameters = string(b)
+	request.Forcerestart = requests.NewBoolean(forceRestart)
+
+	resp, err := c.rdsCli.ModifyParameter(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
+	return err
+}
+
+// RestartDBInstance restarts the instance with the supplied ID.
+func (c *client) RestartDBInstance(ctx context.Context, id string) error {
+	request := alirds.CreateRestartDBInstanceRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return err
+	}
+
+	request.DBInstanceId = id
+
+	resp, err := c.rdsCli.RestartDBInstance(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
+	return err
+}
+
+// ParameterNames returns the sorted names of the supplied parameters.
+func ParameterNames(params map[string]string) []string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DiffParameters returns the desired parameters whose configured values
+// differ from the supplied ones.
+func DiffParameters(desired, configured map[string]string) map[string]string {
+	diff := map[string]string{}
+	for name, v := range desired {
+		if c, ok := configured[name]; !ok || c != v {
+			diff[name] = v
+		}
+	}
+	return diff
+}
+
+// IsParametersUpToDate returns true if the supplied configured parameters
+// have the values of those of the supplied instance parameters and, if the
+// instance may be restarted, none are pending a restart.
+func IsParametersUpToDate(p *v1alpha1.RDSInstanceParameters, configured map[string]string, pending []string) bool {
+	if len(DiffParameters(p.Parameters, configured)) != 0 {
+		return false
+	}
+	return !p.AllowRestart || len(pending) == 0
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rds
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
+)
+
+func TestDiffParameters(t *testing.T) {
+	cases := map[string]struct {
+		reason     string
+		desired    map[string]string
+		configured map[string]string
+		want       map[string]string
+	}{
+		"UpToDate": {
+			reason:     "Parameters with their desired values should not be changed",
+			desired:    map[string]string{"max_connections": "100"},
+			configured: map[string]string{"max_connections": "100", "shared_buffers": "128MB"},
+			want:       map[string]string{},
+		},
+		"Changed": {
+			reason:     "Parameters whose values differ should be changed",
+			desired:    map[string]string{"max_connections": "200", "shared_buffers": "128MB"},
+			configured: map[string]string{"max_connections": "100", "shared_buffers": "128MB"},
+			want:       map[string]string{"max_connections": "200"},
+		},
+		"NotConfigured": {
+			reason:     "Parameters that are not configured should be changed",
+			desired:    map[string]string{"log_min_duration_statement": "1000"},
+			configured: map[string]string{"max_connections": "100"},
+			want:       map[string]string{"log_min_duration_statement": "1000"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := DiffParameters(tc.desired, tc.configured)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nDiffParameters(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIsParametersUpToDate(t *testing.T) {
+	cases := map[string]struct {
+		reason     string
+		p          v1alpha1.RDSInstanceParameters
+		configured map[string]string
+		pending    []string
+		want       bool
+	}{
+		"NoParameters": {
+			reason: "Instances whose spec sets no parameters should be up to date",
+			want:   true,
+		},
+		"Changed": {
+			reason:     "Parameters whose values differ should not be up to date",
+			p:          v1alpha1.RDSInstanceParameters{Parameters: map[string]string{"max_connections": "200"}},
+			configured: map[string]string{"max_connections": "100"},
+			want:       false,
+		},
+		"PendingRestart": {
+			reason:     "Parameters pending a restart should be up to date unless the instance may be restarted",
+			p:          v1alpha1.RDSInstanceParameters{Parameters: map[string]string{"shared_buffers": "256MB"}},
+			configured: map[string]string{"shared_buffers": "256MB"},
+			pending:    []string{"shared_buffers"},
+			want:       true,
+		},
+		"PendingRestartAllowed": {
+			reason:     "Parameters pending a restart should not be up to date if the instance may be restarted",
+			p:          v1alpha1.RDSInstanceParameters{Parameters: map[string]string{"shared_buffers": "256MB"}, AllowRestart: true},
+			configured: map[string]string{"shared_buffers": "256MB"},
+			pending:    []string{"shared_buffers"},
+			want:       false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsParametersUpToDate(&tc.p, tc.configured, tc.pending)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIsParametersUpToDate(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -18,8 +18,10 @@ package rds
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
-	"time"
+	"strconv"
+	"strings"
 
 	sdkerrors "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
 
@@ -27,6 +29,9 @@ import (
 	alirds "github.com/aliyun/alibaba-cloud-sdk-go/services/rds"
 
 	"github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/clients/audit"
+	"github.com/crossplane/provider-alibaba/pkg/clients/bss"
 )
 
 var (
@@ -38,14 +43,62 @@ var (
 
 const (
 	httpsScheme = "https"
+
+	// auditService is the service RDS API calls are audited as.
+	auditService = "rds"
+
+	// listPageSize is the maximum page size of DescribeDBInstances.
+	listPageSize = 100
+
+	// StorageIncrementGB is the increment in which the storage of instances
+	// is sized.
+	StorageIncrementGB = 5
+
+	// QuotaProductCode is the Quota Center product code of RDS.
+	QuotaProductCode = "rds"
+
+	// BSS product code and types of pay-as-you-go and subscription RDS
+	// instances.
+	priceProductCode             = "rds"
+	pricePayAsYouGoProductType   = "bards"
+	priceSubscriptionProductType = "rds"
 )
 
 // Client defines RDS client operations
 type Client interface {
-	DescribeDBInstance(id string) (*DBInstance, error)
-	CreateAccount(id, username, password string) error
-	CreateDBInstance(*CreateDBInstanceRequest) (*DBInstance, error)
-	DeleteDBInstance(id string) error
+	DescribeDBInstance(ctx context.Context, id string) (*DBInstance, error)
+	ListDBInstances(ctx context.Context, tags map[string]string) ([]DBInstance, error)
+	CreateDBInstance(ctx context.Context, req *CreateDBInstanceRequest) (*DBInstance, error)
+	DeleteDBInstance(ctx context.Context, id string) error
+	ModifyDBInstanceSpec(ctx context.Context, id string, req *ModifyDBInstanceSpecRequest) error
+	ModifySecurityIPs(ctx context.Context, id string, g SecurityIPGroup) error
+	AllocatePublicConnection(ctx context.Context, id, port string) error
+	ReleasePublicConnection(ctx context.Context, id, address string) error
+	UpgradeDBInstanceEngineVersion(ctx context.Context, id, engineVersion string) error
+	PrecheckMajorVersionUpgrade(ctx context.Context, id, targetVersion string) (string, error)
+	DescribeMajorVersionUpgradePrecheck(ctx context.Context, id, taskID string) (*UpgradePrecheck, error)
+	UpgradeDBInstanceMajorVersion(ctx context.Context, id string, req *UpgradeMajorVersionRequest) (*DBInstance, error)
+	TransformDBInstancePayType(ctx context.Context, id string, s Subscription) (string, error)
+	ModifyAutoRenewal(ctx context.Context, id string, s Subscription) error
+	DescribeAvailableClasses(ctx context.Context, engine, engineVersion, payType string) (clients.Availability, error)
+	DescribeDatabase(ctx context.Context, id, name string) (*Database, error)
+	CreateDatabase(ctx context.Context, id, name string, p *v1alpha1.RDSDatabaseParameters) error
+	ModifyDatabaseDescription(ctx context.Context, id, name, description string) error
+	DeleteDatabase(ctx context.Context, id, name string) error
+	DescribeAccount(ctx context.Context, id, name string) (*Account, error)
+	CreateAccount(ctx context.Context, id, username, password string, p *v1alpha1.RDSAccountParameters) error
+	GrantAccountPrivilege(ctx context.Context, id, name string, privileges []v1alpha1.AccountPrivilege) error
+	RevokeAccountPrivilege(ctx context.Context, id, name string, databases []string) error
+	ResetAccountPassword(ctx context.Context, id, name, password string) error
+	DeleteAccount(ctx context.Context, id, name string) error
+	CreateReadOnlyDBInstance(ctx context.Context, req *CreateReadOnlyDBInstanceRequest) (*DBInstance, error)
+	DescribeBackupPolicy(ctx context.Context, id string, crossRegion bool) (*v1alpha1.BackupPolicy, error)
+	ModifyBackupPolicy(ctx context.Context, id string, bp *v1alpha1.BackupPolicy) error
+	ModifyCrossRegionBackup(ctx context.Context, id string, cb *v1alpha1.CrossRegionBackup) error
+	DescribeParameters(ctx context.Context, id string, names []string) (map[string]string, []string, error)
+	DescribeParameterTemplates(ctx context.Context, engine, engineVersion string) (map[string]bool, error)
+	ModifyParameters(ctx context.Context, id string, params map[string]string, forceRestart bool) error
+	RestartDBInstance(ctx context.Context, id string) error
 }
 
 // DBInstance defines the DB instance information
@@ -59,8 +112,65 @@ type DBInstance struct {
 	// Instance status
 	Status string
 
-	// Endpoint specifies the connection endpoint.
+	// Endpoint specifies the intranet connection endpoint.
 	Endpoint *v1alpha1.Endpoint
+
+	// PublicEndpoint specifies the public connection endpoint, if any. Only
+	// set by DescribeDBInstance.
+	PublicEndpoint *v1alpha1.Endpoint
+
+	// OrderID of the order that created the instance. Only set by
+	// CreateDBInstance.
+	OrderID string
+
+	// The following fields are only set by DescribeDBInstance and
+	// ListDBInstances.
+
+	// Instance description, used as its name
+	Description string
+
+	// Database engine version
+	EngineVersion string
+
+	// Instance class
+	DBInstanceClass string
+
+	// Storage size in GB
+	DBInstanceStorageInGB int
+
+	// IP whitelist
+	SecurityIPList string
+
+	// IP whitelist groups. Only set by DescribeDBInstance.
+	SecurityIPGroups []SecurityIPGroup
+
+	// Network type, zone, VPC and vSwitch of the instance
+	InstanceNetworkType string
+	ZoneID              string
+	VPCID               string
+	VSwitchID           string
+
+	// Pay type of the instance, and when the subscription of a Prepaid
+	// instance expires
+	PayType    string
+	ExpireTime string
+
+	// Whether the subscription of a Prepaid instance is renewed
+	// automatically. Only set by DescribeDBInstance.
+	AutoRenew bool
+
+	// ID of the primary instance of a read-only instance, and how many
+	// seconds its data lags behind that of the primary instance
+	MasterInstanceID string
+	ReadDelaySeconds int
+
+	// Backup policy. Only set if described by DescribeBackupPolicy.
+	BackupPolicy *v1alpha1.BackupPolicy
+
+	// Configured values of parameters of the engine, and the parameters
+	// pending a restart. Only set if described by DescribeParameters.
+	Parameters               map[string]string
+	PendingRestartParameters []string
 }
 
 // CreateDBInstanceRequest defines the request info to create DB Instance
@@ -71,6 +181,21 @@ type CreateDBInstanceRequest struct {
 	SecurityIPList        string
 	DBInstanceClass       string
 	DBInstanceStorageInGB int
+	InstanceNetworkType   string
+	VPCID                 string
+	VSwitchID             string
+	ZoneID                string
+	PrivateIPAddress      string
+	PayType               string
+	Subscription          Subscription
+}
+
+// ModifyDBInstanceSpecRequest defines the request info to resize a DB
+// Instance of the supplied pay type. Empty fields are not changed.
+type ModifyDBInstanceSpecRequest struct {
+	PayType               string
+	DBInstanceClass       string
+	DBInstanceStorageInGB int
 }
 
 type client struct {
@@ -96,32 +221,110 @@ func NewClient(ctx context.Context, accessKeyID, accessKeySecret, securityToken,
 	return c, nil
 }
 
-func (c *client) DescribeDBInstance(id string) (*DBInstance, error) {
-	request := alirds.CreateDescribeDBInstancesRequest()
+func (c *client) DescribeDBInstance(ctx context.Context, id string) (*DBInstance, error) {
+	db, err := c.describeDBInstanceAttribute(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	db.SecurityIPGroups, err = c.DescribeSecurityIPGroups(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	db.Endpoint, db.PublicEndpoint, err = c.describeEndpoints(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if db.PayType == v1alpha1.PayTypePrepaid {
+		if db.AutoRenew, err = c.describeAutoRenewal(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// ListDBInstances returns all DB instances of the region that have all of the
+// supplied tags.
+func (c *client) ListDBInstances(ctx context.Context, tags map[string]string) ([]DBInstance, error) {
+	var filter string
+	if len(tags) > 0 {
+		b, err := json.Marshal(tags)
+		if err != nil {
+			return nil, err
+		}
+		filter = string(b)
+	}
+
+	var instances []DBInstance
+	for page := 1; ; page++ {
+		request := alirds.CreateDescribeDBInstancesRequest()
+		request.Scheme = httpsScheme
+		if err := clients.WithTimeout(ctx, request); err != nil {
+			return nil, err
+		}
+		request.Tags = filter
+		request.PageSize = requests.NewInteger(listPageSize)
+		request.PageNumber = requests.NewInteger(page)
+
+		response, err := c.rdsCli.DescribeDBInstances(request)
+		if err != nil {
+			return nil, err
+		}
+		for _, rsp := range response.Items.DBInstance {
+			in, err := c.describeDBInstanceAttribute(ctx, rsp.DBInstanceId)
+			if err != nil {
+				return nil, err
+			}
+			instances = append(instances, *in)
+		}
+		if len(response.Items.DBInstance) < listPageSize {
+			return instances, nil
+		}
+	}
+}
+
+func (c *client) describeDBInstanceAttribute(ctx context.Context, id string) (*DBInstance, error) {
+	request := alirds.CreateDescribeDBInstanceAttributeRequest()
 	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return nil, err
+	}
 
 	request.DBInstanceId = id
 
-	response, err := c.rdsCli.DescribeDBInstances(request)
+	response, err := c.rdsCli.DescribeDBInstanceAttribute(request)
 	if err != nil {
 		return nil, err
 	}
-	if len(response.Items.DBInstance) == 0 {
+	if len(response.Items.DBInstanceAttribute) == 0 {
 		return nil, ErrDBInstanceNotFound
 	}
-	rsp := response.Items.DBInstance[0]
-	in := &DBInstance{
-		ID:     rsp.DBInstanceId,
-		Engine: rsp.Engine,
-		Status: rsp.DBInstanceStatus,
-	}
-
-	return in, nil
+	rsp := response.Items.DBInstanceAttribute[0]
+	return &DBInstance{
+		ID:                    rsp.DBInstanceId,
+		Engine:                rsp.Engine,
+		Status:                rsp.DBInstanceStatus,
+		Description:           rsp.DBInstanceDescription,
+		EngineVersion:         rsp.EngineVersion,
+		DBInstanceClass:       rsp.DBInstanceClass,
+		DBInstanceStorageInGB: rsp.DBInstanceStorage,
+		SecurityIPList:        rsp.SecurityIPList,
+		InstanceNetworkType:   rsp.InstanceNetworkType,
+		ZoneID:                rsp.ZoneId,
+		VPCID:                 rsp.VpcId,
+		VSwitchID:             rsp.VSwitchId,
+		PayType:               rsp.PayType,
+		ExpireTime:            rsp.ExpireTime,
+		MasterInstanceID:      rsp.MasterInstanceId,
+		ReadDelaySeconds:      readDelaySeconds(rsp.ReadDelayTime),
+	}, nil
 }
 
-func (c *client) CreateDBInstance(req *CreateDBInstanceRequest) (*DBInstance, error) {
+func (c *client) CreateDBInstance(ctx context.Context, req *CreateDBInstanceRequest) (*DBInstance, error) {
 	request := alirds.CreateCreateDBInstanceRequest()
 	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return nil, err
+	}
 
 	request.DBInstanceDescription = req.Name
 	request.Engine = req.Engine
@@ -129,18 +332,29 @@ func (c *client) CreateDBInstance(req *CreateDBInstanceRequest) (*DBInstance, er
 	request.DBInstanceClass = req.DBInstanceClass
 	request.DBInstanceStorage = requests.NewInteger(req.DBInstanceStorageInGB)
 	request.SecurityIPList = req.SecurityIPList
-	request.DBInstanceNetType = "Internet"
-	request.PayType = "Postpaid"
-	request.ReadTimeout = 60 * time.Second
+	request.DBInstanceNetType = netTypeIntranet
+	request.InstanceNetworkType = req.InstanceNetworkType
+	request.VPCId = req.VPCID
+	request.VSwitchId = req.VSwitchID
+	request.ZoneId = req.ZoneID
+	request.PrivateIpAddress = req.PrivateIPAddress
+	request.PayType = req.PayType
+	if req.PayType == v1alpha1.PayTypePrepaid {
+		request.Period = req.Subscription.Period
+		request.UsedTime = strconv.Itoa(req.Subscription.UsedTime)
+		request.AutoRenew = strconv.FormatBool(req.Subscription.AutoRenew)
+	}
 	request.ClientToken = req.Name
 
 	resp, err := c.rdsCli.CreateDBInstance(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
 	if err != nil {
 		return nil, err
 	}
 
 	return &DBInstance{
-		ID: resp.DBInstanceId,
+		ID:      resp.DBInstanceId,
+		OrderID: resp.OrderId,
 		Endpoint: &v1alpha1.Endpoint{
 			Address: resp.ConnectionString,
 			Port:    resp.Port,
@@ -148,28 +362,77 @@ func (c *client) CreateDBInstance(req *CreateDBInstanceRequest) (*DBInstance, er
 	}, nil
 }
 
-func (c *client) CreateAccount(id, user, pw string) error {
-	request := alirds.CreateCreateAccountRequest()
+func (c *client) DeleteDBInstance(ctx context.Context, id string) error {
+	request := alirds.CreateDeleteDBInstanceRequest()
 	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return err
+	}
+
 	request.DBInstanceId = id
-	request.AccountName = user
-	request.AccountPassword = pw
-	request.ReadTimeout = 60 * time.Second
 
-	_, err := c.rdsCli.CreateAccount(request)
+	resp, err := c.rdsCli.DeleteDBInstance(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
 	return err
 }
 
-func (c *client) DeleteDBInstance(id string) error {
-	request := alirds.CreateDeleteDBInstanceRequest()
+// ModifyDBInstanceSpec resizes the instance with the supplied ID. The instance
+// is DBInstanceClassChanging until the resize completes.
+func (c *client) ModifyDBInstanceSpec(ctx context.Context, id string, req *ModifyDBInstanceSpecRequest) error {
+	request := alirds.CreateModifyDBInstanceSpecRequest()
 	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return err
+	}
 
 	request.DBInstanceId = id
+	request.PayType = req.PayType
+	request.DBInstanceClass = req.DBInstanceClass
+	if req.DBInstanceStorageInGB > 0 {
+		request.DBInstanceStorage = requests.NewInteger(req.DBInstanceStorageInGB)
+	}
 
-	_, err := c.rdsCli.DeleteDBInstance(request)
+	resp, err := c.rdsCli.ModifyDBInstanceSpec(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
 	return err
 }
 
+// DescribeAvailableClasses returns the instance classes of the supplied
+// engine version that are available for instances of the supplied pay type in
+// each zone of the region.
+func (c *client) DescribeAvailableClasses(ctx context.Context, engine, engineVersion, payType string) (clients.Availability, error) {
+	request := alirds.CreateDescribeAvailableResourceRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return nil, err
+	}
+
+	request.Engine = engine
+	request.EngineVersion = engineVersion
+	request.InstanceChargeType = payType
+
+	response, err := c.rdsCli.DescribeAvailableResource(request)
+	if err != nil {
+		return nil, err
+	}
+	a := clients.Availability{}
+	for _, z := range response.AvailableZones.AvailableZone {
+		a[z.ZoneId] = nil
+		for _, e := range z.SupportedEngines.SupportedEngine {
+			for _, v := range e.SupportedEngineVersions.SupportedEngineVersion {
+				for _, cat := range v.SupportedCategorys.SupportedCategory {
+					for _, st := range cat.SupportedStorageTypes.SupportedStorageType {
+						for _, r := range st.AvailableResources.AvailableResource {
+							a[z.ZoneId] = append(a[z.ZoneId], r.DBInstanceClass)
+						}
+					}
+				}
+			}
+		}
+	}
+	return a, nil
+}
+
 // LateInitialize fills the empty fields in *v1alpha1.RDSInstanceParameters with
 // the values seen in rds.DBInstance.
 func LateInitialize(in *v1alpha1.RDSInstanceParameters, db *DBInstance) {
@@ -180,20 +443,127 @@ func LateInitialize(in *v1alpha1.RDSInstanceParameters, db *DBInstance) {
 // rds.DBInstance.
 func GenerateObservation(db *DBInstance) v1alpha1.RDSInstanceObservation {
 	return v1alpha1.RDSInstanceObservation{
-		DBInstanceStatus: db.Status,
-		DBInstanceID:     db.ID,
+		DBInstanceStatus:         db.Status,
+		DBInstanceID:             db.ID,
+		EngineVersion:            db.EngineVersion,
+		DBInstanceClass:          db.DBInstanceClass,
+		DBInstanceStorageInGB:    db.DBInstanceStorageInGB,
+		InstanceNetworkType:      db.InstanceNetworkType,
+		ZoneID:                   db.ZoneID,
+		VPCID:                    db.VPCID,
+		VSwitchID:                db.VSwitchID,
+		PayType:                  db.PayType,
+		ExpireTime:               db.ExpireTime,
+		AutoRenew:                db.AutoRenew,
+		Endpoint:                 db.Endpoint,
+		PublicEndpoint:           db.PublicEndpoint,
+		SecurityIPGroups:         securityIPGroupObservations(db.SecurityIPGroups),
+		BackupPolicy:             db.BackupPolicy,
+		Parameters:               db.Parameters,
+		PendingRestartParameters: db.PendingRestartParameters,
+	}
+}
+
+// IsUpToDate returns true if the engine version, class, storage, whitelist
+// groups, public endpoint, billing, backup policy and engine parameters of the
+// supplied instance are those of the supplied parameters and IPs read from
+// ConfigMaps.
+func IsUpToDate(p *v1alpha1.RDSInstanceParameters, ipsFrom map[string][]string, db *DBInstance) bool {
+	o := &v1alpha1.RDSInstanceObservation{
+		SecurityIPGroups: securityIPGroupObservations(db.SecurityIPGroups),
+		PayType:          db.PayType,
+		AutoRenew:        db.AutoRenew,
 	}
+	return SameEngineVersion(p.EngineVersion, db.EngineVersion) && p.DBInstanceClass == db.DBInstanceClass && p.DBInstanceStorageInGB == db.DBInstanceStorageInGB &&
+		len(MakeModifySecurityIPsRequests(p, ipsFrom, o)) == 0 && IsPublicConnectionUpToDate(p, db.PublicEndpoint) &&
+		IsBillingUpToDate(p, o) && IsBackupPolicyUpToDate(p.BackupPolicy, db.BackupPolicy) &&
+		IsCrossRegionBackupUpToDate(p.BackupPolicy, db.BackupPolicy) &&
+		IsParametersUpToDate(p, db.Parameters, db.PendingRestartParameters)
 }
 
-// MakeCreateDBInstanceRequest generates CreateDBInstanceRequest
-func MakeCreateDBInstanceRequest(name string, p *v1alpha1.RDSInstanceParameters) *CreateDBInstanceRequest {
+// MakeModifyDBInstanceSpecRequest generates the ModifyDBInstanceSpecRequest
+// that resizes an instance with the supplied observation to the supplied
+// parameters. It returns nil if the instance needs no resize. Instances are
+// resized with their current pay type.
+func MakeModifyDBInstanceSpecRequest(p *v1alpha1.RDSInstanceParameters, o *v1alpha1.RDSInstanceObservation) *ModifyDBInstanceSpecRequest {
+	req := &ModifyDBInstanceSpecRequest{}
+	if p.DBInstanceClass != o.DBInstanceClass {
+		req.DBInstanceClass = p.DBInstanceClass
+	}
+	if p.DBInstanceStorageInGB != o.DBInstanceStorageInGB {
+		req.DBInstanceStorageInGB = p.DBInstanceStorageInGB
+	}
+	if *req == (ModifyDBInstanceSpecRequest{}) {
+		return nil
+	}
+	req.PayType = o.PayType
+	if req.PayType == "" {
+		req.PayType = v1alpha1.PayTypePostpaid
+	}
+	return req
+}
+
+// GenerateParameters is used to produce v1alpha1.RDSInstanceParameters from
+// an rds.DBInstance returned by ListDBInstances.
+func GenerateParameters(db *DBInstance) v1alpha1.RDSInstanceParameters {
+	return v1alpha1.RDSInstanceParameters{
+		Engine:                db.Engine,
+		EngineVersion:         db.EngineVersion,
+		DBInstanceClass:       db.DBInstanceClass,
+		DBInstanceStorageInGB: db.DBInstanceStorageInGB,
+		SecurityIPList:        db.SecurityIPList,
+		InstanceNetworkType:   db.InstanceNetworkType,
+		VPCID:                 db.VPCID,
+		VSwitchID:             db.VSwitchID,
+		ZoneID:                db.ZoneID,
+		PayType:               db.PayType,
+	}
+}
+
+// MakeCreateDBInstanceRequest generates CreateDBInstanceRequest. Instances are
+// created with the IPs of their default whitelist group; other groups are
+// added once they are running.
+func MakeCreateDBInstanceRequest(name string, p *v1alpha1.RDSInstanceParameters, ipsFrom map[string][]string) *CreateDBInstanceRequest {
+	ips := noAccessIP
+	for _, g := range SecurityIPGroups(p, ipsFrom) {
+		if g.Name == v1alpha1.DefaultSecurityIPGroup {
+			ips = strings.Join(g.IPs, ",")
+		}
+	}
 	return &CreateDBInstanceRequest{
 		Name:                  name,
 		Engine:                p.Engine,
 		EngineVersion:         p.EngineVersion,
-		SecurityIPList:        p.SecurityIPList,
+		SecurityIPList:        ips,
 		DBInstanceClass:       p.DBInstanceClass,
 		DBInstanceStorageInGB: p.DBInstanceStorageInGB,
+		InstanceNetworkType:   NetworkType(p),
+		VPCID:                 p.VPCID,
+		VSwitchID:             p.VSwitchID,
+		ZoneID:                p.ZoneID,
+		PrivateIPAddress:      p.PrivateIPAddress,
+		PayType:               PayType(p),
+		Subscription:          MakeSubscription(p),
+	}
+}
+
+// MakePriceRequest generates the BSS price request of an instance with the
+// supplied parameters in the supplied region.
+func MakePriceRequest(p *v1alpha1.RDSInstanceParameters, region string) bss.PriceRequest {
+	productType := pricePayAsYouGoProductType
+	if PayType(p) == v1alpha1.PayTypePrepaid {
+		productType = priceSubscriptionProductType
+	}
+	return bss.PriceRequest{
+		ProductCode:  priceProductCode,
+		ProductType:  productType,
+		Region:       region,
+		Subscription: PayType(p) == v1alpha1.PayTypePrepaid,
+		Modules: []bss.Module{
+			{Code: "DBInstanceClass", Config: bss.Config("DBInstanceClass", p.DBInstanceClass, "EngineVersion", p.EngineVersion, "Region", region)},
+			{Code: "DBInstanceStorage", Config: bss.Config("DBInstanceStorage", strconv.Itoa(p.DBInstanceStorageInGB))},
+			{Code: "Engine", Config: bss.Config("Engine", p.Engine)},
+		},
 	}
 }
 
@@ -27,3 +27,68 @@ func TestIsErrorNotFound(t *testing.T) {
 		t.Errorf("IsErrorNotFound: want=%v, get=%v", true, isErrorNotFound)
 	}
 }
+
+func TestMakePriceRequest(t *testing.T) {
+	req := MakePriceRequest(&v1alpha1.RDSInstanceParameters{Engine: "MySQL", EngineVersion: "8.0", DBInstanceClass: "rds.mysql.s1.small", DBInstanceStorageInGB: 20}, "cn-beijing")
+	if req.Subscription {
+		t.Errorf("Subscription: want=%v, get=%v", false, req.Subscription)
+	}
+	want := []string{
+		"DBInstanceClass:rds.mysql.s1.small,EngineVersion:8.0,Region:cn-beijing",
+		"DBInstanceStorage:20",
+		"Engine:MySQL",
+	}
+	for i := range want {
+		if i >= len(req.Modules) || req.Modules[i].Config != want[i] {
+			t.Errorf("Modules[%d]: want=%v, get=%v", i, want[i], req.Modules)
+		}
+	}
+}
+
+func TestIsUpToDate(t *testing.T) {
+	p := &v1alpha1.RDSInstanceParameters{EngineVersion: "10.0", DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 20}
+	if !IsUpToDate(p, nil, &DBInstance{EngineVersion: "10.0", DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 20}) {
+		t.Errorf("IsUpToDate: want=%v, get=%v", true, false)
+	}
+	if IsUpToDate(p, nil, &DBInstance{EngineVersion: "10.0", DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 25}) {
+		t.Errorf("IsUpToDate: want=%v, get=%v", false, true)
+	}
+	if IsUpToDate(p, nil, &DBInstance{EngineVersion: "9.4", DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 20}) {
+		t.Errorf("IsUpToDate: want=%v, get=%v", false, true)
+	}
+	p.PayType, p.AutoRenew = v1alpha1.PayTypePrepaid, true
+	if IsUpToDate(p, nil, &DBInstance{EngineVersion: "10.0", DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 20, PayType: v1alpha1.PayTypePostpaid}) {
+		t.Errorf("IsUpToDate: want=%v, get=%v", false, true)
+	}
+	if IsUpToDate(p, nil, &DBInstance{EngineVersion: "10.0", DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 20, PayType: v1alpha1.PayTypePrepaid}) {
+		t.Errorf("IsUpToDate: want=%v, get=%v", false, true)
+	}
+}
+
+func TestMakeModifyDBInstanceSpecRequest(t *testing.T) {
+	p := &v1alpha1.RDSInstanceParameters{DBInstanceClass: "rds.pg.s2.large", DBInstanceStorageInGB: 20}
+	o := &v1alpha1.RDSInstanceObservation{DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 20}
+	req := MakeModifyDBInstanceSpecRequest(p, o)
+	want := ModifyDBInstanceSpecRequest{PayType: v1alpha1.PayTypePostpaid, DBInstanceClass: "rds.pg.s2.large"}
+	if req == nil || *req != want {
+		t.Errorf("MakeModifyDBInstanceSpecRequest: want=%v, get=%v", want, req)
+	}
+	o.DBInstanceClass = p.DBInstanceClass
+	if req := MakeModifyDBInstanceSpecRequest(p, o); req != nil {
+		t.Errorf("MakeModifyDBInstanceSpecRequest: want=%v, get=%v", nil, req)
+	}
+}
+
+func TestMakeCreateDBInstanceRequest(t *testing.T) {
+	p := &v1alpha1.RDSInstanceParameters{SecurityIPList: "10.0.0.2,10.0.0.1", VPCID: "vpc-test", VSwitchID: "vsw-test"}
+	req := MakeCreateDBInstanceRequest("test", p, nil)
+	if req.InstanceNetworkType != v1alpha1.InstanceNetworkTypeVPC {
+		t.Errorf("InstanceNetworkType: want=%v, get=%v", v1alpha1.InstanceNetworkTypeVPC, req.InstanceNetworkType)
+	}
+	if req.SecurityIPList != "10.0.0.1,10.0.0.2" {
+		t.Errorf("SecurityIPList: want=%v, get=%v", "10.0.0.1,10.0.0.2", req.SecurityIPList)
+	}
+	if req := MakeCreateDBInstanceRequest("test", &v1alpha1.RDSInstanceParameters{}, nil); req.SecurityIPList != noAccessIP {
+		t.Errorf("SecurityIPList: want=%v, get=%v", noAccessIP, req.SecurityIPList)
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rds
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	alirds "github.com/aliyun/alibaba-cloud-sdk-go/services/rds"
+
+	"github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/clients/audit"
+)
+
+// CreateReadOnlyDBInstanceRequest defines the request info to create a
+// read-only replica of a DB instance.
+type CreateReadOnlyDBInstanceRequest struct {
+	Name                  string
+	PrimaryDBInstanceID   string
+	EngineVersion         string
+	DBInstanceClass       string
+	DBInstanceStorageInGB int
+	InstanceNetworkType   string
+	ZoneID                string
+	VPCID                 string
+	VSwitchID             string
+}
+
+// CreateReadOnlyDBInstance creates a Postpaid read-only replica of the primary
+// instance of the supplied request.
+func (c *client) CreateReadOnlyDBInstance(ctx context.Context, req *CreateReadOnlyDBInstanceRequest) (*DBInstance, error) {
+	request := alirds.CreateCreateReadOnlyDBInstanceRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return nil, err
+	}
+
+	request.DBInstanceId = req.PrimaryDBInstanceID
+	request.DBInstanceDescription = req.Name
+	request.EngineVersion = req.EngineVersion
+	request.DBInstanceClass = req.DBInstanceClass
+	request.DBInstanceStorage = requests.NewInteger(req.DBInstanceStorageInGB)
+	request.InstanceNetworkType = req.InstanceNetworkType
+	request.ZoneId = req.ZoneID
+	request.VPCId = req.VPCID
+	request.VSwitchId = req.VSwitchID
+	request.PayType = v1alpha1.PayTypePostpaid
+	request.ClientToken = req.Name
+
+	resp, err := c.rdsCli.CreateReadOnlyDBInstance(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DBInstance{
+		ID:               resp.DBInstanceId,
+		OrderID:          resp.OrderId,
+		MasterInstanceID: req.PrimaryDBInstanceID,
+		Endpoint: &v1alpha1.Endpoint{
+			Address: resp.ConnectionString,
+			Port:    resp.Port,
+		},
+	}, nil
+}
+
+// MakeCreateReadOnlyDBInstanceRequest generates a request to create a replica
+// with the supplied name and parameters of the supplied primary instance. The
+// replica runs the engine version and is in the network of the primary
+// instance, and in its zone and vSwitch unless the parameters set others.
+func MakeCreateReadOnlyDBInstanceRequest(name string, p *v1alpha1.RDSReadOnlyInstanceParameters, primary *DBInstance) *CreateReadOnlyDBInstanceRequest {
+	req := &CreateReadOnlyDBInstanceRequest{
+		Name:                  name,
+		PrimaryDBInstanceID:   primary.ID,
+		EngineVersion:         primary.EngineVersion,
+		DBInstanceClass:       p.DBInstanceClass,
+		DBInstanceStorageInGB: p.DBInstanceStorageInGB,
+		InstanceNetworkType:   primary.InstanceNetworkType,
+		ZoneID:                primary.ZoneID,
+		VPCID:                 primary.VPCID,
+		VSwitchID:             primary.VSwitchID,
+	}
+	if p.ZoneID != "" {
+		req.ZoneID = p.ZoneID
+	}
+	if p.VSwitchID != "" {
+		req.VSwitchID = p.VSwitchID
+	}
+	return req
+}
+
+// MakeModifyReadOnlyDBInstanceSpecRequest generates a request to resize a
+// replica with the supplied observation to the class and storage of the
+// supplied parameters, or returns nil if it has them.
+func MakeModifyReadOnlyDBInstanceSpecRequest(p *v1alpha1.RDSReadOnlyInstanceParameters, o *v1alpha1.RDSReadOnlyInstanceObservation) *ModifyDBInstanceSpecRequest {
+	return MakeModifyDBInstanceSpecRequest(
+		&v1alpha1.RDSInstanceParameters{DBInstanceClass: p.DBInstanceClass, DBInstanceStorageInGB: p.DBInstanceStorageInGB},
+		&v1alpha1.RDSInstanceObservation{DBInstanceClass: o.DBInstanceClass, DBInstanceStorageInGB: o.DBInstanceStorageInGB},
+	)
+}
+
+// GenerateReadOnlyObservation is used to produce
+// v1alpha1.RDSReadOnlyInstanceObservation from rds.DBInstance.
+func GenerateReadOnlyObservation(db *DBInstance) v1alpha1.RDSReadOnlyInstanceObservation {
+	return v1alpha1.RDSReadOnlyInstanceObservation{
+		DBInstanceStatus:        db.Status,
+		DBInstanceID:            db.ID,
+		PrimaryDBInstanceID:     db.MasterInstanceID,
+		EngineVersion:           db.EngineVersion,
+		DBInstanceClass:         db.DBInstanceClass,
+		DBInstanceStorageInGB:   db.DBInstanceStorageInGB,
+		ZoneID:                  db.ZoneID,
+		ReplicationDelaySeconds: db.ReadDelaySeconds,
+		Endpoint:                db.Endpoint,
+	}
+}
+
+// IsReadOnlyUpToDate returns true if the supplied replica has the class and
+// storage of the supplied parameters.
+func IsReadOnlyUpToDate(p *v1alpha1.RDSReadOnlyInstanceParameters, db *DBInstance) bool {
+	return p.DBInstanceClass == db.DBInstanceClass && p.DBInstanceStorageInGB == db.DBInstanceStorageInGB
+}
+
+// readDelaySeconds parses the replication delay of a read-only instance,
+// which is empty for other instances.
+func readDelaySeconds(s string) int {
+	d, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+	return d
+}
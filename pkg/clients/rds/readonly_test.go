@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rds
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
+)
+
+func TestMakeCreateReadOnlyDBInstanceRequest(t *testing.T) {
+	primary := &DBInstance{
+		ID:                  "primary",
+		EngineVersion:       "8.0",
+		InstanceNetworkType: v1alpha1.InstanceNetworkTypeVPC,
+		ZoneID:              "cn-hangzhou-h",
+		VPCID:               "vpc-test",
+		VSwitchID:           "vsw-h",
+	}
+
+	cases := map[string]struct {
+		reason string
+		p      v1alpha1.RDSReadOnlyInstanceParameters
+		want   *CreateReadOnlyDBInstanceRequest
+	}{
+		"PrimaryNetwork": {
+			reason: "Replicas should be created in the zone and vSwitch of their primary instance by default",
+			p:      v1alpha1.RDSReadOnlyInstanceParameters{DBInstanceClass: "mysql.n2.medium.1", DBInstanceStorageInGB: 20},
+			want: &CreateReadOnlyDBInstanceRequest{
+				Name:                  "replica",
+				PrimaryDBInstanceID:   "primary",
+				EngineVersion:         "8.0",
+				DBInstanceClass:       "mysql.n2.medium.1",
+				DBInstanceStorageInGB: 20,
+				InstanceNetworkType:   v1alpha1.InstanceNetworkTypeVPC,
+				ZoneID:                "cn-hangzhou-h",
+				VPCID:                 "vpc-test",
+				VSwitchID:             "vsw-h",
+			},
+		},
+		"OwnZone": {
+			reason: "Replicas should be created in their own zone and vSwitch if set",
+			p:      v1alpha1.RDSReadOnlyInstanceParameters{DBInstanceClass: "mysql.n2.medium.1", DBInstanceStorageInGB: 20, ZoneID: "cn-hangzhou-i", VSwitchID: "vsw-i"},
+			want: &CreateReadOnlyDBInstanceRequest{
+				Name:                  "replica",
+				PrimaryDBInstanceID:   "primary",
+				EngineVersion:         "8.0",
+				DBInstanceClass:       "mysql.n2.medium.1",
+				DBInstanceStorageInGB: 20,
+				InstanceNetworkType:   v1alpha1.InstanceNetworkTypeVPC,
+				ZoneID:                "cn-hangzhou-i",
+				VPCID:                 "vpc-test",
+				VSwitchID:             "vsw-i",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := MakeCreateReadOnlyDBInstanceRequest("replica", &tc.p, primary)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nMakeCreateReadOnlyDBInstanceRequest(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
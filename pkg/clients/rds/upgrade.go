@@ -0,0 +1,265 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rds
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	alirds "github.com/aliyun/alibaba-cloud-sdk-go/services/rds"
+
+	"github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/clients/audit"
+)
+
+// The RDS API actions that upgrade the major version of PostgreSQL instances
+// are not part of the SDK, so they are called as common requests.
+const (
+	domain  = "rds.aliyuncs.com"
+	version = "2014-08-15"
+
+	apiUpgradeMajorVersionPrecheck         = "UpgradeDBInstanceMajorVersionPrecheck"
+	apiDescribeUpgradeMajorVersionPrecheck = "DescribeUpgradeMajorVersionPrecheckTask"
+	apiUpgradeMajorVersion                 = "UpgradeDBInstanceMajorVersion"
+
+	// effectiveTimeImmediate upgrades instances as soon as possible rather
+	// than in their maintenance window.
+	effectiveTimeImmediate = "Immediate"
+
+	errFmtEngineVersion = "cannot parse engine version %q"
+)
+
+// Results of upgrade pre-checks.
+const (
+	PrecheckSuccess = "Success"
+	PrecheckFail    = "Fail"
+)
+
+// An UpgradePrecheck is the result of the pre-check of a major version
+// upgrade.
+type UpgradePrecheck struct {
+	// Result is PrecheckSuccess or PrecheckFail once the pre-check
+	// completed, and empty while it is running.
+	Result string
+
+	// Detail of the checks, which describes why a pre-check failed.
+	Detail string
+}
+
+// UpgradeMajorVersionRequest defines the request info to upgrade the major
+// version of a PostgreSQL instance, which clones the instance to a new one
+// of the supplied class, storage, zone, network and billing.
+type UpgradeMajorVersionRequest struct {
+	TargetMajorVersion    string
+	DBInstanceClass       string
+	DBInstanceStorageInGB int
+	ZoneID                string
+	VPCID                 string
+	VSwitchID             string
+	PayType               string
+	Subscription          Subscription
+}
+
+type precheckResponse struct {
+	TaskID json.Number `json:"TaskId"`
+}
+
+type describePrecheckResponse struct {
+	Items []struct {
+		TaskID json.Number `json:"TaskId"`
+		Result string      `json:"Result"`
+		Detail string      `json:"Detail"`
+	} `json:"Items"`
+}
+
+type upgradeMajorVersionResponse struct {
+	DBInstanceID string      `json:"DBInstanceId"`
+	OrderID      json.Number `json:"OrderId"`
+}
+
+// UpgradeDBInstanceEngineVersion upgrades the engine version of the MySQL
+// instance with the supplied ID in place. The instance is
+// EngineVersionUpgrading until the upgrade completes.
+func (c *client) UpgradeDBInstanceEngineVersion(ctx context.Context, id, engineVersion string) error {
+	request := alirds.CreateUpgradeDBInstanceEngineVersionRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return err
+	}
+
+	request.DBInstanceId = id
+	request.EngineVersion = engineVersion
+	request.EffectiveTime = effectiveTimeImmediate
+
+	resp, err := c.rdsCli.UpgradeDBInstanceEngineVersion(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
+	return err
+}
+
+// PrecheckMajorVersionUpgrade starts the pre-check of the upgrade of the
+// PostgreSQL instance with the supplied ID to the supplied major version, and
+// returns the ID of the pre-check task.
+func (c *client) PrecheckMajorVersionUpgrade(ctx context.Context, id, targetVersion string) (string, error) {
+	rsp := &precheckResponse{}
+	err := c.call(ctx, apiUpgradeMajorVersionPrecheck, map[string]string{
+		"DBInstanceId":       id,
+		"TargetMajorVersion": targetVersion,
+	}, true, rsp)
+	return rsp.TaskID.String(), err
+}
+
+// DescribeMajorVersionUpgradePrecheck returns the result of the pre-check task
+// with the supplied ID of the instance with the supplied ID.
+func (c *client) DescribeMajorVersionUpgradePrecheck(ctx context.Context, id, taskID string) (*UpgradePrecheck, error) {
+	rsp := &describePrecheckResponse{}
+	err := c.call(ctx, apiDescribeUpgradeMajorVersionPrecheck, map[string]string{
+		"DBInstanceId": id,
+		"TaskId":       taskID,
+	}, false, rsp)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range rsp.Items {
+		if i.TaskID.String() == taskID {
+			return &UpgradePrecheck{Result: i.Result, Detail: i.Detail}, nil
+		}
+	}
+	return &UpgradePrecheck{}, nil
+}
+
+// UpgradeDBInstanceMajorVersion upgrades the PostgreSQL instance with the
+// supplied ID by cloning it to a new instance of the target major version,
+// which takes over the endpoints of the instance once it is running. It
+// returns the ID of the new instance and of the order that created it.
+func (c *client) UpgradeDBInstanceMajorVersion(ctx context.Context, id string, req *UpgradeMajorVersionRequest) (*DBInstance, error) {
+	params := map[string]string{
+		"DBInstanceId":       id,
+		"TargetMajorVersion": req.TargetMajorVersion,
+		"DBInstanceClass":    req.DBInstanceClass,
+		"DBInstanceStorage":  strconv.Itoa(req.DBInstanceStorageInGB),
+		"PayType":            req.PayType,
+		"SwitchOver":         "true",
+	}
+	if req.PayType == v1alpha1.PayTypePrepaid {
+		params["Period"] = req.Subscription.Period
+		params["UsedTime"] = strconv.Itoa(req.Subscription.UsedTime)
+	}
+	if req.ZoneID != "" {
+		params["ZoneId"] = req.ZoneID
+	}
+	if req.VPCID != "" {
+		params["VPCId"] = req.VPCID
+		params["VSwitchId"] = req.VSwitchID
+	}
+	rsp := &upgradeMajorVersionResponse{}
+	if err := c.call(ctx, apiUpgradeMajorVersion, params, true, rsp); err != nil {
+		return nil, err
+	}
+	return &DBInstance{ID: rsp.DBInstanceID, OrderID: rsp.OrderID.String()}, nil
+}
+
+// call calls the supplied RDS API action with the supplied parameters as a
+// common request and unmarshals its JSON response into the supplied value.
+// Calls that change instances are audited.
+func (c *client) call(ctx context.Context, action string, params map[string]string, audited bool, v interface{}) error {
+	t, err := clients.Timeout(ctx)
+	if err != nil {
+		return err
+	}
+	request := requests.NewCommonRequest()
+	request.Method = requests.POST
+	request.Scheme = httpsScheme
+	request.Domain = domain
+	request.Version = version
+	request.ApiName = action
+	request.SetConnectTimeout(t)
+	request.SetReadTimeout(t)
+	for k, p := range params {
+		request.QueryParams[k] = p
+	}
+
+	response, err := c.rdsCli.ProcessCommonRequest(request)
+	var rsp struct {
+		RequestID string `json:"RequestId"`
+	}
+	if err == nil {
+		err = json.Unmarshal(response.GetHttpContentBytes(), v)
+		_ = json.Unmarshal(response.GetHttpContentBytes(), &rsp)
+	}
+	if audited {
+		audit.Call(ctx, auditService, action, params, rsp.RequestID, err)
+	}
+	return err
+}
+
+// CompareEngineVersions returns -1, 0 or 1 if the engine version a is lower
+// than, equal to or higher than the engine version b, e.g. 5.7 is lower than
+// 8.0 and 10.0 is higher than 9.4.
+func CompareEngineVersions(a, b string) (int, error) {
+	av, err := parseEngineVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	bv, err := parseEngineVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < len(av) || i < len(bv); i++ {
+		var x, y int
+		if i < len(av) {
+			x = av[i]
+		}
+		if i < len(bv) {
+			y = bv[i]
+		}
+		switch {
+		case x < y:
+			return -1, nil
+		case x > y:
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// SameEngineVersion returns true if the supplied engine versions are equal,
+// e.g. 13 and 13.0. Versions that cannot be parsed must be identical.
+func SameEngineVersion(a, b string) bool {
+	cmp, err := CompareEngineVersions(a, b)
+	if err != nil {
+		return a == b
+	}
+	return cmp == 0
+}
+
+func parseEngineVersion(v string) ([]int, error) {
+	parts := strings.Split(v, ".")
+	version := make([]int, len(parts))
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, fmt.Errorf(errFmtEngineVersion, v)
+		}
+		version[i] = n
+	}
+	return version, nil
+}
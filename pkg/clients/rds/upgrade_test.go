@@ -0,0 +1,55 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rds
+
+import (
+	"testing"
+)
+
+func TestCompareEngineVersions(t *testing.T) {
+	cases := map[string]struct {
+		a, b    string
+		want    int
+		wantErr bool
+	}{
+		"Lower":         {a: "5.7", b: "8.0", want: -1},
+		"Higher":        {a: "10.0", b: "9.4", want: 1},
+		"Equal":         {a: "13.0", b: "13.0", want: 0},
+		"TrailingZeros": {a: "13", b: "13.0", want: 0},
+		"Invalid":       {a: "8.0", b: "latest", wantErr: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := CompareEngineVersions(tc.a, tc.b)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("CompareEngineVersions(%q, %q): want error=%v, get=%v", tc.a, tc.b, tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Errorf("CompareEngineVersions(%q, %q): want=%v, get=%v", tc.a, tc.b, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSameEngineVersion(t *testing.T) {
+	if !SameEngineVersion("10", "10.0") {
+		t.Errorf("SameEngineVersion: want=%v, get=%v", true, false)
+	}
+	if SameEngineVersion("latest", "8.0") {
+		t.Errorf("SameEngineVersion: want=%v, get=%v", false, true)
+	}
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rds
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	alirds "github.com/aliyun/alibaba-cloud-sdk-go/services/rds"
+
+	"github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/clients/audit"
+)
+
+const (
+	// noAccessIP is the whitelist entry of groups that allow no access.
+	noAccessIP = "127.0.0.1"
+
+	// hiddenGroupAttribute marks whitelist groups that are managed by other
+	// cloud services, e.g. DTS.
+	hiddenGroupAttribute = "hidden"
+
+	// modifyModeCover replaces the IPs of a whitelist group.
+	modifyModeCover = "Cover"
+)
+
+// reservedGroups are the whitelist groups that cloud services such as DMS,
+// DAS and DBS add to instances to reach them. They are never cleared.
+var reservedGroups = map[string]bool{
+	"ali_dms_group":    true,
+	"hdm_security_ips": true,
+	"dbs_security_ips": true,
+}
+
+// A SecurityIPGroup is a named IP whitelist group of an instance.
+type SecurityIPGroup struct {
+	Name   string
+	IPs    []string
+	IPType string
+}
+
+// DescribeSecurityIPGroups returns the whitelist groups of the instance with
+// the supplied ID, except for hidden groups.
+func (c *client) DescribeSecurityIPGroups(ctx context.Context, id string) ([]SecurityIPGroup, error) {
+	request := alirds.CreateDescribeDBInstanceIPArrayListRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return nil, err
+	}
+
+	request.DBInstanceId = id
+
+	response, err := c.rdsCli.DescribeDBInstanceIPArrayList(request)
+	if err != nil {
+		return nil, err
+	}
+	var groups []SecurityIPGroup
+	for _, a := range response.Items.DBInstanceIPArray {
+		if a.DBInstanceIPArrayAttribute == hiddenGroupAttribute {
+			continue
+		}
+		groups = append(groups, SecurityIPGroup{
+			Name:   a.DBInstanceIPArrayName,
+			IPs:    SplitIPs(a.SecurityIPList),
+			IPType: a.SecurityIPType,
+		})
+	}
+	return groups, nil
+}
+
+// ModifySecurityIPs replaces the IPs of the supplied whitelist group of the
+// instance with the supplied ID, creating the group if it does not exist.
+func (c *client) ModifySecurityIPs(ctx context.Context, id string, g SecurityIPGroup) error {
+	request := alirds.CreateModifySecurityIpsRequest()
+	request.Scheme = httpsScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return err
+	}
+
+	request.DBInstanceId = id
+	request.DBInstanceIPArrayName = g.Name
+	request.SecurityIps = strings.Join(g.IPs, ",")
+	request.SecurityIPType = g.IPType
+	request.ModifyMode = modifyModeCover
+
+	resp, err := c.rdsCli.ModifySecurityIps(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
+	return err
+}
+
+// SplitIPs returns the sorted, unique IPs of the supplied list of IPs, which
+// may be separated by commas or whitespace.
+func SplitIPs(list string) []string {
+	return normalizeIPs(strings.FieldsFunc(list, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	}))
+}
+
+func normalizeIPs(ips []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, ip := range ips {
+		ip = strings.TrimSpace(ip)
+		if ip == "" || seen[ip] {
+			continue
+		}
+		seen[ip] = true
+		out = append(out, ip)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// SecurityIPGroups returns the whitelist groups of the supplied parameters,
+// including the default group of their SecurityIPList. The supplied IPs read
+// from the IPsFrom of each group, by group name, are added to its IPs. Groups
+// without IPs allow no access.
+func SecurityIPGroups(p *v1alpha1.RDSInstanceParameters, ipsFrom map[string][]string) []SecurityIPGroup {
+	var groups []SecurityIPGroup
+	hasDefault := false
+	for _, g := range p.SecurityIPGroups {
+		ips := normalizeIPs(append(append([]string{}, g.IPs...), ipsFrom[g.Name]...))
+		if len(ips) == 0 {
+			ips = []string{noAccessIP}
+		}
+		t := g.IPType
+		if t == "" {
+			t = v1alpha1.SecurityIPTypeIPv4
+		}
+		groups = append(groups, SecurityIPGroup{Name: g.Name, IPs: ips, IPType: t})
+		hasDefault = hasDefault || g.Name == v1alpha1.DefaultSecurityIPGroup
+	}
+	if !hasDefault && p.SecurityIPList != "" {
+		d := SecurityIPGroup{Name: v1alpha1.DefaultSecurityIPGroup, IPs: SplitIPs(p.SecurityIPList), IPType: v1alpha1.SecurityIPTypeIPv4}
+		groups = append([]SecurityIPGroup{d}, groups...)
+	}
+	return groups
+}
+
+// MakeModifySecurityIPsRequests returns the whitelist groups of the supplied
+// parameters and IPs read from ConfigMaps whose IPs differ from those of the
+// supplied observation. When the parameters specify any whitelist group,
+// observed groups they do not specify are cleared to allow no access, except
+// for reserved groups.
+func MakeModifySecurityIPsRequests(p *v1alpha1.RDSInstanceParameters, ipsFrom map[string][]string, o *v1alpha1.RDSInstanceObservation) []SecurityIPGroup {
+	observed := map[string][]string{}
+	for _, g := range o.SecurityIPGroups {
+		observed[g.Name] = normalizeIPs(g.IPs)
+	}
+	desired := SecurityIPGroups(p, ipsFrom)
+	specified := map[string]bool{}
+	var changed []SecurityIPGroup
+	for _, g := range desired {
+		specified[g.Name] = true
+		if ips, ok := observed[g.Name]; !ok || !equalIPs(ips, g.IPs) {
+			changed = append(changed, g)
+		}
+	}
+	if len(desired) == 0 {
+		return changed
+	}
+	cleared := []string{noAccessIP}
+	for _, g := range o.SecurityIPGroups {
+		if specified[g.Name] || reservedGroups[g.Name] || equalIPs(observed[g.Name], cleared) {
+			continue
+		}
+		changed = append(changed, SecurityIPGroup{Name: g.Name, IPs: cleared, IPType: g.IPType})
+	}
+	return changed
+}
+
+func equalIPs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// securityIPGroupObservations returns the observations of the supplied
+// whitelist groups.
+func securityIPGroupObservations(groups []SecurityIPGroup) []v1alpha1.SecurityIPGroupObservation {
+	var obs []v1alpha1.SecurityIPGroupObservation
+	for _, g := range groups {
+		obs = append(obs, v1alpha1.SecurityIPGroupObservation{Name: g.Name, IPs: g.IPs, IPType: g.IPType})
+	}
+	return obs
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rds
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
+)
+
+func TestSplitIPs(t *testing.T) {
+	got := SplitIPs("10.0.0.2, 10.0.0.1\n192.168.0.0/16,10.0.0.1")
+	want := []string{"10.0.0.1", "10.0.0.2", "192.168.0.0/16"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("SplitIPs(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestMakeModifySecurityIPsRequests(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		p       v1alpha1.RDSInstanceParameters
+		ipsFrom map[string][]string
+		o       v1alpha1.RDSInstanceObservation
+		want    []SecurityIPGroup
+	}{
+		"UpToDate": {
+			reason: "Groups whose IPs match the observed ones should not be modified, regardless of order",
+			p:      v1alpha1.RDSInstanceParameters{SecurityIPList: "10.0.0.2,10.0.0.1"},
+			o: v1alpha1.RDSInstanceObservation{SecurityIPGroups: []v1alpha1.SecurityIPGroupObservation{
+				{Name: "default", IPs: []string{"10.0.0.1", "10.0.0.2"}, IPType: "IPv4"},
+				{Name: "cleared", IPs: []string{noAccessIP}, IPType: "IPv4"},
+				{Name: "ali_dms_group", IPs: []string{"100.104.0.0/16"}, IPType: "IPv4"},
+			}},
+		},
+		"UnspecifiedGroup": {
+			reason: "Groups that are not specified, e.g. added in the console or removed from the spec, should be cleared",
+			p: v1alpha1.RDSInstanceParameters{SecurityIPGroups: []v1alpha1.SecurityIPGroup{
+				{Name: "nodes", IPs: []string{"10.0.0.1"}},
+			}},
+			o: v1alpha1.RDSInstanceObservation{SecurityIPGroups: []v1alpha1.SecurityIPGroupObservation{
+				{Name: "default", IPs: []string{noAccessIP}, IPType: "IPv4"},
+				{Name: "nodes", IPs: []string{"10.0.0.1"}, IPType: "IPv4"},
+				{Name: "console", IPs: []string{"0.0.0.0/0"}, IPType: "IPv4"},
+				{Name: "hdm_security_ips", IPs: []string{"100.104.0.0/16"}, IPType: "IPv4"},
+			}},
+			want: []SecurityIPGroup{{Name: "console", IPs: []string{noAccessIP}, IPType: "IPv4"}},
+		},
+		"Unmanaged": {
+			reason: "Observed groups should be left alone when no group is specified",
+			o: v1alpha1.RDSInstanceObservation{SecurityIPGroups: []v1alpha1.SecurityIPGroupObservation{
+				{Name: "console", IPs: []string{"0.0.0.0/0"}, IPType: "IPv4"},
+			}},
+		},
+		"Drifted": {
+			reason: "Groups whose IPs were changed in the cloud should be restored",
+			p:      v1alpha1.RDSInstanceParameters{SecurityIPList: "10.0.0.1"},
+			o: v1alpha1.RDSInstanceObservation{SecurityIPGroups: []v1alpha1.SecurityIPGroupObservation{
+				{Name: "default", IPs: []string{"10.0.0.1", "10.0.0.9"}, IPType: "IPv4"},
+			}},
+			want: []SecurityIPGroup{{Name: "default", IPs: []string{"10.0.0.1"}, IPType: "IPv4"}},
+		},
+		"NewGroup": {
+			reason: "Groups that do not exist should be created with their explicit and resolved IPs",
+			p: v1alpha1.RDSInstanceParameters{SecurityIPGroups: []v1alpha1.SecurityIPGroup{
+				{Name: "nodes", IPs: []string{"10.0.0.1"}},
+			}},
+			ipsFrom: map[string][]string{"nodes": {"10.0.0.3", "10.0.0.1"}},
+			want:    []SecurityIPGroup{{Name: "nodes", IPs: []string{"10.0.0.1", "10.0.0.3"}, IPType: "IPv4"}},
+		},
+		"DefaultGroup": {
+			reason: "A listed default group should take precedence over SecurityIPList, and allow no access without IPs",
+			p: v1alpha1.RDSInstanceParameters{SecurityIPList: "10.0.0.1", SecurityIPGroups: []v1alpha1.SecurityIPGroup{
+				{Name: "default"},
+			}},
+			want: []SecurityIPGroup{{Name: "default", IPs: []string{noAccessIP}, IPType: "IPv4"}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := MakeModifySecurityIPsRequests(&tc.p, tc.ipsFrom, &tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nMakeModifySecurityIPsRequests(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -19,14 +19,17 @@ package redis
 import (
 	"context"
 	"strconv"
-	"time"
 
 	"github.com/pkg/errors"
 
 	sdkerrors "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
 	aliredis "github.com/aliyun/alibaba-cloud-sdk-go/services/r-kvstore"
 
 	"github.com/crossplane/provider-alibaba/apis/redis/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/clients/audit"
+	"github.com/crossplane/provider-alibaba/pkg/clients/bss"
 )
 
 var (
@@ -35,25 +38,40 @@ var (
 )
 
 const (
-	// DefaultReadTime indicates default connect timeout number
-	DefaultReadTime = 60 * time.Second
 	// PubilConnectionDomain indicates instances connect domain
 	PubilConnectionDomain = "-pb.redis.rds.aliyuncs.com"
 	// HTTPSScheme indicates request scheme
 	HTTPSScheme = "https"
 	// VPCNetworkType indicates network type by vpc
 	VPCNetworkType = "VPC"
+
+	// auditService is the service Redis API calls are audited as.
+	auditService = "r-kvstore"
+
+	// listPageSize is the maximum page size of DescribeInstances.
+	listPageSize = 50
+
+	// PrePaidChargeType indicates subscription instances
+	PrePaidChargeType = "PrePaid"
+
+	// QuotaProductCode is the Quota Center product code of Redis.
+	QuotaProductCode = "kvstore"
+
+	// BSS product code of Redis instances.
+	priceProductCode = "redisa"
 )
 
 // Client defines Redis client operations
 type Client interface {
-	DescribeDBInstance(id string) (*DBInstance, error)
-	CreateAccount(id, username, password string) error
-	CreateDBInstance(*CreateRedisInstanceRequest) (*DBInstance, error)
-	DeleteDBInstance(id string) error
-	AllocateInstancePublicConnection(id string, port int) (string, error)
-	ModifyDBInstanceConnectionString(id string, port int) (string, error)
-	Update(id string, req *ModifyRedisInstanceRequest) error
+	DescribeDBInstance(ctx context.Context, id string) (*DBInstance, error)
+	ListDBInstances(ctx context.Context, tags map[string]string) ([]DBInstance, error)
+	CreateAccount(ctx context.Context, id, username, password string) error
+	CreateDBInstance(ctx context.Context, req *CreateRedisInstanceRequest) (*DBInstance, error)
+	DeleteDBInstance(ctx context.Context, id string) error
+	AllocateInstancePublicConnection(ctx context.Context, id string, port int) (string, error)
+	ModifyDBInstanceConnectionString(ctx context.Context, id string, port int) (string, error)
+	Update(ctx context.Context, id string, req *ModifyRedisInstanceRequest) error
+	DescribeAvailableClasses(ctx context.Context, engine, engineVersion, chargeType string) (clients.Availability, error)
 }
 
 // DBInstance defines the DB instance information
@@ -66,6 +84,33 @@ type DBInstance struct {
 
 	// Endpoint specifies the connection endpoint.
 	Endpoint *v1alpha1.Endpoint
+
+	// The following fields are only set by ListDBInstances.
+
+	// Instance name
+	Name string
+
+	// Engine type, e.g. Redis
+	InstanceType string
+
+	// Engine version
+	EngineVersion string
+
+	// Instance class
+	InstanceClass string
+
+	// Service port
+	Port int
+
+	// PrePaid or PostPaid
+	ChargeType string
+
+	// CLASSIC or VPC
+	NetworkType string
+
+	// VPC and VSwitch of VPC instances
+	VpcID     string
+	VSwitchID string
 }
 
 // CreateRedisInstanceRequest defines the request info to create DB Instance
@@ -102,9 +147,12 @@ func NewClient(ctx context.Context, accessKeyID, accessKeySecret, region string)
 	return c, nil
 }
 
-func (c *client) DescribeDBInstance(id string) (*DBInstance, error) {
+func (c *client) DescribeDBInstance(ctx context.Context, id string) (*DBInstance, error) {
 	request := aliredis.CreateDescribeInstancesRequest()
 	request.Scheme = HTTPSScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return nil, err
+	}
 
 	request.InstanceIds = id
 
@@ -117,22 +165,71 @@ func (c *client) DescribeDBInstance(id string) (*DBInstance, error) {
 	}
 	rsp := response.Instances.KVStoreInstance[0]
 	in := &DBInstance{
-		ID:     rsp.InstanceId,
-		Status: rsp.InstanceStatus,
+		ID:            rsp.InstanceId,
+		Status:        rsp.InstanceStatus,
+		InstanceClass: rsp.InstanceClass,
 	}
 
 	return in, nil
 }
 
-func (c *client) CreateDBInstance(req *CreateRedisInstanceRequest) (*DBInstance, error) {
+// ListDBInstances returns all instances of the region that have all of the
+// supplied tags.
+func (c *client) ListDBInstances(ctx context.Context, tags map[string]string) ([]DBInstance, error) {
+	filter := make([]aliredis.DescribeInstancesTag, 0, len(tags))
+	for k, v := range tags {
+		filter = append(filter, aliredis.DescribeInstancesTag{Key: k, Value: v})
+	}
+
+	var instances []DBInstance
+	for page := 1; ; page++ {
+		request := aliredis.CreateDescribeInstancesRequest()
+		request.Scheme = HTTPSScheme
+		if err := clients.WithTimeout(ctx, request); err != nil {
+			return nil, err
+		}
+		if len(filter) > 0 {
+			request.Tag = &filter
+		}
+		request.PageSize = requests.NewInteger(listPageSize)
+		request.PageNumber = requests.NewInteger(page)
+
+		response, err := c.redisCli.DescribeInstances(request)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot list redis instances")
+		}
+		for _, rsp := range response.Instances.KVStoreInstance {
+			instances = append(instances, DBInstance{
+				ID:            rsp.InstanceId,
+				Status:        rsp.InstanceStatus,
+				Name:          rsp.InstanceName,
+				InstanceType:  rsp.InstanceType,
+				EngineVersion: rsp.EngineVersion,
+				InstanceClass: rsp.InstanceClass,
+				Port:          int(rsp.Port),
+				ChargeType:    rsp.ChargeType,
+				NetworkType:   rsp.NetworkType,
+				VpcID:         rsp.VpcId,
+				VSwitchID:     rsp.VSwitchId,
+			})
+		}
+		if len(response.Instances.KVStoreInstance) < listPageSize {
+			return instances, nil
+		}
+	}
+}
+
+func (c *client) CreateDBInstance(ctx context.Context, req *CreateRedisInstanceRequest) (*DBInstance, error) {
 	request := aliredis.CreateCreateInstanceRequest()
 	request.Scheme = HTTPSScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return nil, err
+	}
 
 	request.InstanceName = req.Name
 	request.EngineVersion = req.EngineVersion
 	request.InstanceClass = req.InstanceClass
 	request.InstanceType = req.InstanceType
-	request.ReadTimeout = DefaultReadTime
 	request.ChargeType = req.ChargeType
 	request.NetworkType = req.NetworkType
 
@@ -141,6 +238,7 @@ func (c *client) CreateDBInstance(req *CreateRedisInstanceRequest) (*DBInstance,
 		request.VSwitchId = req.VSwitchID
 	}
 	resp, err := c.redisCli.CreateInstance(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
 	if err != nil {
 		return nil, err
 	}
@@ -154,25 +252,76 @@ func (c *client) CreateDBInstance(req *CreateRedisInstanceRequest) (*DBInstance,
 	}, nil
 }
 
-func (c *client) CreateAccount(id, user, pw string) error {
+// DescribeAvailableClasses returns the instance classes of the supplied
+// engine version that are available for instances of the supplied charge
+// type in each zone of the region.
+func (c *client) DescribeAvailableClasses(ctx context.Context, engine, engineVersion, chargeType string) (clients.Availability, error) {
+	request := aliredis.CreateDescribeAvailableResourceRequest()
+	request.Scheme = HTTPSScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return nil, err
+	}
+
+	request.Engine = engine
+	request.InstanceChargeType = chargeType
+
+	response, err := c.redisCli.DescribeAvailableResource(request)
+	if err != nil {
+		return nil, err
+	}
+	a := clients.Availability{}
+	for _, z := range response.AvailableZones.AvailableZone {
+		a[z.ZoneId] = nil
+		for _, e := range z.SupportedEngines.SupportedEngine {
+			for _, et := range e.SupportedEditionTypes.SupportedEditionType {
+				for _, st := range et.SupportedSeriesTypes.SupportedSeriesType {
+					for _, v := range st.SupportedEngineVersions.SupportedEngineVersion {
+						if v.Version != engineVersion {
+							continue
+						}
+						for _, at := range v.SupportedArchitectureTypes.SupportedArchitectureType {
+							for _, sn := range at.SupportedShardNumbers.SupportedShardNumber {
+								for _, nt := range sn.SupportedNodeTypes.SupportedNodeType {
+									for _, r := range nt.AvailableResources.AvailableResource {
+										a[z.ZoneId] = append(a[z.ZoneId], r.InstanceClass)
+									}
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return a, nil
+}
+
+func (c *client) CreateAccount(ctx context.Context, id, user, pw string) error {
 	request := aliredis.CreateCreateAccountRequest()
 	request.Scheme = HTTPSScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return err
+	}
 	request.InstanceId = id
 	request.AccountName = user
 	request.AccountPassword = pw
-	request.ReadTimeout = DefaultReadTime
 
-	_, err := c.redisCli.CreateAccount(request)
+	resp, err := c.redisCli.CreateAccount(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
 	return err
 }
 
-func (c *client) DeleteDBInstance(id string) error {
+func (c *client) DeleteDBInstance(ctx context.Context, id string) error {
 	request := aliredis.CreateDeleteInstanceRequest()
 	request.Scheme = HTTPSScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return err
+	}
 
 	request.InstanceId = id
 
-	_, err := c.redisCli.DeleteInstance(request)
+	resp, err := c.redisCli.DeleteInstance(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
 	return err
 }
 
@@ -185,6 +334,27 @@ func GenerateObservation(db *DBInstance) v1alpha1.RedisInstanceObservation {
 	}
 }
 
+// IsUpToDate returns true if the supplied instance has the instance class of
+// the supplied parameters.
+func IsUpToDate(p *v1alpha1.RedisInstanceParameters, db *DBInstance) bool {
+	return p.InstanceClass == db.InstanceClass
+}
+
+// GenerateParameters is used to produce v1alpha1.RedisInstanceParameters from
+// a redis.DBInstance returned by ListDBInstances.
+func GenerateParameters(db *DBInstance) v1alpha1.RedisInstanceParameters {
+	return v1alpha1.RedisInstanceParameters{
+		InstanceType:  db.InstanceType,
+		EngineVersion: db.EngineVersion,
+		InstanceClass: db.InstanceClass,
+		InstancePort:  db.Port,
+		ChargeType:    db.ChargeType,
+		NetworkType:   db.NetworkType,
+		VpcID:         db.VpcID,
+		VSwitchID:     db.VSwitchID,
+	}
+}
+
 // MakeCreateDBInstanceRequest generates CreateDBInstanceRequest
 func MakeCreateDBInstanceRequest(name string, p *v1alpha1.RedisInstanceParameters) *CreateRedisInstanceRequest {
 	return &CreateRedisInstanceRequest{
@@ -200,6 +370,19 @@ func MakeCreateDBInstanceRequest(name string, p *v1alpha1.RedisInstanceParameter
 	}
 }
 
+// MakePriceRequest generates the BSS price request of an instance with the
+// supplied parameters in the supplied region.
+func MakePriceRequest(p *v1alpha1.RedisInstanceParameters, region string) bss.PriceRequest {
+	return bss.PriceRequest{
+		ProductCode:  priceProductCode,
+		Region:       region,
+		Subscription: p.ChargeType == PrePaidChargeType,
+		Modules: []bss.Module{
+			{Code: "InstanceClass", Config: bss.Config("InstanceClass", p.InstanceClass, "EngineVersion", p.EngineVersion, "Region", region)},
+		},
+	}
+}
+
 // IsErrorNotFound helper function to test for ErrCodeDBInstanceNotFoundFault error
 func IsErrorNotFound(err error) bool {
 	if err == nil {
@@ -212,50 +395,59 @@ func IsErrorNotFound(err error) bool {
 	return errors.Is(err, ErrDBInstanceNotFound)
 }
 
-func (c *client) AllocateInstancePublicConnection(id string, port int) (string, error) {
+func (c *client) AllocateInstancePublicConnection(ctx context.Context, id string, port int) (string, error) {
 	request := aliredis.CreateAllocateInstancePublicConnectionRequest()
 	request.Scheme = HTTPSScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return "", err
+	}
 	request.InstanceId = id
 	request.ConnectionStringPrefix = id + PubilConnectionDomain
 	request.Port = strconv.Itoa(port)
-	request.ReadTimeout = DefaultReadTime
-	_, err := c.redisCli.AllocateInstancePublicConnection(request)
+	resp, err := c.redisCli.AllocateInstancePublicConnection(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
 	if err != nil {
 		return "", err
 	}
 	return request.ConnectionStringPrefix, err
 }
 
-func (c *client) ModifyDBInstanceConnectionString(id string, port int) (string, error) {
+func (c *client) ModifyDBInstanceConnectionString(ctx context.Context, id string, port int) (string, error) {
 	request := aliredis.CreateModifyDBInstanceConnectionStringRequest()
 	request.Scheme = HTTPSScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return "", err
+	}
 	request.DBInstanceId = id
 	request.CurrentConnectionString = id + PubilConnectionDomain
 	request.Port = strconv.Itoa(port)
-	request.ReadTimeout = DefaultReadTime
-	_, err := c.redisCli.ModifyDBInstanceConnectionString(request)
+	resp, err := c.redisCli.ModifyDBInstanceConnectionString(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
 	if err != nil {
 		return "", err
 	}
 	return request.CurrentConnectionString, err
 }
 
-func (c *client) Update(id string, req *ModifyRedisInstanceRequest) error {
+func (c *client) Update(ctx context.Context, id string, req *ModifyRedisInstanceRequest) error {
 	if req.InstanceClass == "" {
 		return errors.New("modify instances spec is require")
 	}
 	if req.InstanceClass != "" {
-		return c.modifyInstanceSpec(id, req)
+		return c.modifyInstanceSpec(ctx, id, req)
 	}
 	return nil
 }
 
-func (c *client) modifyInstanceSpec(id string, req *ModifyRedisInstanceRequest) error {
+func (c *client) modifyInstanceSpec(ctx context.Context, id string, req *ModifyRedisInstanceRequest) error {
 	request := aliredis.CreateModifyInstanceSpecRequest()
 	request.Scheme = HTTPSScheme
+	if err := clients.WithTimeout(ctx, request); err != nil {
+		return err
+	}
 	request.InstanceId = id
 	request.InstanceClass = req.InstanceClass
-	request.ReadTimeout = DefaultReadTime
-	_, err := c.redisCli.ModifyInstanceSpec(request)
+	resp, err := c.redisCli.ModifyInstanceSpec(request)
+	audit.Request(ctx, auditService, request, resp.RequestId, err)
 	return err
 }
@@ -16,6 +16,16 @@ func TestGenerateObservation(t *testing.T) {
 	}
 }
 
+func TestIsUpToDate(t *testing.T) {
+	p := &v1alpha1.RedisInstanceParameters{InstanceClass: "redis.master.small.default"}
+	if !IsUpToDate(p, &DBInstance{InstanceClass: "redis.master.small.default"}) {
+		t.Errorf("IsUpToDate: want=%v, get=%v", true, false)
+	}
+	if IsUpToDate(p, &DBInstance{InstanceClass: "redis.master.mid.default"}) {
+		t.Errorf("IsUpToDate: want=%v, get=%v", false, true)
+	}
+}
+
 func TestIsErrorNotFound(t *testing.T) {
 	var response = make(map[string]string)
 	response["Code"] = "InvalidInstanceId.NotFound"
@@ -27,3 +37,14 @@ func TestIsErrorNotFound(t *testing.T) {
 		t.Errorf("IsErrorNotFound: want=%v, get=%v", true, isErrorNotFound)
 	}
 }
+
+func TestMakePriceRequest(t *testing.T) {
+	req := MakePriceRequest(&v1alpha1.RedisInstanceParameters{InstanceClass: "redis.master.small.default", EngineVersion: "5.0", ChargeType: PrePaidChargeType}, "cn-beijing")
+	if !req.Subscription {
+		t.Errorf("Subscription: want=%v, get=%v", true, req.Subscription)
+	}
+	want := "InstanceClass:redis.master.small.default,EngineVersion:5.0,Region:cn-beijing"
+	if len(req.Modules) != 1 || req.Modules[0].Config != want {
+		t.Errorf("Modules: want=%v, get=%v", want, req.Modules)
+	}
+}
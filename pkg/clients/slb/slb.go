@@ -18,23 +18,49 @@ package nas
 
 import (
 	"context"
+	"encoding/json"
+	"strconv"
 
 	openapi "github.com/alibabacloud-go/darabonba-openapi/client"
 	sdk "github.com/alibabacloud-go/slb-20140515/v2/client"
+	"github.com/alibabacloud-go/tea/tea"
 	"github.com/pkg/errors"
 
 	"github.com/crossplane/provider-alibaba/apis/slb/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/clients/audit"
+	"github.com/crossplane/provider-alibaba/pkg/clients/bss"
 )
 
 const (
-	errFailedToCreateSLBClient = "failed to crate SLB client"
+	errFailedToCreateSLBClient  = "failed to crate SLB client"
+	errCodeLoadBalancerNotExist = "InvalidLoadBalancerId.NotFound"
+
+	// auditService is the service SLB API calls are audited as.
+	auditService = "slb"
+
+	// listPageSize is the maximum page size of DescribeLoadBalancers.
+	listPageSize = 100
+
+	// prePayType indicates subscription load balancers
+	prePayType = "PrePay"
+
+	// BSS product code of load balancers.
+	priceProductCode = "slb"
 )
 
+// tag is a tag filter of DescribeLoadBalancers
+type tag struct {
+	TagKey   string `json:"TagKey"`
+	TagValue string `json:"TagValue"`
+}
+
 // ClientInterface creates a client interface
 type ClientInterface interface {
-	DescribeLoadBalancers(region, loadBalancerID, vpcID, vSwitchID *string) (*sdk.DescribeLoadBalancersResponse, error)
-	CreateLoadBalancer(name string, clb v1alpha1.CLBParameter) (*sdk.CreateLoadBalancerResponse, error)
-	DeleteLoadBalancer(region, loadBalancerID *string) error
+	DescribeLoadBalancers(ctx context.Context, region, loadBalancerID, vpcID, vSwitchID *string) (*sdk.DescribeLoadBalancersResponse, error)
+	ListLoadBalancers(ctx context.Context, region string, tags map[string]string) ([]*sdk.DescribeLoadBalancersResponseBodyLoadBalancersLoadBalancer, error)
+	CreateLoadBalancer(ctx context.Context, name string, clb v1alpha1.CLBParameters) (*sdk.CreateLoadBalancerResponse, error)
+	DeleteLoadBalancer(ctx context.Context, region, loadBalancerID *string) error
 }
 
 // SDKClient is the SDK client for SLBLoadBalancer
@@ -58,7 +84,7 @@ func NewClient(ctx context.Context, endpoint string, accessKeyID string, accessK
 }
 
 // DescribeLoadBalancers describes a SLBLoadBalancer instance
-func (c *SDKClient) DescribeLoadBalancers(region, loadBalancerID, vpcID, vSwitchID *string) (*sdk.DescribeLoadBalancersResponse, error) {
+func (c *SDKClient) DescribeLoadBalancers(ctx context.Context, region, loadBalancerID, vpcID, vSwitchID *string) (*sdk.DescribeLoadBalancersResponse, error) {
 	describeLoadBalancersRequest := &sdk.DescribeLoadBalancersRequest{
 		RegionId: region,
 	}
@@ -71,75 +97,148 @@ func (c *SDKClient) DescribeLoadBalancers(region, loadBalancerID, vpcID, vSwitch
 	if vSwitchID != nil {
 		describeLoadBalancersRequest.VSwitchId = vSwitchID
 	}
-	fs, err := c.Client.DescribeLoadBalancers(describeLoadBalancersRequest)
+	runtime, err := clients.RuntimeOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fs, err := c.Client.DescribeLoadBalancersWithOptions(describeLoadBalancersRequest, runtime)
 	if err != nil {
 		return nil, err
 	}
 	return fs, nil
 }
 
+// ListLoadBalancers lists the SLBLoadBalancer instances of region that have
+// all of the supplied tags
+func (c *SDKClient) ListLoadBalancers(ctx context.Context, region string, tags map[string]string) ([]*sdk.DescribeLoadBalancersResponseBodyLoadBalancersLoadBalancer, error) {
+	var filter *string
+	if len(tags) > 0 {
+		t := make([]tag, 0, len(tags))
+		for k, v := range tags {
+			t = append(t, tag{TagKey: k, TagValue: v})
+		}
+		b, err := json.Marshal(t)
+		if err != nil {
+			return nil, err
+		}
+		filter = tea.String(string(b))
+	}
+
+	var lbs []*sdk.DescribeLoadBalancersResponseBodyLoadBalancersLoadBalancer
+	for page := int32(1); ; page++ {
+		runtime, err := clients.RuntimeOptions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		res, err := c.Client.DescribeLoadBalancersWithOptions(&sdk.DescribeLoadBalancersRequest{
+			RegionId:   tea.String(region),
+			Tags:       filter,
+			PageNumber: tea.Int32(page),
+			PageSize:   tea.Int32(listPageSize),
+		}, runtime)
+		if err != nil {
+			return nil, err
+		}
+		if res.Body == nil || res.Body.LoadBalancers == nil {
+			return lbs, nil
+		}
+		lbs = append(lbs, res.Body.LoadBalancers.LoadBalancer...)
+		if len(res.Body.LoadBalancers.LoadBalancer) < listPageSize {
+			return lbs, nil
+		}
+	}
+}
+
 // CreateLoadBalancer creates a SLBLoadBalancer instance
-func (c *SDKClient) CreateLoadBalancer(name string, clb v1alpha1.CLBParameter) (*sdk.CreateLoadBalancerResponse, error) {
-	createLoadBalancerRequest := &sdk.CreateLoadBalancerRequest{
-		RegionId:                     clb.Region,
-		AddressType:                  clb.AddressType,
-		Address:                      clb.Address,
-		InternetChargeType:           clb.InternetChargeType,
-		Bandwidth:                    clb.Bandwidth,
-		LoadBalancerName:             &name,
-		VpcId:                        clb.VpcID,
-		VSwitchId:                    clb.VSwitchID,
-		LoadBalancerSpec:             clb.LoadBalancerSpec,
-		ClientToken:                  clb.ClientToken,
-		OwnerId:                      clb.OwnerID,
-		ResourceOwnerAccount:         clb.OwnerAccount,
-		ResourceGroupId:              clb.ResourceGroupID,
-		OwnerAccount:                 clb.OwnerAccount,
-		MasterZoneId:                 clb.MasterZoneID,
-		SlaveZoneId:                  clb.SlaveZoneID,
-		PayType:                      clb.PayType,
-		PricingCycle:                 clb.PricingCycle,
-		Duration:                     clb.Duration,
-		AutoPay:                      clb.AutoPay,
-		DeleteProtection:             clb.DeleteProtection,
-		ModificationProtectionStatus: clb.ModificationProtectionStatus,
-		ModificationProtectionReason: clb.ModificationProtectionReason,
-	}
-	res, err := c.Client.CreateLoadBalancer(createLoadBalancerRequest)
+func (c *SDKClient) CreateLoadBalancer(ctx context.Context, name string, clb v1alpha1.CLBParameters) (*sdk.CreateLoadBalancerResponse, error) {
+	createLoadBalancerRequest := GenerateCLBRequest(&clb)
+	createLoadBalancerRequest.LoadBalancerName = &name
+	runtime, err := clients.RuntimeOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.Client.CreateLoadBalancerWithOptions(createLoadBalancerRequest, runtime)
+	var requestID *string
+	if err == nil {
+		requestID = res.Body.RequestId
+	}
+	audit.Call(ctx, auditService, "CreateLoadBalancer", createLoadBalancerRequest, tea.StringValue(requestID), err)
 	return res, err
 }
 
 // DeleteLoadBalancer deletes the SLBLoadBalancer instance
-func (c *SDKClient) DeleteLoadBalancer(region, loadBalancerID *string) error {
+func (c *SDKClient) DeleteLoadBalancer(ctx context.Context, region, loadBalancerID *string) error {
 	deleteLoadBalancerRequest := &sdk.DeleteLoadBalancerRequest{
 		RegionId:       region,
 		LoadBalancerId: loadBalancerID,
 	}
-	_, err := c.Client.DeleteLoadBalancer(deleteLoadBalancerRequest)
+	runtime, err := clients.RuntimeOptions(ctx)
+	if err != nil {
+		return err
+	}
+	res, err := c.Client.DeleteLoadBalancerWithOptions(deleteLoadBalancerRequest, runtime)
+	var requestID *string
+	if err == nil {
+		requestID = res.Body.RequestId
+	}
+	audit.Call(ctx, auditService, "DeleteLoadBalancer", deleteLoadBalancerRequest, tea.StringValue(requestID), err)
 	return err
 }
 
+// GenerateParameters generates CLBParameters from LoadBalancer information
+func GenerateParameters(lb *sdk.DescribeLoadBalancersResponseBodyLoadBalancersLoadBalancer) v1alpha1.CLBParameters {
+	return v1alpha1.CLBParameters{
+		Region:                       lb.RegionId,
+		AddressType:                  lb.AddressType,
+		Address:                      lb.Address,
+		InternetChargeType:           lb.InternetChargeType,
+		Bandwidth:                    lb.Bandwidth,
+		VpcID:                        lb.VpcId,
+		VSwitchID:                    lb.VSwitchId,
+		LoadBalancerSpec:             lb.LoadBalancerSpec,
+		ResourceGroupID:              lb.ResourceGroupId,
+		MasterZoneID:                 lb.MasterZoneId,
+		SlaveZoneID:                  lb.SlaveZoneId,
+		PayType:                      lb.PayType,
+		DeleteProtection:             lb.DeleteProtection,
+		ModificationProtectionStatus: lb.ModificationProtectionStatus,
+		ModificationProtectionReason: lb.ModificationProtectionReason,
+	}
+}
+
 // GenerateObservation generates CLBObservation from LoadBalancer information
 func GenerateObservation(res *sdk.DescribeLoadBalancersResponse) v1alpha1.CLBObservation {
 	observation := v1alpha1.CLBObservation{}
 	if *res.Body.TotalCount == 0 {
 		return observation
 	}
-	lb := res.Body.LoadBalancers.LoadBalancer[0]
-	observation = v1alpha1.CLBObservation{
-		LoadBalancerID:     lb.LoadBalancerId,
-		CreateTime:         lb.CreateTime,
-		NetworkType:        lb.NetworkType,
-		LoadBalancerStatus: lb.LoadBalancerStatus,
-		Address:            lb.Address,
-	}
+	observation.LoadBalancerObservation = GenerateCLBObservation(res.Body.LoadBalancers.LoadBalancer[0])
 	return observation
 }
 
+// MakePriceRequest generates the BSS price request of a load balancer with
+// the supplied parameters. Its region defaults to the supplied region.
+func MakePriceRequest(p *v1alpha1.CLBParameters, region string) bss.PriceRequest {
+	if p.Region != nil {
+		region = *p.Region
+	}
+	modules := []bss.Module{
+		{Code: "LoadBalancerSpec", Config: bss.Config("LoadBalancerSpec", tea.StringValue(p.LoadBalancerSpec), "Region", region)},
+		{Code: "InternetChargeType", Config: bss.Config("InternetChargeType", tea.StringValue(p.InternetChargeType))},
+	}
+	if p.Bandwidth != nil {
+		modules = append(modules, bss.Module{Code: "Bandwidth", Config: bss.Config("Bandwidth", strconv.Itoa(int(*p.Bandwidth)))})
+	}
+	return bss.PriceRequest{
+		ProductCode:  priceProductCode,
+		Region:       region,
+		Subscription: tea.StringValue(p.PayType) == prePayType,
+		Modules:      modules,
+	}
+}
+
 // IsUpdateToDate checks whether cr is up to date
-//nolint:gocyclo
 func IsUpdateToDate(cr *v1alpha1.CLB, res *sdk.DescribeLoadBalancersResponse) bool {
-	spec := cr.Spec.ForProvider
 	if *res.Body.TotalCount == 0 {
 		return false
 	}
@@ -149,17 +248,20 @@ func IsUpdateToDate(cr *v1alpha1.CLB, res *sdk.DescribeLoadBalancersResponse) bo
 	// LoadBalancerName.
 	// If InternetChargeType is set to `paybytraffic`, the response will be `4`.
 	// If AddressType is set to `internet`, the response will be `intranet`
-	if spec.LoadBalancerSpec != nil && (lb.LoadBalancerSpec == nil || *spec.LoadBalancerSpec != *lb.LoadBalancerSpec) {
+	// The compared fields are listed in sdkgen.yaml.
+	if !IsCLBUpToDate(cr.Spec.ForProvider, lb) {
 		return false
 	}
-	if spec.VpcID != nil && (lb.VpcId == nil || *spec.VpcID != *lb.VpcId) {
-		return false
-	}
-	if spec.VSwitchID != nil && (lb.VSwitchId == nil || *spec.VSwitchID != *lb.VSwitchId) {
+	return tea.StringValue(cr.Spec.ForProvider.Region) == tea.StringValue(lb.RegionId)
+}
+
+// IsNotFoundError helper function to test for SLB load balancer not found error
+func IsNotFoundError(err error) bool {
+	if err == nil {
 		return false
 	}
-	if *spec.Region != *lb.RegionId {
-		return false
+	if e, ok := errors.Cause(err).(*tea.SDKError); ok && e.Code != nil && *e.Code == errCodeLoadBalancerNotExist {
+		return true
 	}
-	return true
+	return false
 }
@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by sdkgen. DO NOT EDIT.
+
+package nas
+
+import (
+	sdk "github.com/alibabacloud-go/slb-20140515/v2/client"
+
+	"github.com/crossplane/provider-alibaba/apis/slb/v1alpha1"
+)
+
+// GenerateCLBRequest returns the CreateLoadBalancerRequest of the supplied CLBParameters.
+func GenerateCLBRequest(in *v1alpha1.CLBParameters) *sdk.CreateLoadBalancerRequest {
+	if in == nil {
+		return nil
+	}
+	out := &sdk.CreateLoadBalancerRequest{}
+	out.OwnerId = in.OwnerID
+	out.ResourceOwnerAccount = in.ResourceOwnerAccount
+	out.ResourceOwnerId = in.ResourceOwnerID
+	out.RegionId = in.Region
+	out.AddressType = in.AddressType
+	out.InternetChargeType = in.InternetChargeType
+	out.Bandwidth = in.Bandwidth
+	out.ClientToken = in.ClientToken
+	out.VpcId = in.VpcID
+	out.VSwitchId = in.VSwitchID
+	out.OwnerAccount = in.OwnerAccount
+	out.MasterZoneId = in.MasterZoneID
+	out.SlaveZoneId = in.SlaveZoneID
+	out.LoadBalancerSpec = in.LoadBalancerSpec
+	out.ResourceGroupId = in.ResourceGroupID
+	out.PayType = in.PayType
+	out.PricingCycle = in.PricingCycle
+	out.Duration = in.Duration
+	out.AutoPay = in.AutoPay
+	out.AddressIPVersion = in.AddressIPVersion
+	out.Address = in.Address
+	out.DeleteProtection = in.DeleteProtection
+	out.ModificationProtectionStatus = in.ModificationProtectionStatus
+	out.ModificationProtectionReason = in.ModificationProtectionReason
+	return out
+}
+
+// GenerateCLBParameters returns the CLBParameters of the supplied CreateLoadBalancerRequest.
+func GenerateCLBParameters(in *sdk.CreateLoadBalancerRequest) v1alpha1.CLBParameters {
+	out := v1alpha1.CLBParameters{}
+	if in == nil {
+		return out
+	}
+	out.OwnerID = in.OwnerId
+	out.ResourceOwnerAccount = in.ResourceOwnerAccount
+	out.ResourceOwnerID = in.ResourceOwnerId
+	out.Region = in.RegionId
+	out.AddressType = in.AddressType
+	out.InternetChargeType = in.InternetChargeType
+	out.Bandwidth = in.Bandwidth
+	out.ClientToken = in.ClientToken
+	out.VpcID = in.VpcId
+	out.VSwitchID = in.VSwitchId
+	out.OwnerAccount = in.OwnerAccount
+	out.MasterZoneID = in.MasterZoneId
+	out.SlaveZoneID = in.SlaveZoneId
+	out.LoadBalancerSpec = in.LoadBalancerSpec
+	out.ResourceGroupID = in.ResourceGroupId
+	out.PayType = in.PayType
+	out.PricingCycle = in.PricingCycle
+	out.Duration = in.Duration
+	out.AutoPay = in.AutoPay
+	out.AddressIPVersion = in.AddressIPVersion
+	out.Address = in.Address
+	out.DeleteProtection = in.DeleteProtection
+	out.ModificationProtectionStatus = in.ModificationProtectionStatus
+	out.ModificationProtectionReason = in.ModificationProtectionReason
+	return out
+}
+
+// GenerateCLBObservation returns the LoadBalancerObservation of the supplied DescribeLoadBalancersResponseBodyLoadBalancersLoadBalancer.
+func GenerateCLBObservation(in *sdk.DescribeLoadBalancersResponseBodyLoadBalancersLoadBalancer) v1alpha1.LoadBalancerObservation {
+	out := v1alpha1.LoadBalancerObservation{}
+	if in == nil {
+		return out
+	}
+	out.LoadBalancerID = in.LoadBalancerId
+	out.CreateTime = in.CreateTime
+	out.NetworkType = in.NetworkType
+	out.Address = in.Address
+	out.MasterZoneID = in.MasterZoneId
+	out.ModificationProtectionReason = in.ModificationProtectionReason
+	out.ModificationProtectionStatus = in.ModificationProtectionStatus
+	out.LoadBalancerStatus = in.LoadBalancerStatus
+	out.ResourceGroupID = in.ResourceGroupId
+	out.DeleteProtection = in.DeleteProtection
+	return out
+}
+
+// IsCLBUpToDate returns true if the supplied DescribeLoadBalancersResponseBodyLoadBalancersLoadBalancer matches the
+// supplied CLBParameters.
+func IsCLBUpToDate(p v1alpha1.CLBParameters, o *sdk.DescribeLoadBalancersResponseBodyLoadBalancersLoadBalancer) bool {
+	if o == nil {
+		return false
+	}
+	if p.LoadBalancerSpec != nil && (o.LoadBalancerSpec == nil || *p.LoadBalancerSpec != *o.LoadBalancerSpec) {
+		return false
+	}
+	if p.VpcID != nil && (o.VpcId == nil || *p.VpcID != *o.VpcId) {
+		return false
+	}
+	if p.VSwitchID != nil && (o.VSwitchId == nil || *p.VSwitchID != *o.VSwitchId) {
+		return false
+	}
+	return true
+}
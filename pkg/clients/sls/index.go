@@ -19,10 +19,13 @@
 package sls
 
 import (
+	"context"
+
 	sdk "github.com/aliyun/aliyun-log-go-sdk"
 	"github.com/pkg/errors"
 
 	"github.com/crossplane/provider-alibaba/apis/sls/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients/audit"
 )
 
 var (
@@ -35,14 +38,20 @@ var (
 )
 
 // DescribeIndex describes SLS Logstore index
-func (c *LogClient) DescribeIndex(project, logstore *string) (*sdk.Index, error) {
+func (c *LogClient) DescribeIndex(ctx context.Context, project, logstore *string) (*sdk.Index, error) {
+	if err := c.withTimeout(ctx); err != nil {
+		return nil, err
+	}
 	index, err := c.Client.GetIndex(*project, *logstore)
 	return index, errors.Wrap(err, ErrCodeLogstoreIndexNotExist)
 }
 
 // CreateIndex creates SLS Logstore index
 //nolint:gocyclo
-func (c *LogClient) CreateIndex(param v1alpha1.LogstoreIndexParameters) error {
+func (c *LogClient) CreateIndex(ctx context.Context, param v1alpha1.LogstoreIndexParameters) error {
+	if err := c.withTimeout(ctx); err != nil {
+		return err
+	}
 	keys := map[string]sdk.IndexKey{}
 	for name, v := range param.Keys {
 		key := sdk.IndexKey{
@@ -65,18 +74,26 @@ func (c *LogClient) CreateIndex(param v1alpha1.LogstoreIndexParameters) error {
 		Keys: keys,
 	}
 	err := c.Client.CreateIndex(*param.ProjectName, *param.LogstoreName, index)
+	audit.Call(ctx, auditService, "CreateIndex", map[string]interface{}{"projectName": param.ProjectName, "logstoreName": param.LogstoreName, "index": index}, "", err)
 	return errors.Wrap(err, ErrCreateIndex)
 }
 
 // UpdateIndex updates SLS Logstore index
-func (c *LogClient) UpdateIndex(project, logstore *string, index *sdk.Index) error {
+func (c *LogClient) UpdateIndex(ctx context.Context, project, logstore *string, index *sdk.Index) error {
+	if err := c.withTimeout(ctx); err != nil {
+		return err
+	}
 	// TODO(zzxwill) Need to implement Update SLS Logstore index
 	return nil
 }
 
 // DeleteIndex deletes SLS Logstore index
-func (c *LogClient) DeleteIndex(project, logstore *string) error {
+func (c *LogClient) DeleteIndex(ctx context.Context, project, logstore *string) error {
+	if err := c.withTimeout(ctx); err != nil {
+		return err
+	}
 	err := c.Client.DeleteIndex(*project, *logstore)
+	audit.Call(ctx, auditService, "DeleteIndex", map[string]interface{}{"projectName": project, "logstoreName": logstore}, "", err)
 	return errors.Wrap(err, ErrDeleteIndex)
 }
 
@@ -19,12 +19,14 @@
 package sls
 
 import (
+	"context"
 	"reflect"
 
 	sdk "github.com/aliyun/aliyun-log-go-sdk"
 	"github.com/pkg/errors"
 
 	"github.com/crossplane/provider-alibaba/apis/sls/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients/audit"
 )
 
 var (
@@ -44,14 +46,20 @@ var (
 )
 
 // DescribeMachineGroup describes SLS Logtail MachineGroup
-func (c *LogClient) DescribeMachineGroup(project *string, name string) (*sdk.MachineGroup, error) {
+func (c *LogClient) DescribeMachineGroup(ctx context.Context, project *string, name string) (*sdk.MachineGroup, error) {
+	if err := c.withTimeout(ctx); err != nil {
+		return nil, err
+	}
 	machineGroup, err := c.Client.GetMachineGroup(*project, name)
 	return machineGroup, errors.Wrap(err, ErrCodeMachineGroupNotExist)
 }
 
 // CreateMachineGroup creates SLS Logtail MachineGroup
 //nolint:gocyclo
-func (c *LogClient) CreateMachineGroup(name string, param v1alpha1.MachineGroupParameters) error {
+func (c *LogClient) CreateMachineGroup(ctx context.Context, name string, param v1alpha1.MachineGroupParameters) error {
+	if err := c.withTimeout(ctx); err != nil {
+		return err
+	}
 	machineGroup := &sdk.MachineGroup{
 		Name:          name,
 		MachineIDType: *param.MachineIDType,
@@ -63,18 +71,26 @@ func (c *LogClient) CreateMachineGroup(name string, param v1alpha1.MachineGroupP
 	}
 
 	err := c.Client.CreateMachineGroup(*param.Project, machineGroup)
+	audit.Call(ctx, auditService, "CreateMachineGroup", map[string]interface{}{"projectName": param.Project, "machineGroup": machineGroup}, "", err)
 	return errors.Wrap(err, ErrCreateMachineGroup)
 }
 
 // UpdateMachineGroup updates SLS Logtail MachineGroup
-func (c *LogClient) UpdateMachineGroup(project, logstore *string, machineGroup *sdk.MachineGroup) error {
+func (c *LogClient) UpdateMachineGroup(ctx context.Context, project, logstore *string, machineGroup *sdk.MachineGroup) error {
+	if err := c.withTimeout(ctx); err != nil {
+		return err
+	}
 	// TODO(zzxwill) Need to implement Update SLS Logtail MachineGroup
 	return nil
 }
 
 // DeleteMachineGroup deletes SLS Logtail MachineGroup
-func (c *LogClient) DeleteMachineGroup(project *string, machineGroup string) error {
+func (c *LogClient) DeleteMachineGroup(ctx context.Context, project *string, machineGroup string) error {
+	if err := c.withTimeout(ctx); err != nil {
+		return err
+	}
 	err := c.Client.DeleteMachineGroup(*project, machineGroup)
+	audit.Call(ctx, auditService, "DeleteMachineGroup", map[string]interface{}{"projectName": project, "groupName": machineGroup}, "", err)
 	return errors.Wrap(err, ErrDeleteMachineGroup)
 }
 
@@ -118,23 +134,34 @@ func IsMachineGroupNotFoundError(err error) bool {
 }
 
 // GetAppliedConfigs gets applied configs to a machine group
-func (c *LogClient) GetAppliedConfigs(projectName *string,
+func (c *LogClient) GetAppliedConfigs(ctx context.Context, projectName *string,
 	groupName *string) ([]string, error) {
+	if err := c.withTimeout(ctx); err != nil {
+		return nil, err
+	}
 	configs, err := c.Client.GetAppliedConfigs(*projectName, *groupName)
 	return configs, errors.Wrap(err, ErrGetAppliedConfigs)
 }
 
 // ApplyConfigToMachineGroup applied a config to a machine group
-func (c *LogClient) ApplyConfigToMachineGroup(projectName,
+func (c *LogClient) ApplyConfigToMachineGroup(ctx context.Context, projectName,
 	groupName, confName *string) error {
+	if err := c.withTimeout(ctx); err != nil {
+		return err
+	}
 	err := c.Client.ApplyConfigToMachineGroup(*projectName, *confName, *groupName)
+	audit.Call(ctx, auditService, "ApplyConfigToMachineGroup", map[string]interface{}{"projectName": projectName, "groupName": groupName, "configName": confName}, "", err)
 	return errors.Wrap(err, ErrApplyConfigToMachineGroup)
 }
 
 // RemoveConfigFromMachineGroup remove a config from a machine group
-func (c *LogClient) RemoveConfigFromMachineGroup(projectName,
+func (c *LogClient) RemoveConfigFromMachineGroup(ctx context.Context, projectName,
 	groupName, confName *string) error {
+	if err := c.withTimeout(ctx); err != nil {
+		return err
+	}
 	err := c.Client.RemoveConfigFromMachineGroup(*projectName, *confName, *groupName)
+	audit.Call(ctx, auditService, "RemoveConfigFromMachineGroup", map[string]interface{}{"projectName": projectName, "groupName": groupName, "configName": confName}, "", err)
 	return errors.Wrap(err, ErrRemoveConfigFromMachineGroup)
 }
 
@@ -19,12 +19,15 @@
 package sls
 
 import (
+	"context"
 	"fmt"
 
 	sdk "github.com/aliyun/aliyun-log-go-sdk"
 	"github.com/pkg/errors"
 
 	"github.com/crossplane/provider-alibaba/apis/sls/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/clients/audit"
 )
 
 var (
@@ -52,38 +55,53 @@ var (
 
 	// ErrCodeLogtailNotExist is the error code when Logtail doesn't exist
 	ErrCodeLogtailNotExist = "ConfigNotExist"
+	// ErrFailedToListSLSProjects is the error of failing to list SLS projects
+	ErrFailedToListSLSProjects = "FailedToListSLSProjects"
+	// ErrFailedToListSLSProjectTags is the error of failing to list the tags of SLS projects
+	ErrFailedToListSLSProjectTags = "FailedToListSLSProjectTags"
 )
 
+// listPageSize is the maximum page size of ListProjectV2.
+const listPageSize = 500
+
+// resourceTypeProject is the resource type of SLS projects in the tag API.
+const resourceTypeProject = "project"
+
+// auditService is the service SLS API calls are audited as.
+const auditService = "sls"
+
 // LogClientInterface is the Log client interface
 type LogClientInterface interface {
-	Describe(name string) (*sdk.LogProject, error)
-	Create(name, description string) (*sdk.LogProject, error)
-	Update(name, description string) (*sdk.LogProject, error)
-	Delete(name string) error
-
-	DescribeStore(project string, logstore string) (*sdk.LogStore, error)
-	CreateStore(project string, store *sdk.LogStore) error
-	UpdateStore(project string, logstore string, ttl int) error
-	DeleteStore(project string, logstore string) error
-
-	DescribeConfig(project string, config string) (*sdk.LogConfig, error)
-	CreateConfig(name string, config v1alpha1.LogtailParameters) error
-	UpdateConfig(project string, config *sdk.LogConfig) error
-	DeleteConfig(project string, config string) error
-
-	DescribeIndex(project, logstore *string) (*sdk.Index, error)
-	CreateIndex(param v1alpha1.LogstoreIndexParameters) error
-	UpdateIndex(project, logstore *string, index *sdk.Index) error
-	DeleteIndex(project, logstore *string) error
-
-	DescribeMachineGroup(project *string, name string) (*sdk.MachineGroup, error)
-	CreateMachineGroup(name string, param v1alpha1.MachineGroupParameters) error
-	UpdateMachineGroup(project, logstore *string, machineGroup *sdk.MachineGroup) error
-	DeleteMachineGroup(project *string, logstore string) error
-
-	GetAppliedConfigs(projectName *string, groupName *string) ([]string, error)
-	ApplyConfigToMachineGroup(projectName, groupName, confName *string) error
-	RemoveConfigFromMachineGroup(projectName, groupName, confName *string) error
+	Describe(ctx context.Context, name string) (*sdk.LogProject, error)
+	List(ctx context.Context) ([]sdk.LogProject, error)
+	ListTags(ctx context.Context, tags map[string]string) (map[string]map[string]string, error)
+	Create(ctx context.Context, name, description string) (*sdk.LogProject, error)
+	Update(ctx context.Context, name, description string) (*sdk.LogProject, error)
+	Delete(ctx context.Context, name string) error
+
+	DescribeStore(ctx context.Context, project string, logstore string) (*sdk.LogStore, error)
+	CreateStore(ctx context.Context, project string, store *sdk.LogStore) error
+	UpdateStore(ctx context.Context, project string, logstore string, ttl int) error
+	DeleteStore(ctx context.Context, project string, logstore string) error
+
+	DescribeConfig(ctx context.Context, project string, config string) (*sdk.LogConfig, error)
+	CreateConfig(ctx context.Context, name string, config v1alpha1.LogtailParameters) error
+	UpdateConfig(ctx context.Context, project string, config *sdk.LogConfig) error
+	DeleteConfig(ctx context.Context, project string, config string) error
+
+	DescribeIndex(ctx context.Context, project, logstore *string) (*sdk.Index, error)
+	CreateIndex(ctx context.Context, param v1alpha1.LogstoreIndexParameters) error
+	UpdateIndex(ctx context.Context, project, logstore *string, index *sdk.Index) error
+	DeleteIndex(ctx context.Context, project, logstore *string) error
+
+	DescribeMachineGroup(ctx context.Context, project *string, name string) (*sdk.MachineGroup, error)
+	CreateMachineGroup(ctx context.Context, name string, param v1alpha1.MachineGroupParameters) error
+	UpdateMachineGroup(ctx context.Context, project, logstore *string, machineGroup *sdk.MachineGroup) error
+	DeleteMachineGroup(ctx context.Context, project *string, logstore string) error
+
+	GetAppliedConfigs(ctx context.Context, projectName *string, groupName *string) ([]string, error)
+	ApplyConfigToMachineGroup(ctx context.Context, projectName, groupName, confName *string) error
+	RemoveConfigFromMachineGroup(ctx context.Context, projectName, groupName, confName *string) error
 }
 
 // LogClient is the SDK client of SLS
@@ -98,30 +116,108 @@ func NewClient(accessKeyID, accessKeySecret, securityToken, region string) *LogC
 	return &LogClient{Client: logClient}
 }
 
+// withTimeout sets the request and retry timeouts of the SDK client to the
+// time remaining until the deadline of the supplied context.
+func (c *LogClient) withTimeout(ctx context.Context) error {
+	t, err := clients.Timeout(ctx)
+	if err != nil {
+		return err
+	}
+	if cl, ok := c.Client.(*sdk.Client); ok {
+		cl.RequestTimeOut = t
+		cl.RetryTimeOut = t
+	}
+	return nil
+}
+
 // ----------------------SLS Project------------------------------ //
 
 // Describe describes SLS project
-func (c *LogClient) Describe(name string) (*sdk.LogProject, error) {
+func (c *LogClient) Describe(ctx context.Context, name string) (*sdk.LogProject, error) {
+	if err := c.withTimeout(ctx); err != nil {
+		return nil, err
+	}
 	logProject, err := c.Client.GetProject(name)
 	return logProject, errors.Wrap(err, ErrFailedToGetSLSProject)
 }
 
+// List lists all SLS projects
+func (c *LogClient) List(ctx context.Context) ([]sdk.LogProject, error) {
+	if err := c.withTimeout(ctx); err != nil {
+		return nil, err
+	}
+	var projects []sdk.LogProject
+	for {
+		page, count, total, err := c.Client.ListProjectV2(len(projects), listPageSize)
+		if err != nil {
+			return nil, errors.Wrap(err, ErrFailedToListSLSProjects)
+		}
+		projects = append(projects, page...)
+		if count == 0 || len(projects) >= total {
+			return projects, nil
+		}
+	}
+}
+
+// ListTags lists the tags of the SLS projects that have any of the supplied
+// tags, keyed by project name
+func (c *LogClient) ListTags(ctx context.Context, tags map[string]string) (map[string]map[string]string, error) {
+	if err := c.withTimeout(ctx); err != nil {
+		return nil, err
+	}
+	filter := make([]sdk.ResourceFilterTag, 0, len(tags))
+	for k, v := range tags {
+		k, v := k, v
+		filter = append(filter, sdk.ResourceFilterTag{Key: &k, Value: &v})
+	}
+	projects := map[string]map[string]string{}
+	token := ""
+	for {
+		res, next, err := c.Client.ListTagResources("", resourceTypeProject, []string{}, filter, token)
+		if err != nil {
+			return nil, errors.Wrap(err, ErrFailedToListSLSProjectTags)
+		}
+		for _, t := range res {
+			if projects[t.ResourceID] == nil {
+				projects[t.ResourceID] = map[string]string{}
+			}
+			projects[t.ResourceID][t.TagKey] = t.TagValue
+		}
+		if next == "" {
+			return projects, nil
+		}
+		token = next
+	}
+}
+
 // Create creates SLS project
-func (c *LogClient) Create(name, description string) (*sdk.LogProject, error) {
+func (c *LogClient) Create(ctx context.Context, name, description string) (*sdk.LogProject, error) {
+	if err := c.withTimeout(ctx); err != nil {
+		return nil, err
+	}
 	logProject, err := c.Client.CreateProject(name, description)
+	audit.Call(ctx, auditService, "CreateProject", map[string]interface{}{"projectName": name, "description": description}, "", err)
 	return logProject, errors.Wrap(err, ErrFailedToCreateSLSProject)
 }
 
 // Update updates SLS project's description
-func (c *LogClient) Update(name, description string) (*sdk.LogProject, error) {
+func (c *LogClient) Update(ctx context.Context, name, description string) (*sdk.LogProject, error) {
+	if err := c.withTimeout(ctx); err != nil {
+		return nil, err
+	}
 	logProject, err := c.Client.UpdateProject(name, description)
+	audit.Call(ctx, auditService, "UpdateProject", map[string]interface{}{"projectName": name, "description": description}, "", err)
 	return logProject, errors.Wrap(err, ErrFailedToUpdateSLSProject)
 
 }
 
 // Delete deletes SLS project
-func (c *LogClient) Delete(name string) error {
+func (c *LogClient) Delete(ctx context.Context, name string) error {
+	if err := c.withTimeout(ctx); err != nil {
+		return err
+	}
 	err := c.Client.DeleteProject(name)
+	audit.Call(ctx, auditService, "DeleteProject", map[string]interface{}{"projectName": name}, "", err)
 	return errors.Wrap(err, ErrFailedToDeleteSLSProject)
 }
 
@@ -136,6 +232,13 @@ func GenerateObservation(project *sdk.LogProject) v1alpha1.ProjectObservation {
 	}
 }
 
+// GenerateParameters is used to produce v1alpha1.ProjectParameters
+func GenerateParameters(project *sdk.LogProject) v1alpha1.ProjectParameters {
+	return v1alpha1.ProjectParameters{
+		Description: project.Description,
+	}
+}
+
 // IsNotFoundError helper function to test for SLS project not found error
 func IsNotFoundError(err error) bool {
 	if err == nil {
@@ -153,27 +256,42 @@ func IsNotFoundError(err error) bool {
 // ----------------------SLS LogStore------------------------------ //
 
 // DescribeStore describes SLS store
-func (c *LogClient) DescribeStore(project string, logstore string) (*sdk.LogStore, error) {
+func (c *LogClient) DescribeStore(ctx context.Context, project string, logstore string) (*sdk.LogStore, error) {
+	if err := c.withTimeout(ctx); err != nil {
+		return nil, err
+	}
 	logStore, err := c.Client.GetLogStore(project, logstore)
 	return logStore, errors.Wrap(err, ErrFailedToGetSLSStore)
 }
 
 // CreateStore creates SLS store
-func (c *LogClient) CreateStore(project string, logstore *sdk.LogStore) error {
+func (c *LogClient) CreateStore(ctx context.Context, project string, logstore *sdk.LogStore) error {
+	if err := c.withTimeout(ctx); err != nil {
+		return err
+	}
 	err := c.Client.CreateLogStoreV2(project, logstore)
+	audit.Call(ctx, auditService, "CreateLogStore", map[string]interface{}{"projectName": project, "logstore": logstore}, "", err)
 	return errors.Wrap(err, ErrFailedToCreateSLSStore)
 }
 
 // UpdateStore updates SLS store's description
-func (c *LogClient) UpdateStore(project string, logstore string, ttl int) error {
+func (c *LogClient) UpdateStore(ctx context.Context, project string, logstore string, ttl int) error {
+	if err := c.withTimeout(ctx); err != nil {
+		return err
+	}
 	err := c.Client.UpdateLogStore(project, logstore, ttl, 2)
+	audit.Call(ctx, auditService, "UpdateLogStore", map[string]interface{}{"projectName": project, "logstoreName": logstore, "ttl": ttl, "shardCount": 2}, "", err)
 	return errors.Wrap(err, ErrFailedToUpdateSLSStore)
 
 }
 
 // DeleteStore deletes SLS store
-func (c *LogClient) DeleteStore(project string, logstore string) error {
+func (c *LogClient) DeleteStore(ctx context.Context, project string, logstore string) error {
+	if err := c.withTimeout(ctx); err != nil {
+		return err
+	}
 	err := c.Client.DeleteLogStore(project, logstore)
+	audit.Call(ctx, auditService, "DeleteLogStore", map[string]interface{}{"projectName": project, "logstoreName": logstore}, "", err)
 	return errors.Wrap(err, ErrFailedToDeleteSLSStore)
 }
 
@@ -207,14 +325,20 @@ func IsStoreNotFoundError(err error) bool {
 // ----------------------SLS Logtail------------------------------ //
 
 // DescribeConfig describes SLS Logtail config
-func (c *LogClient) DescribeConfig(project string, config string) (*sdk.LogConfig, error) {
+func (c *LogClient) DescribeConfig(ctx context.Context, project string, config string) (*sdk.LogConfig, error) {
+	if err := c.withTimeout(ctx); err != nil {
+		return nil, err
+	}
 	logStore, err := c.Client.GetConfig(project, config)
 	return logStore, errors.Wrap(err, ErrFailedToGetSLSStore)
 }
 
 // CreateConfig creates SLS Logtail config
 //nolint:gocyclo
-func (c *LogClient) CreateConfig(name string, t v1alpha1.LogtailParameters) error {
+func (c *LogClient) CreateConfig(ctx context.Context, name string, t v1alpha1.LogtailParameters) error {
+	if err := c.withTimeout(ctx); err != nil {
+		return err
+	}
 	in := t.InputDetail
 	inputDetail := sdk.RegexConfigInputDetail{}
 	switch {
@@ -272,9 +396,9 @@ func (c *LogClient) CreateConfig(name string, t v1alpha1.LogtailParameters) erro
 			inputDetail.Regex = *in.Regex
 		}
 	case *t.InputType != "file":
-		return fmt.Errorf("InputType %s is not supported", *t.InputType)
+		return clients.NewTerminalErrorf("InputType %s is not supported", *t.InputType)
 	case *in.LogType == "common_reg_log":
-		return fmt.Errorf("LogType %s is not supported", *in.LogType)
+		return clients.NewTerminalErrorf("LogType %s is not supported", *in.LogType)
 	}
 
 	outputDetail := sdk.OutputDetail{
@@ -292,19 +416,28 @@ func (c *LogClient) CreateConfig(name string, t v1alpha1.LogtailParameters) erro
 		config.LogSample = *t.LogSample
 	}
 	err := c.Client.CreateConfig(t.OutputDetail.ProjectName, config)
+	audit.Call(ctx, auditService, "CreateConfig", map[string]interface{}{"projectName": t.OutputDetail.ProjectName, "config": config}, "", err)
 	return errors.Wrap(err, ErrFailedToCreateSLSStore)
 }
 
 // UpdateConfig updates SLS Logtail config's description
-func (c *LogClient) UpdateConfig(project string, config *sdk.LogConfig) error {
+func (c *LogClient) UpdateConfig(ctx context.Context, project string, config *sdk.LogConfig) error {
+	if err := c.withTimeout(ctx); err != nil {
+		return err
+	}
 	err := c.Client.UpdateConfig(project, config)
+	audit.Call(ctx, auditService, "UpdateConfig", map[string]interface{}{"projectName": project, "config": config}, "", err)
 	return errors.Wrap(err, ErrFailedToUpdateSLSStore)
 
 }
 
 // DeleteConfig deletes SLS Logtail config
-func (c *LogClient) DeleteConfig(project string, config string) error {
+func (c *LogClient) DeleteConfig(ctx context.Context, project string, config string) error {
+	if err := c.withTimeout(ctx); err != nil {
+		return err
+	}
 	err := c.Client.DeleteConfig(project, config)
+	audit.Call(ctx, auditService, "DeleteConfig", map[string]interface{}{"projectName": project, "configName": config}, "", err)
 	return errors.Wrap(err, ErrFailedToDeleteSLSStore)
 }
 
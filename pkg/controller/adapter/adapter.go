@@ -0,0 +1,630 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package adapter wires the kind-specific cloud calls of a managed resource
+// into a managed.ExternalConnecter, so that connecting, type checking,
+// not-found handling and condition setting behave the same for every kind.
+package adapter
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients/audit"
+	"github.com/crossplane/provider-alibaba/pkg/clients/bss"
+	"github.com/crossplane/provider-alibaba/pkg/clients/quotas"
+	"github.com/crossplane/provider-alibaba/pkg/util"
+)
+
+const (
+	errNoProvider        = "no provider config or provider specified"
+	errGetProvider       = "cannot get provider"
+	errGetProviderConfig = "cannot get provider config"
+	errListProviderCfgs  = "cannot list provider configs"
+	errTrackUsage        = "cannot track provider config usage"
+	errNewClient         = "cannot create cloud client"
+	errListDependents    = "cannot list dependent resources"
+	errIndexCloudID      = "cannot index managed resources by cloud ID"
+
+	errFmtNotKind               = "managed resource is not a %s custom resource"
+	errFmtUnsupportedCredSource = "credentials source %q is not currently supported"
+	errFmtNotDiscoverable       = "%s resources cannot be discovered"
+)
+
+// ErrNotFound may be returned by Kind.Describe to indicate that the cloud
+// resource does not exist.
+var ErrNotFound = errors.New("cloud resource does not exist")
+
+// A Kind supplies the kind-specific behaviour of a managed resource. The
+// managed resources passed to its functions are always of the same type as
+// Type, and the clients are always those returned by NewClient.
+type Kind struct {
+	// Type is an empty instance of the managed resource, e.g.
+	// &v1alpha1.Bucket{}.
+	Type resource.Managed
+
+	// GroupVersionKind of the managed resource.
+	GroupVersionKind schema.GroupVersionKind
+
+	// NewClient returns the cloud client used to reconcile the supplied
+	// managed resource.
+	NewClient func(ctx context.Context, mg resource.Managed, creds util.Credentials) (interface{}, error)
+
+	// Describe returns the cloud resource of the supplied managed resource.
+	// It returns ErrNotFound, or an error satisfying IsNotFound, if the cloud
+	// resource does not exist.
+	Describe func(ctx context.Context, client interface{}, mg resource.Managed) (interface{}, error)
+
+	// IsNotFound returns true if the supplied error indicates that the cloud
+	// resource does not exist. Optional.
+	IsNotFound func(err error) bool
+
+	// Observe records the described cloud resource in the status of the
+	// managed resource. Optional.
+	Observe func(mg resource.Managed, observed interface{})
+
+	// LateInitialize fills the unset spec fields of the managed resource from
+	// the described cloud resource and returns true if it changed any.
+	// Optional.
+	LateInitialize func(mg resource.Managed, observed interface{}) bool
+
+	// IsUpToDate returns true if the described cloud resource matches the
+	// spec of the managed resource. Optional; resources are up to date if
+	// unset.
+	IsUpToDate func(mg resource.Managed, observed interface{}) bool
+
+	// Condition returns the availability of the described cloud resource.
+	// Optional; up to date resources are Available if unset.
+	Condition func(mg resource.Managed, observed interface{}) xpv1.Condition
+
+	// ConnectionDetails returns the connection details of the described cloud
+	// resource. It is not called in dry run mode, since it may change the
+	// cloud resource, e.g. to create the account whose password it returns.
+	// Optional.
+	ConnectionDetails func(ctx context.Context, client interface{}, mg resource.Managed, observed interface{}) (managed.ConnectionDetails, error)
+
+	// Create creates the cloud resource of the supplied managed resource.
+	Create func(ctx context.Context, client interface{}, mg resource.Managed) (managed.ExternalCreation, error)
+
+	// Update updates the cloud resource of the supplied managed resource.
+	// Optional; updates are no-ops if unset.
+	Update func(ctx context.Context, client interface{}, mg resource.Managed) (managed.ExternalUpdate, error)
+
+	// Delete deletes the cloud resource of the supplied managed resource.
+	// Errors indicating that the cloud resource does not exist are ignored.
+	Delete func(ctx context.Context, client interface{}, mg resource.Managed) error
+
+	// Dependents returns the managed resources that have to be deleted before
+	// the supplied managed resource can be deleted. Optional.
+	Dependents func(ctx context.Context, kube client.Reader, mg resource.Managed) ([]util.Dependent, error)
+
+	// Discover returns a managed resource for each existing cloud resource
+	// that matches the supplied filter, with its external name and parameters
+	// set from the cloud resource. Its name is set to the name of the cloud
+	// resource, which may not be a valid object name. Optional.
+	Discover func(ctx context.Context, client interface{}, f Filter) ([]resource.Managed, error)
+
+	// Parameters returns the parameters of the described cloud resource in
+	// the form of the desired parameters of the managed resource, so that
+	// dry run mode can report which fields an update would change.
+	// Optional.
+	Parameters func(observed interface{}) interface{}
+
+	// Pricing estimates the cost of the cloud resource of a managed resource
+	// whose ProviderConfig enables cost estimation. Optional.
+	Pricing *Pricing
+
+	// Operations track the asynchronous changes requested by Create and
+	// Update, so that they are not requested again while in progress.
+	// Optional.
+	Operations *Operations
+
+	// CloudID returns the ID of the cloud resource of the supplied managed
+	// resource if it is not its external name, so that cloud change events
+	// can be mapped to it. Optional.
+	CloudID func(mg resource.Managed) string
+
+	// Preflight checks that cloud resources can be created before Create is
+	// called. Optional.
+	Preflight *Preflight
+}
+
+// A Filter selects the cloud resources returned by Kind.Discover.
+type Filter struct {
+	// Region of the cloud resources. Set by Discover from the credentials.
+	Region string
+
+	// NamePrefix selects cloud resources whose name starts with it.
+	NamePrefix string
+
+	// Tags selects cloud resources that have all of these tags.
+	Tags map[string]string
+}
+
+// MatchesName returns true if the supplied cloud resource name is selected.
+func (f Filter) MatchesName(name string) bool {
+	return strings.HasPrefix(name, f.NamePrefix)
+}
+
+// MatchesTags returns true if the supplied cloud resource tags are selected.
+func (f Filter) MatchesTags(tags map[string]string) bool {
+	for k, v := range f.Tags {
+		if t, ok := tags[k]; !ok || t != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (k Kind) check(mg resource.Managed) error {
+	if mg == nil || reflect.TypeOf(mg) != reflect.TypeOf(k.Type) {
+		return errors.Errorf(errFmtNotKind, k.GroupVersionKind.Kind)
+	}
+	return nil
+}
+
+func (k Kind) isNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Cause(err) == ErrNotFound {
+		return true
+	}
+	return k.IsNotFound != nil && (k.IsNotFound(err) || k.IsNotFound(errors.Cause(err)))
+}
+
+// Options configure the controller of a Kind.
+type Options struct {
+	// PollInterval is how often up to date managed resources are observed.
+	// The managed reconciler's default is used if zero.
+	PollInterval time.Duration
+
+	// MaxConcurrentReconciles is the number of managed resources reconciled
+	// concurrently. One if zero.
+	MaxConcurrentReconciles int
+
+	// TerminalErrorWait is how long managed resources whose reconciliation
+	// failed with a terminal error wait before they are reconciled again,
+	// unless their spec changes. Thirty minutes if zero.
+	TerminalErrorWait time.Duration
+
+	// ThrottledWait is how long managed resources whose reconciliation was
+	// throttled by a cloud API wait before they are reconciled again. Two
+	// minutes if zero.
+	ThrottledWait time.Duration
+
+	// ReconcileTimeout bounds the cloud API calls made while reconciling a
+	// managed resource. The managed reconciler's default is used if zero.
+	ReconcileTimeout time.Duration
+
+	// Events enqueues reconciles of managed resources whose cloud resources
+	// changed. Optional.
+	Events *Receiver
+
+	// Audit records the cloud API calls that create, update or delete cloud
+	// resources. Optional.
+	Audit audit.Sink
+}
+
+// Setup adds a controller that reconciles managed resources of the supplied
+// Kind.
+func Setup(mgr ctrl.Manager, l logging.Logger, k Kind, o Options) error {
+	name := managed.ControllerName(k.GroupVersionKind.GroupKind().String())
+	r := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+	errs := newErrorTracker()
+
+	co := []ConnecterOption{WithRecorder(r), withErrorTracker(errs)}
+	if o.Audit != nil {
+		co = append(co, WithAuditSink(o.Audit))
+	}
+	ro := []managed.ReconcilerOption{
+		managed.WithExternalConnecter(NewConnecter(mgr.GetClient(), k, co...)),
+		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(r),
+	}
+	if k.Pricing != nil {
+		ro = append(ro, managed.WithFinalizer(costFinalizer{
+			Finalizer: resource.NewAPIFinalizer(mgr.GetClient(), managedFinalizer),
+			kind:      k,
+		}))
+	}
+	if o.PollInterval > 0 {
+		ro = append(ro, managed.WithLongWait(o.PollInterval))
+	}
+	if o.ReconcileTimeout > 0 {
+		ro = append(ro, managed.WithTimeout(o.ReconcileTimeout))
+	}
+	br := &backoffReconciler{
+		Reconciler:    managed.NewReconciler(mgr, resource.ManagedKind(k.GroupVersionKind), ro...),
+		kube:          mgr.GetClient(),
+		newManaged:    func() resource.Managed { return k.Type.DeepCopyObject().(resource.Managed) },
+		errs:          errs,
+		terminalWait:  defaultTerminalErrorWait,
+		throttledWait: defaultThrottledWait,
+	}
+	if o.TerminalErrorWait > 0 {
+		br.terminalWait = o.TerminalErrorWait
+	}
+	if o.ThrottledWait > 0 {
+		br.throttledWait = o.ThrottledWait
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(k.Type).
+		WithOptions(controller.Options{MaxConcurrentReconciles: o.MaxConcurrentReconciles})
+	if o.Events != nil {
+		if err := mgr.GetFieldIndexer().IndexField(context.Background(), k.Type, indexCloudID, func(o runtime.Object) []string {
+			return k.cloudIDs(o.(resource.Managed))
+		}); err != nil {
+			return errors.Wrap(err, errIndexCloudID)
+		}
+		ch, err := o.Events.register(k, mgr.GetScheme())
+		if err != nil {
+			return err
+		}
+		b = b.Watches(&source.Channel{Source: ch}, &handler.EnqueueRequestForObject{})
+	}
+	return b.Complete(br)
+}
+
+// Discover returns managed resources for the existing cloud resources of the
+// supplied Kind that match the supplied filter.
+func Discover(ctx context.Context, k Kind, creds util.Credentials, f Filter) ([]resource.Managed, error) {
+	if k.Discover == nil {
+		return nil, errors.Errorf(errFmtNotDiscoverable, k.GroupVersionKind.Kind)
+	}
+	mg := k.Type.DeepCopyObject().(resource.Managed)
+	mg.GetObjectKind().SetGroupVersionKind(k.GroupVersionKind)
+	cl, err := k.NewClient(ctx, mg, creds)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+	f.Region = creds.Region
+	return k.Discover(ctx, cl, f)
+}
+
+// A ConnecterOption configures a connecter.
+type ConnecterOption func(*connector)
+
+// WithRecorder configures the recorder used to emit the events of dry run
+// mode.
+func WithRecorder(r event.Recorder) ConnecterOption {
+	return func(c *connector) {
+		c.record = r
+	}
+}
+
+// withErrorTracker configures the tracker the classes of the errors returned
+// by external clients are recorded in.
+func withErrorTracker(t *errorTracker) ConnecterOption {
+	return func(c *connector) {
+		c.errs = t
+	}
+}
+
+// NewConnecter returns a managed.ExternalConnecter for the supplied Kind.
+func NewConnecter(kube client.Client, k Kind, o ...ConnecterOption) managed.ExternalConnecter {
+	c := &connector{
+		kube:             kube,
+		usage:            resource.NewProviderConfigUsageTracker(kube, &aliv1alpha1.ProviderConfigUsage{}),
+		kind:             k,
+		record:           event.NewNopRecorder(),
+		newPricingClient: newPricingClient,
+		newQuotaClient:   newQuotaClient,
+	}
+	for _, fn := range o {
+		fn(c)
+	}
+	return c
+}
+
+type connector struct {
+	kube             client.Client
+	usage            resource.Tracker
+	kind             Kind
+	record           event.Recorder
+	newPricingClient NewPricingClientFn
+	newQuotaClient   NewQuotaClientFn
+	errs             *errorTracker
+	audit            audit.Sink
+}
+
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if err := c.kind.check(mg); err != nil {
+		return nil, err
+	}
+
+	var (
+		sel     *xpv1.SecretKeySelector
+		region  string
+		dryRun  = DryRun(mg)
+		priced  bool
+		timeout time.Duration
+	)
+	switch {
+	case mg.GetProviderConfigReference() != nil:
+		if err := c.usage.Track(ctx, mg); err != nil {
+			return nil, errors.Wrap(err, errTrackUsage)
+		}
+
+		pc := &aliv1alpha1.ProviderConfig{}
+		if err := c.kube.Get(ctx, types.NamespacedName{Name: mg.GetProviderConfigReference().Name}, pc); err != nil {
+			return nil, errors.Wrap(err, errGetProviderConfig)
+		}
+		if s := pc.Spec.Credentials.Source; s != xpv1.CredentialsSourceSecret {
+			return nil, errors.Errorf(errFmtUnsupportedCredSource, s)
+		}
+
+		// The condition is only reported for ProviderConfigs with a policy,
+		// and once a resource has been denied.
+		err := authorize(pc, mg)
+		if pc.Spec.Policy != nil || mg.GetCondition(TypeAuthorized).Status == corev1.ConditionFalse {
+			mg.SetConditions(authorized(err))
+		}
+		if err != nil {
+			return nil, err
+		}
+		sel = pc.Spec.Credentials.SecretRef
+		region = pc.Spec.Region
+		dryRun = dryRun || pc.Spec.DryRun
+		priced = pc.Spec.CostEstimation && c.kind.Pricing != nil
+		if pc.Spec.OperationTimeout != nil {
+			timeout = pc.Spec.OperationTimeout.Duration
+		}
+	case mg.GetProviderReference() != nil:
+		p := &aliv1alpha1.Provider{}
+		if err := c.kube.Get(ctx, types.NamespacedName{Name: mg.GetProviderReference().Name}, p); err != nil {
+			return nil, errors.Wrap(err, errGetProvider)
+		}
+		pcs := &aliv1alpha1.ProviderConfigList{}
+		if err := c.kube.List(ctx, pcs); err != nil {
+			return nil, errors.Wrap(err, errListProviderCfgs)
+		}
+		if err := authorizeProvider(pcs.Items); err != nil {
+			mg.SetConditions(authorized(err))
+			return nil, err
+		}
+		sel = p.Spec.CredentialsSecretRef
+		region = p.Spec.Region
+	default:
+		return nil, errors.New(errNoProvider)
+	}
+
+	creds, err := util.GetCredentials(ctx, c.kube, sel, region)
+	if err != nil {
+		return nil, err
+	}
+
+	cl, err := c.kind.NewClient(ctx, mg, creds)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+	e := &external{kind: c.kind, kube: c.kube, client: cl, region: creds.Region, record: c.record}
+	if priced {
+		if e.pricer, err = c.newPricingClient(ctx, creds); err != nil {
+			return nil, errors.Wrap(err, errNewPricingClient)
+		}
+	}
+	if c.kind.checksQuotas() {
+		if e.quotas, err = c.newQuotaClient(ctx, creds); err != nil {
+			return nil, errors.Wrap(err, errNewQuotaClient)
+		}
+	}
+	var ec managed.ExternalClient = e
+	if c.audit != nil {
+		ec = &auditedExternal{ExternalClient: ec, kind: c.kind, sink: c.audit}
+	}
+	if dryRun {
+		ec = newDryRunExternal(e, c.record)
+	}
+	if timeout > 0 {
+		ec = &timeoutExternal{ExternalClient: ec, timeout: timeout}
+	}
+	if c.errs != nil {
+		ec = &trackedExternal{ExternalClient: ec, errs: c.errs}
+	}
+	return ec, nil
+}
+
+// NewExternalClient returns a managed.ExternalClient that reconciles managed
+// resources of the supplied Kind using the supplied cloud client.
+func NewExternalClient(k Kind, kube client.Client, cloud interface{}) managed.ExternalClient {
+	return &external{kind: k, kube: kube, client: cloud, record: event.NewNopRecorder()}
+}
+
+type external struct {
+	kind   Kind
+	kube   client.Client
+	client interface{}
+
+	// pricer estimates the cost of cloud resources. Costs are not estimated
+	// if it is nil.
+	pricer bss.Client
+
+	// quotas reads the quotas checked before cloud resources are created.
+	// Quotas are not checked if it is nil.
+	quotas quotas.Client
+	region string
+	record event.Recorder
+
+	// observed is the cloud resource described by Observe, which precedes
+	// Update in each reconcile.
+	observed interface{}
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	o, observed, err := e.observe(ctx, mg, true)
+	e.observed = observed
+	if err == nil {
+		clearDryRun(mg)
+	}
+	return o, err
+}
+
+// observe observes the cloud resource of the supplied managed resource and
+// also returns the described cloud resource, which is nil if it does not
+// exist. Connection details are only returned if details is true.
+func (e *external) observe(ctx context.Context, mg resource.Managed, details bool) (managed.ExternalObservation, interface{}, error) {
+	if err := e.kind.check(mg); err != nil {
+		return managed.ExternalObservation{}, nil, err
+	}
+
+	// Kind.Observe may overwrite the cost estimate and the pending operation
+	// in the status.
+	cost := e.kind.costEstimate(mg)
+	op := e.kind.pendingOperation(mg)
+
+	observed, err := e.kind.Describe(ctx, e.client, mg)
+	if e.kind.isNotFound(err) {
+		e.estimateCost(ctx, mg, cost)
+		e.trackOperation(mg, op, nil, false)
+		return managed.ExternalObservation{ResourceExists: false}, nil, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, nil, err
+	}
+
+	if e.kind.Observe != nil {
+		e.kind.Observe(mg, observed)
+	}
+	e.estimateCost(ctx, mg, cost)
+
+	lateInitialized := false
+	if e.kind.LateInitialize != nil {
+		lateInitialized = e.kind.LateInitialize(mg, observed)
+	}
+
+	upToDate := e.kind.IsUpToDate == nil || e.kind.IsUpToDate(mg, observed)
+	e.trackOperation(mg, op, observed, upToDate)
+	switch {
+	case e.kind.Condition != nil:
+		mg.SetConditions(e.kind.Condition(mg, observed))
+	case upToDate:
+		mg.SetConditions(xpv1.Available())
+	}
+
+	var cd managed.ConnectionDetails
+	if details && e.kind.ConnectionDetails != nil {
+		if cd, err = e.kind.ConnectionDetails(ctx, e.client, mg, observed); err != nil {
+			return managed.ExternalObservation{}, nil, err
+		}
+	}
+
+	return managed.ExternalObservation{
+		ResourceExists:          true,
+		ResourceUpToDate:        upToDate,
+		ResourceLateInitialized: lateInitialized,
+		ConnectionDetails:       cd,
+	}, observed, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	if err := e.kind.check(mg); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	if err := e.enforcePolicies(ctx, mg, false); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	mg.SetConditions(xpv1.Creating())
+	if inFlight, err := e.kind.inFlight(mg); err != nil || inFlight {
+		return managed.ExternalCreation{}, err
+	}
+	if err := e.preflight(ctx, mg); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	// Kind.Create may overwrite the cost estimate in the status.
+	cost := e.kind.costEstimate(mg)
+	ctx, op, err := e.kind.newOperation(ctx, mg, OperationCreate)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	c, err := e.kind.Create(ctx, e.client, mg)
+	if e.kind.Pricing != nil {
+		e.kind.Pricing.SetEstimate(mg, cost)
+	}
+	if err == nil {
+		e.kind.startOperation(ctx, mg, op)
+	}
+	return c, err
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	if err := e.kind.check(mg); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	if e.kind.Update == nil {
+		return managed.ExternalUpdate{}, nil
+	}
+	if err := e.enforcePolicies(ctx, mg, true); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	if inFlight, err := e.kind.inFlight(mg); err != nil || inFlight {
+		return managed.ExternalUpdate{}, err
+	}
+	ctx, op, err := e.kind.newOperation(ctx, mg, OperationUpdate)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	u, err := e.kind.Update(ctx, e.client, mg)
+	if err == nil {
+		e.kind.startOperation(ctx, mg, op)
+	}
+	return u, err
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	if err := e.kind.check(mg); err != nil {
+		return err
+	}
+
+	if e.kind.Dependents != nil {
+		dependents, err := e.kind.Dependents(ctx, e.kube, mg)
+		if err != nil {
+			return errors.Wrap(err, errListDependents)
+		}
+		if wait, err := util.WaitForDependents(ctx, e.kube, mg, dependents); wait || err != nil {
+			return err
+		}
+	}
+
+	mg.SetConditions(xpv1.Deleting())
+	if err := e.kind.Delete(ctx, e.client, mg); err != nil && !e.kind.isNotFound(err) {
+		return err
+	}
+	return nil
+}
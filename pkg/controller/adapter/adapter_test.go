@@ -0,0 +1,544 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-alibaba/apis/oss/v1alpha1"
+	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/util"
+)
+
+var errBoom = errors.New("boom")
+
+// bucketKind returns a Kind whose cloud resource is a string held by the
+// cloud client, which is a *string.
+func bucketKind() Kind {
+	return Kind{
+		Type:             &v1alpha1.Bucket{},
+		GroupVersionKind: v1alpha1.BucketGroupVersionKind,
+		NewClient: func(_ context.Context, _ resource.Managed, creds util.Credentials) (interface{}, error) {
+			return &creds.Region, nil
+		},
+		Describe: func(_ context.Context, c interface{}, _ resource.Managed) (interface{}, error) {
+			switch s := *c.(*string); s {
+			case "":
+				return nil, ErrNotFound
+			case "boom":
+				return nil, errBoom
+			default:
+				return s, nil
+			}
+		},
+		IsUpToDate: func(mg resource.Managed, observed interface{}) bool {
+			return mg.(*v1alpha1.Bucket).Spec.ACL == observed.(string)
+		},
+		ConnectionDetails: func(_ context.Context, _ interface{}, _ resource.Managed, observed interface{}) (managed.ConnectionDetails, error) {
+			return managed.ConnectionDetails{"Bucket": []byte(observed.(string))}, nil
+		},
+		Create: func(_ context.Context, c interface{}, _ resource.Managed) (managed.ExternalCreation, error) {
+			if *c.(*string) == "boom" {
+				return managed.ExternalCreation{}, errBoom
+			}
+			return managed.ExternalCreation{}, nil
+		},
+		Delete: func(_ context.Context, c interface{}, _ resource.Managed) error {
+			switch *c.(*string) {
+			case "":
+				return ErrNotFound
+			case "boom":
+				return errBoom
+			default:
+				return nil
+			}
+		},
+	}
+}
+
+func TestConnect(t *testing.T) {
+	type fields struct {
+		kube  client.Client
+		usage resource.Tracker
+		kind  Kind
+	}
+
+	pcWithSecret := func(obj runtime.Object) error {
+		if t, ok := obj.(*aliv1alpha1.ProviderConfig); ok {
+			*t = aliv1alpha1.ProviderConfig{
+				Spec: aliv1alpha1.ProviderConfigSpec{
+					ProviderConfigSpec: xpv1.ProviderConfigSpec{
+						Credentials: xpv1.ProviderCredentials{
+							Source: xpv1.CredentialsSourceSecret,
+							SecretRef: &xpv1.SecretKeySelector{
+								SecretReference: xpv1.SecretReference{
+									Name: "coolsecret",
+								},
+							},
+						},
+					},
+				},
+			}
+		}
+		return nil
+	}
+	withProviderConfig := &v1alpha1.Bucket{
+		Spec: v1alpha1.BucketSpec{
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: &xpv1.Reference{},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		reason string
+		fields fields
+		mg     resource.Managed
+		want   error
+	}{
+		"NotKind": {
+			reason: "Should return an error if the supplied managed resource is not of the Kind",
+			fields: fields{
+				kind: bucketKind(),
+			},
+			mg:   nil,
+			want: errors.Errorf(errFmtNotKind, v1alpha1.BucketKind),
+		},
+		"NoProvider": {
+			reason: "Should return an error if neither a ProviderConfig nor a Provider is referenced",
+			fields: fields{
+				kind: bucketKind(),
+			},
+			mg:   &v1alpha1.Bucket{},
+			want: errors.New(errNoProvider),
+		},
+		"TrackProviderConfigUsageError": {
+			reason: "Errors tracking a ProviderConfigUsage should be returned",
+			fields: fields{
+				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return errBoom }),
+				kind:  bucketKind(),
+			},
+			mg:   withProviderConfig,
+			want: errors.Wrap(errBoom, errTrackUsage),
+		},
+		"GetProviderConfigError": {
+			reason: "Errors getting a ProviderConfig should be returned",
+			fields: fields{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				},
+				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
+				kind:  bucketKind(),
+			},
+			mg:   withProviderConfig,
+			want: errors.Wrap(errBoom, errGetProviderConfig),
+		},
+		"UnsupportedCredentialsError": {
+			reason: "An error should be returned if the selected credentials source is unsupported",
+			fields: fields{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+						t := obj.(*aliv1alpha1.ProviderConfig)
+						*t = aliv1alpha1.ProviderConfig{
+							Spec: aliv1alpha1.ProviderConfigSpec{
+								ProviderConfigSpec: xpv1.ProviderConfigSpec{
+									Credentials: xpv1.ProviderCredentials{
+										Source: xpv1.CredentialsSource("wat"),
+									},
+								},
+							},
+						}
+						return nil
+					}),
+				},
+				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
+				kind:  bucketKind(),
+			},
+			mg:   withProviderConfig,
+			want: errors.Errorf(errFmtUnsupportedCredSource, "wat"),
+		},
+		"GetProviderError": {
+			reason: "Errors getting a Provider should be returned",
+			fields: fields{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				},
+				kind: bucketKind(),
+			},
+			mg: &v1alpha1.Bucket{
+				Spec: v1alpha1.BucketSpec{
+					ResourceSpec: xpv1.ResourceSpec{
+						ProviderReference: &xpv1.Reference{},
+					},
+				},
+			},
+			want: errors.Wrap(errBoom, errGetProvider),
+		},
+		"ListProviderConfigsError": {
+			reason: "Errors listing the ProviderConfigs whose policies a Provider would bypass should be returned",
+			fields: fields{
+				kube: &test.MockClient{
+					MockGet:  test.NewMockGetFn(nil),
+					MockList: test.NewMockListFn(errBoom),
+				},
+				kind: bucketKind(),
+			},
+			mg: &v1alpha1.Bucket{
+				Spec: v1alpha1.BucketSpec{
+					ResourceSpec: xpv1.ResourceSpec{
+						ProviderReference: &xpv1.Reference{},
+					},
+				},
+			},
+			want: errors.Wrap(errBoom, errListProviderCfgs),
+		},
+		"NewClientError": {
+			reason: "Errors creating a cloud client should be returned",
+			fields: fields{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, pcWithSecret),
+				},
+				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
+				kind: func() Kind {
+					k := bucketKind()
+					k.NewClient = func(_ context.Context, _ resource.Managed, _ util.Credentials) (interface{}, error) {
+						return nil, errBoom
+					}
+					return k
+				}(),
+			},
+			mg:   withProviderConfig,
+			want: errors.Wrap(errBoom, errNewClient),
+		},
+		"Success": {
+			reason: "No error should be returned if the cloud client was created",
+			fields: fields{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, pcWithSecret),
+				},
+				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
+				kind:  bucketKind(),
+			},
+			mg:   withProviderConfig,
+			want: nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &connector{kube: tc.fields.kube, usage: tc.fields.usage, kind: tc.fields.kind}
+			_, err := c.Connect(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Connect(...) -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObserve(t *testing.T) {
+	type want struct {
+		o    managed.ExternalObservation
+		err  error
+		cond xpv1.Condition
+	}
+
+	cases := map[string]struct {
+		reason string
+		cloud  string
+		mg     resource.Managed
+		want   want
+	}{
+		"NotKind": {
+			reason: "We should return an error if the supplied managed resource is not of the Kind",
+			mg:     nil,
+			want: want{
+				err: errors.Errorf(errFmtNotKind, v1alpha1.BucketKind),
+			},
+		},
+		"NotFound": {
+			reason: "We should report that the cloud resource does not exist",
+			cloud:  "",
+			mg:     &v1alpha1.Bucket{},
+			want: want{
+				o:    managed.ExternalObservation{ResourceExists: false},
+				cond: xpv1.Condition{Type: xpv1.TypeReady, Status: corev1.ConditionUnknown},
+			},
+		},
+		"DescribeFailed": {
+			reason: "We should return errors describing the cloud resource",
+			cloud:  "boom",
+			mg:     &v1alpha1.Bucket{},
+			want: want{
+				err:  errBoom,
+				cond: xpv1.Condition{Type: xpv1.TypeReady, Status: corev1.ConditionUnknown},
+			},
+		},
+		"NotUpToDate": {
+			reason: "We should report a cloud resource that differs from the spec as not up to date",
+			cloud:  "private",
+			mg:     &v1alpha1.Bucket{Spec: v1alpha1.BucketSpec{BucketParameter: v1alpha1.BucketParameter{ACL: "public-read"}}},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: managed.ConnectionDetails{"Bucket": []byte("private")},
+				},
+				cond: xpv1.Condition{Type: xpv1.TypeReady, Status: corev1.ConditionUnknown},
+			},
+		},
+		"Available": {
+			reason: "We should report an up to date cloud resource as available",
+			cloud:  "private",
+			mg:     &v1alpha1.Bucket{Spec: v1alpha1.BucketSpec{BucketParameter: v1alpha1.BucketParameter{ACL: "private"}}},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{"Bucket": []byte("private")},
+				},
+				cond: xpv1.Available(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cloud := tc.cloud
+			e := NewExternalClient(bucketKind(), nil, &cloud)
+			got, err := e.Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if tc.mg == nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.cond, tc.mg.GetCondition(xpv1.TypeReady), test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want condition, +got condition:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type want struct {
+		err  error
+		cond xpv1.Condition
+	}
+
+	cases := map[string]struct {
+		reason string
+		cloud  string
+		mg     resource.Managed
+		want   want
+	}{
+		"NotKind": {
+			reason: "We should return an error if the supplied managed resource is not of the Kind",
+			mg:     nil,
+			want: want{
+				err: errors.Errorf(errFmtNotKind, v1alpha1.BucketKind),
+			},
+		},
+		"CreateFailed": {
+			reason: "We should return errors creating the cloud resource",
+			cloud:  "boom",
+			mg:     &v1alpha1.Bucket{},
+			want: want{
+				err:  errBoom,
+				cond: xpv1.Creating(),
+			},
+		},
+		"Success": {
+			reason: "We should mark the managed resource as creating",
+			cloud:  "private",
+			mg:     &v1alpha1.Bucket{},
+			want: want{
+				cond: xpv1.Creating(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cloud := tc.cloud
+			e := NewExternalClient(bucketKind(), nil, &cloud)
+			_, err := e.Create(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if tc.mg == nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.cond, tc.mg.GetCondition(xpv1.TypeReady), test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want condition, +got condition:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		mg     resource.Managed
+		want   error
+	}{
+		"NotKind": {
+			reason: "We should return an error if the supplied managed resource is not of the Kind",
+			mg:     nil,
+			want:   errors.Errorf(errFmtNotKind, v1alpha1.BucketKind),
+		},
+		"NoUpdate": {
+			reason: "Updates should be no-ops if the Kind does not support them",
+			mg:     &v1alpha1.Bucket{},
+			want:   nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cloud := ""
+			e := NewExternalClient(bucketKind(), nil, &cloud)
+			_, err := e.Update(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	withDependents := func(k Kind) Kind {
+		k.Dependents = func(_ context.Context, c client.Reader, _ resource.Managed) ([]util.Dependent, error) {
+			l := &v1alpha1.BucketList{}
+			if err := c.List(context.Background(), l); err != nil {
+				return nil, err
+			}
+			var dependents []util.Dependent
+			for i := range l.Items {
+				dependents = append(dependents, util.Dependent{Kind: v1alpha1.BucketKind, Managed: &l.Items[i]})
+			}
+			return dependents, nil
+		}
+		return k
+	}
+
+	type want struct {
+		err  error
+		cond xpv1.Condition
+	}
+
+	cases := map[string]struct {
+		reason string
+		kind   Kind
+		kube   client.Client
+		cloud  string
+		mg     resource.Managed
+		want   want
+	}{
+		"NotKind": {
+			reason: "We should return an error if the supplied managed resource is not of the Kind",
+			kind:   bucketKind(),
+			mg:     nil,
+			want: want{
+				err: errors.Errorf(errFmtNotKind, v1alpha1.BucketKind),
+			},
+		},
+		"ListDependentsFailed": {
+			reason: "Errors listing dependent resources should be returned",
+			kind:   withDependents(bucketKind()),
+			kube:   &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			mg:     &v1alpha1.Bucket{},
+			want: want{
+				err:  errors.Wrap(errBoom, errListDependents),
+				cond: xpv1.Condition{Type: xpv1.TypeReady, Status: corev1.ConditionUnknown},
+			},
+		},
+		"WaitForDependents": {
+			reason: "We should not delete the cloud resource while dependent resources exist",
+			kind:   withDependents(bucketKind()),
+			kube: &test.MockClient{MockList: test.NewMockListFn(nil, func(obj runtime.Object) error {
+				obj.(*v1alpha1.BucketList).Items = []v1alpha1.Bucket{{ObjectMeta: metav1.ObjectMeta{Name: "child"}}}
+				return nil
+			})},
+			cloud: "boom",
+			mg:    &v1alpha1.Bucket{},
+			want: want{
+				cond: xpv1.Deleting().WithMessage("waiting for dependent resources to be deleted: Bucket/child"),
+			},
+		},
+		"DeleteFailed": {
+			reason: "We should return errors deleting the cloud resource",
+			kind:   bucketKind(),
+			cloud:  "boom",
+			mg:     &v1alpha1.Bucket{},
+			want: want{
+				err:  errBoom,
+				cond: xpv1.Deleting(),
+			},
+		},
+		"NotFound": {
+			reason: "We should ignore cloud resources that do not exist",
+			kind:   bucketKind(),
+			cloud:  "",
+			mg:     &v1alpha1.Bucket{},
+			want: want{
+				cond: xpv1.Deleting(),
+			},
+		},
+		"Success": {
+			reason: "We should mark the managed resource as deleting",
+			kind:   withDependents(bucketKind()),
+			kube:   &test.MockClient{MockList: test.NewMockListFn(nil)},
+			cloud:  "private",
+			mg:     &v1alpha1.Bucket{},
+			want: want{
+				cond: xpv1.Deleting(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cloud := tc.cloud
+			e := NewExternalClient(tc.kind, tc.kube, &cloud)
+			err := e.Delete(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if tc.mg == nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.cond, tc.mg.GetCondition(xpv1.TypeReady), test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want condition, +got condition:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-alibaba/pkg/clients/audit"
+)
+
+// WithAuditSink configures the sink the cloud API calls that create, update or
+// delete cloud resources are recorded to.
+func WithAuditSink(s audit.Sink) ConnecterOption {
+	return func(c *connector) {
+		c.audit = s
+	}
+}
+
+// An auditedExternal makes the cloud clients record the calls made by each
+// operation of an external client for the managed resource of the operation.
+// Observe is audited too, since it may change cloud resources, e.g. by
+// creating the accounts of database instances.
+type auditedExternal struct {
+	managed.ExternalClient
+	kind Kind
+	sink audit.Sink
+}
+
+// resource returns the audited identity of the supplied managed resource.
+func (e *auditedExternal) resource(mg resource.Managed) audit.Resource {
+	r := audit.Resource{
+		APIVersion: e.kind.GroupVersionKind.GroupVersion().String(),
+		Kind:       e.kind.GroupVersionKind.Kind,
+		Name:       mg.GetName(),
+	}
+	switch {
+	case mg.GetProviderConfigReference() != nil:
+		r.ProviderConfig = mg.GetProviderConfigReference().Name
+	case mg.GetProviderReference() != nil:
+		r.ProviderConfig = mg.GetProviderReference().Name
+	}
+	return r
+}
+
+func (e *auditedExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	return e.ExternalClient.Observe(audit.WithResource(ctx, e.sink, e.resource(mg)), mg)
+}
+
+func (e *auditedExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	return e.ExternalClient.Create(audit.WithResource(ctx, e.sink, e.resource(mg)), mg)
+}
+
+func (e *auditedExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	return e.ExternalClient.Update(audit.WithResource(ctx, e.sink, e.resource(mg)), mg)
+}
+
+func (e *auditedExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	return e.ExternalClient.Delete(audit.WithResource(ctx, e.sink, e.resource(mg)), mg)
+}
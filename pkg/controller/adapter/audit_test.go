@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/crossplane/provider-alibaba/apis/oss/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients/audit"
+)
+
+type auditRecorder struct {
+	records []audit.Record
+}
+
+func (s *auditRecorder) Write(r audit.Record) error {
+	s.records = append(s.records, r)
+	return nil
+}
+
+func TestAuditedExternal(t *testing.T) {
+	k := bucketKind()
+	k.Create = func(ctx context.Context, _ interface{}, _ resource.Managed) (managed.ExternalCreation, error) {
+		audit.Call(ctx, "oss", "PutBucket", map[string]string{"BucketName": "cool"}, "", nil)
+		return managed.ExternalCreation{}, nil
+	}
+	k.Delete = func(ctx context.Context, _ interface{}, _ resource.Managed) error {
+		audit.Call(ctx, "oss", "DeleteBucket", map[string]string{"BucketName": "cool"}, "", nil)
+		return nil
+	}
+
+	mg := &v1alpha1.Bucket{Spec: v1alpha1.BucketSpec{ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: "default"}}}}
+	mg.SetName("cool-bucket")
+
+	cloud := "cool"
+	s := &auditRecorder{}
+	e := &auditedExternal{ExternalClient: &external{kind: k, client: &cloud, record: &recorder{}}, kind: k, sink: s}
+	if _, err := e.Create(context.Background(), mg); err != nil {
+		t.Fatalf("e.Create(...): %s", err)
+	}
+	if err := e.Delete(context.Background(), mg); err != nil {
+		t.Fatalf("e.Delete(...): %s", err)
+	}
+
+	r := audit.Resource{APIVersion: "oss.alibaba.crossplane.io/v1alpha1", Kind: "Bucket", Name: "cool-bucket", ProviderConfig: "default"}
+	want := []audit.Record{
+		{Resource: r, Service: "oss", Action: "PutBucket", Parameters: map[string]interface{}{"BucketName": "cool"}, Result: audit.ResultSuccess},
+		{Resource: r, Service: "oss", Action: "DeleteBucket", Parameters: map[string]interface{}{"BucketName": "cool"}, Result: audit.ResultSuccess},
+	}
+	if diff := cmp.Diff(want, s.records, cmpopts.IgnoreFields(audit.Record{}, "Time")); diff != "" {
+		t.Errorf("e.Create(...), e.Delete(...): -want records, +got records:\n%s", diff)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
+)
+
+// TypeAuthorized conditions report whether a managed resource may use its
+// ProviderConfig.
+const TypeAuthorized xpv1.ConditionType = "Authorized"
+
+// Reasons of TypeAuthorized conditions.
+const (
+	ReasonAllowed xpv1.ConditionReason = "ProviderConfigAllowed"
+	ReasonDenied  xpv1.ConditionReason = "ProviderConfigDenied"
+)
+
+const (
+	errInvalidSelector          = "ProviderConfig policy has an invalid selector"
+	errFmtLabelsDenied          = "ProviderConfig %s may not be used by managed resources with labels %q"
+	errFmtSecretNamespaceDenied = "ProviderConfig %s may not read secrets in namespace %q"
+	errFmtProviderDenied        = "Providers may not be used while ProviderConfig %s has a policy; use a providerConfigRef"
+)
+
+// authorize returns an error if the supplied managed resource may not use the
+// supplied ProviderConfig.
+func authorize(pc *aliv1alpha1.ProviderConfig, mg resource.Managed) error {
+	p := pc.Spec.Policy
+	if p == nil {
+		return nil
+	}
+
+	if p.Selector != nil {
+		s, err := metav1.LabelSelectorAsSelector(p.Selector)
+		if err != nil {
+			return errors.Wrap(err, errInvalidSelector)
+		}
+		if !s.Matches(labels.Set(mg.GetLabels())) {
+			return errors.Errorf(errFmtLabelsDenied, pc.GetName(), labels.Set(mg.GetLabels()).String())
+		}
+	}
+
+	if len(p.AllowedSecretNamespaces) > 0 {
+		if ref := pc.Spec.Credentials.SecretRef; ref != nil && !contains(p.AllowedSecretNamespaces, ref.Namespace) {
+			return errors.Errorf(errFmtSecretNamespaceDenied, pc.GetName(), ref.Namespace)
+		}
+	}
+
+	return nil
+}
+
+// authorizeProvider returns an error if any of the supplied ProviderConfigs
+// has a policy. Legacy Providers have no policy, so managed resources
+// referencing one would bypass the policies of ProviderConfigs.
+func authorizeProvider(pcs []aliv1alpha1.ProviderConfig) error {
+	for _, pc := range pcs {
+		if pc.Spec.Policy != nil {
+			return errors.Errorf(errFmtProviderDenied, pc.GetName())
+		}
+	}
+	return nil
+}
+
+// authorized returns the TypeAuthorized condition of the supplied
+// authorization error.
+func authorized(err error) xpv1.Condition {
+	c := xpv1.Condition{
+		Type:               TypeAuthorized,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonAllowed,
+	}
+	if err != nil {
+		c.Status = corev1.ConditionFalse
+		c.Reason = ReasonDenied
+		c.Message = err.Error()
+	}
+	return c
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/provider-alibaba/apis/oss/v1alpha1"
+	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
+)
+
+func providerConfig(p *aliv1alpha1.ProviderConfigPolicy) *aliv1alpha1.ProviderConfig {
+	pc := &aliv1alpha1.ProviderConfig{
+		Spec: aliv1alpha1.ProviderConfigSpec{
+			ProviderConfigSpec: xpv1.ProviderConfigSpec{
+				Credentials: xpv1.ProviderCredentials{
+					Source: xpv1.CredentialsSourceSecret,
+					SecretRef: &xpv1.SecretKeySelector{
+						SecretReference: xpv1.SecretReference{Namespace: "crossplane-system", Name: "coolsecret"},
+					},
+				},
+			},
+			Policy: p,
+		},
+	}
+	pc.SetName("team-a")
+	return pc
+}
+
+func managedWith(labels, annotations map[string]string) resource.Managed {
+	mg := &v1alpha1.Bucket{}
+	mg.SetLabels(labels)
+	mg.SetAnnotations(annotations)
+	return mg
+}
+
+func TestAuthorize(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		pc     *aliv1alpha1.ProviderConfig
+		mg     resource.Managed
+		want   error
+	}{
+		"NoPolicy": {
+			reason: "All managed resources may use a ProviderConfig without a policy",
+			pc:     providerConfig(nil),
+			mg:     managedWith(nil, nil),
+		},
+		"LabelsDenied": {
+			reason: "Managed resources not matching the selector may not use the ProviderConfig",
+			pc: providerConfig(&aliv1alpha1.ProviderConfigPolicy{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+			}),
+			mg:   managedWith(map[string]string{"team": "b"}, nil),
+			want: errors.Errorf(errFmtLabelsDenied, "team-a", "team=b"),
+		},
+		"SecretNamespaceDenied": {
+			reason: "A ProviderConfig may not read credentials from namespaces that are not allowed",
+			pc:     providerConfig(&aliv1alpha1.ProviderConfigPolicy{AllowedSecretNamespaces: []string{"team-a"}}),
+			mg:     managedWith(nil, nil),
+			want:   errors.Errorf(errFmtSecretNamespaceDenied, "team-a", "crossplane-system"),
+		},
+		"Allowed": {
+			reason: "Managed resources satisfying all rules may use the ProviderConfig",
+			pc: providerConfig(&aliv1alpha1.ProviderConfigPolicy{
+				Selector:                &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+				AllowedSecretNamespaces: []string{"crossplane-system"},
+			}),
+			mg: managedWith(map[string]string{"team": "a"}, nil),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := authorize(tc.pc, tc.mg)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nauthorize(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConnectDenied(t *testing.T) {
+	pc := providerConfig(&aliv1alpha1.ProviderConfigPolicy{
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+	})
+	c := &connector{
+		kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+			if t, ok := obj.(*aliv1alpha1.ProviderConfig); ok {
+				*t = *pc
+			}
+			return nil
+		})},
+		usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
+		kind:  bucketKind(),
+	}
+	mg := managedWith(map[string]string{"team": "b"}, nil)
+	mg.SetProviderConfigReference(&xpv1.Reference{Name: "team-a"})
+
+	wantErr := errors.Errorf(errFmtLabelsDenied, "team-a", "team=b")
+	_, err := c.Connect(context.Background(), mg)
+	if diff := cmp.Diff(wantErr, err, test.EquateErrors()); diff != "" {
+		t.Errorf("c.Connect(...): -want error, +got error:\n%s", diff)
+	}
+	want := xpv1.Condition{Type: TypeAuthorized, Status: corev1.ConditionFalse, Reason: ReasonDenied, Message: wantErr.Error()}
+	if diff := cmp.Diff(want, mg.GetCondition(TypeAuthorized), test.EquateConditions()); diff != "" {
+		t.Errorf("c.Connect(...): -want condition, +got condition:\n%s", diff)
+	}
+}
+
+func TestConnectProviderDenied(t *testing.T) {
+	pc := providerConfig(&aliv1alpha1.ProviderConfigPolicy{
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+	})
+	c := &connector{
+		kube: &test.MockClient{
+			MockGet: test.NewMockGetFn(nil),
+			MockList: test.NewMockListFn(nil, func(obj runtime.Object) error {
+				obj.(*aliv1alpha1.ProviderConfigList).Items = []aliv1alpha1.ProviderConfig{*pc}
+				return nil
+			}),
+		},
+		kind: bucketKind(),
+	}
+	mg := managedWith(map[string]string{"team": "b"}, nil)
+	mg.SetProviderReference(&xpv1.Reference{Name: "legacy"})
+
+	wantErr := errors.Errorf(errFmtProviderDenied, "team-a")
+	_, err := c.Connect(context.Background(), mg)
+	if diff := cmp.Diff(wantErr, err, test.EquateErrors()); diff != "" {
+		t.Errorf("c.Connect(...): -want error, +got error:\n%s", diff)
+	}
+	want := xpv1.Condition{Type: TypeAuthorized, Status: corev1.ConditionFalse, Reason: ReasonDenied, Message: wantErr.Error()}
+	if diff := cmp.Diff(want, mg.GetCondition(TypeAuthorized), test.EquateConditions()); diff != "" {
+		t.Errorf("c.Connect(...): -want condition, +got condition:\n%s", diff)
+	}
+}
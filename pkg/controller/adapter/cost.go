@@ -0,0 +1,176 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients/bss"
+	"github.com/crossplane/provider-alibaba/pkg/util"
+)
+
+const (
+	errNewPricingClient = "cannot create pricing client"
+	errEstimateCost     = "cannot estimate cost"
+
+	reasonCannotEstimateCost event.Reason = "CannotEstimateCost"
+
+	// managedFinalizer is the finalizer the managed reconciler adds to
+	// managed resources.
+	managedFinalizer = "finalizer.managedresource.crossplane.io"
+)
+
+// A Pricing estimates the cost of the cloud resources of a Kind.
+type Pricing struct {
+	// Request returns the price request of the cloud resource of the supplied
+	// managed resource in the supplied region.
+	Request func(mg resource.Managed, region string) bss.PriceRequest
+
+	// Estimate returns the cost estimate recorded in the status of the
+	// supplied managed resource, if any.
+	Estimate func(mg resource.Managed) *aliv1alpha1.CostEstimate
+
+	// SetEstimate records the supplied cost estimate in the status of the
+	// supplied managed resource.
+	SetEstimate func(mg resource.Managed, e *aliv1alpha1.CostEstimate)
+}
+
+// A NewPricingClientFn returns the client used to estimate the cost of cloud
+// resources.
+type NewPricingClientFn func(ctx context.Context, creds util.Credentials) (bss.Client, error)
+
+func newPricingClient(ctx context.Context, creds util.Credentials) (bss.Client, error) {
+	return bss.NewClient(ctx, creds.AccessKeyID, creds.AccessKeySecret, creds.SecurityToken, creds.Region)
+}
+
+// WithPricingClient configures how the client used to estimate the cost of
+// cloud resources is created.
+func WithPricingClient(fn NewPricingClientFn) ConnecterOption {
+	return func(c *connector) {
+		c.newPricingClient = fn
+	}
+}
+
+var estimatedCost = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "alibaba_managed_resource_estimated_cost",
+	Help: "Estimated cost of the cloud resource of a managed resource per period.",
+}, []string{"kind", "name", "currency", "period"})
+
+func init() {
+	metrics.Registry.MustRegister(estimatedCost)
+}
+
+// estimateCost records the estimated cost of the supplied managed resource,
+// which was previously estimated as prev. The cost is only estimated anew if
+// the parameters it depends on changed. Failures to estimate the cost are
+// reported as events rather than errors, so that they do not block the
+// reconciliation of the managed resource.
+func (e *external) estimateCost(ctx context.Context, mg resource.Managed, prev *aliv1alpha1.CostEstimate) {
+	p := e.kind.Pricing
+	if p == nil || e.pricer == nil {
+		return
+	}
+
+	req := p.Request(mg, e.region)
+	hash, err := hashOf(req)
+	if err != nil {
+		e.record.Event(mg, event.Warning(reasonCannotEstimateCost, errors.Wrap(err, errEstimateCost)))
+		return
+	}
+
+	est := prev
+	if prev == nil || prev.ParametersHash != hash {
+		price, err := e.pricer.GetPrice(ctx, req)
+		if err != nil {
+			e.record.Event(mg, event.Warning(reasonCannotEstimateCost, errors.Wrap(err, errEstimateCost)))
+			p.SetEstimate(mg, prev)
+			return
+		}
+		est = &aliv1alpha1.CostEstimate{
+			Amount:           strconv.FormatFloat(price.Amount, 'f', -1, 64),
+			Currency:         price.Currency,
+			Period:           price.Period,
+			ParametersHash:   hash,
+			LastEstimateTime: metav1.Now(),
+		}
+		forgetCost(e.kind, mg, prev)
+	}
+	p.SetEstimate(mg, est)
+
+	if a, err := strconv.ParseFloat(est.Amount, 64); err == nil {
+		estimatedCost.WithLabelValues(e.kind.GroupVersionKind.Kind, mg.GetName(), est.Currency, est.Period).Set(a)
+	}
+}
+
+// forgetCost removes the metric of the supplied cost estimate.
+func forgetCost(k Kind, mg resource.Managed, est *aliv1alpha1.CostEstimate) {
+	if est == nil {
+		return
+	}
+	estimatedCost.DeleteLabelValues(k.GroupVersionKind.Kind, mg.GetName(), est.Currency, est.Period)
+}
+
+// A costFinalizer forgets the cost estimate of managed resources once they are
+// finalized, whether their cloud resources were deleted, orphaned or already
+// gone.
+type costFinalizer struct {
+	resource.Finalizer
+	kind Kind
+}
+
+// RemoveFinalizer removes the finalizer of the supplied managed resource and
+// forgets its cost estimate.
+func (f costFinalizer) RemoveFinalizer(ctx context.Context, obj resource.Object) error {
+	if err := f.Finalizer.RemoveFinalizer(ctx, obj); err != nil {
+		return err
+	}
+	if mg, ok := obj.(resource.Managed); ok {
+		forgetCost(f.kind, mg, f.kind.costEstimate(mg))
+	}
+	return nil
+}
+
+// costEstimate returns the cost estimate recorded in the status of the
+// supplied managed resource, if any.
+func (k Kind) costEstimate(mg resource.Managed) *aliv1alpha1.CostEstimate {
+	if k.Pricing == nil {
+		return nil
+	}
+	return k.Pricing.Estimate(mg)
+}
+
+// hashOf returns a short hash of the JSON representation of the supplied
+// value.
+func hashOf(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(b))[:16], nil
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/crossplane/provider-alibaba/apis/oss/v1alpha1"
+	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients/bss"
+)
+
+// pricer is a local stand-in for the BSS pricing APIs, which charges one
+// currency unit per hour for each module.
+type pricer struct {
+	calls int
+	err   error
+}
+
+func (p *pricer) GetPrice(ctx context.Context, r bss.PriceRequest) (*bss.Price, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &bss.Price{Amount: float64(len(r.Modules)), Currency: "CNY", Period: bss.PeriodHour}, nil
+}
+
+// pricedBucketKind returns a bucketKind whose price depends on its ACL, and
+// whose Observe forgets the cost estimate like the Observe of most kinds.
+func pricedBucketKind(estimates map[resource.Managed]*aliv1alpha1.CostEstimate) Kind {
+	k := bucketKind()
+	k.Observe = func(mg resource.Managed, _ interface{}) {
+		delete(estimates, mg)
+	}
+	k.Pricing = &Pricing{
+		Request: func(mg resource.Managed, region string) bss.PriceRequest {
+			return bss.PriceRequest{ProductCode: "oss", Region: region, Modules: []bss.Module{
+				{Code: "ACL", Config: bss.Config("ACL", mg.(*v1alpha1.Bucket).Spec.ACL)},
+			}}
+		},
+		Estimate:    func(mg resource.Managed) *aliv1alpha1.CostEstimate { return estimates[mg] },
+		SetEstimate: func(mg resource.Managed, e *aliv1alpha1.CostEstimate) { estimates[mg] = e },
+	}
+	return k
+}
+
+func TestEstimateCost(t *testing.T) {
+	type want struct {
+		estimate *aliv1alpha1.CostEstimate
+		calls    int
+		events   int
+	}
+
+	hash := func(acl string) string {
+		h, _ := hashOf(bss.PriceRequest{ProductCode: "oss", Region: "cn-beijing", Modules: []bss.Module{
+			{Code: "ACL", Config: bss.Config("ACL", acl)},
+		}})
+		return h
+	}
+
+	cases := map[string]struct {
+		reason string
+		prev   *aliv1alpha1.CostEstimate
+		err    error
+		want   want
+	}{
+		"FirstEstimate": {
+			reason: "The cost of managed resources without an estimate should be estimated",
+			want: want{
+				estimate: &aliv1alpha1.CostEstimate{Amount: "1", Currency: "CNY", Period: bss.PeriodHour, ParametersHash: hash("private")},
+				calls:    1,
+			},
+		},
+		"Unchanged": {
+			reason: "The cost should not be estimated anew if the parameters did not change",
+			prev:   &aliv1alpha1.CostEstimate{Amount: "2", Currency: "CNY", Period: bss.PeriodHour, ParametersHash: hash("private")},
+			want: want{
+				estimate: &aliv1alpha1.CostEstimate{Amount: "2", Currency: "CNY", Period: bss.PeriodHour, ParametersHash: hash("private")},
+			},
+		},
+		"ParametersChanged": {
+			reason: "The cost should be estimated anew if the parameters changed",
+			prev:   &aliv1alpha1.CostEstimate{Amount: "2", Currency: "CNY", Period: bss.PeriodHour, ParametersHash: hash("public-read")},
+			want: want{
+				estimate: &aliv1alpha1.CostEstimate{Amount: "1", Currency: "CNY", Period: bss.PeriodHour, ParametersHash: hash("private")},
+				calls:    1,
+			},
+		},
+		"PricingFailed": {
+			reason: "The previous estimate should be kept and an event emitted if the cost cannot be estimated",
+			prev:   &aliv1alpha1.CostEstimate{Amount: "2", Currency: "CNY", Period: bss.PeriodHour, ParametersHash: hash("public-read")},
+			err:    errBoom,
+			want: want{
+				estimate: &aliv1alpha1.CostEstimate{Amount: "2", Currency: "CNY", Period: bss.PeriodHour, ParametersHash: hash("public-read")},
+				calls:    1,
+				events:   1,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			estimates := map[resource.Managed]*aliv1alpha1.CostEstimate{}
+			mg := &v1alpha1.Bucket{}
+			mg.Spec.ACL = "private"
+			if tc.prev != nil {
+				estimates[mg] = tc.prev
+			}
+
+			p := &pricer{err: tc.err}
+			r := &recorder{}
+			cloud := "private"
+			e := &external{kind: pricedBucketKind(estimates), client: &cloud, pricer: p, region: "cn-beijing", record: r}
+
+			if _, err := e.Observe(context.Background(), mg); err != nil {
+				t.Fatalf("e.Observe(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want.estimate, estimates[mg], cmpopts.IgnoreFields(aliv1alpha1.CostEstimate{}, "LastEstimateTime")); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want estimate, +got estimate:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.calls, p.calls); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want pricing calls, +got pricing calls:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.events, len(r.events)); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want events, +got events:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCostFinalizer(t *testing.T) {
+	est := &aliv1alpha1.CostEstimate{Amount: "1", Currency: "CNY", Period: bss.PeriodHour}
+
+	cases := map[string]struct {
+		reason    string
+		err       error
+		want      error
+		forgotten bool
+	}{
+		"Finalized": {
+			reason:    "The cost estimate should be forgotten once the managed resource is finalized, whatever its deletion policy",
+			forgotten: true,
+		},
+		"RemoveFinalizerError": {
+			reason: "The cost estimate should be kept if the finalizer cannot be removed",
+			err:    errBoom,
+			want:   errBoom,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &v1alpha1.Bucket{}
+			mg.SetName(name)
+			k := pricedBucketKind(map[resource.Managed]*aliv1alpha1.CostEstimate{mg: est})
+			estimatedCost.WithLabelValues(k.GroupVersionKind.Kind, name, est.Currency, est.Period).Set(1)
+
+			f := costFinalizer{
+				Finalizer: resource.FinalizerFns{RemoveFinalizerFn: func(_ context.Context, _ resource.Object) error { return tc.err }},
+				kind:      k,
+			}
+			err := f.RemoveFinalizer(context.Background(), mg)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nf.RemoveFinalizer(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			forgotten := !estimatedCost.DeleteLabelValues(k.GroupVersionKind.Kind, name, est.Currency, est.Period)
+			if diff := cmp.Diff(tc.forgotten, forgotten); diff != "" {
+				t.Errorf("\n%s\nf.RemoveFinalizer(...): -want forgotten, +got forgotten:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
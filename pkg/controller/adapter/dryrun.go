@@ -0,0 +1,240 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-alibaba/pkg/util"
+)
+
+// AnnotationKeyDryRun is the annotation which, when set to "true" on a managed
+// resource, makes it report the cloud API calls it would make instead of
+// making them. Dry run mode may also be enabled for all managed resources
+// using a ProviderConfig.
+const AnnotationKeyDryRun = "alibaba.crossplane.io/dry-run"
+
+// TypeDryRun conditions report the pending change of a managed resource in
+// dry run mode.
+const TypeDryRun xpv1.ConditionType = "DryRun"
+
+// Reasons of TypeDryRun conditions.
+const (
+	ReasonPendingCreate    xpv1.ConditionReason = "PendingCreate"
+	ReasonPendingUpdate    xpv1.ConditionReason = "PendingUpdate"
+	ReasonPendingDelete    xpv1.ConditionReason = "PendingDelete"
+	ReasonNoPendingChanges xpv1.ConditionReason = "NoPendingChanges"
+	ReasonDryRunDisabled   xpv1.ConditionReason = "DryRunDisabled"
+)
+
+const (
+	errDryRun     = "dry run mode: cloud resources are not changed"
+	errDiffFailed = "cannot compute the pending changes"
+
+	unset = "<unset>"
+)
+
+// DryRun returns true if the supplied managed resource is annotated to be in
+// dry run mode.
+func DryRun(o metav1.Object) bool {
+	return o.GetAnnotations()[AnnotationKeyDryRun] == "true"
+}
+
+// A FieldDiff is a parameter whose observed value differs from its desired
+// value. Values are JSON encoded.
+type FieldDiff struct {
+	Path     string
+	Observed string
+	Desired  string
+}
+
+func (d FieldDiff) String() string {
+	return fmt.Sprintf("%s: %s -> %s", d.Path, d.Observed, d.Desired)
+}
+
+// A dryRunExternal observes cloud resources like an external client, but
+// records the change it would make in a TypeDryRun condition and an event
+// instead of making it.
+type dryRunExternal struct {
+	*external
+	record event.Recorder
+}
+
+func newDryRunExternal(e *external, r event.Recorder) managed.ExternalClient {
+	if r == nil {
+		r = event.NewNopRecorder()
+	}
+	return &dryRunExternal{external: e, record: r}
+}
+
+// Observe reports resources that would be created or updated as existing and
+// up to date, so that they are not. Resources that would be deleted are still
+// reported as existing, so that Delete records their pending deletion and
+// they keep their finalizer. Connection details are not observed, since
+// observing them may change the cloud resource.
+func (e *dryRunExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	o, observed, err := e.observe(ctx, mg, false)
+	if err != nil || meta.WasDeleted(mg) {
+		return o, err
+	}
+
+	var c xpv1.Condition
+	switch {
+	case !o.ResourceExists:
+		diffs, err := createDiff(mg)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errDiffFailed)
+		}
+		c = dryRunCondition(ReasonPendingCreate, "Create", diffs)
+	case !o.ResourceUpToDate:
+		var diffs []FieldDiff
+		if e.kind.Parameters != nil {
+			if diffs, err = updateDiff(mg, e.kind.Parameters(observed)); err != nil {
+				return managed.ExternalObservation{}, errors.Wrap(err, errDiffFailed)
+			}
+		}
+		c = dryRunCondition(ReasonPendingUpdate, "Update", diffs)
+	default:
+		c = dryRunCondition(ReasonNoPendingChanges, "", nil)
+	}
+	e.setCondition(mg, c)
+
+	return managed.ExternalObservation{
+		ResourceExists:          true,
+		ResourceUpToDate:        true,
+		ResourceLateInitialized: o.ResourceLateInitialized,
+	}, nil
+}
+
+func (e *dryRunExternal) Create(_ context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+	return managed.ExternalCreation{}, errors.New(errDryRun)
+}
+
+func (e *dryRunExternal) Update(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+	return managed.ExternalUpdate{}, errors.New(errDryRun)
+}
+
+// Delete records the deletion of the cloud resource instead of deleting it.
+func (e *dryRunExternal) Delete(_ context.Context, mg resource.Managed) error {
+	e.setCondition(mg, dryRunCondition(ReasonPendingDelete, "Delete", nil))
+	return nil
+}
+
+// setCondition sets the supplied TypeDryRun condition, emitting an event if
+// the pending change differs from the one previously recorded.
+func (e *dryRunExternal) setCondition(mg resource.Managed, c xpv1.Condition) {
+	if !mg.GetCondition(TypeDryRun).Equal(c) && c.Reason != ReasonNoPendingChanges {
+		e.record.Event(mg, event.Normal(event.Reason(c.Reason), c.Message))
+	}
+	mg.SetConditions(c)
+}
+
+// clearDryRun marks a previously recorded pending change as obsolete once dry
+// run mode is disabled.
+func clearDryRun(mg resource.Managed) {
+	if mg.GetCondition(TypeDryRun).Status != corev1.ConditionTrue {
+		return
+	}
+	mg.SetConditions(xpv1.Condition{
+		Type:               TypeDryRun,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDryRunDisabled,
+	})
+}
+
+// dryRunCondition returns a TypeDryRun condition whose message names the
+// pending call on its first line, followed by one line per field diff.
+func dryRunCondition(r xpv1.ConditionReason, call string, diffs []FieldDiff) xpv1.Condition {
+	var msg []string
+	if call != "" {
+		msg = append(msg, fmt.Sprintf("Would call %s", call))
+	}
+	for _, d := range diffs {
+		msg = append(msg, d.String())
+	}
+	return xpv1.Condition{
+		Type:               TypeDryRun,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             r,
+		Message:            strings.Join(msg, "\n"),
+	}
+}
+
+// createDiff returns all desired parameters of the supplied managed resource.
+func createDiff(mg resource.Managed) ([]FieldDiff, error) {
+	desired, err := util.Parameters(mg)
+	if err != nil {
+		return nil, err
+	}
+	d, err := util.Flatten(desired)
+	if err != nil {
+		return nil, err
+	}
+	diffs := make([]FieldDiff, 0, len(d))
+	for _, p := range sortedKeys(d) {
+		diffs = append(diffs, FieldDiff{Path: p, Observed: unset, Desired: d[p]})
+	}
+	return diffs, nil
+}
+
+// updateDiff returns the desired parameters of the supplied managed resource
+// that differ from the supplied observed parameters. Parameters that are not
+// observed, or not desired, are not compared.
+func updateDiff(mg resource.Managed, observed interface{}) ([]FieldDiff, error) {
+	desired, err := util.Parameters(mg)
+	if err != nil {
+		return nil, err
+	}
+	d, err := util.Flatten(desired)
+	if err != nil {
+		return nil, err
+	}
+	o, err := util.Flatten(observed)
+	if err != nil {
+		return nil, err
+	}
+	var diffs []FieldDiff
+	for _, p := range sortedKeys(d) {
+		if ov, ok := o[p]; ok && ov != d[p] {
+			diffs = append(diffs, FieldDiff{Path: p, Observed: ov, Desired: d[p]})
+		}
+	}
+	return diffs, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/provider-alibaba/apis/oss/v1alpha1"
+)
+
+// recorder counts the events it records.
+type recorder struct {
+	events []event.Event
+}
+
+func (r *recorder) Event(_ runtime.Object, e event.Event) {
+	r.events = append(r.events, e)
+}
+
+func (r *recorder) WithAnnotations(_ ...string) event.Recorder {
+	return r
+}
+
+func dryRunBucketKind() Kind {
+	k := bucketKind()
+	k.Parameters = func(observed interface{}) interface{} {
+		return v1alpha1.BucketParameter{ACL: observed.(string), StorageClass: "Standard"}
+	}
+	return k
+}
+
+func TestDryRunObserve(t *testing.T) {
+	now := metav1.Now()
+	deleted := &v1alpha1.Bucket{Spec: v1alpha1.BucketSpec{BucketParameter: v1alpha1.BucketParameter{ACL: "public-read"}}}
+	deleted.SetDeletionTimestamp(&now)
+
+	type want struct {
+		o      managed.ExternalObservation
+		err    error
+		cond   xpv1.Condition
+		events int
+	}
+
+	cases := map[string]struct {
+		reason string
+		cloud  string
+		mg     resource.Managed
+		want   want
+	}{
+		"DescribeFailed": {
+			reason: "We should return errors describing the cloud resource",
+			cloud:  "boom",
+			mg:     &v1alpha1.Bucket{},
+			want: want{
+				err:  errBoom,
+				cond: xpv1.Condition{Type: TypeDryRun, Status: corev1.ConditionUnknown},
+			},
+		},
+		"PendingCreate": {
+			reason: "We should record all desired parameters of a cloud resource that would be created",
+			cloud:  "",
+			mg:     &v1alpha1.Bucket{Spec: v1alpha1.BucketSpec{BucketParameter: v1alpha1.BucketParameter{ACL: "private", StorageClass: "IA"}}},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				cond: xpv1.Condition{
+					Type:    TypeDryRun,
+					Status:  corev1.ConditionTrue,
+					Reason:  ReasonPendingCreate,
+					Message: "Would call Create\nacl: <unset> -> \"private\"\nstorageClass: <unset> -> \"IA\"",
+				},
+				events: 1,
+			},
+		},
+		"PendingUpdate": {
+			reason: "We should record the observed parameters that differ from the desired parameters, without observing connection details, which may change the cloud resource",
+			cloud:  "private",
+			mg:     &v1alpha1.Bucket{Spec: v1alpha1.BucketSpec{BucketParameter: v1alpha1.BucketParameter{ACL: "public-read", StorageClass: "Standard"}}},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				cond: xpv1.Condition{
+					Type:    TypeDryRun,
+					Status:  corev1.ConditionTrue,
+					Reason:  ReasonPendingUpdate,
+					Message: "Would call Update\nacl: \"private\" -> \"public-read\"",
+				},
+				events: 1,
+			},
+		},
+		"NoPendingChanges": {
+			reason: "We should record that an up to date cloud resource would not be changed",
+			cloud:  "private",
+			mg:     &v1alpha1.Bucket{Spec: v1alpha1.BucketSpec{BucketParameter: v1alpha1.BucketParameter{ACL: "private"}}},
+			want: want{
+				o:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				cond: xpv1.Condition{Type: TypeDryRun, Status: corev1.ConditionTrue, Reason: ReasonNoPendingChanges},
+			},
+		},
+		"Deleted": {
+			reason: "We should report the cloud resource of a deleted managed resource as is, leaving its pending deletion to Delete",
+			cloud:  "private",
+			mg:     deleted,
+			want: want{
+				o:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				cond: xpv1.Condition{Type: TypeDryRun, Status: corev1.ConditionUnknown},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cloud := tc.cloud
+			r := &recorder{}
+			e := newDryRunExternal(NewExternalClient(dryRunBucketKind(), nil, &cloud).(*external), r)
+			got, err := e.Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cond, tc.mg.GetCondition(TypeDryRun), test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want condition, +got condition:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.events, len(r.events)); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want events, +got events:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDryRunNoChanges(t *testing.T) {
+	cloud := "private"
+	e := newDryRunExternal(NewExternalClient(dryRunBucketKind(), nil, &cloud).(*external), nil)
+	mg := &v1alpha1.Bucket{}
+
+	if _, err := e.Create(context.Background(), mg); errors.Cause(err).Error() != errDryRun {
+		t.Errorf("e.Create(...): want error %q, got %v", errDryRun, err)
+	}
+	if _, err := e.Update(context.Background(), mg); errors.Cause(err).Error() != errDryRun {
+		t.Errorf("e.Update(...): want error %q, got %v", errDryRun, err)
+	}
+}
+
+func TestDryRunDelete(t *testing.T) {
+	cloud := "private"
+	r := &recorder{}
+	e := newDryRunExternal(NewExternalClient(dryRunBucketKind(), nil, &cloud).(*external), r)
+	mg := &v1alpha1.Bucket{}
+
+	if err := e.Delete(context.Background(), mg); err != nil {
+		t.Fatalf("e.Delete(...): %s", err)
+	}
+	want := xpv1.Condition{Type: TypeDryRun, Status: corev1.ConditionTrue, Reason: ReasonPendingDelete, Message: "Would call Delete"}
+	if diff := cmp.Diff(want, mg.GetCondition(TypeDryRun), test.EquateConditions()); diff != "" {
+		t.Errorf("e.Delete(...): -want condition, +got condition:\n%s", diff)
+	}
+	if diff := cmp.Diff("private", cloud); diff != "" {
+		t.Errorf("e.Delete(...): -want cloud resource, +got cloud resource:\n%s", diff)
+	}
+	if diff := cmp.Diff(1, len(r.events)); diff != "" {
+		t.Errorf("e.Delete(...): -want events, +got events:\n%s", diff)
+	}
+}
+
+func TestClearDryRun(t *testing.T) {
+	mg := &v1alpha1.Bucket{Spec: v1alpha1.BucketSpec{BucketParameter: v1alpha1.BucketParameter{ACL: "private"}}}
+	mg.SetConditions(dryRunCondition(ReasonPendingUpdate, "Update", nil))
+
+	cloud := "private"
+	if _, err := NewExternalClient(bucketKind(), nil, &cloud).Observe(context.Background(), mg); err != nil {
+		t.Fatalf("e.Observe(...): %s", err)
+	}
+	want := xpv1.Condition{Type: TypeDryRun, Status: corev1.ConditionFalse, Reason: ReasonDryRunDisabled}
+	if diff := cmp.Diff(want, mg.GetCondition(TypeDryRun), test.EquateConditions()); diff != "" {
+		t.Errorf("e.Observe(...): -want condition, +got condition:\n%s", diff)
+	}
+}
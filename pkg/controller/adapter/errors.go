@@ -0,0 +1,189 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+)
+
+// ReasonReconcileTerminalError replaces the ReconcileError reason of the
+// Synced condition of managed resources whose reconciliation failed with an
+// error that will not succeed until their spec changes.
+const ReasonReconcileTerminalError xpv1.ConditionReason = "ReconcileTerminalError"
+
+const (
+	defaultTerminalErrorWait = 30 * time.Minute
+	defaultThrottledWait     = 2 * time.Minute
+)
+
+// A terminalError records that the reconciliation of a generation of a
+// managed resource failed with a terminal error.
+type terminalError struct {
+	generation int64
+	until      time.Time
+}
+
+// An errorTracker records the classes of the errors returned by the external
+// clients of managed resources, so that their reconciler can back off.
+type errorTracker struct {
+	mu       sync.Mutex
+	last     map[types.NamespacedName]clients.ErrorClass
+	terminal map[types.NamespacedName]terminalError
+}
+
+func newErrorTracker() *errorTracker {
+	return &errorTracker{
+		last:     map[types.NamespacedName]clients.ErrorClass{},
+		terminal: map[types.NamespacedName]terminalError{},
+	}
+}
+
+// record records the class of the supplied error returned while reconciling
+// the supplied managed resource, and returns the error.
+func (t *errorTracker) record(mg resource.Managed, err error) error {
+	if t == nil || err == nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.last[types.NamespacedName{Name: mg.GetName()}] = clients.ClassifyError(err)
+	return err
+}
+
+// take returns and forgets the class of the last error recorded for the
+// supplied managed resource. It returns ErrorRetryable if none was recorded.
+func (t *errorTracker) take(nn types.NamespacedName) clients.ErrorClass {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c, ok := t.last[nn]
+	delete(t.last, nn)
+	if !ok {
+		return clients.ErrorRetryable
+	}
+	return c
+}
+
+func (t *errorTracker) setTerminal(nn types.NamespacedName, te terminalError) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.terminal[nn] = te
+}
+
+func (t *errorTracker) getTerminal(nn types.NamespacedName) (terminalError, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	te, ok := t.terminal[nn]
+	return te, ok
+}
+
+func (t *errorTracker) forgetTerminal(nn types.NamespacedName) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.terminal, nn)
+}
+
+// A trackedExternal records the classes of the errors returned by an
+// external client.
+type trackedExternal struct {
+	managed.ExternalClient
+	errs *errorTracker
+}
+
+func (e *trackedExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	o, err := e.ExternalClient.Observe(ctx, mg)
+	return o, e.errs.record(mg, err)
+}
+
+func (e *trackedExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	c, err := e.ExternalClient.Create(ctx, mg)
+	return c, e.errs.record(mg, err)
+}
+
+func (e *trackedExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	u, err := e.ExternalClient.Update(ctx, mg)
+	return u, e.errs.record(mg, err)
+}
+
+func (e *trackedExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	return e.errs.record(mg, e.ExternalClient.Delete(ctx, mg))
+}
+
+// A backoffReconciler wraps a managed reconciler so that managed resources
+// whose reconciliation failed with a terminal error are not reconciled again
+// until their spec changes or the terminal error wait elapses, and managed
+// resources whose reconciliation was throttled are reconciled again after
+// the throttled wait.
+type backoffReconciler struct {
+	reconcile.Reconciler
+	kube          client.Client
+	newManaged    func() resource.Managed
+	errs          *errorTracker
+	terminalWait  time.Duration
+	throttledWait time.Duration
+}
+
+func (r *backoffReconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	ctx := context.Background()
+
+	mg := r.newManaged()
+	if err := r.kube.Get(ctx, req.NamespacedName, mg); err == nil {
+		te, ok := r.errs.getTerminal(req.NamespacedName)
+		if ok && te.generation == mg.GetGeneration() && !meta.WasDeleted(mg) {
+			if wait := time.Until(te.until); wait > 0 {
+				return reconcile.Result{RequeueAfter: wait}, nil
+			}
+		}
+	}
+	r.errs.forgetTerminal(req.NamespacedName)
+
+	result, err := r.Reconciler.Reconcile(req)
+	class := r.errs.take(req.NamespacedName)
+	if err != nil {
+		return result, err
+	}
+
+	switch class {
+	case clients.ErrorThrottled:
+		return reconcile.Result{RequeueAfter: r.throttledWait}, nil
+	case clients.ErrorTerminal:
+		mg := r.newManaged()
+		if err := r.kube.Get(ctx, req.NamespacedName, mg); err != nil {
+			return result, client.IgnoreNotFound(err)
+		}
+		r.errs.setTerminal(req.NamespacedName, terminalError{generation: mg.GetGeneration(), until: time.Now().Add(r.terminalWait)})
+		c := mg.GetCondition(xpv1.TypeSynced)
+		if c.Reason != xpv1.ReasonReconcileError {
+			return reconcile.Result{RequeueAfter: r.terminalWait}, nil
+		}
+		c.Reason = ReasonReconcileTerminalError
+		mg.SetConditions(c)
+		return reconcile.Result{RequeueAfter: r.terminalWait}, client.IgnoreNotFound(r.kube.Status().Update(ctx, mg))
+	}
+	return result, nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	sdkerrors "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/provider-alibaba/apis/oss/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+)
+
+func TestBackoffReconciler(t *testing.T) {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cool"}}
+
+	type want struct {
+		result    reconcile.Result
+		waiting   bool
+		reason    xpv1.ConditionReason
+		reconcile int
+	}
+
+	cases := map[string]struct {
+		reason     string
+		err        error
+		terminal   *terminalError
+		generation int64
+		want       want
+	}{
+		"Retryable": {
+			reason: "Managed resources failing with retryable errors should be requeued by the managed reconciler",
+			err:    errBoom,
+			want: want{
+				result:    reconcile.Result{RequeueAfter: time.Second},
+				reason:    xpv1.ReasonReconcileError,
+				reconcile: 1,
+			},
+		},
+		"Throttled": {
+			reason: "Managed resources whose cloud API calls were throttled should be requeued after the throttled wait",
+			err:    sdkerrors.NewServerError(http.StatusBadRequest, `{"Code": "Throttling.User"}`, ""),
+			want: want{
+				result:    reconcile.Result{RequeueAfter: time.Minute},
+				reason:    xpv1.ReasonReconcileError,
+				reconcile: 1,
+			},
+		},
+		"Terminal": {
+			reason: "Managed resources failing with terminal errors should report them and be requeued after the terminal error wait",
+			err:    clients.NewTerminalError("invalid"),
+			want: want{
+				result:    reconcile.Result{RequeueAfter: time.Hour},
+				reason:    ReasonReconcileTerminalError,
+				reconcile: 1,
+			},
+		},
+		"TerminalUnchanged": {
+			reason:     "Managed resources that failed with a terminal error should not be reconciled until their spec changes",
+			terminal:   &terminalError{generation: 1, until: time.Now().Add(time.Hour)},
+			generation: 1,
+			want: want{
+				waiting: true,
+				reason:  xpv1.ReasonReconcileError,
+			},
+		},
+		"TerminalSpecChanged": {
+			reason:     "Managed resources that failed with a terminal error should be reconciled once their spec changes",
+			terminal:   &terminalError{generation: 1, until: time.Now().Add(time.Hour)},
+			generation: 2,
+			want: want{
+				result:    reconcile.Result{RequeueAfter: time.Second},
+				reason:    xpv1.ReasonReconcileError,
+				reconcile: 1,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &v1alpha1.Bucket{}
+			mg.SetName(req.Name)
+			mg.SetGeneration(tc.generation)
+			mg.SetConditions(xpv1.ReconcileError(errBoom))
+
+			errs := newErrorTracker()
+			if tc.terminal != nil {
+				errs.setTerminal(req.NamespacedName, *tc.terminal)
+			}
+
+			reconciles := 0
+			r := &backoffReconciler{
+				Reconciler: reconcile.Func(func(reconcile.Request) (reconcile.Result, error) {
+					reconciles++
+					errs.record(mg, tc.err)
+					return reconcile.Result{RequeueAfter: time.Second}, nil
+				}),
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+						mg.DeepCopyInto(obj.(*v1alpha1.Bucket))
+						return nil
+					}),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(obj runtime.Object) error {
+						obj.(*v1alpha1.Bucket).DeepCopyInto(mg)
+						return nil
+					}),
+				},
+				newManaged:    func() resource.Managed { return &v1alpha1.Bucket{} },
+				errs:          errs,
+				terminalWait:  time.Hour,
+				throttledWait: time.Minute,
+			}
+
+			got, err := r.Reconcile(req)
+			if err != nil {
+				t.Fatalf("r.Reconcile(...): %s", err)
+			}
+			if tc.want.waiting {
+				// The remainder of the terminal error wait.
+				if got.RequeueAfter <= 0 || got.RequeueAfter > time.Hour {
+					t.Errorf("\n%s\nr.Reconcile(...): want requeue within an hour, got %s\n", tc.reason, got.RequeueAfter)
+				}
+			} else if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want result, +got result:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.reason, mg.GetCondition(xpv1.TypeSynced).Reason); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want reason, +got reason:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.reconcile, reconciles); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want reconciles, +got reconciles:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
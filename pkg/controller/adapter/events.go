@@ -0,0 +1,271 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlevent "sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// EventsPath is the path cloud change events are delivered to.
+const EventsPath = "/events"
+
+// indexCloudID indexes managed resources by their external name and the ID
+// of their cloud resource.
+const indexCloudID = "alibaba.crossplane.io/cloud-id"
+
+const (
+	maxEventsSize = 1 << 20
+
+	// eventsBuffer is the number of managed resources that may be waiting to
+	// be enqueued by the controller of a Kind. Further changes are dropped
+	// until it catches up; their managed resources are still observed on
+	// their next poll.
+	eventsBuffer = 100
+
+	errNoEventsToken = "a token is required to receive cloud change events"
+	errServeEvents   = "cannot serve cloud change events"
+	errReadEvents    = "cannot read cloud change events"
+	errDecodeEvents  = "cannot decode cloud change events"
+	errListManaged   = "cannot list managed resources"
+	errNewList       = "cannot create managed resource list"
+)
+
+// A Receiver accepts the cloud change events delivered by EventBridge, e.g.
+// the ActionTrail events of API calls made from the console, and enqueues
+// reconciles of the managed resources of the changed cloud resources, so that
+// changes made outside of Crossplane are corrected before the next sync.
+type Receiver struct {
+	kube  client.Reader
+	addr  string
+	token string
+	log   logging.Logger
+
+	mu      sync.RWMutex
+	targets []eventTarget
+}
+
+// An eventTarget is a Kind whose controller reconciles the managed resources
+// sent to its channel.
+type eventTarget struct {
+	gvk     schema.GroupVersionKind
+	newList func() runtime.Object
+	ch      chan ctrlevent.GenericEvent
+}
+
+// NewReceiver returns a Receiver that serves on the supplied address and looks
+// up managed resources using the supplied client, which must be backed by the
+// cache of the manager the controllers are added to. Deliveries must carry
+// the supplied token as a bearer token or a token query parameter.
+func NewReceiver(kube client.Reader, addr, token string, l logging.Logger) (*Receiver, error) {
+	if token == "" {
+		return nil, errors.New(errNoEventsToken)
+	}
+	return &Receiver{kube: kube, addr: addr, token: token, log: l}, nil
+}
+
+// register returns the channel the managed resources of the supplied Kind are
+// sent to when their cloud resource changes.
+func (r *Receiver) register(k Kind, s *runtime.Scheme) (<-chan ctrlevent.GenericEvent, error) {
+	lgvk := k.GroupVersionKind.GroupVersion().WithKind(k.GroupVersionKind.Kind + "List")
+	if _, err := s.New(lgvk); err != nil {
+		return nil, errors.Wrap(err, errNewList)
+	}
+	t := eventTarget{
+		gvk: k.GroupVersionKind,
+		newList: func() runtime.Object {
+			l, _ := s.New(lgvk)
+			return l
+		},
+		ch: make(chan ctrlevent.GenericEvent, eventsBuffer),
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.targets = append(r.targets, t)
+	return t.ch, nil
+}
+
+// Start serves cloud change events until the supplied channel is closed.
+func (r *Receiver) Start(stop <-chan struct{}) error {
+	mux := http.NewServeMux()
+	mux.Handle(EventsPath, r)
+	srv := &http.Server{Addr: r.addr, Handler: mux}
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+	select {
+	case err := <-errc:
+		return errors.Wrap(err, errServeEvents)
+	case <-stop:
+		return srv.Shutdown(context.Background())
+	}
+}
+
+// ServeHTTP accepts a delivery of a cloud change event, or of a batch of them,
+// in the CloudEvents JSON format used by EventBridge.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if !r.authorized(req) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, maxEventsSize))
+	if err != nil {
+		http.Error(w, errors.Wrap(err, errReadEvents).Error(), http.StatusBadRequest)
+		return
+	}
+	ids, err := eventCloudIDs(body)
+	if err != nil {
+		http.Error(w, errors.Wrap(err, errDecodeEvents).Error(), http.StatusBadRequest)
+		return
+	}
+	if err := r.enqueue(req.Context(), ids); err != nil {
+		r.log.Info("Cannot enqueue reconciles of changed cloud resources", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (r *Receiver) authorized(req *http.Request) bool {
+	token := req.URL.Query().Get("token")
+	if h := req.Header.Get("Authorization"); strings.HasPrefix(h, "Bearer ") {
+		token = strings.TrimPrefix(h, "Bearer ")
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(r.token)) == 1
+}
+
+// enqueue sends the managed resources of the cloud resources with the
+// supplied IDs to the controllers of their Kinds. It does not wait for
+// controllers that are not running yet, e.g. on replicas that are not the
+// leader, or that are behind.
+func (r *Receiver) enqueue(ctx context.Context, ids []string) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, t := range r.targets {
+		seen := map[string]bool{}
+		for _, id := range ids {
+			l := t.newList()
+			if err := r.kube.List(ctx, l, client.MatchingFields{indexCloudID: id}); err != nil {
+				return errors.Wrap(err, errListManaged)
+			}
+			objs, err := kmeta.ExtractList(l)
+			if err != nil {
+				return errors.Wrap(err, errListManaged)
+			}
+			for _, o := range objs {
+				mg, ok := o.(resource.Managed)
+				if !ok || seen[mg.GetName()] {
+					continue
+				}
+				seen[mg.GetName()] = true
+				r.log.Debug("Cloud resource changed", "kind", t.gvk.Kind, "name", mg.GetName(), "id", id)
+				select {
+				case t.ch <- ctrlevent.GenericEvent{Meta: mg, Object: mg}:
+				default:
+					r.log.Debug("Dropped cloud change event", "kind", t.gvk.Kind, "name", mg.GetName(), "id", id)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// A cloudEvent is the part of a CloudEvents event delivered by EventBridge
+// that identifies the changed cloud resources.
+type cloudEvent struct {
+	// Subject is the ARN of the changed cloud resource, e.g.
+	// acs:rds:cn-beijing:123456:dbinstance/rm-abc.
+	Subject string `json:"subject"`
+
+	Data struct {
+		// ResourceName of ActionTrail events lists the IDs of the cloud
+		// resources the API call applied to.
+		ResourceName string `json:"resourceName"`
+
+		// ResourceID of Cloud Config resource change events.
+		ResourceID string `json:"resourceId"`
+	} `json:"data"`
+}
+
+// eventCloudIDs returns the IDs of the cloud resources changed by the
+// supplied event, or batch of events.
+func eventCloudIDs(body []byte) ([]string, error) {
+	var events []cloudEvent
+	if err := json.Unmarshal(body, &events); err != nil {
+		var e cloudEvent
+		if err := json.Unmarshal(body, &e); err != nil {
+			return nil, err
+		}
+		events = []cloudEvent{e}
+	}
+
+	var ids []string
+	for _, e := range events {
+		// The resource of an ARN is a path that starts with a type, e.g.
+		// dbinstance/rm-abc, or with a name, e.g. bucket/object. Segments
+		// that are not IDs match no managed resources.
+		if p := strings.SplitN(e.Subject, ":", 5); len(p) == 5 {
+			ids = append(ids, strings.Split(p[4], "/")...)
+		}
+		ids = append(ids, strings.FieldsFunc(e.Data.ResourceName, func(r rune) bool { return r == ';' || r == ',' })...)
+		ids = append(ids, e.Data.ResourceID)
+	}
+	return unique(ids), nil
+}
+
+// cloudIDs returns the values of the cloud ID index of the supplied managed
+// resource of the supplied Kind.
+func (k Kind) cloudIDs(mg resource.Managed) []string {
+	ids := []string{meta.GetExternalName(mg)}
+	if k.CloudID != nil {
+		ids = append(ids, k.CloudID(mg))
+	}
+	return unique(ids)
+}
+
+// unique returns the supplied strings without empty strings and duplicates.
+func unique(s []string) []string {
+	var u []string
+	seen := map[string]bool{"": true}
+	for _, v := range s {
+		v = strings.TrimSpace(v)
+		if !seen[v] {
+			seen[v] = true
+			u = append(u, v)
+		}
+	}
+	return u
+}
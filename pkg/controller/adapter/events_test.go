@@ -0,0 +1,240 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-alibaba/apis/oss/v1alpha1"
+)
+
+func TestEventCloudIDs(t *testing.T) {
+	type want struct {
+		ids []string
+		err bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		body   string
+		want   want
+	}{
+		"ActionTrail": {
+			reason: "The IDs of ActionTrail events should be read from their subject and resource name",
+			body:   `{"subject": "acs:rds:cn-beijing:123456:dbinstance/rm-a", "data": {"resourceName": "rm-a;rm-b"}}`,
+			want:   want{ids: []string{"dbinstance", "rm-a", "rm-b"}},
+		},
+		"Batch": {
+			reason: "The IDs of a batch of events should be read from each event",
+			body:   `[{"data": {"resourceId": "lb-a"}}, {"subject": "acs:oss:cn-beijing:123456:cool/object"}]`,
+			want:   want{ids: []string{"cool", "lb-a", "object"}},
+		},
+		"Malformed": {
+			reason: "Deliveries that are not events should be rejected",
+			body:   `"cool"`,
+			want:   want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := eventCloudIDs([]byte(tc.body))
+			sort.Strings(got)
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\neventCloudIDs(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ids, got); diff != "" {
+				t.Errorf("\n%s\neventCloudIDs(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReceiver(t *testing.T) {
+	type want struct {
+		status   int
+		enqueued []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		method string
+		token  string
+		body   string
+		want   want
+	}{
+		"Changed": {
+			reason: "The managed resources of changed cloud resources should be enqueued",
+			method: http.MethodPost,
+			token:  "secret",
+			body:   `{"subject": "acs:oss:cn-beijing:123456:cool"}`,
+			want:   want{status: http.StatusNoContent, enqueued: []string{"cool-bucket"}},
+		},
+		"Unmanaged": {
+			reason: "Events of cloud resources without managed resources should be accepted",
+			method: http.MethodPost,
+			token:  "secret",
+			body:   `{"subject": "acs:oss:cn-beijing:123456:other"}`,
+			want:   want{status: http.StatusNoContent},
+		},
+		"Unauthorized": {
+			reason: "Deliveries without the configured token should be rejected",
+			method: http.MethodPost,
+			token:  "wrong",
+			body:   `{"subject": "acs:oss:cn-beijing:123456:cool"}`,
+			want:   want{status: http.StatusUnauthorized},
+		},
+		"Malformed": {
+			reason: "Malformed deliveries should be rejected",
+			method: http.MethodPost,
+			token:  "secret",
+			body:   `cool`,
+			want:   want{status: http.StatusBadRequest},
+		},
+		"MethodNotAllowed": {
+			reason: "Only POST requests should be accepted",
+			method: http.MethodGet,
+			token:  "secret",
+			want:   want{status: http.StatusMethodNotAllowed},
+		},
+	}
+
+	s := runtime.NewScheme()
+	if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &v1alpha1.Bucket{}
+			mg.SetName("cool-bucket")
+			meta.SetExternalName(mg, "cool")
+
+			kube := &test.MockClient{
+				MockList: func(_ context.Context, list runtime.Object, opts ...client.ListOption) error {
+					lo := &client.ListOptions{}
+					lo.ApplyOptions(opts)
+					if id, _ := lo.FieldSelector.RequiresExactMatch(indexCloudID); id == "cool" {
+						list.(*v1alpha1.BucketList).Items = []v1alpha1.Bucket{*mg}
+					}
+					return nil
+				},
+			}
+			r, err := NewReceiver(kube, "", "secret", logging.NewNopLogger())
+			if err != nil {
+				t.Fatalf("NewReceiver(...): %s", err)
+			}
+			ch, err := r.register(bucketKind(), s)
+			if err != nil {
+				t.Fatalf("r.register(...): %s", err)
+			}
+
+			var enqueued []string
+			done := make(chan struct{})
+			go func() {
+				for e := range ch {
+					enqueued = append(enqueued, e.Meta.GetName())
+				}
+				close(done)
+			}()
+
+			req := httptest.NewRequest(tc.method, EventsPath+"?token="+tc.token, strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			close(r.targets[0].ch)
+			<-done
+
+			if diff := cmp.Diff(tc.want.status, w.Code); diff != "" {
+				t.Errorf("\n%s\nr.ServeHTTP(...): -want status, +got status:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.enqueued, enqueued); diff != "" {
+				t.Errorf("\n%s\nr.ServeHTTP(...): -want enqueued, +got enqueued:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewReceiverNoToken(t *testing.T) {
+	_, err := NewReceiver(&test.MockClient{}, ":8090", "", logging.NewNopLogger())
+	if diff := cmp.Diff(errors.New(errNoEventsToken), err, test.EquateErrors()); diff != "" {
+		t.Errorf("NewReceiver(...): -want error, +got error:\n%s", diff)
+	}
+}
+
+func TestReceiverNotRunning(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	kube := &test.MockClient{
+		MockList: func(_ context.Context, list runtime.Object, _ ...client.ListOption) error {
+			mg := v1alpha1.Bucket{}
+			mg.SetName("cool-bucket")
+			list.(*v1alpha1.BucketList).Items = []v1alpha1.Bucket{mg}
+			return nil
+		},
+	}
+	r, err := NewReceiver(kube, "", "secret", logging.NewNopLogger())
+	if err != nil {
+		t.Fatalf("NewReceiver(...): %s", err)
+	}
+	ch, err := r.register(bucketKind(), s)
+	if err != nil {
+		t.Fatalf("r.register(...): %s", err)
+	}
+
+	// Nothing reads the channel, as if the controller was not running.
+	for i := 0; i <= eventsBuffer; i++ {
+		req := httptest.NewRequest(http.MethodPost, EventsPath+"?token=secret", strings.NewReader(`{"subject": "acs:oss:cn-beijing:123456:cool"}`))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if diff := cmp.Diff(http.StatusNoContent, w.Code); diff != "" {
+			t.Fatalf("r.ServeHTTP(...): -want status, +got status:\n%s", diff)
+		}
+	}
+	if diff := cmp.Diff(eventsBuffer, len(ch)); diff != "" {
+		t.Errorf("r.ServeHTTP(...): -want buffered, +got buffered:\n%s", diff)
+	}
+}
+
+func TestCloudIDs(t *testing.T) {
+	mg := &v1alpha1.Bucket{}
+	meta.SetExternalName(mg, "cool")
+
+	k := bucketKind()
+	if diff := cmp.Diff([]string{"cool"}, k.cloudIDs(mg)); diff != "" {
+		t.Errorf("k.cloudIDs(...): -want, +got:\n%s", diff)
+	}
+	k.CloudID = func(_ resource.Managed) string { return "b-123" }
+	if diff := cmp.Diff([]string{"cool", "b-123"}, k.cloudIDs(mg)); diff != "" {
+		t.Errorf("k.cloudIDs(...): -want, +got:\n%s", diff)
+	}
+}
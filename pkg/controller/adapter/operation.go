@@ -0,0 +1,258 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/util"
+)
+
+// TypeProgressing conditions report the progress of the last asynchronous
+// change of the cloud resource of a managed resource.
+const TypeProgressing xpv1.ConditionType = "Progressing"
+
+// Reasons of TypeProgressing conditions.
+const (
+	ReasonOperationInProgress xpv1.ConditionReason = "OperationInProgress"
+	ReasonOperationComplete   xpv1.ConditionReason = "OperationComplete"
+	ReasonOperationStuck      xpv1.ConditionReason = "OperationStuck"
+)
+
+// Operations recorded by the adapter.
+const (
+	OperationCreate = "Create"
+	OperationUpdate = "Update"
+)
+
+const (
+	defaultOperationDeadline    = time.Hour
+	defaultOperationGracePeriod = 5 * time.Minute
+
+	errHashParameters = "cannot hash parameters"
+
+	fmtOperationInProgress = "%s requested at %s"
+	fmtOperationProgress   = "%s: %s"
+	fmtOperationStuck      = "%s requested at %s has not completed within %s"
+
+	reasonOperationStuck event.Reason = "OperationStuck"
+)
+
+// Operations track the asynchronous changes of the cloud resources of a Kind,
+// so that changes that are in progress are not requested again.
+type Operations struct {
+	// Pending returns the operation recorded in the status of the supplied
+	// managed resource, if any.
+	Pending func(mg resource.Managed) *aliv1alpha1.PendingOperation
+
+	// SetPending records the supplied operation in the status of the
+	// supplied managed resource, or clears it if nil.
+	SetPending func(mg resource.Managed, op *aliv1alpha1.PendingOperation)
+
+	// InProgress returns true while the described cloud resource is still
+	// applying a change, e.g. while its status is Creating. Optional;
+	// operations complete once the cloud resource is observed if unset.
+	InProgress func(observed interface{}) bool
+
+	// Progress describes the progress of an operation that is in progress
+	// for the TypeProgressing condition, e.g. the status of the described
+	// cloud resource. Optional.
+	Progress func(mg resource.Managed, observed interface{}) string
+
+	// Deadline after which operations that have not completed are reported
+	// as stuck. One hour if zero.
+	Deadline time.Duration
+
+	// GracePeriod after which updates that were neither observed to start
+	// nor to apply the spec complete anyway, e.g. because they completed
+	// between two observations. Five minutes if zero.
+	GracePeriod time.Duration
+}
+
+type operationKey struct{}
+
+// A requestedOperation is the operation requested by a Kind.Create or
+// Kind.Update call, recorded in the context passed to it.
+type requestedOperation struct {
+	op      *aliv1alpha1.PendingOperation
+	skipped bool
+}
+
+// SetTaskID records the supplied cloud task ID, e.g. an order ID, in the
+// operation requested by the Kind.Create or Kind.Update call that was passed
+// the supplied context. It is a no-op if the Kind does not track operations.
+func SetTaskID(ctx context.Context, id string) {
+	if r, ok := ctx.Value(operationKey{}).(*requestedOperation); ok {
+		r.op.TaskID = id
+	}
+}
+
+// SkipOperation tells the adapter that the Kind.Create or Kind.Update call
+// that was passed the supplied context did not request a change, e.g. because
+// the cloud resource is busy applying another one, so that no operation is
+// recorded and the call is made again once the cloud resource is observed. It
+// is a no-op if the Kind does not track operations.
+func SkipOperation(ctx context.Context) {
+	if r, ok := ctx.Value(operationKey{}).(*requestedOperation); ok {
+		r.skipped = true
+	}
+}
+
+// pendingOperation returns the operation recorded in the status of the
+// supplied managed resource, if any.
+func (k Kind) pendingOperation(mg resource.Managed) *aliv1alpha1.PendingOperation {
+	if k.Operations == nil {
+		return nil
+	}
+	return k.Operations.Pending(mg)
+}
+
+func (k Kind) operationDeadline() time.Duration {
+	if k.Operations == nil || k.Operations.Deadline == 0 {
+		return defaultOperationDeadline
+	}
+	return k.Operations.Deadline
+}
+
+func (k Kind) operationGracePeriod() time.Duration {
+	if k.Operations == nil || k.Operations.GracePeriod == 0 {
+		return defaultOperationGracePeriod
+	}
+	return k.Operations.GracePeriod
+}
+
+// inFlight returns true if the operation recorded in the status of the
+// supplied managed resource applies its current parameters, so that
+// requesting the operation again would duplicate it.
+func (k Kind) inFlight(mg resource.Managed) (bool, error) {
+	op := k.pendingOperation(mg)
+	if op == nil {
+		return false, nil
+	}
+	hash, err := parametersHash(mg)
+	if err != nil {
+		return false, errors.Wrap(err, errHashParameters)
+	}
+	return op.SpecHash == hash, nil
+}
+
+// newOperation returns a new operation applying the current parameters of the
+// supplied managed resource, and a context that Kind functions may record
+// its task ID in. It returns a nil operation if the Kind does not track
+// operations.
+func (k Kind) newOperation(ctx context.Context, mg resource.Managed, operation string) (context.Context, *aliv1alpha1.PendingOperation, error) {
+	if k.Operations == nil {
+		return ctx, nil, nil
+	}
+	hash, err := parametersHash(mg)
+	if err != nil {
+		return ctx, nil, errors.Wrap(err, errHashParameters)
+	}
+	op := &aliv1alpha1.PendingOperation{Operation: operation, RequestedAt: metav1.Now(), SpecHash: hash}
+	return context.WithValue(ctx, operationKey{}, &requestedOperation{op: op}), op, nil
+}
+
+// startOperation records the supplied operation, which was requested
+// successfully by the Kind function that was passed the supplied context, in
+// the status of the supplied managed resource, unless the function skipped it.
+func (k Kind) startOperation(ctx context.Context, mg resource.Managed, op *aliv1alpha1.PendingOperation) {
+	if op == nil {
+		return
+	}
+	if r, ok := ctx.Value(operationKey{}).(*requestedOperation); ok && r.skipped {
+		return
+	}
+	k.Operations.SetPending(mg, op)
+	mg.SetConditions(progressing(op, ReasonOperationInProgress, 0))
+}
+
+// trackOperation records the supplied operation, which was recorded in the
+// status of the supplied managed resource before it was observed, until the
+// observed cloud resource shows that it completed. Creations complete once
+// the cloud resource exists and is no longer changing. Since clouds may report
+// an update as changing some time after it was requested, updates complete
+// once the cloud resource is up to date, once it stopped changing after it
+// was observed changing, or once the grace period of the Kind passed without
+// either; a Kind.Update may apply a spec in several steps, each requested once
+// the previous one completed. Operations that have not completed within the
+// deadline of the Kind are reported as stuck.
+func (e *external) trackOperation(mg resource.Managed, op *aliv1alpha1.PendingOperation, observed interface{}, upToDate bool) {
+	if op == nil || e.kind.Operations == nil {
+		return
+	}
+	inProgress := observed != nil && e.kind.Operations.InProgress != nil && e.kind.Operations.InProgress(observed)
+	if inProgress {
+		op.Started = true
+	}
+	if observed != nil && !inProgress && (op.Operation != OperationUpdate || upToDate || op.Started ||
+		time.Since(op.RequestedAt.Time) > e.kind.operationGracePeriod()) {
+		e.kind.Operations.SetPending(mg, nil)
+		mg.SetConditions(progressing(op, ReasonOperationComplete, 0))
+		return
+	}
+
+	e.kind.Operations.SetPending(mg, op)
+	deadline := e.kind.operationDeadline()
+	if time.Since(op.RequestedAt.Time) <= deadline {
+		c := progressing(op, ReasonOperationInProgress, 0)
+		if observed != nil && e.kind.Operations.Progress != nil {
+			c.Message = fmt.Sprintf(fmtOperationProgress, c.Message, e.kind.Operations.Progress(mg, observed))
+		}
+		mg.SetConditions(c)
+		return
+	}
+	c := progressing(op, ReasonOperationStuck, deadline)
+	if mg.GetCondition(TypeProgressing).Reason != ReasonOperationStuck {
+		e.record.Event(mg, event.Warning(reasonOperationStuck, errors.New(c.Message)))
+	}
+	mg.SetConditions(c)
+}
+
+func progressing(op *aliv1alpha1.PendingOperation, r xpv1.ConditionReason, deadline time.Duration) xpv1.Condition {
+	c := xpv1.Condition{
+		Type:               TypeProgressing,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             r,
+	}
+	switch r {
+	case ReasonOperationInProgress:
+		c.Message = fmt.Sprintf(fmtOperationInProgress, op.Operation, op.RequestedAt.UTC().Format(time.RFC3339))
+	case ReasonOperationStuck:
+		c.Status = corev1.ConditionFalse
+		c.Message = fmt.Sprintf(fmtOperationStuck, op.Operation, op.RequestedAt.UTC().Format(time.RFC3339), deadline)
+	}
+	return c
+}
+
+func parametersHash(mg resource.Managed) (string, error) {
+	params, err := util.Parameters(mg)
+	if err != nil {
+		return "", err
+	}
+	return hashOf(params)
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-alibaba/apis/oss/v1alpha1"
+	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
+)
+
+// operationBucketKind returns a bucketKind that records its pending operation
+// in the supplied pointer, and whose buckets are in progress while their
+// cloud resource is "changing".
+func operationBucketKind(pending **aliv1alpha1.PendingOperation) Kind {
+	k := bucketKind()
+	k.Operations = &Operations{
+		Pending: func(_ resource.Managed) *aliv1alpha1.PendingOperation {
+			return *pending
+		},
+		SetPending: func(_ resource.Managed, op *aliv1alpha1.PendingOperation) {
+			*pending = op
+		},
+		InProgress: func(observed interface{}) bool {
+			return observed.(string) == "changing"
+		},
+		Deadline: time.Hour,
+	}
+	return k
+}
+
+func TestCreateInFlight(t *testing.T) {
+	var pending *aliv1alpha1.PendingOperation
+	k := operationBucketKind(&pending)
+	creates := 0
+	k.Create = func(ctx context.Context, _ interface{}, _ resource.Managed) (managed.ExternalCreation, error) {
+		creates++
+		SetTaskID(ctx, "order")
+		return managed.ExternalCreation{}, nil
+	}
+	cloud := ""
+	e := NewExternalClient(k, nil, &cloud)
+
+	mg := &v1alpha1.Bucket{}
+	mg.Spec.ACL = "private"
+	for i := 0; i < 2; i++ {
+		if _, err := e.Create(context.Background(), mg); err != nil {
+			t.Fatalf("e.Create(...): %s", err)
+		}
+	}
+	if diff := cmp.Diff(1, creates); diff != "" {
+		t.Errorf("e.Create(...): creates of an operation in flight: -want, +got:\n%s", diff)
+	}
+	if pending == nil || pending.Operation != OperationCreate || pending.TaskID != "order" {
+		t.Errorf("e.Create(...): want pending Create with task ID, got %+v", pending)
+	}
+	if diff := cmp.Diff(ReasonOperationInProgress, mg.GetCondition(TypeProgressing).Reason); diff != "" {
+		t.Errorf("e.Create(...): -want reason, +got reason:\n%s", diff)
+	}
+
+	// Changing the parameters requests a new operation.
+	mg.Spec.ACL = "public-read"
+	if _, err := e.Create(context.Background(), mg); err != nil {
+		t.Fatalf("e.Create(...): %s", err)
+	}
+	if diff := cmp.Diff(2, creates); diff != "" {
+		t.Errorf("e.Create(...): creates after a spec change: -want, +got:\n%s", diff)
+	}
+}
+
+func TestUpdateSkipped(t *testing.T) {
+	var pending *aliv1alpha1.PendingOperation
+	k := operationBucketKind(&pending)
+	updates := 0
+	k.Update = func(ctx context.Context, _ interface{}, _ resource.Managed) (managed.ExternalUpdate, error) {
+		updates++
+		SkipOperation(ctx)
+		return managed.ExternalUpdate{}, nil
+	}
+	cloud := "changing"
+	e := NewExternalClient(k, nil, &cloud)
+
+	mg := &v1alpha1.Bucket{}
+	mg.Spec.ACL = "private"
+	for i := 0; i < 2; i++ {
+		if _, err := e.Update(context.Background(), mg); err != nil {
+			t.Fatalf("e.Update(...): %s", err)
+		}
+	}
+	if diff := cmp.Diff(2, updates); diff != "" {
+		t.Errorf("e.Update(...): updates that skipped their operation: -want, +got:\n%s", diff)
+	}
+	if pending != nil {
+		t.Errorf("e.Update(...): want no pending operation, got %+v", pending)
+	}
+}
+
+func TestUpdateNotStarted(t *testing.T) {
+	var pending *aliv1alpha1.PendingOperation
+	k := operationBucketKind(&pending)
+	updates := 0
+	k.Update = func(_ context.Context, _ interface{}, _ resource.Managed) (managed.ExternalUpdate, error) {
+		updates++
+		return managed.ExternalUpdate{}, nil
+	}
+	// The cloud resource does not report the update as changing yet.
+	cloud := "private"
+	e := NewExternalClient(k, nil, &cloud)
+
+	mg := &v1alpha1.Bucket{}
+	mg.Spec.ACL = "public-read"
+	for i := 0; i < 2; i++ {
+		if _, err := e.Observe(context.Background(), mg); err != nil {
+			t.Fatalf("e.Observe(...): %s", err)
+		}
+		if _, err := e.Update(context.Background(), mg); err != nil {
+			t.Fatalf("e.Update(...): %s", err)
+		}
+	}
+	if diff := cmp.Diff(1, updates); diff != "" {
+		t.Errorf("e.Update(...): updates before the first was observed to start: -want, +got:\n%s", diff)
+	}
+	if pending == nil || pending.Started {
+		t.Errorf("e.Observe(...): want pending Update that has not started, got %+v", pending)
+	}
+
+	// The update is observed to start and then to complete.
+	cloud = "changing"
+	if _, err := e.Observe(context.Background(), mg); err != nil {
+		t.Fatalf("e.Observe(...): %s", err)
+	}
+	if pending == nil || !pending.Started {
+		t.Errorf("e.Observe(...): want started Update, got %+v", pending)
+	}
+	cloud = "private"
+	if _, err := e.Observe(context.Background(), mg); err != nil {
+		t.Fatalf("e.Observe(...): %s", err)
+	}
+	if pending != nil {
+		t.Errorf("e.Observe(...): want no pending operation, got %+v", pending)
+	}
+}
+
+func TestTrackOperation(t *testing.T) {
+	type want struct {
+		pending bool
+		reason  string
+		events  int
+	}
+
+	cases := map[string]struct {
+		reason      string
+		cloud       string
+		operation   string
+		requestedAt time.Time
+		started     bool
+		want        want
+	}{
+		"InProgress": {
+			reason:      "Operations should remain pending while the cloud resource is changing",
+			cloud:       "changing",
+			operation:   OperationCreate,
+			requestedAt: time.Now(),
+			want:        want{pending: true, reason: string(ReasonOperationInProgress)},
+		},
+		"NotFound": {
+			reason:      "Creations should remain pending until the cloud resource is observed",
+			operation:   OperationCreate,
+			requestedAt: time.Now(),
+			want:        want{pending: true, reason: string(ReasonOperationInProgress)},
+		},
+		"Complete": {
+			reason:      "Operations should complete once the cloud resource is no longer changing",
+			cloud:       "private",
+			operation:   OperationUpdate,
+			requestedAt: time.Now(),
+			want:        want{reason: string(ReasonOperationComplete)},
+		},
+		"UpdatePartiallyApplied": {
+			reason:      "Updates should complete once the cloud resource stopped changing, even if it is not up to date yet, so that the remaining changes are requested",
+			cloud:       "public-read",
+			operation:   OperationUpdate,
+			requestedAt: time.Now(),
+			started:     true,
+			want:        want{reason: string(ReasonOperationComplete)},
+		},
+		"UpdateNotStarted": {
+			reason:      "Updates should remain pending while the cloud resource has neither started changing nor applied the spec",
+			cloud:       "public-read",
+			operation:   OperationUpdate,
+			requestedAt: time.Now(),
+			want:        want{pending: true, reason: string(ReasonOperationInProgress)},
+		},
+		"UpdateGracePeriodPassed": {
+			reason:      "Updates that were never observed to start should complete once the grace period passed",
+			cloud:       "public-read",
+			operation:   OperationUpdate,
+			requestedAt: time.Now().Add(-10 * time.Minute),
+			want:        want{reason: string(ReasonOperationComplete)},
+		},
+		"Stuck": {
+			reason:      "Operations that have not completed within the deadline should be reported as stuck",
+			cloud:       "changing",
+			operation:   OperationUpdate,
+			requestedAt: time.Now().Add(-2 * time.Hour),
+			want:        want{pending: true, reason: string(ReasonOperationStuck), events: 1},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pending := &aliv1alpha1.PendingOperation{Operation: tc.operation, RequestedAt: metav1.NewTime(tc.requestedAt), Started: tc.started}
+			r := &recorder{}
+			e := &external{kind: operationBucketKind(&pending), client: &tc.cloud, record: r}
+
+			mg := &v1alpha1.Bucket{}
+			mg.Spec.ACL = "private"
+			if _, err := e.Observe(context.Background(), mg); err != nil {
+				t.Fatalf("e.Observe(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want.pending, pending != nil); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want pending, +got pending:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.reason, string(mg.GetCondition(TypeProgressing).Reason)); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want reason, +got reason:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.events, len(r.events)); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want events, +got events:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestOperationProgress(t *testing.T) {
+	pending := &aliv1alpha1.PendingOperation{Operation: OperationUpdate, RequestedAt: metav1.Now()}
+	k := operationBucketKind(&pending)
+	k.Operations.Progress = func(_ resource.Managed, observed interface{}) string {
+		return "bucket is " + observed.(string)
+	}
+	cloud := "changing"
+	e := &external{kind: k, client: &cloud, record: &recorder{}}
+
+	mg := &v1alpha1.Bucket{}
+	if _, err := e.Observe(context.Background(), mg); err != nil {
+		t.Fatalf("e.Observe(...): %s", err)
+	}
+	want := "Update requested at " + pending.RequestedAt.UTC().Format(time.RFC3339) + ": bucket is changing"
+	if diff := cmp.Diff(want, mg.GetCondition(TypeProgressing).Message); diff != "" {
+		t.Errorf("e.Observe(...): -want message, +got message:\n%s", diff)
+	}
+}
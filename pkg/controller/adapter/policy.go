@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/policy"
+	"github.com/crossplane/provider-alibaba/pkg/util"
+)
+
+// TypeCompliant conditions report whether the parameters of a managed
+// resource satisfy all ProvisioningPolicies.
+const TypeCompliant xpv1.ConditionType = "Compliant"
+
+// Reasons of TypeCompliant conditions.
+const (
+	ReasonPolicySatisfied xpv1.ConditionReason = "PolicySatisfied"
+	ReasonPolicyViolated  xpv1.ConditionReason = "PolicyViolated"
+)
+
+const (
+	errCheckPolicies = "cannot check provisioning policies"
+	errFmtViolated   = "managed resource violates provisioning policies: %s"
+)
+
+// enforcePolicies returns an error if the supplied managed resource violates
+// a ProvisioningPolicy. Updates only fail if they change a field to a
+// violating value, i.e. if it differs from the value of the observed cloud
+// resource, like the validating webhook, so that resources created before a
+// policy was tightened can still be updated. Fields that are not observed are
+// left to the webhook. The condition is only reported once a resource has
+// violated a policy.
+func (e *external) enforcePolicies(ctx context.Context, mg resource.Managed, update bool) error {
+	if e.kube == nil {
+		return nil
+	}
+	params, err := util.Parameters(mg)
+	if err != nil {
+		return errors.Wrap(err, errCheckPolicies)
+	}
+	vs, err := policy.Check(ctx, e.kube, e.kind.GroupVersionKind.Kind, params)
+	if err != nil {
+		return errors.Wrap(err, errCheckPolicies)
+	}
+	if len(vs) == 0 {
+		if mg.GetCondition(TypeCompliant).Status == corev1.ConditionFalse {
+			mg.SetConditions(compliant(nil))
+		}
+		return nil
+	}
+	mg.SetConditions(compliant(vs))
+	if update {
+		if vs, err = e.changed(vs, params); err != nil {
+			return errors.Wrap(err, errCheckPolicies)
+		}
+		if len(vs) == 0 {
+			return nil
+		}
+	}
+	return clients.NewTerminalErrorf(errFmtViolated, policy.Message(vs))
+}
+
+// changed returns the supplied violations of the supplied desired parameters
+// whose value differs from the parameters of the observed cloud resource.
+func (e *external) changed(vs []policy.Violation, params map[string]interface{}) ([]policy.Violation, error) {
+	if e.kind.Parameters == nil || e.observed == nil {
+		return nil, nil
+	}
+	o, err := util.Flatten(e.kind.Parameters(e.observed))
+	if err != nil {
+		return nil, err
+	}
+	d, err := util.Flatten(params)
+	if err != nil {
+		return nil, err
+	}
+	var c []policy.Violation
+	for _, v := range vs {
+		if ov, ok := o[v.Field]; ok && ov != d[v.Field] {
+			c = append(c, v)
+		}
+	}
+	return c, nil
+}
+
+func compliant(vs []policy.Violation) xpv1.Condition {
+	if len(vs) > 0 {
+		return xpv1.Condition{
+			Type:               TypeCompliant,
+			Status:             corev1.ConditionFalse,
+			LastTransitionTime: metav1.Now(),
+			Reason:             ReasonPolicyViolated,
+			Message:            policy.Message(vs),
+		}
+	}
+	return xpv1.Condition{
+		Type:               TypeCompliant,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPolicySatisfied,
+	}
+}
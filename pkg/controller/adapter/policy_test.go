@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/provider-alibaba/apis/oss/v1alpha1"
+	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/policy"
+)
+
+func TestCreateViolatesPolicy(t *testing.T) {
+	p := aliv1alpha1.ProvisioningPolicy{Spec: aliv1alpha1.ProvisioningPolicySpec{Rules: []aliv1alpha1.PolicyRule{{
+		Name:     "no-public-buckets",
+		Kind:     v1alpha1.BucketKind,
+		Field:    "acl",
+		Operator: aliv1alpha1.PolicyOperatorNotIn,
+		Values:   []string{"public-read-write"},
+	}}}}
+	p.SetName("buckets")
+	kube := &test.MockClient{MockList: test.NewMockListFn(nil, func(obj runtime.Object) error {
+		obj.(*aliv1alpha1.ProvisioningPolicyList).Items = []aliv1alpha1.ProvisioningPolicy{p}
+		return nil
+	})}
+	region := "cn-beijing"
+	e := NewExternalClient(bucketKind(), kube, &region)
+
+	mg := &v1alpha1.Bucket{}
+	mg.Spec.ACL = "public-read-write"
+
+	vs := []policy.Violation{{
+		Policy: "buckets",
+		Rule:   "no-public-buckets",
+		Field:  "acl",
+		Value:  `is "public-read-write", must not be one of public-read-write`,
+	}}
+	wantErr := clients.NewTerminalErrorf(errFmtViolated, policy.Message(vs))
+	_, err := e.Create(context.Background(), mg)
+	if diff := cmp.Diff(wantErr, err, test.EquateErrors()); diff != "" {
+		t.Errorf("e.Create(...): -want error, +got error:\n%s", diff)
+	}
+	want := xpv1.Condition{Type: TypeCompliant, Status: corev1.ConditionFalse, Reason: ReasonPolicyViolated, Message: policy.Message(vs)}
+	if diff := cmp.Diff(want, mg.GetCondition(TypeCompliant), test.EquateConditions()); diff != "" {
+		t.Errorf("e.Create(...): -want condition, +got condition:\n%s", diff)
+	}
+
+	mg.Spec.ACL = "private"
+	if _, err := e.Create(context.Background(), mg); err != nil {
+		t.Errorf("e.Create(...): %s", err)
+	}
+	want = xpv1.Condition{Type: TypeCompliant, Status: corev1.ConditionTrue, Reason: ReasonPolicySatisfied}
+	if diff := cmp.Diff(want, mg.GetCondition(TypeCompliant), test.EquateConditions()); diff != "" {
+		t.Errorf("e.Create(...): -want condition, +got condition:\n%s", diff)
+	}
+}
+
+func TestUpdateViolatesPolicy(t *testing.T) {
+	p := aliv1alpha1.ProvisioningPolicy{Spec: aliv1alpha1.ProvisioningPolicySpec{Rules: []aliv1alpha1.PolicyRule{{
+		Name:     "no-public-buckets",
+		Kind:     v1alpha1.BucketKind,
+		Field:    "acl",
+		Operator: aliv1alpha1.PolicyOperatorNotIn,
+		Values:   []string{"public-read-write"},
+	}}}}
+	p.SetName("buckets")
+	vs := []policy.Violation{{
+		Policy: "buckets",
+		Rule:   "no-public-buckets",
+		Field:  "acl",
+		Value:  `is "public-read-write", must not be one of public-read-write`,
+	}}
+
+	cases := map[string]struct {
+		reason     string
+		observed   string
+		parameters func(observed interface{}) interface{}
+		want       error
+	}{
+		"Changed": {
+			reason:     "Updates changing a field to a violating value should fail",
+			observed:   "private",
+			parameters: func(observed interface{}) interface{} { return map[string]string{"acl": observed.(string)} },
+			want:       clients.NewTerminalErrorf(errFmtViolated, policy.Message(vs)),
+		},
+		"Unchanged": {
+			reason:     "Updates of resources that violated a policy before it was created should succeed",
+			observed:   "public-read-write",
+			parameters: func(observed interface{}) interface{} { return map[string]string{"acl": observed.(string)} },
+		},
+		"NotObserved": {
+			reason:   "Updates of fields that are not observed should be left to the webhook",
+			observed: "private",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kube := &test.MockClient{MockList: test.NewMockListFn(nil, func(obj runtime.Object) error {
+				obj.(*aliv1alpha1.ProvisioningPolicyList).Items = []aliv1alpha1.ProvisioningPolicy{p}
+				return nil
+			})}
+			k := bucketKind()
+			k.Update = func(_ context.Context, _ interface{}, _ resource.Managed) (managed.ExternalUpdate, error) {
+				return managed.ExternalUpdate{}, nil
+			}
+			k.Parameters = tc.parameters
+			e := NewExternalClient(k, kube, &tc.observed)
+
+			mg := &v1alpha1.Bucket{}
+			mg.Spec.ACL = "public-read-write"
+			if _, err := e.Observe(context.Background(), mg); err != nil {
+				t.Fatalf("e.Observe(...): %s", err)
+			}
+			_, err := e.Update(context.Background(), mg)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			want := xpv1.Condition{Type: TypeCompliant, Status: corev1.ConditionFalse, Reason: ReasonPolicyViolated, Message: policy.Message(vs)}
+			if diff := cmp.Diff(want, mg.GetCondition(TypeCompliant), test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want condition, +got condition:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
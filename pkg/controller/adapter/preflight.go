@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/clients/quotas"
+	"github.com/crossplane/provider-alibaba/pkg/util"
+)
+
+const (
+	errNewQuotaClient = "cannot create quota client"
+	errPreflight      = "cannot check whether the cloud resource can be created"
+	errListQuotas     = "cannot list quotas"
+
+	errFmtQuotasExhausted = "%s quotas are exhausted in %s: %s; request a quota increase in the Quota Center"
+	fmtQuotaUsage         = "%s (%g of %g used)"
+
+	reasonPreflightIncomplete event.Reason = "PreflightIncomplete"
+)
+
+// A Preflight checks that the cloud resources of a Kind can be created before
+// Create is called, so that creations that would fail late, e.g. because an
+// instance class is sold out, fail fast with an actionable error instead.
+type Preflight struct {
+	// Check returns a terminal error if the cloud resource of the supplied
+	// managed resource cannot be created in the supplied region, e.g.
+	// because its instance class is not available in its zone. Checks are
+	// best effort; any other error, e.g. because the offerings cannot be
+	// described, is recorded as an event and creation proceeds. Optional.
+	Check func(ctx context.Context, client interface{}, mg resource.Managed, region string) error
+
+	// QuotaProductCode is the Quota Center code of the product of the cloud
+	// resources, e.g. rds. Creations fail while any quota of the product
+	// that limits the whole account in the region is exhausted. Optional.
+	QuotaProductCode string
+}
+
+// A NewQuotaClientFn returns the client used to read the quotas of the
+// account.
+type NewQuotaClientFn func(ctx context.Context, creds util.Credentials) (quotas.Client, error)
+
+func newQuotaClient(ctx context.Context, creds util.Credentials) (quotas.Client, error) {
+	return quotas.NewClient(ctx, creds.AccessKeyID, creds.AccessKeySecret, creds.SecurityToken, creds.Region)
+}
+
+// WithQuotaClient configures how the client used to read the quotas of the
+// account is created.
+func WithQuotaClient(fn NewQuotaClientFn) ConnecterOption {
+	return func(c *connector) {
+		c.newQuotaClient = fn
+	}
+}
+
+// checksQuotas returns true if the Kind checks quotas before creating cloud
+// resources.
+func (k Kind) checksQuotas() bool {
+	return k.Preflight != nil && k.Preflight.QuotaProductCode != ""
+}
+
+// preflight returns a terminal error if the cloud resource of the supplied
+// managed resource cannot be created.
+func (e *external) preflight(ctx context.Context, mg resource.Managed) error {
+	if e.kind.Preflight == nil {
+		return nil
+	}
+	if e.kind.Preflight.Check != nil {
+		err := e.kind.Preflight.Check(ctx, e.client, mg, e.region)
+		if clients.IsTerminalError(err) {
+			return err
+		}
+		if err != nil {
+			e.record.Event(mg, event.Warning(reasonPreflightIncomplete, errors.Wrap(err, errPreflight)))
+		}
+	}
+	if e.quotas == nil || !e.kind.checksQuotas() {
+		return nil
+	}
+	// Quotas are not checked if the account cannot read them.
+	qs, err := e.quotas.ListProductQuotas(ctx, e.kind.Preflight.QuotaProductCode)
+	if err != nil {
+		e.record.Event(mg, event.Warning(reasonPreflightIncomplete, errors.Wrap(err, errListQuotas)))
+		return nil
+	}
+	exhausted := quotas.Exhausted(qs, e.region)
+	if len(exhausted) == 0 {
+		return nil
+	}
+	usage := make([]string, len(exhausted))
+	for i, q := range exhausted {
+		usage[i] = fmt.Sprintf(fmtQuotaUsage, q.Name, q.Usage, q.Limit)
+	}
+	return clients.NewTerminalErrorf(errFmtQuotasExhausted, e.kind.GroupVersionKind.Kind, e.region, strings.Join(usage, ", "))
+}
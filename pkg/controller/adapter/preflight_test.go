@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-alibaba/apis/oss/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/clients/quotas"
+)
+
+type fakeQuotaClient struct {
+	quotas []quotas.Quota
+	err    error
+}
+
+func (c *fakeQuotaClient) ListProductQuotas(_ context.Context, _ string) ([]quotas.Quota, error) {
+	return c.quotas, c.err
+}
+
+func TestPreflight(t *testing.T) {
+	errUnavailable := clients.NewTerminalError("sold out")
+
+	type want struct {
+		err     error
+		created bool
+		events  []event.Event
+	}
+
+	cases := map[string]struct {
+		reason string
+		check  error
+		quotas *fakeQuotaClient
+		want   want
+	}{
+		"Unavailable": {
+			reason: "Creations of unavailable cloud resources should fail before Create is called",
+			check:  errUnavailable,
+			want:   want{err: errUnavailable},
+		},
+		"QuotaExhausted": {
+			reason: "Creations should fail while a regional quota of the product is exhausted",
+			quotas: &fakeQuotaClient{quotas: []quotas.Quota{
+				{Name: "Instances", Limit: 10, Usage: 10, Dimensions: map[string]string{"regionId": "cn-beijing"}},
+			}},
+			want: want{err: clients.NewTerminalError("Bucket quotas are exhausted in cn-beijing: Instances (10 of 10 used); request a quota increase in the Quota Center")},
+		},
+		"QuotaAvailable": {
+			reason: "Creations should proceed while the regional quotas of the product are not exhausted",
+			quotas: &fakeQuotaClient{quotas: []quotas.Quota{
+				{Name: "Instances", Limit: 10, Usage: 9},
+				{Name: "Other region", Limit: 10, Usage: 10, Dimensions: map[string]string{"regionId": "cn-shanghai"}},
+				{Name: "Accounts", Limit: 10, Usage: 10, Dimensions: map[string]string{"regionId": "cn-beijing", "instanceId": "cool"}},
+			}},
+			want: want{created: true},
+		},
+		"QuotasUnreadable": {
+			reason: "Creations should proceed if the quotas cannot be read",
+			quotas: &fakeQuotaClient{err: errBoom},
+			want: want{
+				created: true,
+				events:  []event.Event{event.Warning(reasonPreflightIncomplete, errors.Wrap(errBoom, errListQuotas))},
+			},
+		},
+		"CheckIncomplete": {
+			reason: "Creations should proceed, and record why, if the check cannot tell whether they would succeed",
+			check:  errBoom,
+			want: want{
+				created: true,
+				events:  []event.Event{event.Warning(reasonPreflightIncomplete, errors.Wrap(errBoom, errPreflight))},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			created := false
+			k := bucketKind()
+			k.Create = func(_ context.Context, _ interface{}, _ resource.Managed) (managed.ExternalCreation, error) {
+				created = true
+				return managed.ExternalCreation{}, nil
+			}
+			k.Preflight = &Preflight{
+				Check: func(_ context.Context, _ interface{}, _ resource.Managed, _ string) error {
+					return tc.check
+				},
+				QuotaProductCode: "oss",
+			}
+			cloud := ""
+			r := &recorder{}
+			e := &external{kind: k, client: &cloud, region: "cn-beijing", record: r}
+			if tc.quotas != nil {
+				e.quotas = tc.quotas
+			}
+
+			_, err := e.Create(context.Background(), &v1alpha1.Bucket{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.created, created); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want created, +got created:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.events, r.events, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want events, +got events:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-alibaba/apis/oss/v1alpha1"
+)
+
+func TestTimeoutExternal(t *testing.T) {
+	var deadlines []time.Duration
+	record := func(ctx context.Context) {
+		d, ok := ctx.Deadline()
+		if !ok {
+			deadlines = append(deadlines, 0)
+			return
+		}
+		deadlines = append(deadlines, time.Until(d))
+	}
+	e := &timeoutExternal{
+		ExternalClient: &managed.ExternalClientFns{
+			ObserveFn: func(ctx context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+				record(ctx)
+				return managed.ExternalObservation{}, nil
+			},
+			CreateFn: func(ctx context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+				record(ctx)
+				return managed.ExternalCreation{}, nil
+			},
+			UpdateFn: func(ctx context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+				record(ctx)
+				return managed.ExternalUpdate{}, nil
+			},
+			DeleteFn: func(ctx context.Context, _ resource.Managed) error {
+				record(ctx)
+				return nil
+			},
+		},
+		timeout: time.Minute,
+	}
+
+	// A shorter deadline of the reconciler must not be extended.
+	short, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	mg := &v1alpha1.Bucket{}
+	_, _ = e.Observe(context.Background(), mg)
+	_, _ = e.Create(context.Background(), mg)
+	_, _ = e.Update(context.Background(), mg)
+	_ = e.Delete(short, mg)
+
+	for i, d := range deadlines[:3] {
+		if d <= 0 || d > time.Minute {
+			t.Errorf("operation %d: want deadline within the operation timeout, got %s", i, d)
+		}
+	}
+	if d := deadlines[3]; d <= 0 || d > time.Second {
+		t.Errorf("Delete(...): want deadline of the reconciler, got %s", d)
+	}
+}
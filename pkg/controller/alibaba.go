@@ -17,9 +17,20 @@ limitations under the License.
 package controller
 
 import (
+	"strings"
+
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	databasev1alpha1 "github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
+	nasv1alpha1 "github.com/crossplane/provider-alibaba/apis/nas/v1alpha1"
+	ossv1alpha1 "github.com/crossplane/provider-alibaba/apis/oss/v1alpha1"
+	redisv1alpha1 "github.com/crossplane/provider-alibaba/apis/redis/v1alpha1"
+	slbv1alpha1 "github.com/crossplane/provider-alibaba/apis/slb/v1alpha1"
+	slsv1alpha1 "github.com/crossplane/provider-alibaba/apis/sls/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
 	"github.com/crossplane/provider-alibaba/pkg/controller/config"
 	"github.com/crossplane/provider-alibaba/pkg/controller/database"
 	"github.com/crossplane/provider-alibaba/pkg/controller/nas"
@@ -29,26 +40,134 @@ import (
 	"github.com/crossplane/provider-alibaba/pkg/controller/sls"
 )
 
+const errFmtUnknownController = "unknown controller %q"
+
+// A managedController reconciles the managed resources of one kind.
+type managedController struct {
+	gvk   schema.GroupVersionKind
+	setup func(ctrl.Manager, logging.Logger, adapter.Options) error
+}
+
+var managedControllers = []managedController{
+	{databasev1alpha1.RDSInstanceGroupVersionKind, database.SetupRDSInstance},
+	{databasev1alpha1.RDSDatabaseGroupVersionKind, database.SetupRDSDatabase},
+	{databasev1alpha1.RDSAccountGroupVersionKind, database.SetupRDSAccount},
+	{databasev1alpha1.RDSReadOnlyInstanceGroupVersionKind, database.SetupRDSReadOnlyInstance},
+	{redisv1alpha1.RedisInstanceGroupVersionKind, redis.SetupRedisInstance},
+	{slsv1alpha1.ProjectGroupVersionKind, sls.SetupProject},
+	{slsv1alpha1.StoreGroupVersionKind, sls.SetupStore},
+	{slsv1alpha1.LogtailGroupVersionKind, sls.SetupLogtail},
+	{slsv1alpha1.IndexGroupVersionKind, sls.SetupIndex},
+	{slsv1alpha1.MachineGroupVersionKind, sls.SetupMachineGroup},
+	{slsv1alpha1.MachineGroupBindingGroupVersionKind, sls.SetupMachineGroupBinding},
+	{ossv1alpha1.BucketGroupVersionKind, oss.SetupBucket},
+	{nasv1alpha1.NASFileSystemGroupVersionKind, nas.SetupNASFileSystem},
+	{nasv1alpha1.NASMountTargetGroupVersionKind, nas.SetupNASMountTarget},
+	{slbv1alpha1.CLBGroupVersionKind, slb.SetupCLB},
+}
+
+// Options configure the controllers started by Setup. Controllers are
+// selected by the kind of their managed resources, e.g. RDSInstance, or by
+// their service, e.g. sls for all kinds of the sls.alibaba.crossplane.io API
+// group. Selectors are case insensitive.
+type Options struct {
+	adapter.Options
+
+	// MaxConcurrentReconcilesPerKind overrides MaxConcurrentReconciles for
+	// the selected controllers.
+	MaxConcurrentReconcilesPerKind map[string]int
+
+	// Enabled controllers. All controllers are enabled if empty.
+	Enabled []string
+
+	// Disabled controllers, even if they are enabled.
+	Disabled []string
+}
+
 // Setup creates Alibaba controllers with the supplied logger and adds them to the supplied manager.
-func Setup(mgr ctrl.Manager, l logging.Logger) error {
-	for _, setup := range []func(ctrl.Manager, logging.Logger) error{
-		config.Setup,
-		database.SetupRDSInstance,
-		redis.SetupRedisInstance,
-		sls.SetupProject,
-		sls.SetupStore,
-		sls.SetupLogtail,
-		sls.SetupIndex,
-		sls.SetupMachineGroup,
-		sls.SetupMachineGroupBinding,
-		oss.SetupBucket,
-		nas.SetupNASFileSystem,
-		nas.SetupNASMountTarget,
-		slb.SetupCLB,
-	} {
-		if err := setup(mgr, l); err != nil {
+func Setup(mgr ctrl.Manager, l logging.Logger, o Options) error {
+	if err := o.validate(); err != nil {
+		return err
+	}
+	if err := config.Setup(mgr, l); err != nil {
+		return err
+	}
+	for _, c := range managedControllers {
+		ko, enabled := o.forKind(c.gvk)
+		if !enabled {
+			continue
+		}
+		if err := c.setup(mgr, l, ko); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// forKind returns the options of the controller of the supplied kind, and
+// whether it is enabled.
+func (o Options) forKind(gvk schema.GroupVersionKind) (adapter.Options, bool) {
+	enabled := len(o.Enabled) == 0
+	for _, sel := range o.Enabled {
+		enabled = enabled || selects(sel, gvk)
+	}
+	for _, sel := range o.Disabled {
+		enabled = enabled && !selects(sel, gvk)
+	}
+	// Kind selectors take precedence over service selectors.
+	ko := o.Options
+	for sel, n := range o.MaxConcurrentReconcilesPerKind {
+		if strings.EqualFold(sel, service(gvk)) {
+			ko.MaxConcurrentReconciles = n
+		}
+	}
+	for sel, n := range o.MaxConcurrentReconcilesPerKind {
+		if strings.EqualFold(sel, gvk.Kind) {
+			ko.MaxConcurrentReconciles = n
+		}
+	}
+	return ko, enabled
+}
+
+// validate returns an error if a selector selects no controller, which is
+// most likely a typo.
+func (o Options) validate() error {
+	selectors := append(append([]string{}, o.Enabled...), o.Disabled...)
+	for sel := range o.MaxConcurrentReconcilesPerKind {
+		selectors = append(selectors, sel)
+	}
+	for _, sel := range selectors {
+		found := false
+		for _, c := range managedControllers {
+			found = found || selects(sel, c.gvk)
+		}
+		if !found {
+			return errors.Errorf(errFmtUnknownController, sel)
+		}
+	}
+	return nil
+}
+
+// selects returns true if the supplied selector is the kind or service of
+// the supplied managed resource kind.
+func selects(selector string, gvk schema.GroupVersionKind) bool {
+	return strings.EqualFold(selector, gvk.Kind) || strings.EqualFold(selector, service(gvk))
+}
+
+// service returns the service of the supplied managed resource kind, which is
+// the first label of its API group.
+func service(gvk schema.GroupVersionKind) string {
+	return strings.SplitN(gvk.Group, ".", 2)[0]
+}
+
+// Discoverable returns the kinds of managed resources whose existing cloud
+// resources can be discovered.
+func Discoverable() []adapter.Kind {
+	return []adapter.Kind{
+		database.RDSInstanceKind(),
+		redis.RedisInstanceKind(),
+		oss.BucketKind(),
+		slb.CLBKind(),
+		sls.ProjectKind(),
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	databasev1alpha1 "github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
+	slsv1alpha1 "github.com/crossplane/provider-alibaba/apis/sls/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
+)
+
+func TestForKind(t *testing.T) {
+	defaults := adapter.Options{PollInterval: time.Minute, MaxConcurrentReconciles: 2}
+
+	type want struct {
+		o       adapter.Options
+		enabled bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		o      Options
+		gvk    schema.GroupVersionKind
+		want   want
+	}{
+		"AllEnabled": {
+			reason: "All controllers should be enabled with the default options if none are selected",
+			o:      Options{Options: defaults},
+			gvk:    databasev1alpha1.RDSInstanceGroupVersionKind,
+			want:   want{o: defaults, enabled: true},
+		},
+		"EnabledService": {
+			reason: "Controllers of enabled services should be enabled",
+			o:      Options{Options: defaults, Enabled: []string{"SLS"}},
+			gvk:    slsv1alpha1.StoreGroupVersionKind,
+			want:   want{o: defaults, enabled: true},
+		},
+		"NotEnabled": {
+			reason: "Controllers that are not enabled should be disabled",
+			o:      Options{Options: defaults, Enabled: []string{"sls"}},
+			gvk:    databasev1alpha1.RDSInstanceGroupVersionKind,
+			want:   want{o: defaults, enabled: false},
+		},
+		"Disabled": {
+			reason: "Disabled controllers should be disabled even if their service is enabled",
+			o:      Options{Options: defaults, Enabled: []string{"sls"}, Disabled: []string{"logtail"}},
+			gvk:    slsv1alpha1.LogtailGroupVersionKind,
+			want:   want{o: defaults, enabled: false},
+		},
+		"PerKind": {
+			reason: "Per kind concurrency should override per service and global concurrency",
+			o: Options{
+				Options:                        defaults,
+				MaxConcurrentReconcilesPerKind: map[string]int{"sls": 5, "LogStore": 10},
+			},
+			gvk:  slsv1alpha1.StoreGroupVersionKind,
+			want: want{o: adapter.Options{PollInterval: time.Minute, MaxConcurrentReconciles: 10}, enabled: true},
+		},
+		"PerService": {
+			reason: "Per service concurrency should override global concurrency",
+			o: Options{
+				Options:                        defaults,
+				MaxConcurrentReconcilesPerKind: map[string]int{"sls": 5, "LogStore": 10},
+			},
+			gvk:  slsv1alpha1.ProjectGroupVersionKind,
+			want: want{o: adapter.Options{PollInterval: time.Minute, MaxConcurrentReconciles: 5}, enabled: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, enabled := tc.o.forKind(tc.gvk)
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("\n%s\no.forKind(...): -want options, +got options:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.enabled, enabled); diff != "" {
+				t.Errorf("\n%s\no.forKind(...): -want enabled, +got enabled:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		o      Options
+		want   error
+	}{
+		"Valid": {
+			reason: "Selectors of known kinds and services should be valid",
+			o: Options{
+				Enabled:                        []string{"sls", "RDSInstance"},
+				Disabled:                       []string{"logtail"},
+				MaxConcurrentReconcilesPerKind: map[string]int{"bucket": 3},
+			},
+		},
+		"UnknownController": {
+			reason: "Selectors that select no controller should be invalid",
+			o:      Options{Disabled: []string{"rdsinstances"}},
+			want:   errors.Errorf(errFmtUnknownController, "rdsinstances"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.o.validate()
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\no.validate(): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
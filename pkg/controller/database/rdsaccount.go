@@ -0,0 +1,265 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/password"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/clients/rds"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
+	"github.com/crossplane/provider-alibaba/pkg/util"
+)
+
+const (
+	errDescribeAccountFailed = "cannot describe RDS account"
+	errDeleteAccountFailed   = "cannot delete RDS account"
+	errResetPasswordFailed   = "cannot reset RDS account password"
+	errGrantFailed           = "cannot grant RDS account privileges"
+	errRevokeFailed          = "cannot revoke RDS account privileges"
+	errGeneratePassword      = "cannot generate RDS account password"
+
+	errFmtGetPasswordSecret   = "cannot get password Secret %s/%s"
+	errFmtPasswordSecretKey   = "password Secret %s/%s has no key %q"
+	errFmtGetConnectionSecret = "cannot get connection Secret %s/%s"
+)
+
+// SetupRDSAccount adds a controller that reconciles RDSAccounts.
+func SetupRDSAccount(mgr ctrl.Manager, l logging.Logger, o adapter.Options) error {
+	k := rdsAccountKind
+	k.NewClient = newRDSAccountClient(mgr.GetClient())
+	return adapter.Setup(mgr, l, k, o)
+}
+
+// rdsAccountKind is the adapter.Kind of RDSAccounts. Its NewClient is set by
+// SetupRDSAccount, since its clients also read Secrets.
+var rdsAccountKind = adapter.Kind{
+	Type:              &v1alpha1.RDSAccount{},
+	GroupVersionKind:  v1alpha1.RDSAccountGroupVersionKind,
+	Describe:          describeRDSAccount,
+	IsNotFound:        rds.IsAccountNotFound,
+	Observe:           observeRDSAccount,
+	IsUpToDate:        isRDSAccountUpToDate,
+	Condition:         getRDSAccountCondition,
+	ConnectionDetails: getRDSAccountConnectionDetails,
+	Create:            createRDSAccount,
+	Update:            updateRDSAccount,
+	Delete:            deleteRDSAccount,
+}
+
+// An accountClient is the RDS client of RDSAccounts. It also reads the
+// Secrets their passwords are read from and published to.
+type accountClient struct {
+	rds.Client
+	kube client.Reader
+}
+
+// newRDSAccountClient returns a function that returns the accountClient of
+// an RDSAccount, which reads Secrets using the supplied client.
+func newRDSAccountClient(kube client.Reader) func(ctx context.Context, mg resource.Managed, creds util.Credentials) (interface{}, error) {
+	return func(ctx context.Context, mg resource.Managed, creds util.Credentials) (interface{}, error) {
+		c, err := newRDSClient(ctx, mg, creds)
+		if err != nil {
+			return nil, err
+		}
+		return &accountClient{Client: c.(rds.Client), kube: kube}, nil
+	}
+}
+
+// password returns the password the supplied RDSAccount reads from a Secret,
+// if any.
+func (c *accountClient) password(ctx context.Context, cr *v1alpha1.RDSAccount) (string, error) {
+	sel := cr.Spec.ForProvider.PasswordSecretRef
+	if sel == nil {
+		return "", nil
+	}
+	s := &corev1.Secret{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Namespace: sel.Namespace, Name: sel.Name}, s); err != nil {
+		return "", errors.Wrapf(err, errFmtGetPasswordSecret, sel.Namespace, sel.Name)
+	}
+	pw, ok := s.Data[sel.Key]
+	if !ok {
+		return "", errors.Errorf(errFmtPasswordSecretKey, sel.Namespace, sel.Name, sel.Key)
+	}
+	return string(pw), nil
+}
+
+// changedPassword returns the password the supplied RDSAccount reads from a
+// Secret if it differs from the password in its connection secret, i.e. if
+// the account has not been reset to it since it last changed. The connection
+// secret records the password, so accounts without one are never reset.
+func (c *accountClient) changedPassword(ctx context.Context, cr *v1alpha1.RDSAccount) (string, error) {
+	ref := cr.GetWriteConnectionSecretToReference()
+	if ref == nil {
+		return "", nil
+	}
+	pw, err := c.password(ctx, cr)
+	if err != nil || pw == "" {
+		return "", err
+	}
+	s := &corev1.Secret{}
+	err = c.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s)
+	if resource.IgnoreNotFound(err) != nil {
+		return "", errors.Wrapf(err, errFmtGetConnectionSecret, ref.Namespace, ref.Name)
+	}
+	if string(s.Data[xpv1.ResourceCredentialsSecretPasswordKey]) == pw {
+		return "", nil
+	}
+	return pw, nil
+}
+
+// An observedAccount is an RDS account, and whether the password Secret of
+// its RDSAccount changed since the account was last reset to it.
+type observedAccount struct {
+	*rds.Account
+	passwordChanged bool
+}
+
+func describeRDSAccount(ctx context.Context, c interface{}, mg resource.Managed) (interface{}, error) {
+	cr := mg.(*v1alpha1.RDSAccount)
+	id := cr.Spec.ForProvider.DBInstanceID
+	if id == "" || meta.GetExternalName(cr) == "" {
+		return nil, adapter.ErrNotFound
+	}
+	client := c.(*accountClient)
+	a, err := client.DescribeAccount(ctx, id, meta.GetExternalName(cr))
+	if err != nil {
+		return nil, errors.Wrap(err, errDescribeAccountFailed)
+	}
+	pw, err := client.changedPassword(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	return &observedAccount{Account: a, passwordChanged: pw != ""}, nil
+}
+
+func observeRDSAccount(mg resource.Managed, observed interface{}) {
+	mg.(*v1alpha1.RDSAccount).Status.AtProvider = rds.GenerateAccountObservation(observed.(*observedAccount).Account)
+}
+
+// isRDSAccountUpToDate returns true if the account has the privileges of the
+// spec of the supplied RDSAccount and, if it reads its password from a
+// Secret, has been reset to the password since it last changed.
+func isRDSAccountUpToDate(mg resource.Managed, observed interface{}) bool {
+	a := observed.(*observedAccount)
+	return !a.passwordChanged && rds.IsAccountUpToDate(&mg.(*v1alpha1.RDSAccount).Spec.ForProvider, a.Account)
+}
+
+func getRDSAccountCondition(mg resource.Managed, _ interface{}) xpv1.Condition {
+	if mg.(*v1alpha1.RDSAccount).Status.AtProvider.AccountStatus == v1alpha1.RDSAccountStateAvailable {
+		return xpv1.Available()
+	}
+	return xpv1.Unavailable()
+}
+
+// getRDSAccountConnectionDetails returns the endpoints of the instance of the
+// account along with its name. Its password is published when the account
+// is created or its password is reset.
+func getRDSAccountConnectionDetails(_ context.Context, _ interface{}, _ resource.Managed, observed interface{}) (managed.ConnectionDetails, error) {
+	a := observed.(*observedAccount)
+	cd := managed.ConnectionDetails{xpv1.ResourceCredentialsSecretUserKey: []byte(a.Name)}
+	addEndpointConnectionDetails(cd, a.Endpoint, a.PublicEndpoint)
+	return cd, nil
+}
+
+// createRDSAccount creates the account with the password read from a Secret,
+// or a generated one, and returns the password. Privileges are granted once
+// the account is available.
+func createRDSAccount(ctx context.Context, c interface{}, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr := mg.(*v1alpha1.RDSAccount)
+	id := cr.Spec.ForProvider.DBInstanceID
+	if id == "" {
+		return managed.ExternalCreation{}, clients.NewTerminalError(errNoInstance)
+	}
+	client := c.(*accountClient)
+	pw, err := client.password(ctx, cr)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	if pw == "" {
+		if pw, err = password.Generate(); err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errGeneratePassword)
+		}
+	}
+	if err := client.CreateAccount(ctx, id, meta.GetExternalName(cr), pw, &cr.Spec.ForProvider); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateAccountFailed)
+	}
+	return managed.ExternalCreation{ConnectionDetails: managed.ConnectionDetails{
+		xpv1.ResourceCredentialsSecretUserKey:     []byte(meta.GetExternalName(cr)),
+		xpv1.ResourceCredentialsSecretPasswordKey: []byte(pw),
+	}}, nil
+}
+
+// updateRDSAccount resets the password of the account to the one read from a
+// Secret if it changed, and revokes and grants privileges until those of the
+// account are those of its spec.
+func updateRDSAccount(ctx context.Context, c interface{}, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr := mg.(*v1alpha1.RDSAccount)
+	if cr.Status.AtProvider.AccountStatus != v1alpha1.RDSAccountStateAvailable {
+		return managed.ExternalUpdate{}, nil
+	}
+	client := c.(*accountClient)
+	id, name := cr.Spec.ForProvider.DBInstanceID, meta.GetExternalName(cr)
+	p := cr.Spec.ForProvider
+
+	u := managed.ExternalUpdate{}
+	pw, err := client.changedPassword(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	if pw != "" {
+		if err := client.ResetAccountPassword(ctx, id, name, pw); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errResetPasswordFailed)
+		}
+		u.ConnectionDetails = managed.ConnectionDetails{xpv1.ResourceCredentialsSecretPasswordKey: []byte(pw)}
+	}
+
+	if cr.Status.AtProvider.AccountType == v1alpha1.AccountTypeSuper {
+		return u, nil
+	}
+	grant, revoke := rds.DiffPrivileges(p.Privileges, cr.Status.AtProvider.Privileges)
+	if len(revoke) != 0 {
+		if err := client.RevokeAccountPrivilege(ctx, id, name, revoke); err != nil {
+			return u, errors.Wrap(err, errRevokeFailed)
+		}
+	}
+	if len(grant) != 0 {
+		if err := client.GrantAccountPrivilege(ctx, id, name, grant); err != nil {
+			return u, errors.Wrap(err, errGrantFailed)
+		}
+	}
+	return u, nil
+}
+
+func deleteRDSAccount(ctx context.Context, c interface{}, mg resource.Managed) error {
+	cr := mg.(*v1alpha1.RDSAccount)
+	err := c.(rds.Client).DeleteAccount(ctx, cr.Spec.ForProvider.DBInstanceID, meta.GetExternalName(cr))
+	return errors.Wrap(err, errDeleteAccountFailed)
+}
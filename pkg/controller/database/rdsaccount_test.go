@@ -0,0 +1,367 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/clients/rds"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
+)
+
+const testAccount = "reader"
+
+var (
+	readOnly  = []v1alpha1.AccountPrivilege{{Database: testDatabase, Privilege: "ReadOnly"}}
+	readWrite = []v1alpha1.AccountPrivilege{{Database: testDatabase, Privilege: "ReadWrite"}}
+)
+
+func rdsAccount(p v1alpha1.RDSAccountParameters, o v1alpha1.RDSAccountObservation) *v1alpha1.RDSAccount {
+	cr := &v1alpha1.RDSAccount{
+		Spec:   v1alpha1.RDSAccountSpec{ForProvider: p},
+		Status: v1alpha1.RDSAccountStatus{AtProvider: o},
+	}
+	cr.SetName(testAccount)
+	meta.SetExternalName(cr, testAccount)
+	return cr
+}
+
+var (
+	passwordRef   = &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Namespace: "ns", Name: "password"}, Key: "pw"}
+	connectionRef = &xpv1.SecretReference{Namespace: "ns", Name: "connection"}
+)
+
+// secrets returns a client that reads the supplied password from the password
+// Secret and the supplied published password from the connection Secret.
+func secrets(pw, published string) client.Client {
+	return &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+		data := map[string][]byte{passwordRef.Key: []byte(pw)}
+		if key.Name == connectionRef.Name {
+			data = map[string][]byte{xpv1.ResourceCredentialsSecretPasswordKey: []byte(published)}
+		}
+		obj.(*corev1.Secret).Data = data
+		return nil
+	}}
+}
+
+func TestAccountClientChangedPassword(t *testing.T) {
+	type want struct {
+		pw  string
+		err error
+	}
+
+	cases := map[string]struct {
+		reason     string
+		kube       client.Client
+		password   *xpv1.SecretKeySelector
+		connection *xpv1.SecretReference
+		want       want
+	}{
+		"NoSecrets": {
+			reason:     "Accounts that read no password from a Secret should never be reset",
+			connection: connectionRef,
+		},
+		"NoConnectionSecret": {
+			reason:   "Accounts without a connection Secret should never be reset",
+			password: passwordRef,
+		},
+		"Changed": {
+			reason:     "Passwords that differ from the published one should be returned",
+			kube:       secrets("new", "old"),
+			password:   passwordRef,
+			connection: connectionRef,
+			want:       want{pw: "new"},
+		},
+		"Published": {
+			reason:     "Passwords that have been published should not be returned",
+			kube:       secrets("pw", "pw"),
+			password:   passwordRef,
+			connection: connectionRef,
+		},
+		"NotPublishedYet": {
+			reason:     "Connection Secrets that do not exist yet should publish no password",
+			kube:       &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, connectionRef.Name))},
+			connection: connectionRef,
+		},
+		"NoPasswordKey": {
+			reason:     "Password Secrets without the referenced key should return an error",
+			kube:       &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+			password:   passwordRef,
+			connection: connectionRef,
+			want:       want{err: errors.Errorf(errFmtPasswordSecretKey, "ns", "password", "pw")},
+		},
+		"GetPasswordSecretFailed": {
+			reason:     "Errors getting the password Secret should be returned",
+			kube:       &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			password:   passwordRef,
+			connection: connectionRef,
+			want:       want{err: errors.Wrapf(errBoom, errFmtGetPasswordSecret, "ns", "password")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := rdsAccount(v1alpha1.RDSAccountParameters{DBInstanceID: testName, PasswordSecretRef: tc.password}, v1alpha1.RDSAccountObservation{})
+			cr.SetWriteConnectionSecretToReference(tc.connection)
+			c := &accountClient{Client: &fakeRDSClient{}, kube: tc.kube}
+			pw, err := c.changedPassword(context.Background(), cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.changedPassword(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.pw, pw); diff != "" {
+				t.Errorf("\n%s\nc.changedPassword(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRDSAccountObserve(t *testing.T) {
+	e := adapter.NewExternalClient(rdsAccountKind, nil, &accountClient{Client: &fakeRDSClient{}})
+	cr := rdsAccount(v1alpha1.RDSAccountParameters{DBInstanceID: testName, Privileges: readWrite}, v1alpha1.RDSAccountObservation{})
+	o, err := e.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("e.Observe(...): %s", err)
+	}
+
+	want := managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: false,
+		ConnectionDetails: managed.ConnectionDetails{
+			xpv1.ResourceCredentialsSecretUserKey:     []byte(testAccount),
+			xpv1.ResourceCredentialsSecretEndpointKey: []byte("test.pg.rds.aliyuncs.com"),
+			xpv1.ResourceCredentialsSecretPortKey:     []byte("5432"),
+		},
+	}
+	if diff := cmp.Diff(want, o); diff != "" {
+		t.Errorf("e.Observe(...): -want, +got:\n%s", diff)
+	}
+	wantStatus := v1alpha1.RDSAccountObservation{
+		AccountStatus: v1alpha1.RDSAccountStateAvailable,
+		DBInstanceID:  testName,
+		AccountType:   v1alpha1.AccountTypeNormal,
+		Privileges:    readOnly,
+	}
+	if diff := cmp.Diff(wantStatus, cr.Status.AtProvider); diff != "" {
+		t.Errorf("e.Observe(...): -want status, +got status:\n%s", diff)
+	}
+}
+
+func TestRDSAccountCreate(t *testing.T) {
+	type want struct {
+		created  string
+		pw       string
+		terminal bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		params v1alpha1.RDSAccountParameters
+		want   want
+	}{
+		"NoInstance": {
+			reason: "Accounts without an instance should not be created",
+			want:   want{terminal: true},
+		},
+		"SecretPassword": {
+			reason: "Accounts should be created with the password read from their Secret",
+			params: v1alpha1.RDSAccountParameters{DBInstanceID: testName, AccountType: v1alpha1.AccountTypeSuper, PasswordSecretRef: passwordRef},
+			want:   want{created: testAccount + ":pw:" + v1alpha1.AccountTypeSuper, pw: "pw"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &fakeRDSClient{}
+			cre, err := createRDSAccount(context.Background(), &accountClient{Client: c, kube: secrets("pw", "")}, rdsAccount(tc.params, v1alpha1.RDSAccountObservation{}))
+			if diff := cmp.Diff(tc.want.terminal, clients.IsTerminalError(err)); diff != "" {
+				t.Errorf("\n%s\ncreateRDSAccount(...): -want terminal error, +got terminal error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.created, c.createdAccount); diff != "" {
+				t.Errorf("\n%s\ncreateRDSAccount(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if err == nil {
+				if diff := cmp.Diff(tc.want.pw, string(cre.ConnectionDetails[xpv1.ResourceCredentialsSecretPasswordKey])); diff != "" {
+					t.Errorf("\n%s\ncreateRDSAccount(...): -want password, +got password:\n%s\n", tc.reason, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestRDSAccountCreateGeneratesPassword(t *testing.T) {
+	c := &fakeRDSClient{}
+	cre, err := createRDSAccount(context.Background(), &accountClient{Client: c}, rdsAccount(v1alpha1.RDSAccountParameters{DBInstanceID: testName}, v1alpha1.RDSAccountObservation{}))
+	if err != nil {
+		t.Fatalf("createRDSAccount(...): %s", err)
+	}
+	pw := string(cre.ConnectionDetails[xpv1.ResourceCredentialsSecretPasswordKey])
+	if pw == "" {
+		t.Fatal("createRDSAccount(...): want generated password, got none")
+	}
+	if diff := cmp.Diff(testAccount+":"+pw+":", c.createdAccount); diff != "" {
+		t.Errorf("createRDSAccount(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestRDSAccountUpdate(t *testing.T) {
+	type want struct {
+		reset   string
+		granted []v1alpha1.AccountPrivilege
+		revoked []string
+		cd      managed.ConnectionDetails
+	}
+
+	cases := map[string]struct {
+		reason string
+		params v1alpha1.RDSAccountParameters
+		obs    v1alpha1.RDSAccountObservation
+		want   want
+	}{
+		"Unavailable": {
+			reason: "Accounts should not be updated until they are available",
+			params: v1alpha1.RDSAccountParameters{DBInstanceID: testName, Privileges: readWrite, PasswordSecretRef: passwordRef},
+			obs:    v1alpha1.RDSAccountObservation{AccountStatus: v1alpha1.RDSAccountStateUnavailable, DBInstanceID: testName},
+		},
+		"ResetPassword": {
+			reason: "Accounts whose password Secret changed should be reset to the new password, which should be published",
+			params: v1alpha1.RDSAccountParameters{DBInstanceID: testName, Privileges: readOnly, PasswordSecretRef: passwordRef},
+			obs:    v1alpha1.RDSAccountObservation{AccountStatus: v1alpha1.RDSAccountStateAvailable, DBInstanceID: testName, Privileges: readOnly},
+			want: want{
+				reset: "new",
+				cd:    managed.ConnectionDetails{xpv1.ResourceCredentialsSecretPasswordKey: []byte("new")},
+			},
+		},
+		"ChangePrivileges": {
+			reason: "Changed privileges should be revoked and granted",
+			params: v1alpha1.RDSAccountParameters{DBInstanceID: testName, Privileges: readWrite},
+			obs:    v1alpha1.RDSAccountObservation{AccountStatus: v1alpha1.RDSAccountStateAvailable, DBInstanceID: testName, Privileges: readOnly},
+			want:   want{granted: readWrite, revoked: []string{testDatabase}},
+		},
+		"Super": {
+			reason: "The privileges of Super accounts should be left alone",
+			params: v1alpha1.RDSAccountParameters{DBInstanceID: testName, Privileges: readWrite},
+			obs:    v1alpha1.RDSAccountObservation{AccountStatus: v1alpha1.RDSAccountStateAvailable, DBInstanceID: testName, AccountType: v1alpha1.AccountTypeSuper},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &fakeRDSClient{}
+			cr := rdsAccount(tc.params, tc.obs)
+			cr.SetWriteConnectionSecretToReference(connectionRef)
+			u, err := updateRDSAccount(context.Background(), &accountClient{Client: c, kube: secrets("new", "old")}, cr)
+			if err != nil {
+				t.Fatalf("\n%s\nupdateRDSAccount(...): %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.reset, c.resetPassword); diff != "" {
+				t.Errorf("\n%s\nupdateRDSAccount(...): -want reset password, +got reset password:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.granted, c.granted); diff != "" {
+				t.Errorf("\n%s\nupdateRDSAccount(...): -want granted, +got granted:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.revoked, c.revoked); diff != "" {
+				t.Errorf("\n%s\nupdateRDSAccount(...): -want revoked, +got revoked:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cd, u.ConnectionDetails); diff != "" {
+				t.Errorf("\n%s\nupdateRDSAccount(...): -want connection details, +got connection details:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRDSAccountDelete(t *testing.T) {
+	c := &fakeRDSClient{}
+	cr := rdsAccount(v1alpha1.RDSAccountParameters{DBInstanceID: testName}, v1alpha1.RDSAccountObservation{})
+	if err := deleteRDSAccount(context.Background(), c, cr); err != nil {
+		t.Fatalf("deleteRDSAccount(...): %s", err)
+	}
+	if diff := cmp.Diff(testAccount, c.deletedAccount); diff != "" {
+		t.Errorf("deleteRDSAccount(...): -want, +got:\n%s", diff)
+	}
+}
+
+func (c *fakeRDSClient) DescribeAccount(ctx context.Context, id, name string) (*rds.Account, error) {
+	if id != testName || name != testAccount {
+		return nil, rds.ErrAccountNotFound
+	}
+	return &rds.Account{
+		Name:         name,
+		DBInstanceID: id,
+		Status:       v1alpha1.RDSAccountStateAvailable,
+		Type:         v1alpha1.AccountTypeNormal,
+		Privileges:   readOnly,
+		Endpoint:     &v1alpha1.Endpoint{Address: "test.pg.rds.aliyuncs.com", Port: "5432"},
+	}, nil
+}
+
+func (c *fakeRDSClient) CreateAccount(ctx context.Context, id, user, pw string, p *v1alpha1.RDSAccountParameters) error {
+	if id != testName {
+		return errors.New("CreateAccount: client doesn't work")
+	}
+	c.createdAccount = user + ":" + pw + ":"
+	if p != nil {
+		c.createdAccount += p.AccountType
+	}
+	return nil
+}
+
+func (c *fakeRDSClient) GrantAccountPrivilege(ctx context.Context, id, name string, privileges []v1alpha1.AccountPrivilege) error {
+	if id != testName {
+		return errors.New("GrantAccountPrivilege: client doesn't work")
+	}
+	c.granted = privileges
+	return nil
+}
+
+func (c *fakeRDSClient) RevokeAccountPrivilege(ctx context.Context, id, name string, databases []string) error {
+	if id != testName {
+		return errors.New("RevokeAccountPrivilege: client doesn't work")
+	}
+	c.revoked = databases
+	return nil
+}
+
+func (c *fakeRDSClient) ResetAccountPassword(ctx context.Context, id, name, pw string) error {
+	if id != testName {
+		return errors.New("ResetAccountPassword: client doesn't work")
+	}
+	c.resetPassword = pw
+	return nil
+}
+
+func (c *fakeRDSClient) DeleteAccount(ctx context.Context, id, name string) error {
+	if id != testName {
+		return errors.New("DeleteAccount: client doesn't work")
+	}
+	c.deletedAccount = name
+	return nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/clients/rds"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
+)
+
+const (
+	errCreateDatabaseFailed   = "cannot create RDS database"
+	errDescribeDatabaseFailed = "cannot describe RDS database"
+	errModifyDatabaseFailed   = "cannot modify RDS database description"
+	errDeleteDatabaseFailed   = "cannot delete RDS database"
+	errNoInstance             = "dbInstanceId, dbInstanceIdRef or dbInstanceIdSelector is required"
+
+	// databaseKey is the connection details key of the name of a database.
+	databaseKey = "database"
+)
+
+// SetupRDSDatabase adds a controller that reconciles RDSDatabases.
+func SetupRDSDatabase(mgr ctrl.Manager, l logging.Logger, o adapter.Options) error {
+	return adapter.Setup(mgr, l, rdsDatabaseKind, o)
+}
+
+var rdsDatabaseKind = adapter.Kind{
+	Type:              &v1alpha1.RDSDatabase{},
+	GroupVersionKind:  v1alpha1.RDSDatabaseGroupVersionKind,
+	NewClient:         newRDSClient,
+	Describe:          describeRDSDatabase,
+	IsNotFound:        rds.IsDatabaseNotFound,
+	Observe:           observeRDSDatabase,
+	IsUpToDate:        isRDSDatabaseUpToDate,
+	Condition:         getRDSDatabaseCondition,
+	ConnectionDetails: getRDSDatabaseConnectionDetails,
+	Create:            createRDSDatabase,
+	Update:            updateRDSDatabase,
+	Delete:            deleteRDSDatabase,
+}
+
+func describeRDSDatabase(ctx context.Context, c interface{}, mg resource.Managed) (interface{}, error) {
+	cr := mg.(*v1alpha1.RDSDatabase)
+	id := cr.Spec.ForProvider.DBInstanceID
+	if id == "" || meta.GetExternalName(cr) == "" {
+		return nil, adapter.ErrNotFound
+	}
+	db, err := c.(rds.Client).DescribeDatabase(ctx, id, meta.GetExternalName(cr))
+	return db, errors.Wrap(err, errDescribeDatabaseFailed)
+}
+
+func observeRDSDatabase(mg resource.Managed, observed interface{}) {
+	mg.(*v1alpha1.RDSDatabase).Status.AtProvider = rds.GenerateDatabaseObservation(observed.(*rds.Database))
+}
+
+func isRDSDatabaseUpToDate(mg resource.Managed, observed interface{}) bool {
+	return rds.IsDatabaseUpToDate(&mg.(*v1alpha1.RDSDatabase).Spec.ForProvider, observed.(*rds.Database))
+}
+
+func getRDSDatabaseCondition(mg resource.Managed, _ interface{}) xpv1.Condition {
+	switch mg.(*v1alpha1.RDSDatabase).Status.AtProvider.DBStatus {
+	case v1alpha1.RDSDatabaseStateRunning:
+		return xpv1.Available()
+	case v1alpha1.RDSDatabaseStateCreating:
+		return xpv1.Creating()
+	case v1alpha1.RDSDatabaseStateDeleting:
+		return xpv1.Deleting()
+	default:
+		return xpv1.Unavailable()
+	}
+}
+
+// getRDSDatabaseConnectionDetails returns the endpoints of the instance of
+// the database along with its name.
+func getRDSDatabaseConnectionDetails(_ context.Context, _ interface{}, _ resource.Managed, observed interface{}) (managed.ConnectionDetails, error) {
+	db := observed.(*rds.Database)
+	cd := managed.ConnectionDetails{databaseKey: []byte(db.Name)}
+	addEndpointConnectionDetails(cd, db.Endpoint, db.PublicEndpoint)
+	return cd, nil
+}
+
+func createRDSDatabase(ctx context.Context, c interface{}, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr := mg.(*v1alpha1.RDSDatabase)
+	id := cr.Spec.ForProvider.DBInstanceID
+	if id == "" {
+		return managed.ExternalCreation{}, clients.NewTerminalError(errNoInstance)
+	}
+	err := c.(rds.Client).CreateDatabase(ctx, id, meta.GetExternalName(cr), &cr.Spec.ForProvider)
+	return managed.ExternalCreation{}, errors.Wrap(err, errCreateDatabaseFailed)
+}
+
+func updateRDSDatabase(ctx context.Context, c interface{}, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr := mg.(*v1alpha1.RDSDatabase)
+	if cr.Status.AtProvider.DBStatus != v1alpha1.RDSDatabaseStateRunning {
+		return managed.ExternalUpdate{}, nil
+	}
+	err := c.(rds.Client).ModifyDatabaseDescription(ctx, cr.Spec.ForProvider.DBInstanceID, meta.GetExternalName(cr), cr.Spec.ForProvider.Description)
+	return managed.ExternalUpdate{}, errors.Wrap(err, errModifyDatabaseFailed)
+}
+
+func deleteRDSDatabase(ctx context.Context, c interface{}, mg resource.Managed) error {
+	cr := mg.(*v1alpha1.RDSDatabase)
+	if cr.Status.AtProvider.DBStatus == v1alpha1.RDSDatabaseStateDeleting {
+		return nil
+	}
+	err := c.(rds.Client).DeleteDatabase(ctx, cr.Spec.ForProvider.DBInstanceID, meta.GetExternalName(cr))
+	return errors.Wrap(err, errDeleteDatabaseFailed)
+}
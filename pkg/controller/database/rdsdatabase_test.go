@@ -0,0 +1,262 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/clients/rds"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
+)
+
+const testDatabase = "app"
+
+func rdsDatabase(p v1alpha1.RDSDatabaseParameters, o v1alpha1.RDSDatabaseObservation) *v1alpha1.RDSDatabase {
+	cr := &v1alpha1.RDSDatabase{
+		Spec:   v1alpha1.RDSDatabaseSpec{ForProvider: p},
+		Status: v1alpha1.RDSDatabaseStatus{AtProvider: o},
+	}
+	cr.SetName(testDatabase)
+	meta.SetExternalName(cr, testDatabase)
+	return cr
+}
+
+func TestRDSDatabaseResolveReferences(t *testing.T) {
+	type want struct {
+		id  string
+		ref *xpv1.Reference
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		params v1alpha1.RDSDatabaseParameters
+		want   want
+	}{
+		"InstanceID": {
+			reason: "Instance IDs that are set should not be resolved",
+			params: v1alpha1.RDSDatabaseParameters{DBInstanceID: testName},
+			want:   want{id: testName},
+		},
+		"Reference": {
+			reason: "Referenced RDSInstances should be resolved to their instance ID",
+			kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+				obj.(*v1alpha1.RDSInstance).Status.AtProvider.DBInstanceID = testName
+				return nil
+			})},
+			params: v1alpha1.RDSDatabaseParameters{DBInstanceIDRef: &xpv1.Reference{Name: "instance"}},
+			want:   want{id: testName, ref: &xpv1.Reference{Name: "instance"}},
+		},
+		"Selector": {
+			reason: "Selected RDSInstances should be referenced and resolved to their instance ID",
+			kube: &test.MockClient{MockList: test.NewMockListFn(nil, func(obj runtime.Object) error {
+				instance := v1alpha1.RDSInstance{}
+				instance.SetName("instance")
+				instance.Status.AtProvider.DBInstanceID = testName
+				obj.(*v1alpha1.RDSInstanceList).Items = []v1alpha1.RDSInstance{instance}
+				return nil
+			})},
+			params: v1alpha1.RDSDatabaseParameters{DBInstanceIDSelector: &xpv1.Selector{MatchLabels: map[string]string{"app": "cool"}}},
+			want:   want{id: testName, ref: &xpv1.Reference{Name: "instance"}},
+		},
+		"GetFailed": {
+			reason: "Errors getting the referenced RDSInstance should be returned",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			params: v1alpha1.RDSDatabaseParameters{DBInstanceIDRef: &xpv1.Reference{Name: "instance"}},
+			want:   want{ref: &xpv1.Reference{Name: "instance"}, err: errors.Wrap(errors.Wrap(errBoom, "cannot get referenced resource"), "mg.Spec.ForProvider.DBInstanceID")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := rdsDatabase(tc.params, v1alpha1.RDSDatabaseObservation{})
+			err := cr.ResolveReferences(context.Background(), tc.kube)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ncr.ResolveReferences(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.id, cr.Spec.ForProvider.DBInstanceID); diff != "" {
+				t.Errorf("\n%s\ncr.ResolveReferences(...): -want instance ID, +got instance ID:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ref, cr.Spec.ForProvider.DBInstanceIDRef); diff != "" {
+				t.Errorf("\n%s\ncr.ResolveReferences(...): -want reference, +got reference:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRDSDatabaseObserve(t *testing.T) {
+	e := adapter.NewExternalClient(rdsDatabaseKind, nil, &fakeRDSClient{})
+	cr := rdsDatabase(v1alpha1.RDSDatabaseParameters{DBInstanceID: testName, CharacterSetName: "UTF8", Description: "cool"}, v1alpha1.RDSDatabaseObservation{})
+	o, err := e.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("e.Observe(...): %s", err)
+	}
+
+	want := managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: false,
+		ConnectionDetails: managed.ConnectionDetails{
+			databaseKey: []byte(testDatabase),
+			xpv1.ResourceCredentialsSecretEndpointKey: []byte("test.pg.rds.aliyuncs.com"),
+			xpv1.ResourceCredentialsSecretPortKey:     []byte("5432"),
+		},
+	}
+	if diff := cmp.Diff(want, o); diff != "" {
+		t.Errorf("e.Observe(...): -want, +got:\n%s", diff)
+	}
+	wantStatus := v1alpha1.RDSDatabaseObservation{DBStatus: v1alpha1.RDSDatabaseStateRunning, DBInstanceID: testName, Engine: v1alpha1.PostgresqlEngine, CharacterSetName: "UTF8"}
+	if diff := cmp.Diff(wantStatus, cr.Status.AtProvider); diff != "" {
+		t.Errorf("e.Observe(...): -want status, +got status:\n%s", diff)
+	}
+}
+
+func TestRDSDatabaseCreate(t *testing.T) {
+	type want struct {
+		created  string
+		terminal bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		params v1alpha1.RDSDatabaseParameters
+		want   want
+	}{
+		"NoInstance": {
+			reason: "Databases without an instance should not be created",
+			want:   want{terminal: true},
+		},
+		"Create": {
+			reason: "Databases should be created in their instance",
+			params: v1alpha1.RDSDatabaseParameters{DBInstanceID: testName, CharacterSetName: "UTF8"},
+			want:   want{created: testDatabase + ":UTF8"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &fakeRDSClient{}
+			_, err := createRDSDatabase(context.Background(), c, rdsDatabase(tc.params, v1alpha1.RDSDatabaseObservation{}))
+			if diff := cmp.Diff(tc.want.terminal, clients.IsTerminalError(err)); diff != "" {
+				t.Errorf("\n%s\ncreateRDSDatabase(...): -want terminal error, +got terminal error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.created, c.createdDatabase); diff != "" {
+				t.Errorf("\n%s\ncreateRDSDatabase(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRDSDatabaseUpdateAndDelete(t *testing.T) {
+	c := &fakeRDSClient{}
+	cr := rdsDatabase(
+		v1alpha1.RDSDatabaseParameters{DBInstanceID: testName, Description: "cool"},
+		v1alpha1.RDSDatabaseObservation{DBStatus: v1alpha1.RDSDatabaseStateRunning, DBInstanceID: testName},
+	)
+	if _, err := updateRDSDatabase(context.Background(), c, cr); err != nil {
+		t.Fatalf("updateRDSDatabase(...): %s", err)
+	}
+	if diff := cmp.Diff(testDatabase+":cool", c.modifiedDatabase); diff != "" {
+		t.Errorf("updateRDSDatabase(...): -want, +got:\n%s", diff)
+	}
+
+	if err := deleteRDSDatabase(context.Background(), c, cr); err != nil {
+		t.Fatalf("deleteRDSDatabase(...): %s", err)
+	}
+	if diff := cmp.Diff(testDatabase, c.deletedDatabase); diff != "" {
+		t.Errorf("deleteRDSDatabase(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestGetRDSInstanceDependents(t *testing.T) {
+	kube := &test.MockClient{MockList: test.NewMockListFn(nil, func(obj runtime.Object) error {
+		switch l := obj.(type) {
+		case *v1alpha1.RDSDatabaseList, *v1alpha1.RDSAccountList:
+			t.Errorf("getRDSInstanceDependents(...): databases and accounts are deleted along with the instance and should not be listed")
+		case *v1alpha1.RDSReadOnlyInstanceList:
+			byRef := rdsReadOnlyInstance(v1alpha1.RDSReadOnlyInstanceParameters{PrimaryDBInstanceIDRef: &xpv1.Reference{Name: "instance"}}, v1alpha1.RDSReadOnlyInstanceObservation{})
+			other := rdsReadOnlyInstance(v1alpha1.RDSReadOnlyInstanceParameters{PrimaryDBInstanceID: "other"}, v1alpha1.RDSReadOnlyInstanceObservation{})
+			other.SetName("other")
+			l.Items = []v1alpha1.RDSReadOnlyInstance{*byRef, *other}
+		}
+		return nil
+	})}
+	cr := &v1alpha1.RDSInstance{Status: v1alpha1.RDSInstanceStatus{AtProvider: v1alpha1.RDSInstanceObservation{DBInstanceID: testName}}}
+	cr.SetName("instance")
+
+	dependents, err := getRDSInstanceDependents(context.Background(), kube, cr)
+	if err != nil {
+		t.Fatalf("getRDSInstanceDependents(...): %s", err)
+	}
+	var got []string
+	for _, d := range dependents {
+		got = append(got, d.String())
+	}
+	if diff := cmp.Diff([]string{"RDSReadOnlyInstance/" + testReplica}, got); diff != "" {
+		t.Errorf("getRDSInstanceDependents(...): -want, +got:\n%s", diff)
+	}
+}
+
+func (c *fakeRDSClient) DescribeDatabase(ctx context.Context, id, name string) (*rds.Database, error) {
+	if id != testName || name != testDatabase {
+		return nil, rds.ErrDatabaseNotFound
+	}
+	return &rds.Database{
+		Name:             name,
+		DBInstanceID:     id,
+		Engine:           v1alpha1.PostgresqlEngine,
+		Status:           v1alpha1.RDSDatabaseStateRunning,
+		CharacterSetName: "UTF8",
+		Endpoint:         &v1alpha1.Endpoint{Address: "test.pg.rds.aliyuncs.com", Port: "5432"},
+	}, nil
+}
+
+func (c *fakeRDSClient) CreateDatabase(ctx context.Context, id, name string, p *v1alpha1.RDSDatabaseParameters) error {
+	if id != testName {
+		return errors.New("CreateDatabase: client doesn't work")
+	}
+	c.createdDatabase = name + ":" + p.CharacterSetName
+	return nil
+}
+
+func (c *fakeRDSClient) ModifyDatabaseDescription(ctx context.Context, id, name, description string) error {
+	if id != testName {
+		return errors.New("ModifyDatabaseDescription: client doesn't work")
+	}
+	c.modifiedDatabase = name + ":" + description
+	return nil
+}
+
+func (c *fakeRDSClient) DeleteDatabase(ctx context.Context, id, name string) error {
+	if id != testName {
+		return errors.New("DeleteDatabase: client doesn't work")
+	}
+	c.deletedDatabase = name
+	return nil
+}
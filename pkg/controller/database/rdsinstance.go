@@ -18,10 +18,10 @@ package database
 
 import (
 	"context"
+	"fmt"
 
 	sdkerror "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
-	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/password"
@@ -35,160 +35,331 @@ import (
 
 	"github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
 	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/clients/bss"
 	"github.com/crossplane/provider-alibaba/pkg/clients/rds"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
 	"github.com/crossplane/provider-alibaba/pkg/util"
 )
 
 const (
-	errNotRDSInstance = "managed resource is not an RDS instance custom resource"
-
-	errNoProvider          = "no provider config or provider specified"
-	errCreateRDSClient     = "cannot create RDS client"
-	errGetProvider         = "cannot get provider"
-	errGetProviderConfig   = "cannot get provider config"
-	errTrackUsage          = "cannot track provider config usage"
-	errNoConnectionSecret  = "no connection secret specified"
-	errGetConnectionSecret = "cannot get connection secret"
-
-	errCreateFailed        = "cannot create RDS instance"
-	errCreateAccountFailed = "cannot create RDS database account"
-	errDeleteFailed        = "cannot delete RDS instance"
-	errDescribeFailed      = "cannot describe RDS instance"
-
-	errFmtUnsupportedCredSource = "credentials source %q is not currently supported"
+	errCreateFailed         = "cannot create RDS instance"
+	errCreateAccountFailed  = "cannot create RDS database account"
+	errDeleteFailed         = "cannot delete RDS instance"
+	errReleaseSourceFailed  = "cannot release RDS instance replaced by its upgraded clone"
+	errResizeFailed         = "cannot resize RDS instance"
+	errUpgradeFailed        = "cannot upgrade RDS instance"
+	errPrecheckFailed       = "cannot pre-check upgrade of RDS instance"
+	errModifyIPsFailed      = "cannot modify RDS instance IP whitelist"
+	errAllocatePublicFailed = "cannot allocate RDS instance public endpoint"
+	errReleasePublicFailed  = "cannot release RDS instance public endpoint"
+	errTransformFailed      = "cannot convert RDS instance to subscription"
+	errAutoRenewFailed      = "cannot modify RDS instance auto-renewal"
+	errDescribeBackupFailed = "cannot describe RDS instance backup policy"
+	errModifyBackupFailed   = "cannot modify RDS instance backup policy"
+	errCrossBackupFailed    = "cannot modify RDS instance cross-region backup"
+	errDescribeParamsFailed = "cannot describe RDS instance parameters"
+	errTemplatesFailed      = "cannot describe RDS instance parameter templates"
+	errModifyParamsFailed   = "cannot modify RDS instance parameters"
+	errRestartFailed        = "cannot restart RDS instance"
+	errNoVPC                = "vpcId and vSwitchId are required to create VPC instances"
+	errDescribeFailed       = "cannot describe RDS instance"
+	errListFailed           = "cannot list RDS instances"
+
+	errDescribeAvailableClasses = "cannot describe available RDS instance classes"
+
+	fmtInstanceClass = "RDS %s %s instance class"
+
+	errFmtStorageIncrement = "dbInstanceStorageInGB %d is not a multiple of %d"
+	fmtInstanceProgress    = "instance is %s with class %s and %d GB of storage"
+
+	errFmtDowngrade            = "cannot downgrade engine version from %s to %s"
+	errFmtUpgradeNotAllowed    = "upgrading engine version from %s to %s makes the instance unavailable for a while; set allowMajorVersionUpgrade to upgrade it"
+	errFmtUpgradePrecheckFails = "pre-check of upgrade to engine version %s failed: %s"
+	errFmtUpgradePrepaid       = "cannot upgrade Prepaid PostgreSQL instance %s, which is upgraded by cloning it and could not be released once its clone took over"
+	errFmtPayType              = "cannot convert %s instance to %s"
+	errFmtDeletePrepaid        = "cannot delete Prepaid instance %s, which is released once its subscription expires; disable autoRenew to let it expire, or set deletionPolicy to Orphan to delete the resource only"
+	errFmtGetConfigMap         = "cannot get ConfigMap %s/%s"
+	errFmtConfigMapKey         = "ConfigMap %s/%s has no key %q"
+	errFmtUnknownParameter     = "parameter %s cannot be modified for %s %s"
+
+	// Connection details keys of the public endpoint of an instance.
+	publicEndpointKey = "publicEndpoint"
+	publicPortKey     = "publicPort"
 )
 
 // SetupRDSInstance adds a controller that reconciles RDSInstances.
-func SetupRDSInstance(mgr ctrl.Manager, l logging.Logger) error {
-	name := managed.ControllerName(v1alpha1.RDSInstanceGroupKind)
-
-	return ctrl.NewControllerManagedBy(mgr).
-		Named(name).
-		For(&v1alpha1.RDSInstance{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(v1alpha1.RDSInstanceGroupVersionKind),
-			managed.WithExternalConnecter(&connector{
-				client:       mgr.GetClient(),
-				usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &aliv1alpha1.ProviderConfigUsage{}),
-				newRDSClient: rds.NewClient,
-			}),
-			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
-}
-
-type connector struct {
-	client       client.Client
-	usage        resource.Tracker
-	newRDSClient func(ctx context.Context, accessKeyID, accessKeySecret, securityToken, region string) (rds.Client, error)
-}
-
-func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) { //nolint:gocyclo
-	// TODO(negz): This connect method will be simpler once we no longer have to
-	// account for the deprecated Provider type.
-	cr, ok := mg.(*v1alpha1.RDSInstance)
-	if !ok {
-		return nil, errors.New(errNotRDSInstance)
-	}
-
-	// TODO(negz): This connection logic should be generalised once this
-	// provider has more than one kind of managed resource.
-	var (
-		sel    *xpv1.SecretKeySelector
-		region string
-	)
-	switch {
-	case cr.GetProviderConfigReference() != nil:
-		if err := c.usage.Track(ctx, mg); err != nil {
-			return nil, errors.Wrap(err, errTrackUsage)
-		}
+func SetupRDSInstance(mgr ctrl.Manager, l logging.Logger, o adapter.Options) error {
+	k := rdsInstanceKind
+	k.NewClient = newRDSInstanceClient(mgr.GetClient())
+	return adapter.Setup(mgr, l, k, o)
+}
 
-		pc := &aliv1alpha1.ProviderConfig{}
-		if err := c.client.Get(ctx, types.NamespacedName{Name: cr.Spec.ProviderConfigReference.Name}, pc); err != nil {
-			return nil, errors.Wrap(err, errGetProviderConfig)
-		}
-		if s := pc.Spec.Credentials.Source; s != xpv1.CredentialsSourceSecret {
-			return nil, errors.Errorf(errFmtUnsupportedCredSource, s)
-		}
-		sel = pc.Spec.Credentials.SecretRef
-		region = pc.Spec.Region
-	case cr.GetProviderReference() != nil:
-		p := &aliv1alpha1.Provider{}
-		if err := c.client.Get(ctx, types.NamespacedName{Name: cr.Spec.ProviderReference.Name}, p); err != nil {
-			return nil, errors.Wrap(err, errGetProvider)
-		}
-		sel = p.Spec.CredentialsSecretRef
-		region = p.Spec.Region
-	default:
-		return nil, errors.New(errNoProvider)
-	}
+// RDSInstanceKind returns the adapter.Kind of RDSInstances.
+func RDSInstanceKind() adapter.Kind {
+	return rdsInstanceKind
+}
 
-	if sel == nil {
-		return nil, errors.New(errNoConnectionSecret)
-	}
+// rdsInstanceKind is the adapter.Kind of RDSInstances. Its NewClient returns
+// the RDS clients used to discover instances; SetupRDSInstance replaces it,
+// since the clients that reconcile instances also read ConfigMaps.
+var rdsInstanceKind = adapter.Kind{
+	Type:              &v1alpha1.RDSInstance{},
+	GroupVersionKind:  v1alpha1.RDSInstanceGroupVersionKind,
+	NewClient:         newRDSClient,
+	Describe:          describeRDSInstance,
+	IsNotFound:        rds.IsErrorNotFound,
+	Observe:           observeRDSInstance,
+	IsUpToDate:        isRDSInstanceUpToDate,
+	Condition:         getRDSInstanceCondition,
+	ConnectionDetails: getRDSInstanceConnectionDetails,
+	Create:            createRDSInstance,
+	Update:            updateRDSInstance,
+	Delete:            deleteRDSInstance,
+	Dependents:        getRDSInstanceDependents,
+	Discover:          discoverRDSInstances,
+	Parameters:        rdsInstanceParameters,
+	CloudID: func(mg resource.Managed) string {
+		return mg.(*v1alpha1.RDSInstance).Status.AtProvider.DBInstanceID
+	},
+	Preflight: &adapter.Preflight{
+		Check:            checkRDSInstanceAvailable,
+		QuotaProductCode: rds.QuotaProductCode,
+	},
+	Pricing: &adapter.Pricing{
+		Request: func(mg resource.Managed, region string) bss.PriceRequest {
+			return rds.MakePriceRequest(&mg.(*v1alpha1.RDSInstance).Spec.ForProvider, region)
+		},
+		Estimate: func(mg resource.Managed) *aliv1alpha1.CostEstimate {
+			return mg.(*v1alpha1.RDSInstance).Status.AtProvider.CostEstimate
+		},
+		SetEstimate: func(mg resource.Managed, e *aliv1alpha1.CostEstimate) {
+			mg.(*v1alpha1.RDSInstance).Status.AtProvider.CostEstimate = e
+		},
+	},
+	Operations: &adapter.Operations{
+		Pending: func(mg resource.Managed) *aliv1alpha1.PendingOperation {
+			return mg.(*v1alpha1.RDSInstance).Status.AtProvider.PendingOperation
+		},
+		SetPending: func(mg resource.Managed, op *aliv1alpha1.PendingOperation) {
+			mg.(*v1alpha1.RDSInstance).Status.AtProvider.PendingOperation = op
+		},
+		InProgress: func(observed interface{}) bool {
+			return observed.(*observedInstance).Status != v1alpha1.RDSInstanceStateRunning
+		},
+		Progress: func(_ resource.Managed, observed interface{}) string {
+			db := observed.(*observedInstance)
+			return fmt.Sprintf(fmtInstanceProgress, db.Status, db.DBInstanceClass, db.DBInstanceStorageInGB)
+		},
+	},
+}
 
-	s := &corev1.Secret{}
-	nn := types.NamespacedName{Namespace: sel.Namespace, Name: sel.Name}
-	if err := c.client.Get(ctx, nn, s); err != nil {
-		return nil, errors.Wrap(err, errGetConnectionSecret)
+func newRDSClient(ctx context.Context, _ resource.Managed, creds util.Credentials) (interface{}, error) {
+	return rds.NewClient(ctx, creds.AccessKeyID, creds.AccessKeySecret, creds.SecurityToken, creds.Region)
+}
+
+// An instanceClient is the RDS client of RDSInstances. It also reads the
+// ConfigMaps that list IPs of their whitelist groups.
+type instanceClient struct {
+	rds.Client
+	kube client.Reader
+}
+
+// newRDSInstanceClient returns a function that returns the instanceClient of
+// an RDSInstance, which reads ConfigMaps using the supplied client.
+func newRDSInstanceClient(kube client.Reader) func(ctx context.Context, mg resource.Managed, creds util.Credentials) (interface{}, error) {
+	return func(ctx context.Context, mg resource.Managed, creds util.Credentials) (interface{}, error) {
+		c, err := newRDSClient(ctx, mg, creds)
+		if err != nil {
+			return nil, err
+		}
+		return &instanceClient{Client: c.(rds.Client), kube: kube}, nil
 	}
+}
 
-	rdsClient, err := c.newRDSClient(ctx, string(s.Data[util.AccessKeyID]), string(s.Data[util.AccessKeySecret]), string(s.Data[util.SecurityToken]), region)
-	return &external{client: rdsClient}, errors.Wrap(err, errCreateRDSClient)
+// ipsFrom returns the IPs the whitelist groups of the supplied RDSInstance
+// read from ConfigMaps, by group name.
+func (c *instanceClient) ipsFrom(ctx context.Context, cr *v1alpha1.RDSInstance) (map[string][]string, error) {
+	ips := map[string][]string{}
+	for _, g := range cr.Spec.ForProvider.SecurityIPGroups {
+		for _, sel := range g.IPsFrom {
+			cm := &corev1.ConfigMap{}
+			if err := c.kube.Get(ctx, types.NamespacedName{Namespace: sel.Namespace, Name: sel.Name}, cm); err != nil {
+				return nil, errors.Wrapf(err, errFmtGetConfigMap, sel.Namespace, sel.Name)
+			}
+			v, ok := cm.Data[sel.Key]
+			if !ok {
+				return nil, errors.Errorf(errFmtConfigMapKey, sel.Namespace, sel.Name, sel.Key)
+			}
+			ips[g.Name] = append(ips[g.Name], rds.SplitIPs(v)...)
+		}
+	}
+	return ips, nil
 }
 
-type external struct {
-	client rds.Client
+// An observedInstance is an observed RDS instance along with the IPs its
+// whitelist groups read from ConfigMaps when it was observed.
+type observedInstance struct {
+	*rds.DBInstance
+	ipsFrom map[string][]string
 }
 
-func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
-	cr, ok := mg.(*v1alpha1.RDSInstance)
-	if !ok {
-		return managed.ExternalObservation{}, errors.New(errNotRDSInstance)
+func describeRDSInstance(ctx context.Context, c interface{}, mg resource.Managed) (interface{}, error) {
+	cr := mg.(*v1alpha1.RDSInstance)
+	// Imported instances are identified by their external name until the
+	// instance ID has been observed.
+	id := cr.Status.AtProvider.DBInstanceID
+	if id == "" {
+		id = meta.GetExternalName(cr)
+	}
+	if id == "" {
+		return nil, adapter.ErrNotFound
+	}
+	client := c.(*instanceClient)
+	ips, err := client.ipsFrom(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	if err := observeEngineUpgrade(ctx, client, cr); err != nil {
+		return nil, err
+	}
+	// Instances upgraded by cloning them are followed by their clone once it
+	// runs the target version.
+	if u := cr.Status.AtProvider.EngineUpgrade; u != nil && u.TargetDBInstanceID != "" {
+		target, err := client.DescribeDBInstance(ctx, u.TargetDBInstanceID)
+		if err == nil && target.Status == v1alpha1.RDSInstanceStateRunning && rds.SameEngineVersion(target.EngineVersion, u.TargetEngineVersion) {
+			return &observedInstance{DBInstance: target, ipsFrom: ips}, describeSettings(ctx, client, cr, target)
+		}
+	}
+	instance, err := client.DescribeDBInstance(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, errDescribeFailed)
 	}
+	return &observedInstance{DBInstance: instance, ipsFrom: ips}, describeSettings(ctx, client, cr, instance)
+}
 
-	if cr.Status.AtProvider.DBInstanceID == "" {
-		return managed.ExternalObservation{}, nil
+// describeSettings describes the backup policy and the engine parameters of
+// the supplied instance while it is running, if the spec of the supplied
+// RDSInstance sets them.
+func describeSettings(ctx context.Context, client rds.Client, cr *v1alpha1.RDSInstance, instance *rds.DBInstance) error {
+	if instance.Status != v1alpha1.RDSInstanceStateRunning {
+		return nil
 	}
+	p := cr.Spec.ForProvider
+	var err error
+	if bp := p.BackupPolicy; bp != nil {
+		if instance.BackupPolicy, err = client.DescribeBackupPolicy(ctx, instance.ID, bp.CrossRegionBackup != nil); err != nil {
+			return errors.Wrap(err, errDescribeBackupFailed)
+		}
+	}
+	if len(p.Parameters) != 0 {
+		instance.Parameters, instance.PendingRestartParameters, err = client.DescribeParameters(ctx, instance.ID, rds.ParameterNames(p.Parameters))
+		return errors.Wrap(err, errDescribeParamsFailed)
+	}
+	return nil
+}
 
-	instance, err := e.client.DescribeDBInstance(cr.Status.AtProvider.DBInstanceID)
+// observeEngineUpgrade records the result of the pre-check of the engine
+// upgrade of the supplied RDSInstance once it completed.
+func observeEngineUpgrade(ctx context.Context, client rds.Client, cr *v1alpha1.RDSInstance) error {
+	u := cr.Status.AtProvider.EngineUpgrade
+	if u == nil || u.State != v1alpha1.EngineUpgradePrechecking {
+		return nil
+	}
+	pc, err := client.DescribeMajorVersionUpgradePrecheck(ctx, cr.Status.AtProvider.DBInstanceID, u.PrecheckTaskID)
 	if err != nil {
-		return managed.ExternalObservation{}, errors.Wrap(resource.Ignore(rds.IsErrorNotFound, err), errDescribeFailed)
+		return errors.Wrap(err, errPrecheckFailed)
+	}
+	switch pc.Result {
+	case rds.PrecheckSuccess:
+		u.State = v1alpha1.EngineUpgradePrecheckPassed
+	case rds.PrecheckFail:
+		u.State = v1alpha1.EngineUpgradePrecheckFailed
+		u.Message = pc.Detail
 	}
+	return nil
+}
 
-	cr.Status.AtProvider = rds.GenerateObservation(instance)
+func observeRDSInstance(mg resource.Managed, observed interface{}) {
+	cr := mg.(*v1alpha1.RDSInstance)
+	db := observed.(*observedInstance).DBInstance
+	u := cr.Status.AtProvider.EngineUpgrade
+	cr.Status.AtProvider = rds.GenerateObservation(db)
+	// Upgrades by cloning are remembered until their source is released once
+	// the clone took over. Other upgrades are forgotten once the instance runs
+	// their target version, or once the spec no longer asks for it.
+	switch {
+	case u != nil && u.SourceDBInstanceID != "" && u.TargetDBInstanceID == db.ID:
+		u.State = v1alpha1.EngineUpgradeReleasingSource
+		cr.Status.AtProvider.EngineUpgrade = u
+	case u != nil && !rds.SameEngineVersion(u.TargetEngineVersion, db.EngineVersion) &&
+		rds.SameEngineVersion(u.TargetEngineVersion, cr.Spec.ForProvider.EngineVersion):
+		cr.Status.AtProvider.EngineUpgrade = u
+	}
+}
 
-	var pw string
-	switch cr.Status.AtProvider.DBInstanceStatus {
-	case v1alpha1.RDSInstanceStateRunning:
-		cr.Status.SetConditions(xpv1.Available())
-		pw, err = e.createAccountIfNeeded(cr)
-		if err != nil {
-			return managed.ExternalObservation{}, errors.Wrap(err, errCreateAccountFailed)
+func isRDSInstanceUpToDate(mg resource.Managed, observed interface{}) bool {
+	cr := mg.(*v1alpha1.RDSInstance)
+	// There is nothing to request until the pre-check of an upgrade completes,
+	// while the source of an upgrade by cloning has to be released.
+	if u := cr.Status.AtProvider.EngineUpgrade; u != nil {
+		switch u.State {
+		case v1alpha1.EngineUpgradePrechecking:
+			return true
+		case v1alpha1.EngineUpgradeReleasingSource:
+			return false
 		}
+	}
+	o := observed.(*observedInstance)
+	return rds.IsUpToDate(&cr.Spec.ForProvider, o.ipsFrom, o.DBInstance)
+}
+
+func getRDSInstanceCondition(mg resource.Managed, _ interface{}) xpv1.Condition {
+	return instanceCondition(mg.(*v1alpha1.RDSInstance).Status.AtProvider.DBInstanceStatus)
+}
+
+// instanceCondition returns the condition of an instance in the supplied
+// state. Instances stay available while their class changes.
+func instanceCondition(state string) xpv1.Condition {
+	switch state {
+	case v1alpha1.RDSInstanceStateRunning, v1alpha1.RDSInstanceStateClassChanging:
+		return xpv1.Available()
 	case v1alpha1.RDSInstanceStateCreating:
-		cr.Status.SetConditions(xpv1.Creating())
+		return xpv1.Creating()
 	case v1alpha1.RDSInstanceStateDeleting:
-		cr.Status.SetConditions(xpv1.Deleting())
+		return xpv1.Deleting()
 	default:
-		cr.Status.SetConditions(xpv1.Unavailable())
+		return xpv1.Unavailable()
 	}
+}
 
-	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  true,
-		ConnectionDetails: getConnectionDetails(pw, cr, instance),
-	}, nil
+// getRDSInstanceConnectionDetails creates the master account once the
+// instance is running, and returns its password along with the endpoint.
+func getRDSInstanceConnectionDetails(ctx context.Context, c interface{}, mg resource.Managed, observed interface{}) (managed.ConnectionDetails, error) {
+	cr := mg.(*v1alpha1.RDSInstance)
+	var pw string
+	if cr.Status.AtProvider.DBInstanceStatus == v1alpha1.RDSInstanceStateRunning {
+		var err error
+		if pw, err = createAccountIfNeeded(ctx, c.(rds.Client), cr); err != nil {
+			return nil, errors.Wrap(err, errCreateAccountFailed)
+		}
+	}
+	return getConnectionDetails(pw, cr, observed.(*observedInstance).DBInstance), nil
 }
 
-func (e *external) createAccountIfNeeded(cr *v1alpha1.RDSInstance) (string, error) {
-	if cr.Status.AtProvider.AccountReady {
+// createAccountIfNeeded creates the master account of the supplied
+// RDSInstance and returns its password, unless it has been created already.
+// Instances without a master username, e.g. discovered ones, get no master
+// account.
+func createAccountIfNeeded(ctx context.Context, client rds.Client, cr *v1alpha1.RDSInstance) (string, error) {
+	if cr.Status.AtProvider.AccountReady || cr.Spec.ForProvider.MasterUsername == "" {
 		return "", nil
 	}
 	pw, err := password.Generate()
 	if err != nil {
 		return "", err
 	}
-	err = e.client.CreateAccount(cr.Status.AtProvider.DBInstanceID, cr.Spec.ForProvider.MasterUsername, pw)
+	err = client.CreateAccount(ctx, cr.Status.AtProvider.DBInstanceID, cr.Spec.ForProvider.MasterUsername, pw, nil)
 	if err != nil {
 		// The previous request might fail due to timeout. That's fine we will eventually reconcile it.
 		if sdkErr, ok := err.(sdkerror.Error); ok {
@@ -203,46 +374,323 @@ func (e *external) createAccountIfNeeded(cr *v1alpha1.RDSInstance) (string, erro
 	return pw, nil
 }
 
-func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
-	cr, ok := mg.(*v1alpha1.RDSInstance)
-	if !ok {
-		return managed.ExternalCreation{}, errors.New(errNotRDSInstance)
+// checkRDSInstanceAvailable returns a terminal error if the supplied
+// RDSInstance is a VPC instance without a VPC, or if its instance class is not
+// available in its zone, or in any zone of the region if it has none.
+func checkRDSInstanceAvailable(ctx context.Context, c interface{}, mg resource.Managed, region string) error {
+	p := mg.(*v1alpha1.RDSInstance).Spec.ForProvider
+	if rds.NetworkType(&p) == v1alpha1.InstanceNetworkTypeVPC && (p.VPCID == "" || p.VSwitchID == "") {
+		return clients.NewTerminalError(errNoVPC)
 	}
+	a, err := c.(rds.Client).DescribeAvailableClasses(ctx, p.Engine, p.EngineVersion, rds.PayType(&p))
+	if err != nil {
+		return errors.Wrap(err, errDescribeAvailableClasses)
+	}
+	return a.Check(fmt.Sprintf(fmtInstanceClass, p.Engine, p.EngineVersion), p.DBInstanceClass, region, p.ZoneID)
+}
 
-	cr.SetConditions(xpv1.Creating())
+func createRDSInstance(ctx context.Context, c interface{}, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr := mg.(*v1alpha1.RDSInstance)
 	if cr.Status.AtProvider.DBInstanceStatus == v1alpha1.RDSInstanceStateCreating {
 		return managed.ExternalCreation{}, nil
 	}
 
-	req := rds.MakeCreateDBInstanceRequest(meta.GetExternalName(cr), &cr.Spec.ForProvider)
-	instance, err := e.client.CreateDBInstance(req)
+	ips, err := c.(*instanceClient).ipsFrom(ctx, cr)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	req := rds.MakeCreateDBInstanceRequest(meta.GetExternalName(cr), &cr.Spec.ForProvider, ips)
+	instance, err := c.(rds.Client).CreateDBInstance(ctx, req)
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreateFailed)
 	}
 
 	// The crossplane runtime will send status update back to apiserver.
 	cr.Status.AtProvider.DBInstanceID = instance.ID
+	adapter.SetTaskID(ctx, instance.OrderID)
 
 	// Any connection details emitted in ExternalClient are cumulative.
 	return managed.ExternalCreation{ConnectionDetails: getConnectionDetails("", cr, instance)}, nil
 }
 
-func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	return managed.ExternalUpdate{}, nil
+// updateRDSInstance upgrades the instance to the engine version of its spec,
+// or converts it to the pay type of its spec, or syncs its whitelist groups,
+// public endpoint, auto-renewal, backup policy and engine parameters and
+// resizes it to the class and storage of its spec. Instances are only changed
+// while running; updates of instances that are not skip their operation, so
+// that changes made while another change is in progress are applied once it
+// completes.
+func updateRDSInstance(ctx context.Context, c interface{}, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr := mg.(*v1alpha1.RDSInstance)
+	if cr.Status.AtProvider.DBInstanceStatus != v1alpha1.RDSInstanceStateRunning {
+		adapter.SkipOperation(ctx)
+		return managed.ExternalUpdate{}, nil
+	}
+	p := cr.Spec.ForProvider
+	if p.DBInstanceStorageInGB%rds.StorageIncrementGB != 0 {
+		return managed.ExternalUpdate{}, clients.NewTerminalErrorf(errFmtStorageIncrement, p.DBInstanceStorageInGB, rds.StorageIncrementGB)
+	}
+	if u := cr.Status.AtProvider.EngineUpgrade; u != nil && u.State == v1alpha1.EngineUpgradeReleasingSource {
+		return managed.ExternalUpdate{}, releaseUpgradeSource(ctx, c.(rds.Client), cr)
+	}
+	if !rds.SameEngineVersion(p.EngineVersion, cr.Status.AtProvider.EngineVersion) {
+		return managed.ExternalUpdate{}, upgradeRDSInstance(ctx, c.(rds.Client), cr)
+	}
+	if p.PayType != "" && p.PayType != cr.Status.AtProvider.PayType {
+		return managed.ExternalUpdate{}, convertRDSInstance(ctx, c.(rds.Client), cr)
+	}
+	ips, err := c.(*instanceClient).ipsFrom(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	for _, g := range rds.MakeModifySecurityIPsRequests(&p, ips, &cr.Status.AtProvider) {
+		if err := c.(rds.Client).ModifySecurityIPs(ctx, cr.Status.AtProvider.DBInstanceID, g); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errModifyIPsFailed)
+		}
+	}
+	if err := syncPublicConnection(ctx, c.(rds.Client), cr); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	if !rds.IsBillingUpToDate(&p, &cr.Status.AtProvider) {
+		if err := c.(rds.Client).ModifyAutoRenewal(ctx, cr.Status.AtProvider.DBInstanceID, rds.MakeSubscription(&p)); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errAutoRenewFailed)
+		}
+	}
+	if err := syncBackupPolicy(ctx, c.(rds.Client), cr); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	if err := syncParameters(ctx, c.(rds.Client), cr); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	req := rds.MakeModifyDBInstanceSpecRequest(&p, &cr.Status.AtProvider)
+	if req == nil {
+		return managed.ExternalUpdate{}, nil
+	}
+	err = c.(rds.Client).ModifyDBInstanceSpec(ctx, cr.Status.AtProvider.DBInstanceID, req)
+	return managed.ExternalUpdate{}, errors.Wrap(err, errResizeFailed)
+}
+
+// syncPublicConnection allocates or releases the public endpoint of the
+// supplied RDSInstance as its spec asks. Public endpoints listen on the port
+// of the intranet endpoint.
+func syncPublicConnection(ctx context.Context, client rds.Client, cr *v1alpha1.RDSInstance) error {
+	p := cr.Spec.ForProvider
+	o := cr.Status.AtProvider
+	if rds.IsPublicConnectionUpToDate(&p, o.PublicEndpoint) {
+		return nil
+	}
+	if *p.PubliclyAccessible {
+		port := rds.DefaultPort(p.Engine)
+		if o.Endpoint != nil && o.Endpoint.Port != "" {
+			port = o.Endpoint.Port
+		}
+		return errors.Wrap(client.AllocatePublicConnection(ctx, o.DBInstanceID, port), errAllocatePublicFailed)
+	}
+	return errors.Wrap(client.ReleasePublicConnection(ctx, o.DBInstanceID, o.PublicEndpoint.Address), errReleasePublicFailed)
 }
 
-func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
-	cr, ok := mg.(*v1alpha1.RDSInstance)
-	if !ok {
-		return errors.New(errNotRDSInstance)
+// syncBackupPolicy changes the backup policy of the supplied RDSInstance,
+// including its cross-region backup, to that of its spec.
+func syncBackupPolicy(ctx context.Context, client rds.Client, cr *v1alpha1.RDSInstance) error {
+	p, o := cr.Spec.ForProvider.BackupPolicy, cr.Status.AtProvider.BackupPolicy
+	id := cr.Status.AtProvider.DBInstanceID
+	if !rds.IsBackupPolicyUpToDate(p, o) {
+		if err := client.ModifyBackupPolicy(ctx, id, p); err != nil {
+			return errors.Wrap(err, errModifyBackupFailed)
+		}
+	}
+	if !rds.IsCrossRegionBackupUpToDate(p, o) {
+		if err := client.ModifyCrossRegionBackup(ctx, id, p.CrossRegionBackup); err != nil {
+			return errors.Wrap(err, errCrossBackupFailed)
+		}
+	}
+	return nil
+}
+
+// syncParameters changes the engine parameters of the supplied RDSInstance to
+// those of its spec. Changes that only take effect once the instance restarts
+// restart it if its spec allows, as do such changes that are still pending.
+func syncParameters(ctx context.Context, client rds.Client, cr *v1alpha1.RDSInstance) error {
+	p, o := cr.Spec.ForProvider, cr.Status.AtProvider
+	changed := rds.DiffParameters(p.Parameters, o.Parameters)
+	if len(changed) == 0 {
+		if p.AllowRestart && len(o.PendingRestartParameters) != 0 {
+			return errors.Wrap(client.RestartDBInstance(ctx, o.DBInstanceID), errRestartFailed)
+		}
+		return nil
+	}
+	restart, err := client.DescribeParameterTemplates(ctx, p.Engine, o.EngineVersion)
+	if err != nil {
+		return errors.Wrap(err, errTemplatesFailed)
+	}
+	forceRestart := false
+	for _, name := range rds.ParameterNames(changed) {
+		r, ok := restart[name]
+		if !ok {
+			return clients.NewTerminalErrorf(errFmtUnknownParameter, name, p.Engine, o.EngineVersion)
+		}
+		forceRestart = forceRestart || r
+	}
+	return errors.Wrap(client.ModifyParameters(ctx, o.DBInstanceID, changed, p.AllowRestart && forceRestart), errModifyParamsFailed)
+}
+
+// convertRDSInstance converts the supplied Postpaid RDSInstance to a
+// subscription. Prepaid instances cannot be converted back.
+func convertRDSInstance(ctx context.Context, client rds.Client, cr *v1alpha1.RDSInstance) error {
+	p := cr.Spec.ForProvider
+	o := cr.Status.AtProvider
+	if p.PayType != v1alpha1.PayTypePrepaid {
+		return clients.NewTerminalErrorf(errFmtPayType, o.PayType, p.PayType)
+	}
+	order, err := client.TransformDBInstancePayType(ctx, o.DBInstanceID, rds.MakeSubscription(&p))
+	if err != nil {
+		return errors.Wrap(err, errTransformFailed)
+	}
+	adapter.SetTaskID(ctx, order)
+	return nil
+}
+
+// releaseUpgradeSource releases the instance that the supplied RDSInstance was
+// upgraded from by cloning it, once the clone took over.
+func releaseUpgradeSource(ctx context.Context, client rds.Client, cr *v1alpha1.RDSInstance) error {
+	u := cr.Status.AtProvider.EngineUpgrade
+	if err := client.DeleteDBInstance(ctx, u.SourceDBInstanceID); err != nil && !rds.IsErrorNotFound(err) {
+		return errors.Wrap(err, errReleaseSourceFailed)
+	}
+	cr.Status.AtProvider.EngineUpgrade = nil
+	return nil
+}
+
+// upgradeRDSInstance upgrades the engine version of the supplied RDSInstance
+// to that of its spec, if allowed. MySQL instances are upgraded in place.
+// PostgreSQL instances are upgraded by cloning them to a new instance of the
+// target version once the pre-check of the upgrade passed; the upgraded
+// instance is released once the clone took over, so Prepaid PostgreSQL
+// instances are not upgraded.
+func upgradeRDSInstance(ctx context.Context, client rds.Client, cr *v1alpha1.RDSInstance) error {
+	p := cr.Spec.ForProvider
+	o := &cr.Status.AtProvider
+	cmp, err := rds.CompareEngineVersions(p.EngineVersion, o.EngineVersion)
+	switch {
+	case err != nil:
+		return clients.Terminal(err)
+	case cmp < 0:
+		return clients.NewTerminalErrorf(errFmtDowngrade, o.EngineVersion, p.EngineVersion)
+	case !p.AllowMajorVersionUpgrade:
+		return clients.NewTerminalErrorf(errFmtUpgradeNotAllowed, o.EngineVersion, p.EngineVersion)
+	}
+
+	u := o.EngineUpgrade
+	if u != nil && !rds.SameEngineVersion(u.TargetEngineVersion, p.EngineVersion) {
+		u = nil
 	}
-	cr.SetConditions(xpv1.Deleting())
+	switch {
+	case u != nil && u.State == v1alpha1.EngineUpgradeUpgrading:
+		return nil
+	case p.Engine != v1alpha1.PostgresqlEngine:
+		if err := client.UpgradeDBInstanceEngineVersion(ctx, o.DBInstanceID, p.EngineVersion); err != nil {
+			return errors.Wrap(err, errUpgradeFailed)
+		}
+		o.EngineUpgrade = &v1alpha1.EngineUpgrade{TargetEngineVersion: p.EngineVersion, State: v1alpha1.EngineUpgradeUpgrading}
+	case o.PayType == v1alpha1.PayTypePrepaid:
+		return clients.NewTerminalErrorf(errFmtUpgradePrepaid, o.DBInstanceID)
+	case u == nil:
+		id, err := client.PrecheckMajorVersionUpgrade(ctx, o.DBInstanceID, p.EngineVersion)
+		if err != nil {
+			return errors.Wrap(err, errPrecheckFailed)
+		}
+		o.EngineUpgrade = &v1alpha1.EngineUpgrade{TargetEngineVersion: p.EngineVersion, State: v1alpha1.EngineUpgradePrechecking, PrecheckTaskID: id}
+	case u.State == v1alpha1.EngineUpgradePrecheckFailed:
+		return clients.NewTerminalErrorf(errFmtUpgradePrecheckFails, u.TargetEngineVersion, u.Message)
+	case u.State == v1alpha1.EngineUpgradePrecheckPassed:
+		target, err := client.UpgradeDBInstanceMajorVersion(ctx, o.DBInstanceID, &rds.UpgradeMajorVersionRequest{
+			TargetMajorVersion:    p.EngineVersion,
+			DBInstanceClass:       p.DBInstanceClass,
+			DBInstanceStorageInGB: p.DBInstanceStorageInGB,
+			ZoneID:                o.ZoneID,
+			VPCID:                 o.VPCID,
+			VSwitchID:             o.VSwitchID,
+			PayType:               o.PayType,
+			Subscription:          rds.MakeSubscription(&p),
+		})
+		if err != nil {
+			return errors.Wrap(err, errUpgradeFailed)
+		}
+		u.State = v1alpha1.EngineUpgradeUpgrading
+		u.TargetDBInstanceID = target.ID
+		u.SourceDBInstanceID = o.DBInstanceID
+		adapter.SetTaskID(ctx, target.OrderID)
+	}
+	return nil
+}
+
+func deleteRDSInstance(ctx context.Context, c interface{}, mg resource.Managed) error {
+	cr := mg.(*v1alpha1.RDSInstance)
 	if cr.Status.AtProvider.DBInstanceStatus == v1alpha1.RDSInstanceStateDeleting {
 		return nil
 	}
+	// Subscriptions cannot be released before they expire.
+	if cr.Status.AtProvider.PayType == v1alpha1.PayTypePrepaid {
+		return clients.NewTerminalErrorf(errFmtDeletePrepaid, cr.Status.AtProvider.DBInstanceID)
+	}
+	if u := cr.Status.AtProvider.EngineUpgrade; u != nil && u.State == v1alpha1.EngineUpgradeReleasingSource {
+		if err := releaseUpgradeSource(ctx, c.(rds.Client), cr); err != nil {
+			return err
+		}
+	}
 
-	err := e.client.DeleteDBInstance(cr.Status.AtProvider.DBInstanceID)
-	return errors.Wrap(resource.Ignore(rds.IsErrorNotFound, err), errDeleteFailed)
+	err := c.(rds.Client).DeleteDBInstance(ctx, cr.Status.AtProvider.DBInstanceID)
+	return errors.Wrap(err, errDeleteFailed)
+}
+
+// getRDSInstanceDependents lists the RDSReadOnlyInstances of the supplied
+// instance, which have to be deleted before it. RDSDatabases and RDSAccounts
+// are deleted along with the instance, so they do not block its deletion.
+func getRDSInstanceDependents(ctx context.Context, kube client.Reader, mg resource.Managed) ([]util.Dependent, error) {
+	cr := mg.(*v1alpha1.RDSInstance)
+	replicas := &v1alpha1.RDSReadOnlyInstanceList{}
+	if err := kube.List(ctx, replicas); err != nil {
+		return nil, err
+	}
+	var dependents []util.Dependent
+	for i := range replicas.Items {
+		if isOfRDSInstance(cr, replicas.Items[i].Spec.ForProvider.PrimaryDBInstanceIDRef, replicas.Items[i].Spec.ForProvider.PrimaryDBInstanceID) {
+			dependents = append(dependents, util.Dependent{Kind: v1alpha1.RDSReadOnlyInstanceKind, Managed: &replicas.Items[i]})
+		}
+	}
+	return dependents, nil
+}
+
+// isOfRDSInstance returns true if a resource with the supplied reference and
+// instance ID belongs to the supplied RDSInstance.
+func isOfRDSInstance(cr *v1alpha1.RDSInstance, ref *xpv1.Reference, id string) bool {
+	if ref != nil && ref.Name == cr.GetName() {
+		return true
+	}
+	return id != "" && id == cr.Status.AtProvider.DBInstanceID
+}
+
+func rdsInstanceParameters(observed interface{}) interface{} {
+	return rds.GenerateParameters(observed.(*observedInstance).DBInstance)
+}
+
+func discoverRDSInstances(ctx context.Context, c interface{}, f adapter.Filter) ([]resource.Managed, error) {
+	instances, err := c.(rds.Client).ListDBInstances(ctx, f.Tags)
+	if err != nil {
+		return nil, errors.Wrap(err, errListFailed)
+	}
+	var mgs []resource.Managed
+	for i := range instances {
+		if !f.MatchesName(instances[i].Description) {
+			continue
+		}
+		cr := &v1alpha1.RDSInstance{}
+		cr.SetName(instances[i].Description)
+		meta.SetExternalName(cr, instances[i].ID)
+		cr.Spec.ForProvider = rds.GenerateParameters(&instances[i])
+		mgs = append(mgs, cr)
+	}
+	return mgs, nil
 }
 
 func getConnectionDetails(password string, cr *v1alpha1.RDSInstance, instance *rds.DBInstance) managed.ConnectionDetails {
@@ -254,10 +702,19 @@ func getConnectionDetails(password string, cr *v1alpha1.RDSInstance, instance *r
 		cd[xpv1.ResourceCredentialsSecretPasswordKey] = []byte(password)
 	}
 
-	if instance.Endpoint != nil {
-		cd[xpv1.ResourceCredentialsSecretEndpointKey] = []byte(instance.Endpoint.Address)
-		cd[xpv1.ResourceCredentialsSecretPortKey] = []byte(instance.Endpoint.Port)
-	}
-
+	addEndpointConnectionDetails(cd, instance.Endpoint, instance.PublicEndpoint)
 	return cd
 }
+
+// addEndpointConnectionDetails adds the supplied intranet and public endpoints
+// of an instance to the supplied connection details, unless they are nil.
+func addEndpointConnectionDetails(cd managed.ConnectionDetails, endpoint, public *v1alpha1.Endpoint) {
+	if endpoint != nil {
+		cd[xpv1.ResourceCredentialsSecretEndpointKey] = []byte(endpoint.Address)
+		cd[xpv1.ResourceCredentialsSecretPortKey] = []byte(endpoint.Port)
+	}
+	if public != nil {
+		cd[publicEndpointKey] = []byte(public.Address)
+		cd[publicPortKey] = []byte(public.Port)
+	}
+}
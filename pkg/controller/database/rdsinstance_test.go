@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -14,254 +15,28 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	crossplanemeta "github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
-	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
-	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
 	"github.com/crossplane/provider-alibaba/pkg/clients/rds"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
 )
 
-const testName = "test"
-
-func TestConnector(t *testing.T) {
-	errBoom := errors.New("boom")
-
-	type fields struct {
-		client       client.Client
-		usage        resource.Tracker
-		newRDSClient func(ctx context.Context, accessKeyID, accessKeySecret, securityToken, region string) (rds.Client, error)
-	}
-
-	type args struct {
-		ctx context.Context
-		mg  resource.Managed
-	}
+const (
+	testName  = "test"
+	testClone = "clone"
+)
 
-	cases := map[string]struct {
-		reason string
-		fields fields
-		args   args
-		want   error
-	}{
-		"NotRDSInstance": {
-			reason: "Should return an error if the supplied managed resource is not an RDSInstance",
-			args: args{
-				mg: nil,
-			},
-			want: errors.New(errNotRDSInstance),
-		},
-		"TrackProviderConfigUsageError": {
-			reason: "Errors tracking a ProviderConfigUsage should be returned",
-			fields: fields{
-				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return errBoom }),
-			},
-			args: args{
-				mg: &v1alpha1.RDSInstance{
-					Spec: v1alpha1.RDSInstanceSpec{
-						ResourceSpec: xpv1.ResourceSpec{
-							ProviderConfigReference: &xpv1.Reference{},
-						},
-					},
-				},
-			},
-			want: errors.Wrap(errBoom, errTrackUsage),
-		},
-		"GetProviderConfigError": {
-			reason: "Errors getting a ProviderConfig should be returned",
-			fields: fields{
-				client: &test.MockClient{
-					MockGet: test.NewMockGetFn(errBoom),
-				},
-				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
-			},
-			args: args{
-				mg: &v1alpha1.RDSInstance{
-					Spec: v1alpha1.RDSInstanceSpec{
-						ResourceSpec: xpv1.ResourceSpec{
-							ProviderConfigReference: &xpv1.Reference{},
-						},
-					},
-				},
-			},
-			want: errors.Wrap(errBoom, errGetProviderConfig),
-		},
-		"UnsupportedCredentialsError": {
-			reason: "An error should be returned if the selected credentials source is unsupported",
-			fields: fields{
-				client: &test.MockClient{
-					MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
-						t := obj.(*aliv1alpha1.ProviderConfig)
-						*t = aliv1alpha1.ProviderConfig{
-							Spec: aliv1alpha1.ProviderConfigSpec{
-								ProviderConfigSpec: xpv1.ProviderConfigSpec{
-									Credentials: xpv1.ProviderCredentials{
-										Source: xpv1.CredentialsSource("wat"),
-									},
-								},
-							},
-						}
-						return nil
-					}),
-				},
-				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
-			},
-			args: args{
-				mg: &v1alpha1.RDSInstance{
-					Spec: v1alpha1.RDSInstanceSpec{
-						ResourceSpec: xpv1.ResourceSpec{
-							ProviderConfigReference: &xpv1.Reference{},
-						},
-					},
-				},
-			},
-			want: errors.Errorf(errFmtUnsupportedCredSource, "wat"),
-		},
-		"GetProviderError": {
-			reason: "Errors getting a Provider should be returned",
-			fields: fields{
-				client: &test.MockClient{
-					MockGet: test.NewMockGetFn(errBoom),
-				},
-				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
-			},
-			args: args{
-				mg: &v1alpha1.RDSInstance{
-					Spec: v1alpha1.RDSInstanceSpec{
-						ResourceSpec: xpv1.ResourceSpec{
-							ProviderReference: &xpv1.Reference{},
-						},
-					},
-				},
-			},
-			want: errors.Wrap(errBoom, errGetProvider),
-		},
-		"NoConnectionSecretError": {
-			reason: "An error should be returned if no connection secret was specified",
-			fields: fields{
-				client: &test.MockClient{
-					MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
-						t := obj.(*aliv1alpha1.ProviderConfig)
-						*t = aliv1alpha1.ProviderConfig{
-							Spec: aliv1alpha1.ProviderConfigSpec{
-								ProviderConfigSpec: xpv1.ProviderConfigSpec{
-									Credentials: xpv1.ProviderCredentials{
-										Source: xpv1.CredentialsSourceSecret,
-									},
-								},
-							},
-						}
-						return nil
-					}),
-				},
-				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
-			},
-			args: args{
-				mg: &v1alpha1.RDSInstance{
-					Spec: v1alpha1.RDSInstanceSpec{
-						ResourceSpec: xpv1.ResourceSpec{
-							ProviderConfigReference: &xpv1.Reference{},
-						},
-					},
-				},
-			},
-			want: errors.New(errNoConnectionSecret),
-		},
-		"GetConnectionSecretError": {
-			reason: "Errors getting a secret should be returned",
-			fields: fields{
-				client: &test.MockClient{
-					MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
-						switch t := obj.(type) {
-						case *corev1.Secret:
-							return errBoom
-						case *aliv1alpha1.ProviderConfig:
-							*t = aliv1alpha1.ProviderConfig{
-								Spec: aliv1alpha1.ProviderConfigSpec{
-									ProviderConfigSpec: xpv1.ProviderConfigSpec{
-										Credentials: xpv1.ProviderCredentials{
-											Source: xpv1.CredentialsSourceSecret,
-											SecretRef: &xpv1.SecretKeySelector{
-												SecretReference: xpv1.SecretReference{
-													Name: "coolsecret",
-												},
-											},
-										},
-									},
-								},
-							}
-						}
-						return nil
-					}),
-				},
-				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
-			},
-			args: args{
-				mg: &v1alpha1.RDSInstance{
-					Spec: v1alpha1.RDSInstanceSpec{
-						ResourceSpec: xpv1.ResourceSpec{
-							ProviderConfigReference: &xpv1.Reference{},
-						},
-					},
-				},
-			},
-			want: errors.Wrap(errBoom, errGetConnectionSecret),
-		},
-		"NewRDSClientError": {
-			reason: "Errors getting a secret should be returned",
-			fields: fields{
-				client: &test.MockClient{
-					MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
-						if t, ok := obj.(*aliv1alpha1.ProviderConfig); ok {
-							*t = aliv1alpha1.ProviderConfig{
-								Spec: aliv1alpha1.ProviderConfigSpec{
-									ProviderConfigSpec: xpv1.ProviderConfigSpec{
-										Credentials: xpv1.ProviderCredentials{
-											Source: xpv1.CredentialsSourceSecret,
-											SecretRef: &xpv1.SecretKeySelector{
-												SecretReference: xpv1.SecretReference{
-													Name: "coolsecret",
-												},
-											},
-										},
-									},
-								},
-							}
-						}
-						return nil
-					}),
-				},
-				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
-				newRDSClient: func(ctx context.Context, accessKeyID, accessKeySecret, securityToken, region string) (rds.Client, error) {
-					return nil, errBoom
-				},
-			},
-			args: args{
-				mg: &v1alpha1.RDSInstance{
-					Spec: v1alpha1.RDSInstanceSpec{
-						ResourceSpec: xpv1.ResourceSpec{
-							ProviderConfigReference: &xpv1.Reference{},
-						},
-					},
-				},
-			},
-			want: errors.Wrap(errBoom, errCreateRDSClient),
-		},
-	}
+var (
+	errBoom = errors.New("boom")
 
-	for name, tc := range cases {
-		t.Run(name, func(t *testing.T) {
-			c := &connector{client: tc.fields.client, usage: tc.fields.usage, newRDSClient: tc.fields.newRDSClient}
-			_, err := c.Connect(tc.args.ctx, tc.args.mg)
-			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
-				t.Errorf("\n%s\nc.Connect(...) -want error, +got error:\n%s\n", tc.reason, diff)
-			}
-		})
-	}
-}
+	publiclyAccessible    = true
+	notPubliclyAccessible = false
+)
 
 func TestExternalClientObserve(t *testing.T) {
-	e := &external{client: &fakeRDSClient{}}
+	e := adapter.NewExternalClient(rdsInstanceKind, nil, &instanceClient{Client: &fakeRDSClient{}})
 	obj := &v1alpha1.RDSInstance{
 		Spec: v1alpha1.RDSInstanceSpec{
 			ForProvider: v1alpha1.RDSInstanceParameters{
@@ -289,8 +64,60 @@ func TestExternalClientObserve(t *testing.T) {
 	}
 }
 
+func TestCreateAccountIfNeeded(t *testing.T) {
+	type want struct {
+		created string
+		ready   bool
+	}
+
+	cases := map[string]struct {
+		reason   string
+		username string
+		ready    bool
+		want     want
+	}{
+		"Create": {
+			reason:   "The master account should be created if it is not ready",
+			username: testName,
+			want:     want{created: testName, ready: true},
+		},
+		"Ready": {
+			reason:   "The master account should not be created again once it is ready",
+			username: testName,
+			ready:    true,
+			want:     want{ready: true},
+		},
+		"NoMasterUsername": {
+			reason: "Instances without a master username, e.g. discovered ones, should get no master account",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &fakeRDSClient{}
+			cr := &v1alpha1.RDSInstance{
+				Spec:   v1alpha1.RDSInstanceSpec{ForProvider: v1alpha1.RDSInstanceParameters{MasterUsername: tc.username}},
+				Status: v1alpha1.RDSInstanceStatus{AtProvider: v1alpha1.RDSInstanceObservation{DBInstanceID: testName, AccountReady: tc.ready}},
+			}
+			pw, err := createAccountIfNeeded(context.Background(), c, cr)
+			if err != nil {
+				t.Fatalf("\n%s\ncreateAccountIfNeeded(...): %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.created, strings.Split(c.createdAccount, ":")[0]); diff != "" {
+				t.Errorf("\n%s\ncreateAccountIfNeeded(...): -want created account, +got created account:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.created != "", pw != ""); diff != "" {
+				t.Errorf("\n%s\ncreateAccountIfNeeded(...): -want password, +got password:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ready, cr.Status.AtProvider.AccountReady); diff != "" {
+				t.Errorf("\n%s\ncreateAccountIfNeeded(...): -want account ready, +got account ready:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestExternalClientCreate(t *testing.T) {
-	e := &external{client: &fakeRDSClient{}}
+	e := adapter.NewExternalClient(rdsInstanceKind, nil, &instanceClient{Client: &fakeRDSClient{}})
 	obj := &v1alpha1.RDSInstance{
 		ObjectMeta: metav1.ObjectMeta{
 			Annotations: map[string]string{
@@ -305,6 +132,8 @@ func TestExternalClientCreate(t *testing.T) {
 				SecurityIPList:        "0.0.0.0/0",
 				DBInstanceClass:       "rds.pg.s1.small",
 				DBInstanceStorageInGB: 20,
+				VPCID:                 "vpc-test",
+				VSwitchID:             "vsw-test",
 			},
 		},
 	}
@@ -321,8 +150,60 @@ func TestExternalClientCreate(t *testing.T) {
 	}
 }
 
+func TestExternalClientCreateUnavailable(t *testing.T) {
+	e := adapter.NewExternalClient(rdsInstanceKind, nil, &instanceClient{Client: &fakeRDSClient{}})
+	obj := &v1alpha1.RDSInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				crossplanemeta.AnnotationKeyExternalName: testName,
+			},
+		},
+		Spec: v1alpha1.RDSInstanceSpec{
+			ForProvider: v1alpha1.RDSInstanceParameters{
+				MasterUsername:        testName,
+				Engine:                "PostgreSQL",
+				EngineVersion:         "10.0",
+				DBInstanceClass:       "rds.pg.s3.large",
+				DBInstanceStorageInGB: 20,
+				VPCID:                 "vpc-test",
+				VSwitchID:             "vsw-test",
+			},
+		},
+	}
+	_, err := e.Create(context.Background(), obj)
+	if !clients.IsTerminalError(err) {
+		t.Errorf("Create of an unavailable instance class should fail with a terminal error, got %v", err)
+	}
+	if obj.Status.AtProvider.DBInstanceID != "" {
+		t.Error("DBInstanceID should not be set")
+	}
+}
+
+func TestExternalClientCreateNoVPC(t *testing.T) {
+	e := adapter.NewExternalClient(rdsInstanceKind, nil, &instanceClient{Client: &fakeRDSClient{}})
+	obj := &v1alpha1.RDSInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				crossplanemeta.AnnotationKeyExternalName: testName,
+			},
+		},
+		Spec: v1alpha1.RDSInstanceSpec{
+			ForProvider: v1alpha1.RDSInstanceParameters{
+				Engine:                "PostgreSQL",
+				EngineVersion:         "10.0",
+				DBInstanceClass:       "rds.pg.s1.small",
+				DBInstanceStorageInGB: 20,
+			},
+		},
+	}
+	_, err := e.Create(context.Background(), obj)
+	if diff := cmp.Diff(clients.NewTerminalError(errNoVPC), err, test.EquateErrors()); diff != "" {
+		t.Errorf("Create of a VPC instance without a VPC: -want error, +got error:\n%s", diff)
+	}
+}
+
 func TestExternalClientDelete(t *testing.T) {
-	e := &external{client: &fakeRDSClient{}}
+	e := adapter.NewExternalClient(rdsInstanceKind, &test.MockClient{MockList: test.NewMockListFn(nil)}, &instanceClient{Client: &fakeRDSClient{}})
 	obj := &v1alpha1.RDSInstance{
 		Status: v1alpha1.RDSInstanceStatus{
 			AtProvider: v1alpha1.RDSInstanceObservation{
@@ -334,6 +215,727 @@ func TestExternalClientDelete(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+
+	obj.Status.AtProvider.PayType = v1alpha1.PayTypePrepaid
+	if err := e.Delete(context.Background(), obj); !clients.IsTerminalError(err) {
+		t.Errorf("e.Delete(...): want terminal error deleting Prepaid instance, got %v", err)
+	}
+}
+
+func TestExternalClientUpdate(t *testing.T) {
+	type want struct {
+		modified    *rds.ModifyDBInstanceSpecRequest
+		ipGroups    []rds.SecurityIPGroup
+		public      string
+		transformed *rds.Subscription
+		autoRenewal *rds.Subscription
+		backup      *v1alpha1.BackupPolicy
+		crossBackup *v1alpha1.CrossRegionBackup
+		parameters  map[string]string
+		restart     bool
+		restarted   bool
+		terminal    bool
+	}
+
+	cases := map[string]struct {
+		reason     string
+		status     string
+		params     v1alpha1.RDSInstanceParameters
+		public     *v1alpha1.Endpoint
+		payType    string
+		backup     *v1alpha1.BackupPolicy
+		parameters map[string]string
+		pending    []string
+		want       want
+	}{
+		"Resize": {
+			reason: "Running instances should be resized to the class and storage of their spec",
+			status: v1alpha1.RDSInstanceStateRunning,
+			params: v1alpha1.RDSInstanceParameters{DBInstanceClass: "rds.pg.s2.large", DBInstanceStorageInGB: 40},
+			want:   want{modified: &rds.ModifyDBInstanceSpecRequest{PayType: v1alpha1.PayTypePostpaid, DBInstanceClass: "rds.pg.s2.large", DBInstanceStorageInGB: 40}},
+		},
+		"ResizeStorage": {
+			reason: "Only the storage of instances whose class is unchanged should be resized",
+			status: v1alpha1.RDSInstanceStateRunning,
+			params: v1alpha1.RDSInstanceParameters{DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 25},
+			want:   want{modified: &rds.ModifyDBInstanceSpecRequest{PayType: v1alpha1.PayTypePostpaid, DBInstanceStorageInGB: 25}},
+		},
+		"ResizePrepaid": {
+			reason:  "Prepaid instances should be resized as subscriptions",
+			status:  v1alpha1.RDSInstanceStateRunning,
+			params:  v1alpha1.RDSInstanceParameters{DBInstanceClass: "rds.pg.s2.large", DBInstanceStorageInGB: 20},
+			payType: v1alpha1.PayTypePrepaid,
+			want:    want{modified: &rds.ModifyDBInstanceSpecRequest{PayType: v1alpha1.PayTypePrepaid, DBInstanceClass: "rds.pg.s2.large"}},
+		},
+		"ClassChanging": {
+			reason: "Instances should not be resized while a resize is in progress",
+			status: v1alpha1.RDSInstanceStateClassChanging,
+			params: v1alpha1.RDSInstanceParameters{DBInstanceClass: "rds.pg.s2.large", DBInstanceStorageInGB: 40},
+		},
+		"SecurityIPs": {
+			reason: "Whitelist groups whose IPs differ from the spec should be modified",
+			status: v1alpha1.RDSInstanceStateRunning,
+			params: v1alpha1.RDSInstanceParameters{
+				DBInstanceClass:       "rds.pg.s1.small",
+				DBInstanceStorageInGB: 20,
+				SecurityIPList:        "10.0.0.1",
+				SecurityIPGroups:      []v1alpha1.SecurityIPGroup{{Name: "nodes", IPsFrom: []v1alpha1.ConfigMapKeySelector{egressIPs}}},
+			},
+			want: want{ipGroups: []rds.SecurityIPGroup{
+				{Name: "default", IPs: []string{"10.0.0.1"}, IPType: "IPv4"},
+				{Name: "nodes", IPs: []string{"10.0.1.1"}, IPType: "IPv4"},
+			}},
+		},
+		"AllocatePublicConnection": {
+			reason: "Instances that should be publicly accessible should get a public endpoint on the port of their intranet endpoint",
+			status: v1alpha1.RDSInstanceStateRunning,
+			params: v1alpha1.RDSInstanceParameters{DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 20, PubliclyAccessible: &publiclyAccessible},
+			want:   want{public: "allocated:5433"},
+		},
+		"ReleasePublicConnection": {
+			reason: "Instances that should not be publicly accessible should release their public endpoint",
+			status: v1alpha1.RDSInstanceStateRunning,
+			params: v1alpha1.RDSInstanceParameters{DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 20, PubliclyAccessible: &notPubliclyAccessible},
+			public: &v1alpha1.Endpoint{Address: "test-public.pg.rds.aliyuncs.com", Port: "5433"},
+			want:   want{public: "released:test-public.pg.rds.aliyuncs.com"},
+		},
+		"KeepPublicConnection": {
+			reason: "Public endpoints should be left alone if the spec does not ask either way",
+			status: v1alpha1.RDSInstanceStateRunning,
+			params: v1alpha1.RDSInstanceParameters{DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 20},
+			public: &v1alpha1.Endpoint{Address: "test-public.pg.rds.aliyuncs.com", Port: "5433"},
+		},
+		"ConvertToPrepaid": {
+			reason:  "Postpaid instances should be converted to subscriptions if their spec asks, before they are resized",
+			status:  v1alpha1.RDSInstanceStateRunning,
+			params:  v1alpha1.RDSInstanceParameters{DBInstanceClass: "rds.pg.s2.large", DBInstanceStorageInGB: 20, PayType: v1alpha1.PayTypePrepaid, Period: v1alpha1.PeriodYear, AutoRenew: true},
+			payType: v1alpha1.PayTypePostpaid,
+			want:    want{transformed: &rds.Subscription{Period: v1alpha1.PeriodYear, UsedTime: 1, AutoRenew: true}},
+		},
+		"ConvertToPostpaid": {
+			reason:  "Prepaid instances should not be converted back to Postpaid",
+			status:  v1alpha1.RDSInstanceStateRunning,
+			params:  v1alpha1.RDSInstanceParameters{DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 20, PayType: v1alpha1.PayTypePostpaid},
+			payType: v1alpha1.PayTypePrepaid,
+			want:    want{terminal: true},
+		},
+		"AutoRenew": {
+			reason:  "The auto-renewal of Prepaid instances should be enabled if their spec asks",
+			status:  v1alpha1.RDSInstanceStateRunning,
+			params:  v1alpha1.RDSInstanceParameters{DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 20, PayType: v1alpha1.PayTypePrepaid, UsedTime: 3, AutoRenew: true},
+			payType: v1alpha1.PayTypePrepaid,
+			want:    want{autoRenewal: &rds.Subscription{Period: v1alpha1.PeriodMonth, UsedTime: 3, AutoRenew: true}},
+		},
+		"BackupPolicy": {
+			reason: "Backup policies that differ from the spec should be modified",
+			status: v1alpha1.RDSInstanceStateRunning,
+			params: v1alpha1.RDSInstanceParameters{DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 20, BackupPolicy: &v1alpha1.BackupPolicy{BackupRetentionPeriod: 30}},
+			backup: &v1alpha1.BackupPolicy{BackupRetentionPeriod: 7, PreferredBackupTime: "02:00Z-03:00Z"},
+			want:   want{backup: &v1alpha1.BackupPolicy{BackupRetentionPeriod: 30}},
+		},
+		"BackupPolicyUpToDate": {
+			reason: "Backup policies that have the fields set in the spec should be left alone",
+			status: v1alpha1.RDSInstanceStateRunning,
+			params: v1alpha1.RDSInstanceParameters{DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 20, BackupPolicy: &v1alpha1.BackupPolicy{BackupRetentionPeriod: 7}},
+			backup: &v1alpha1.BackupPolicy{BackupRetentionPeriod: 7, PreferredBackupTime: "02:00Z-03:00Z"},
+		},
+		"CrossRegionBackup": {
+			reason: "Cross-region backups should be enabled if the spec asks, without modifying the rest of the backup policy",
+			status: v1alpha1.RDSInstanceStateRunning,
+			params: v1alpha1.RDSInstanceParameters{
+				DBInstanceClass:       "rds.pg.s1.small",
+				DBInstanceStorageInGB: 20,
+				BackupPolicy:          &v1alpha1.BackupPolicy{BackupRetentionPeriod: 7, CrossRegionBackup: &v1alpha1.CrossRegionBackup{Region: "cn-shanghai"}},
+			},
+			backup: &v1alpha1.BackupPolicy{BackupRetentionPeriod: 7, CrossRegionBackup: &v1alpha1.CrossRegionBackup{}},
+			want:   want{crossBackup: &v1alpha1.CrossRegionBackup{Region: "cn-shanghai"}},
+		},
+		"Parameters": {
+			reason:     "Parameters that differ from the spec should be modified",
+			status:     v1alpha1.RDSInstanceStateRunning,
+			params:     v1alpha1.RDSInstanceParameters{DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 20, Parameters: map[string]string{"max_connections": "200", "shared_buffers": "128MB"}},
+			parameters: map[string]string{"max_connections": "100", "shared_buffers": "128MB"},
+			want:       want{parameters: map[string]string{"max_connections": "200"}},
+		},
+		"RestartParameters": {
+			reason:     "Parameters that only take effect once the instance restarts should restart it if the spec allows",
+			status:     v1alpha1.RDSInstanceStateRunning,
+			params:     v1alpha1.RDSInstanceParameters{DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 20, Parameters: map[string]string{"shared_buffers": "256MB"}, AllowRestart: true},
+			parameters: map[string]string{"shared_buffers": "128MB"},
+			want:       want{parameters: map[string]string{"shared_buffers": "256MB"}, restart: true},
+		},
+		"RestartNotAllowed": {
+			reason:     "Parameters that only take effect once the instance restarts should not restart it unless the spec allows",
+			status:     v1alpha1.RDSInstanceStateRunning,
+			params:     v1alpha1.RDSInstanceParameters{DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 20, Parameters: map[string]string{"shared_buffers": "256MB"}},
+			parameters: map[string]string{"shared_buffers": "128MB"},
+			want:       want{parameters: map[string]string{"shared_buffers": "256MB"}},
+		},
+		"RestartPending": {
+			reason:     "Instances with parameters pending a restart should be restarted if the spec allows",
+			status:     v1alpha1.RDSInstanceStateRunning,
+			params:     v1alpha1.RDSInstanceParameters{DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 20, Parameters: map[string]string{"shared_buffers": "128MB"}, AllowRestart: true},
+			parameters: map[string]string{"shared_buffers": "128MB"},
+			pending:    []string{"shared_buffers"},
+			want:       want{restarted: true},
+		},
+		"UnknownParameter": {
+			reason:     "Parameters that cannot be modified for the engine of the instance should be rejected",
+			status:     v1alpha1.RDSInstanceStateRunning,
+			params:     v1alpha1.RDSInstanceParameters{DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 20, Parameters: map[string]string{"innodb_buffer_pool_size": "1073741824"}},
+			parameters: map[string]string{},
+			want:       want{terminal: true},
+		},
+		"StorageIncrement": {
+			reason: "Storage that is not sized in 5 GB increments should be rejected before anything else is changed",
+			status: v1alpha1.RDSInstanceStateRunning,
+			params: v1alpha1.RDSInstanceParameters{DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 22, PubliclyAccessible: &publiclyAccessible},
+			want:   want{terminal: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &fakeRDSClient{}
+			obj := &v1alpha1.RDSInstance{
+				Spec: v1alpha1.RDSInstanceSpec{ForProvider: tc.params},
+				Status: v1alpha1.RDSInstanceStatus{
+					AtProvider: v1alpha1.RDSInstanceObservation{
+						DBInstanceID:             testName,
+						DBInstanceStatus:         tc.status,
+						DBInstanceClass:          "rds.pg.s1.small",
+						DBInstanceStorageInGB:    20,
+						Endpoint:                 &v1alpha1.Endpoint{Address: "test.pg.rds.aliyuncs.com", Port: "5433"},
+						PublicEndpoint:           tc.public,
+						PayType:                  tc.payType,
+						BackupPolicy:             tc.backup,
+						Parameters:               tc.parameters,
+						PendingRestartParameters: tc.pending,
+					},
+				},
+			}
+			_, err := updateRDSInstance(context.Background(), &instanceClient{Client: c, kube: configMap("10.0.1.1")}, obj)
+			if diff := cmp.Diff(tc.want.terminal, clients.IsTerminalError(err)); diff != "" {
+				t.Errorf("\n%s\nupdateRDSInstance(...): -want terminal error, +got terminal error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.modified, c.modified); diff != "" {
+				t.Errorf("\n%s\nupdateRDSInstance(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ipGroups, c.ipGroups); diff != "" {
+				t.Errorf("\n%s\nupdateRDSInstance(...): -want whitelist groups, +got whitelist groups:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.public, c.public); diff != "" {
+				t.Errorf("\n%s\nupdateRDSInstance(...): -want public endpoint change, +got public endpoint change:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.transformed, c.transformed); diff != "" {
+				t.Errorf("\n%s\nupdateRDSInstance(...): -want subscription conversion, +got subscription conversion:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.autoRenewal, c.autoRenewal); diff != "" {
+				t.Errorf("\n%s\nupdateRDSInstance(...): -want auto-renewal change, +got auto-renewal change:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.backup, c.backup); diff != "" {
+				t.Errorf("\n%s\nupdateRDSInstance(...): -want backup policy change, +got backup policy change:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.crossBackup, c.crossBackup); diff != "" {
+				t.Errorf("\n%s\nupdateRDSInstance(...): -want cross-region backup change, +got cross-region backup change:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.parameters, c.parameters); diff != "" {
+				t.Errorf("\n%s\nupdateRDSInstance(...): -want parameter changes, +got parameter changes:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.restart, c.forceRestart); diff != "" {
+				t.Errorf("\n%s\nupdateRDSInstance(...): -want forced restart, +got forced restart:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.restarted, c.restarted); diff != "" {
+				t.Errorf("\n%s\nupdateRDSInstance(...): -want restart, +got restart:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestExternalClientUpgrade(t *testing.T) {
+	type calls struct {
+		upgradedTo    string
+		prechecked    string
+		upgradedMajor *rds.UpgradeMajorVersionRequest
+	}
+	type want struct {
+		upgrade  *v1alpha1.EngineUpgrade
+		calls    calls
+		terminal bool
+	}
+
+	cases := map[string]struct {
+		reason  string
+		params  v1alpha1.RDSInstanceParameters
+		version string
+		prepaid bool
+		upgrade *v1alpha1.EngineUpgrade
+		want    want
+	}{
+		"Downgrade": {
+			reason:  "Engine versions should not be downgraded",
+			params:  v1alpha1.RDSInstanceParameters{Engine: v1alpha1.PostgresqlEngine, EngineVersion: "9.4", AllowMajorVersionUpgrade: true},
+			version: "10.0",
+			want:    want{terminal: true},
+		},
+		"NotAllowed": {
+			reason:  "Engine versions should only be upgraded if upgrades are allowed",
+			params:  v1alpha1.RDSInstanceParameters{Engine: v1alpha1.PostgresqlEngine, EngineVersion: "13.0"},
+			version: "10.0",
+			want:    want{terminal: true},
+		},
+		"UpgradeMySQL": {
+			reason:  "MySQL instances should be upgraded in place",
+			params:  v1alpha1.RDSInstanceParameters{Engine: v1alpha1.MysqlEngine, EngineVersion: "8.0", AllowMajorVersionUpgrade: true},
+			version: "5.7",
+			want: want{
+				calls:   calls{upgradedTo: "8.0"},
+				upgrade: &v1alpha1.EngineUpgrade{TargetEngineVersion: "8.0", State: v1alpha1.EngineUpgradeUpgrading},
+			},
+		},
+		"Precheck": {
+			reason:  "The upgrade of PostgreSQL instances should be pre-checked first",
+			params:  v1alpha1.RDSInstanceParameters{Engine: v1alpha1.PostgresqlEngine, EngineVersion: "13.0", AllowMajorVersionUpgrade: true},
+			version: "10.0",
+			want: want{
+				calls:   calls{prechecked: "13.0"},
+				upgrade: &v1alpha1.EngineUpgrade{TargetEngineVersion: "13.0", State: v1alpha1.EngineUpgradePrechecking, PrecheckTaskID: "1"},
+			},
+		},
+		"PrecheckFailed": {
+			reason:  "PostgreSQL instances whose pre-check failed should not be upgraded",
+			params:  v1alpha1.RDSInstanceParameters{Engine: v1alpha1.PostgresqlEngine, EngineVersion: "13.0", AllowMajorVersionUpgrade: true},
+			version: "10.0",
+			upgrade: &v1alpha1.EngineUpgrade{TargetEngineVersion: "13.0", State: v1alpha1.EngineUpgradePrecheckFailed, Message: "extensions"},
+			want: want{
+				terminal: true,
+				upgrade:  &v1alpha1.EngineUpgrade{TargetEngineVersion: "13.0", State: v1alpha1.EngineUpgradePrecheckFailed, Message: "extensions"},
+			},
+		},
+		"RePrecheck": {
+			reason:  "Upgrades to another version should be pre-checked again",
+			params:  v1alpha1.RDSInstanceParameters{Engine: v1alpha1.PostgresqlEngine, EngineVersion: "12.0", AllowMajorVersionUpgrade: true},
+			version: "10.0",
+			upgrade: &v1alpha1.EngineUpgrade{TargetEngineVersion: "13.0", State: v1alpha1.EngineUpgradePrecheckFailed, Message: "extensions"},
+			want: want{
+				calls:   calls{prechecked: "12.0"},
+				upgrade: &v1alpha1.EngineUpgrade{TargetEngineVersion: "12.0", State: v1alpha1.EngineUpgradePrechecking, PrecheckTaskID: "1"},
+			},
+		},
+		"UpgradePostgreSQL": {
+			reason:  "PostgreSQL instances whose pre-check passed should be upgraded by cloning them",
+			params:  v1alpha1.RDSInstanceParameters{Engine: v1alpha1.PostgresqlEngine, EngineVersion: "13.0", AllowMajorVersionUpgrade: true, DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 20},
+			version: "10.0",
+			upgrade: &v1alpha1.EngineUpgrade{TargetEngineVersion: "13.0", State: v1alpha1.EngineUpgradePrecheckPassed},
+			want: want{
+				calls: calls{upgradedMajor: &rds.UpgradeMajorVersionRequest{
+					TargetMajorVersion:    "13.0",
+					DBInstanceClass:       "rds.pg.s1.small",
+					DBInstanceStorageInGB: 20,
+					ZoneID:                "cn-beijing-a",
+					PayType:               v1alpha1.PayTypePostpaid,
+					Subscription:          rds.Subscription{Period: v1alpha1.PeriodMonth, UsedTime: 1},
+				}},
+				upgrade: &v1alpha1.EngineUpgrade{TargetEngineVersion: "13.0", State: v1alpha1.EngineUpgradeUpgrading, TargetDBInstanceID: testClone, SourceDBInstanceID: testName},
+			},
+		},
+		"UpgradePrepaidPostgreSQL": {
+			reason:  "Prepaid PostgreSQL instances should not be upgraded, since they could not be released once their clone took over",
+			params:  v1alpha1.RDSInstanceParameters{Engine: v1alpha1.PostgresqlEngine, EngineVersion: "13.0", AllowMajorVersionUpgrade: true, PayType: v1alpha1.PayTypePrepaid},
+			version: "10.0",
+			prepaid: true,
+			upgrade: &v1alpha1.EngineUpgrade{TargetEngineVersion: "13.0", State: v1alpha1.EngineUpgradePrecheckPassed},
+			want: want{
+				terminal: true,
+				upgrade:  &v1alpha1.EngineUpgrade{TargetEngineVersion: "13.0", State: v1alpha1.EngineUpgradePrecheckPassed},
+			},
+		},
+		"Upgrading": {
+			reason:  "Instances should not be upgraded again while an upgrade is in progress",
+			params:  v1alpha1.RDSInstanceParameters{Engine: v1alpha1.PostgresqlEngine, EngineVersion: "13.0", AllowMajorVersionUpgrade: true},
+			version: "10.0",
+			upgrade: &v1alpha1.EngineUpgrade{TargetEngineVersion: "13.0", State: v1alpha1.EngineUpgradeUpgrading, TargetDBInstanceID: testClone},
+			want: want{
+				upgrade: &v1alpha1.EngineUpgrade{TargetEngineVersion: "13.0", State: v1alpha1.EngineUpgradeUpgrading, TargetDBInstanceID: testClone},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &fakeRDSClient{}
+			payType := v1alpha1.PayTypePostpaid
+			if tc.prepaid {
+				payType = v1alpha1.PayTypePrepaid
+			}
+			obj := &v1alpha1.RDSInstance{
+				Spec: v1alpha1.RDSInstanceSpec{ForProvider: tc.params},
+				Status: v1alpha1.RDSInstanceStatus{
+					AtProvider: v1alpha1.RDSInstanceObservation{
+						DBInstanceID:          testName,
+						DBInstanceStatus:      v1alpha1.RDSInstanceStateRunning,
+						EngineVersion:         tc.version,
+						EngineUpgrade:         tc.upgrade,
+						DBInstanceClass:       "rds.pg.s1.small",
+						DBInstanceStorageInGB: 20,
+						ZoneID:                "cn-beijing-a",
+						PayType:               payType,
+					},
+				},
+			}
+			_, err := updateRDSInstance(context.Background(), &instanceClient{Client: c}, obj)
+			if diff := cmp.Diff(tc.want.terminal, clients.IsTerminalError(err)); diff != "" {
+				t.Errorf("\n%s\nupdateRDSInstance(...): -want terminal error, +got terminal error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.upgrade, obj.Status.AtProvider.EngineUpgrade); diff != "" {
+				t.Errorf("\n%s\nupdateRDSInstance(...): -want upgrade, +got upgrade:\n%s\n", tc.reason, diff)
+			}
+			got := calls{upgradedTo: c.upgradedTo, prechecked: c.prechecked, upgradedMajor: c.upgradedMajor}
+			if diff := cmp.Diff(tc.want.calls, got, cmp.AllowUnexported(calls{})); diff != "" {
+				t.Errorf("\n%s\nupdateRDSInstance(...): -want calls, +got calls:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestExternalClientUpdateNotRunning(t *testing.T) {
+	c := &fakeRDSClient{}
+	e := adapter.NewExternalClient(rdsInstanceKind, nil, &instanceClient{Client: c})
+	obj := &v1alpha1.RDSInstance{
+		Spec: v1alpha1.RDSInstanceSpec{ForProvider: v1alpha1.RDSInstanceParameters{DBInstanceClass: "rds.pg.s2.large", DBInstanceStorageInGB: 40}},
+		Status: v1alpha1.RDSInstanceStatus{
+			AtProvider: v1alpha1.RDSInstanceObservation{
+				DBInstanceID:          testName,
+				DBInstanceStatus:      v1alpha1.RDSInstanceStateClassChanging,
+				DBInstanceClass:       "rds.pg.s1.small",
+				DBInstanceStorageInGB: 20,
+			},
+		},
+	}
+	if _, err := e.Update(context.Background(), obj); err != nil {
+		t.Fatalf("e.Update(...): %s", err)
+	}
+	if c.modified != nil {
+		t.Errorf("e.Update(...): instances that are not running should not be resized, got %+v", c.modified)
+	}
+	if op := obj.Status.AtProvider.PendingOperation; op != nil {
+		t.Errorf("e.Update(...): updates of instances that are not running should not be recorded, got %+v", op)
+	}
+}
+
+// upgradingRDSClient is a fakeRDSClient whose instance runs the engine
+// version it was last upgraded to.
+type upgradingRDSClient struct {
+	*fakeRDSClient
+	instance rds.DBInstance
+}
+
+func (c *upgradingRDSClient) DescribeDBInstance(ctx context.Context, id string) (*rds.DBInstance, error) {
+	db := c.instance
+	// Upgrades complete once they were observed.
+	c.instance.Status = v1alpha1.RDSInstanceStateRunning
+	return &db, nil
+}
+
+func (c *upgradingRDSClient) UpgradeDBInstanceEngineVersion(ctx context.Context, id, engineVersion string) error {
+	c.upgradedTo = engineVersion
+	c.instance.Status = v1alpha1.RDSInstanceStateEngineVersionUpgrading
+	c.instance.EngineVersion = engineVersion
+	return nil
+}
+
+func TestExternalClientUpgradeAndResize(t *testing.T) {
+	c := &upgradingRDSClient{
+		fakeRDSClient: &fakeRDSClient{},
+		instance: rds.DBInstance{
+			ID:                    testName,
+			Status:                v1alpha1.RDSInstanceStateRunning,
+			EngineVersion:         "5.7",
+			DBInstanceClass:       "rds.mysql.s1.small",
+			DBInstanceStorageInGB: 20,
+		},
+	}
+	e := adapter.NewExternalClient(rdsInstanceKind, nil, &instanceClient{Client: c})
+	obj := &v1alpha1.RDSInstance{
+		Spec: v1alpha1.RDSInstanceSpec{ForProvider: v1alpha1.RDSInstanceParameters{
+			Engine:                   v1alpha1.MysqlEngine,
+			EngineVersion:            "8.0",
+			AllowMajorVersionUpgrade: true,
+			DBInstanceClass:          "rds.mysql.s2.large",
+			DBInstanceStorageInGB:    20,
+		}},
+		Status: v1alpha1.RDSInstanceStatus{AtProvider: v1alpha1.RDSInstanceObservation{DBInstanceID: testName}},
+	}
+
+	// The instance is upgraded first, and resized once the upgrade was
+	// observed to complete even though it is not up to date yet.
+	for i := 0; i < 3; i++ {
+		if _, err := e.Observe(context.Background(), obj); err != nil {
+			t.Fatalf("e.Observe(...): %s", err)
+		}
+		if _, err := e.Update(context.Background(), obj); err != nil {
+			t.Fatalf("e.Update(...): %s", err)
+		}
+	}
+	if diff := cmp.Diff("8.0", c.upgradedTo); diff != "" {
+		t.Errorf("e.Update(...): -want upgrade, +got upgrade:\n%s", diff)
+	}
+	want := &rds.ModifyDBInstanceSpecRequest{PayType: v1alpha1.PayTypePostpaid, DBInstanceClass: "rds.mysql.s2.large"}
+	if diff := cmp.Diff(want, c.modified); diff != "" {
+		t.Errorf("e.Update(...): -want resize, +got resize:\n%s", diff)
+	}
+}
+
+func TestExternalClientObserveUpgrade(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		upgrade *v1alpha1.EngineUpgrade
+		want    v1alpha1.RDSInstanceObservation
+	}{
+		"PrecheckPassed": {
+			reason:  "The result of the pre-check of an upgrade should be observed once it completed",
+			upgrade: &v1alpha1.EngineUpgrade{TargetEngineVersion: "13.0", State: v1alpha1.EngineUpgradePrechecking, PrecheckTaskID: "1"},
+			want: v1alpha1.RDSInstanceObservation{
+				DBInstanceID:     testName,
+				DBInstanceStatus: v1alpha1.RDSInstanceStateRunning,
+				EngineVersion:    "10.0",
+				EngineUpgrade:    &v1alpha1.EngineUpgrade{TargetEngineVersion: "13.0", State: v1alpha1.EngineUpgradePrecheckPassed, PrecheckTaskID: "1"},
+			},
+		},
+		"Cloned": {
+			reason:  "Instances upgraded by cloning them should be followed by their clone once it runs the target version, and their source released",
+			upgrade: &v1alpha1.EngineUpgrade{TargetEngineVersion: "13.0", State: v1alpha1.EngineUpgradeUpgrading, TargetDBInstanceID: testClone, SourceDBInstanceID: testName},
+			want: v1alpha1.RDSInstanceObservation{
+				DBInstanceID:     testClone,
+				DBInstanceStatus: v1alpha1.RDSInstanceStateRunning,
+				EngineVersion:    "13.0",
+				EngineUpgrade:    &v1alpha1.EngineUpgrade{TargetEngineVersion: "13.0", State: v1alpha1.EngineUpgradeReleasingSource, TargetDBInstanceID: testClone, SourceDBInstanceID: testName},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := adapter.NewExternalClient(rdsInstanceKind, nil, &instanceClient{Client: &fakeRDSClient{}})
+			obj := &v1alpha1.RDSInstance{
+				Spec: v1alpha1.RDSInstanceSpec{ForProvider: v1alpha1.RDSInstanceParameters{Engine: v1alpha1.PostgresqlEngine, EngineVersion: "13.0"}},
+				Status: v1alpha1.RDSInstanceStatus{
+					AtProvider: v1alpha1.RDSInstanceObservation{DBInstanceID: testName, EngineUpgrade: tc.upgrade},
+				},
+			}
+			if _, err := e.Observe(context.Background(), obj); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, obj.Status.AtProvider); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestExternalClientReleaseUpgradeSource(t *testing.T) {
+	c := &fakeRDSClient{}
+	e := adapter.NewExternalClient(rdsInstanceKind, nil, &instanceClient{Client: c})
+	obj := &v1alpha1.RDSInstance{
+		Spec: v1alpha1.RDSInstanceSpec{ForProvider: v1alpha1.RDSInstanceParameters{Engine: v1alpha1.PostgresqlEngine, EngineVersion: "13.0"}},
+		Status: v1alpha1.RDSInstanceStatus{AtProvider: v1alpha1.RDSInstanceObservation{
+			DBInstanceID:  testName,
+			EngineUpgrade: &v1alpha1.EngineUpgrade{TargetEngineVersion: "13.0", State: v1alpha1.EngineUpgradeUpgrading, TargetDBInstanceID: testClone, SourceDBInstanceID: testName},
+		}},
+	}
+
+	o, err := e.Observe(context.Background(), obj)
+	if err != nil {
+		t.Fatalf("e.Observe(...): %s", err)
+	}
+	if o.ResourceUpToDate {
+		t.Errorf("e.Observe(...): instances whose upgrade source was not released should not be up to date")
+	}
+	if _, err := e.Update(context.Background(), obj); err != nil {
+		t.Fatalf("e.Update(...): %s", err)
+	}
+	if diff := cmp.Diff([]string{testName}, c.deleted); diff != "" {
+		t.Errorf("e.Update(...): -want deleted, +got deleted:\n%s", diff)
+	}
+	if u := obj.Status.AtProvider.EngineUpgrade; u != nil {
+		t.Errorf("e.Update(...): want upgrade forgotten once its source was released, got %+v", u)
+	}
+	if diff := cmp.Diff(testClone, obj.Status.AtProvider.DBInstanceID); diff != "" {
+		t.Errorf("e.Update(...): -want instance ID, +got instance ID:\n%s", diff)
+	}
+}
+
+func TestExternalClientObserveBackupPolicy(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		backup   *v1alpha1.BackupPolicy
+		want     *v1alpha1.BackupPolicy
+		upToDate bool
+	}{
+		"NoBackupPolicy": {
+			reason:   "Backup policies should not be observed if the spec sets none",
+			upToDate: true,
+		},
+		"UpToDate": {
+			reason:   "Backup policies that have the fields set in the spec should be up to date",
+			backup:   &v1alpha1.BackupPolicy{PreferredBackupTime: "02:00Z-03:00Z"},
+			want:     &v1alpha1.BackupPolicy{BackupRetentionPeriod: 7, PreferredBackupTime: "02:00Z-03:00Z"},
+			upToDate: true,
+		},
+		"NotUpToDate": {
+			reason: "Backup policies that differ from the spec should not be up to date",
+			backup: &v1alpha1.BackupPolicy{BackupRetentionPeriod: 30},
+			want:   &v1alpha1.BackupPolicy{BackupRetentionPeriod: 7, PreferredBackupTime: "02:00Z-03:00Z"},
+		},
+		"CrossRegionBackup": {
+			reason: "Cross-region backups should be observed if the spec sets one",
+			backup: &v1alpha1.BackupPolicy{CrossRegionBackup: &v1alpha1.CrossRegionBackup{Region: "cn-shanghai"}},
+			want:   &v1alpha1.BackupPolicy{BackupRetentionPeriod: 7, PreferredBackupTime: "02:00Z-03:00Z", CrossRegionBackup: &v1alpha1.CrossRegionBackup{}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := adapter.NewExternalClient(rdsInstanceKind, nil, &instanceClient{Client: &fakeRDSClient{}})
+			obj := &v1alpha1.RDSInstance{
+				Spec: v1alpha1.RDSInstanceSpec{ForProvider: v1alpha1.RDSInstanceParameters{EngineVersion: "10.0", BackupPolicy: tc.backup}},
+				Status: v1alpha1.RDSInstanceStatus{
+					AtProvider: v1alpha1.RDSInstanceObservation{DBInstanceID: testName},
+				},
+			}
+			ob, err := e.Observe(context.Background(), obj)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, obj.Status.AtProvider.BackupPolicy); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want backup policy, +got backup policy:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.upToDate, ob.ResourceUpToDate); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want up to date, +got up to date:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestExternalClientObserveParameters(t *testing.T) {
+	type want struct {
+		parameters map[string]string
+		pending    []string
+		upToDate   bool
+	}
+
+	cases := map[string]struct {
+		reason       string
+		parameters   map[string]string
+		allowRestart bool
+		want         want
+	}{
+		"NoParameters": {
+			reason: "Parameters should not be observed if the spec sets none",
+			want:   want{upToDate: true},
+		},
+		"UpToDate": {
+			reason:     "Parameters whose configured values are those of the spec should be up to date",
+			parameters: map[string]string{"max_connections": "100"},
+			want:       want{parameters: map[string]string{"max_connections": "100"}, upToDate: true},
+		},
+		"NotUpToDate": {
+			reason:     "Parameters whose configured values differ from the spec should not be up to date",
+			parameters: map[string]string{"max_connections": "200"},
+			want:       want{parameters: map[string]string{"max_connections": "100"}},
+		},
+		"PendingRestart": {
+			reason:     "Parameters pending a restart should be reported, and be up to date unless the spec allows restarts",
+			parameters: map[string]string{"shared_buffers": "256MB"},
+			want:       want{parameters: map[string]string{"shared_buffers": "256MB"}, pending: []string{"shared_buffers"}, upToDate: true},
+		},
+		"PendingRestartAllowed": {
+			reason:       "Parameters pending a restart should not be up to date if the spec allows restarts",
+			parameters:   map[string]string{"shared_buffers": "256MB"},
+			allowRestart: true,
+			want:         want{parameters: map[string]string{"shared_buffers": "256MB"}, pending: []string{"shared_buffers"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := adapter.NewExternalClient(rdsInstanceKind, nil, &instanceClient{Client: &fakeRDSClient{}})
+			obj := &v1alpha1.RDSInstance{
+				Spec: v1alpha1.RDSInstanceSpec{ForProvider: v1alpha1.RDSInstanceParameters{EngineVersion: "10.0", Parameters: tc.parameters, AllowRestart: tc.allowRestart}},
+				Status: v1alpha1.RDSInstanceStatus{
+					AtProvider: v1alpha1.RDSInstanceObservation{DBInstanceID: testName},
+				},
+			}
+			ob, err := e.Observe(context.Background(), obj)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := want{
+				parameters: obj.Status.AtProvider.Parameters,
+				pending:    obj.Status.AtProvider.PendingRestartParameters,
+				upToDate:   ob.ResourceUpToDate,
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+var egressIPs = v1alpha1.ConfigMapKeySelector{Namespace: "kube-system", Name: "egress", Key: "ips"}
+
+// configMap returns a client that reads the supplied IPs from the egressIPs
+// key of any ConfigMap.
+func configMap(ips string) client.Client {
+	return &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+		obj.(*corev1.ConfigMap).Data = map[string]string{egressIPs.Key: ips}
+		return nil
+	})}
+}
+
+func TestInstanceClientIPsFrom(t *testing.T) {
+	type want struct {
+		ips map[string][]string
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		want   want
+	}{
+		"GetFailed": {
+			reason: "Errors getting the ConfigMap should be returned",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:   want{err: errors.Wrapf(errBoom, errFmtGetConfigMap, "kube-system", "egress")},
+		},
+		"NoKey": {
+			reason: "ConfigMaps without the selected key should be reported",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+			want:   want{err: errors.Errorf(errFmtConfigMapKey, "kube-system", "egress", "ips")},
+		},
+		"Read": {
+			reason: "The IPs listed in the ConfigMap should be returned by group name",
+			kube:   configMap("10.0.1.2\n10.0.1.1"),
+			want:   want{ips: map[string][]string{"nodes": {"10.0.1.1", "10.0.1.2"}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1alpha1.RDSInstance{Spec: v1alpha1.RDSInstanceSpec{ForProvider: v1alpha1.RDSInstanceParameters{
+				SecurityIPGroups: []v1alpha1.SecurityIPGroup{{Name: "nodes", IPsFrom: []v1alpha1.ConfigMapKeySelector{egressIPs}}},
+			}}}
+			c := &instanceClient{Client: &fakeRDSClient{}, kube: tc.kube}
+			ips, err := c.ipsFrom(context.Background(), cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.ipsFrom(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ips, ips); diff != "" {
+				t.Errorf("\n%s\nc.ipsFrom(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
 }
 
 func TestGetConnectionDetails(t *testing.T) {
@@ -408,6 +1010,31 @@ func TestGetConnectionDetails(t *testing.T) {
 				},
 			},
 		},
+		"SuccessfulPublic": {
+			args: args{
+				pw: "",
+				cr: &v1alpha1.RDSInstance{
+					Spec: v1alpha1.RDSInstanceSpec{
+						ForProvider: v1alpha1.RDSInstanceParameters{
+							MasterUsername: testName,
+						},
+					},
+				},
+				i: &rds.DBInstance{
+					Endpoint:       &v1alpha1.Endpoint{Address: address, Port: port},
+					PublicEndpoint: &v1alpha1.Endpoint{Address: "test-public.mysql.rds.aliyuncs.com", Port: port},
+				},
+			},
+			want: want{
+				conn: managed.ConnectionDetails{
+					xpv1.ResourceCredentialsSecretUserKey:     []byte(testName),
+					xpv1.ResourceCredentialsSecretEndpointKey: []byte(address),
+					xpv1.ResourceCredentialsSecretPortKey:     []byte(port),
+					publicEndpointKey:                         []byte("test-public.mysql.rds.aliyuncs.com"),
+					publicPortKey:                             []byte(port),
+				},
+			},
+		},
 		"Successful": {
 			args: args{
 				pw: password,
@@ -452,19 +1079,68 @@ func TestGetConnectionDetails(t *testing.T) {
 }
 
 type fakeRDSClient struct {
+	modified      *rds.ModifyDBInstanceSpecRequest
+	ipGroups      []rds.SecurityIPGroup
+	public        string
+	upgradedTo    string
+	prechecked    string
+	upgradedMajor *rds.UpgradeMajorVersionRequest
+	transformed   *rds.Subscription
+	autoRenewal   *rds.Subscription
+	backup        *v1alpha1.BackupPolicy
+	crossBackup   *v1alpha1.CrossRegionBackup
+	parameters    map[string]string
+	forceRestart  bool
+	restarted     bool
+	deleted       []string
+
+	createdDatabase  string
+	modifiedDatabase string
+	deletedDatabase  string
+
+	createdAccount string
+	resetPassword  string
+	granted        []v1alpha1.AccountPrivilege
+	revoked        []string
+	deletedAccount string
+
+	createdReplica *rds.CreateReadOnlyDBInstanceRequest
 }
 
-func (c *fakeRDSClient) DescribeDBInstance(id string) (*rds.DBInstance, error) {
-	if id != testName {
-		return nil, errors.New("DescribeDBInstance: client doesn't work")
+func (c *fakeRDSClient) DescribeDBInstance(ctx context.Context, id string) (*rds.DBInstance, error) {
+	switch id {
+	case testName:
+		return &rds.DBInstance{
+			ID:            id,
+			Status:        v1alpha1.RDSInstanceStateRunning,
+			EngineVersion: "10.0",
+		}, nil
+	case testClone:
+		return &rds.DBInstance{
+			ID:            id,
+			Status:        v1alpha1.RDSInstanceStateRunning,
+			EngineVersion: "13.0",
+		}, nil
+	case testReplica:
+		return &rds.DBInstance{
+			ID:                    id,
+			Status:                v1alpha1.RDSInstanceStateRunning,
+			EngineVersion:         "10.0",
+			DBInstanceClass:       "rds.pg.s1.small",
+			DBInstanceStorageInGB: 20,
+			MasterInstanceID:      testName,
+			ReadDelaySeconds:      3,
+			Endpoint:              &v1alpha1.Endpoint{Address: "replica.pg.rds.aliyuncs.com", Port: "5432"},
+		}, nil
 	}
-	return &rds.DBInstance{
-		ID:     id,
-		Status: v1alpha1.RDSInstanceStateRunning,
-	}, nil
+	return nil, errors.New("DescribeDBInstance: client doesn't work")
+}
+
+func (c *fakeRDSClient) ListDBInstances(ctx context.Context, tags map[string]string) ([]rds.DBInstance, error) {
+	return nil, nil
 }
 
-func (c *fakeRDSClient) CreateDBInstance(req *rds.CreateDBInstanceRequest) (*rds.DBInstance, error) {
+func (c *fakeRDSClient) CreateDBInstance(ctx context.Context, req *rds.CreateDBInstanceRequest) (*rds.DBInstance, error) {
 	if req.Name != testName || req.Engine != "PostgreSQL" {
 		return nil, errors.New("CreateDBInstance: client doesn't work")
 	}
@@ -477,16 +1153,158 @@ func (c *fakeRDSClient) CreateDBInstance(req *rds.CreateDBInstanceRequest) (*rds
 	}, nil
 }
 
-func (c *fakeRDSClient) CreateAccount(id, user, pw string) error {
+func (c *fakeRDSClient) DeleteDBInstance(ctx context.Context, id string) error {
+	if id != testName && id != testReplica {
+		return errors.New("DeleteDBInstance: client doesn't work")
+	}
+	c.deleted = append(c.deleted, id)
+	return nil
+}
+
+func (c *fakeRDSClient) ModifyDBInstanceSpec(ctx context.Context, id string, req *rds.ModifyDBInstanceSpecRequest) error {
+	if id != testName && id != testReplica {
+		return errors.New("ModifyDBInstanceSpec: client doesn't work")
+	}
+	c.modified = req
+	return nil
+}
+
+func (c *fakeRDSClient) DescribeAvailableClasses(ctx context.Context, engine, engineVersion, payType string) (clients.Availability, error) {
+	return clients.Availability{"cn-beijing-a": {"rds.pg.s1.small"}}, nil
+}
+
+func (c *fakeRDSClient) UpgradeDBInstanceEngineVersion(ctx context.Context, id, engineVersion string) error {
 	if id != testName {
-		return errors.New("CreateAccount: client doesn't work")
+		return errors.New("UpgradeDBInstanceEngineVersion: client doesn't work")
 	}
+	c.upgradedTo = engineVersion
 	return nil
 }
 
-func (c *fakeRDSClient) DeleteDBInstance(id string) error {
+func (c *fakeRDSClient) PrecheckMajorVersionUpgrade(ctx context.Context, id, targetVersion string) (string, error) {
 	if id != testName {
-		return errors.New("DeleteDBInstance: client doesn't work")
+		return "", errors.New("PrecheckMajorVersionUpgrade: client doesn't work")
+	}
+	c.prechecked = targetVersion
+	return "1", nil
+}
+
+func (c *fakeRDSClient) DescribeMajorVersionUpgradePrecheck(ctx context.Context, id, taskID string) (*rds.UpgradePrecheck, error) {
+	if id != testName || taskID != "1" {
+		return nil, errors.New("DescribeMajorVersionUpgradePrecheck: client doesn't work")
+	}
+	return &rds.UpgradePrecheck{Result: rds.PrecheckSuccess}, nil
+}
+
+func (c *fakeRDSClient) UpgradeDBInstanceMajorVersion(ctx context.Context, id string, req *rds.UpgradeMajorVersionRequest) (*rds.DBInstance, error) {
+	if id != testName {
+		return nil, errors.New("UpgradeDBInstanceMajorVersion: client doesn't work")
+	}
+	c.upgradedMajor = req
+	return &rds.DBInstance{ID: testClone}, nil
+}
+
+func (c *fakeRDSClient) ModifySecurityIPs(ctx context.Context, id string, g rds.SecurityIPGroup) error {
+	if id != testName {
+		return errors.New("ModifySecurityIPs: client doesn't work")
+	}
+	c.ipGroups = append(c.ipGroups, g)
+	return nil
+}
+
+func (c *fakeRDSClient) AllocatePublicConnection(ctx context.Context, id, port string) error {
+	if id != testName {
+		return errors.New("AllocatePublicConnection: client doesn't work")
+	}
+	c.public = "allocated:" + port
+	return nil
+}
+
+func (c *fakeRDSClient) ReleasePublicConnection(ctx context.Context, id, address string) error {
+	if id != testName {
+		return errors.New("ReleasePublicConnection: client doesn't work")
+	}
+	c.public = "released:" + address
+	return nil
+}
+
+func (c *fakeRDSClient) TransformDBInstancePayType(ctx context.Context, id string, s rds.Subscription) (string, error) {
+	if id != testName {
+		return "", errors.New("TransformDBInstancePayType: client doesn't work")
+	}
+	c.transformed = &s
+	return "1", nil
+}
+
+func (c *fakeRDSClient) ModifyAutoRenewal(ctx context.Context, id string, s rds.Subscription) error {
+	if id != testName {
+		return errors.New("ModifyAutoRenewal: client doesn't work")
+	}
+	c.autoRenewal = &s
+	return nil
+}
+
+func (c *fakeRDSClient) DescribeBackupPolicy(ctx context.Context, id string, crossRegion bool) (*v1alpha1.BackupPolicy, error) {
+	if id != testName {
+		return nil, errors.New("DescribeBackupPolicy: client doesn't work")
+	}
+	bp := &v1alpha1.BackupPolicy{BackupRetentionPeriod: 7, PreferredBackupTime: "02:00Z-03:00Z"}
+	if crossRegion {
+		bp.CrossRegionBackup = &v1alpha1.CrossRegionBackup{}
+	}
+	return bp, nil
+}
+
+func (c *fakeRDSClient) ModifyBackupPolicy(ctx context.Context, id string, bp *v1alpha1.BackupPolicy) error {
+	if id != testName {
+		return errors.New("ModifyBackupPolicy: client doesn't work")
+	}
+	c.backup = bp
+	return nil
+}
+
+func (c *fakeRDSClient) ModifyCrossRegionBackup(ctx context.Context, id string, cb *v1alpha1.CrossRegionBackup) error {
+	if id != testName {
+		return errors.New("ModifyCrossRegionBackup: client doesn't work")
+	}
+	c.crossBackup = cb
+	return nil
+}
+
+func (c *fakeRDSClient) DescribeParameters(ctx context.Context, id string, names []string) (map[string]string, []string, error) {
+	if id != testName {
+		return nil, nil, errors.New("DescribeParameters: client doesn't work")
+	}
+	configured := map[string]string{"max_connections": "100", "shared_buffers": "256MB"}
+	running := map[string]string{"max_connections": "100", "shared_buffers": "128MB"}
+	params := map[string]string{}
+	var pending []string
+	for _, name := range names {
+		params[name] = configured[name]
+		if configured[name] != running[name] {
+			pending = append(pending, name)
+		}
+	}
+	return params, pending, nil
+}
+
+func (c *fakeRDSClient) DescribeParameterTemplates(ctx context.Context, engine, engineVersion string) (map[string]bool, error) {
+	return map[string]bool{"max_connections": false, "shared_buffers": true}, nil
+}
+
+func (c *fakeRDSClient) ModifyParameters(ctx context.Context, id string, params map[string]string, forceRestart bool) error {
+	if id != testName {
+		return errors.New("ModifyParameters: client doesn't work")
+	}
+	c.parameters = params
+	c.forceRestart = forceRestart
+	return nil
+}
+
+func (c *fakeRDSClient) RestartDBInstance(ctx context.Context, id string) error {
+	if id != testName {
+		return errors.New("RestartDBInstance: client doesn't work")
 	}
+	c.restarted = true
 	return nil
 }
@@ -0,0 +1,174 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/clients/rds"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
+)
+
+const (
+	errCreateReplicaFailed   = "cannot create RDS read-only instance"
+	errDescribeReplicaFailed = "cannot describe RDS read-only instance"
+	errDescribePrimaryFailed = "cannot describe primary RDS instance"
+	errResizeReplicaFailed   = "cannot resize RDS read-only instance"
+	errDeleteReplicaFailed   = "cannot delete RDS read-only instance"
+	errNoPrimary             = "primaryDbInstanceId, primaryDbInstanceIdRef or primaryDbInstanceIdSelector is required"
+
+	errFmtPrimaryNotRunning = "primary instance %s is %s"
+)
+
+// SetupRDSReadOnlyInstance adds a controller that reconciles
+// RDSReadOnlyInstances.
+func SetupRDSReadOnlyInstance(mgr ctrl.Manager, l logging.Logger, o adapter.Options) error {
+	return adapter.Setup(mgr, l, rdsReadOnlyInstanceKind, o)
+}
+
+var rdsReadOnlyInstanceKind = adapter.Kind{
+	Type:              &v1alpha1.RDSReadOnlyInstance{},
+	GroupVersionKind:  v1alpha1.RDSReadOnlyInstanceGroupVersionKind,
+	NewClient:         newRDSClient,
+	Describe:          describeRDSReadOnlyInstance,
+	IsNotFound:        rds.IsErrorNotFound,
+	Observe:           observeRDSReadOnlyInstance,
+	IsUpToDate:        isRDSReadOnlyInstanceUpToDate,
+	Condition:         getRDSReadOnlyInstanceCondition,
+	ConnectionDetails: getRDSReadOnlyInstanceConnectionDetails,
+	Create:            createRDSReadOnlyInstance,
+	Update:            updateRDSReadOnlyInstance,
+	Delete:            deleteRDSReadOnlyInstance,
+	CloudID: func(mg resource.Managed) string {
+		return mg.(*v1alpha1.RDSReadOnlyInstance).Status.AtProvider.DBInstanceID
+	},
+}
+
+// replicaInstanceID returns the ID of the supplied RDSReadOnlyInstance.
+// Imported replicas are identified by their external name until their ID has
+// been observed.
+func replicaInstanceID(cr *v1alpha1.RDSReadOnlyInstance) string {
+	if cr.Status.AtProvider.DBInstanceID != "" {
+		return cr.Status.AtProvider.DBInstanceID
+	}
+	return meta.GetExternalName(cr)
+}
+
+func describeRDSReadOnlyInstance(ctx context.Context, c interface{}, mg resource.Managed) (interface{}, error) {
+	id := replicaInstanceID(mg.(*v1alpha1.RDSReadOnlyInstance))
+	if id == "" {
+		return nil, adapter.ErrNotFound
+	}
+	replica, err := c.(rds.Client).DescribeDBInstance(ctx, id)
+	return replica, errors.Wrap(err, errDescribeReplicaFailed)
+}
+
+func observeRDSReadOnlyInstance(mg resource.Managed, observed interface{}) {
+	mg.(*v1alpha1.RDSReadOnlyInstance).Status.AtProvider = rds.GenerateReadOnlyObservation(observed.(*rds.DBInstance))
+}
+
+func isRDSReadOnlyInstanceUpToDate(mg resource.Managed, observed interface{}) bool {
+	return rds.IsReadOnlyUpToDate(&mg.(*v1alpha1.RDSReadOnlyInstance).Spec.ForProvider, observed.(*rds.DBInstance))
+}
+
+func getRDSReadOnlyInstanceCondition(mg resource.Managed, _ interface{}) xpv1.Condition {
+	return instanceCondition(mg.(*v1alpha1.RDSReadOnlyInstance).Status.AtProvider.DBInstanceStatus)
+}
+
+// getRDSReadOnlyInstanceConnectionDetails returns the read endpoints of the
+// replica. Its accounts are those of its primary instance.
+func getRDSReadOnlyInstanceConnectionDetails(_ context.Context, _ interface{}, _ resource.Managed, observed interface{}) (managed.ConnectionDetails, error) {
+	replica := observed.(*rds.DBInstance)
+	cd := managed.ConnectionDetails{}
+	addEndpointConnectionDetails(cd, replica.Endpoint, replica.PublicEndpoint)
+	return cd, nil
+}
+
+// createRDSReadOnlyInstance creates a replica of the primary instance once
+// the primary instance is running.
+func createRDSReadOnlyInstance(ctx context.Context, c interface{}, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr := mg.(*v1alpha1.RDSReadOnlyInstance)
+	if cr.Status.AtProvider.DBInstanceStatus == v1alpha1.RDSInstanceStateCreating {
+		return managed.ExternalCreation{}, nil
+	}
+	p := cr.Spec.ForProvider
+	if p.PrimaryDBInstanceID == "" {
+		return managed.ExternalCreation{}, clients.NewTerminalError(errNoPrimary)
+	}
+	if p.DBInstanceStorageInGB%rds.StorageIncrementGB != 0 {
+		return managed.ExternalCreation{}, clients.NewTerminalErrorf(errFmtStorageIncrement, p.DBInstanceStorageInGB, rds.StorageIncrementGB)
+	}
+
+	client := c.(rds.Client)
+	primary, err := client.DescribeDBInstance(ctx, p.PrimaryDBInstanceID)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errDescribePrimaryFailed)
+	}
+	if primary.Status != v1alpha1.RDSInstanceStateRunning {
+		return managed.ExternalCreation{}, errors.Errorf(errFmtPrimaryNotRunning, primary.ID, primary.Status)
+	}
+	replica, err := client.CreateReadOnlyDBInstance(ctx, rds.MakeCreateReadOnlyDBInstanceRequest(meta.GetExternalName(cr), &p, primary))
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateReplicaFailed)
+	}
+
+	cr.Status.AtProvider.DBInstanceID = replica.ID
+	cr.Status.AtProvider.PrimaryDBInstanceID = replica.MasterInstanceID
+	adapter.SetTaskID(ctx, replica.OrderID)
+
+	cd := managed.ConnectionDetails{}
+	addEndpointConnectionDetails(cd, replica.Endpoint, nil)
+	return managed.ExternalCreation{ConnectionDetails: cd}, nil
+}
+
+// updateRDSReadOnlyInstance resizes the replica to the class and storage of
+// its spec while it is running.
+func updateRDSReadOnlyInstance(ctx context.Context, c interface{}, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr := mg.(*v1alpha1.RDSReadOnlyInstance)
+	if cr.Status.AtProvider.DBInstanceStatus != v1alpha1.RDSInstanceStateRunning {
+		return managed.ExternalUpdate{}, nil
+	}
+	p := cr.Spec.ForProvider
+	if p.DBInstanceStorageInGB%rds.StorageIncrementGB != 0 {
+		return managed.ExternalUpdate{}, clients.NewTerminalErrorf(errFmtStorageIncrement, p.DBInstanceStorageInGB, rds.StorageIncrementGB)
+	}
+	req := rds.MakeModifyReadOnlyDBInstanceSpecRequest(&p, &cr.Status.AtProvider)
+	if req == nil {
+		return managed.ExternalUpdate{}, nil
+	}
+	err := c.(rds.Client).ModifyDBInstanceSpec(ctx, cr.Status.AtProvider.DBInstanceID, req)
+	return managed.ExternalUpdate{}, errors.Wrap(err, errResizeReplicaFailed)
+}
+
+func deleteRDSReadOnlyInstance(ctx context.Context, c interface{}, mg resource.Managed) error {
+	cr := mg.(*v1alpha1.RDSReadOnlyInstance)
+	if cr.Status.AtProvider.DBInstanceStatus == v1alpha1.RDSInstanceStateDeleting {
+		return nil
+	}
+	err := c.(rds.Client).DeleteDBInstance(ctx, replicaInstanceID(cr))
+	return errors.Wrap(err, errDeleteReplicaFailed)
+}
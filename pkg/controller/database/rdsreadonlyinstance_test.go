@@ -0,0 +1,181 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-alibaba/apis/database/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/clients/rds"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
+)
+
+const testReplica = "replica"
+
+func rdsReadOnlyInstance(p v1alpha1.RDSReadOnlyInstanceParameters, o v1alpha1.RDSReadOnlyInstanceObservation) *v1alpha1.RDSReadOnlyInstance {
+	cr := &v1alpha1.RDSReadOnlyInstance{
+		Spec:   v1alpha1.RDSReadOnlyInstanceSpec{ForProvider: p},
+		Status: v1alpha1.RDSReadOnlyInstanceStatus{AtProvider: o},
+	}
+	cr.SetName(testReplica)
+	meta.SetExternalName(cr, testReplica)
+	return cr
+}
+
+func TestRDSReadOnlyInstanceObserve(t *testing.T) {
+	e := adapter.NewExternalClient(rdsReadOnlyInstanceKind, nil, &fakeRDSClient{})
+	cr := rdsReadOnlyInstance(v1alpha1.RDSReadOnlyInstanceParameters{
+		PrimaryDBInstanceID:   testName,
+		DBInstanceClass:       "rds.pg.s1.small",
+		DBInstanceStorageInGB: 40,
+	}, v1alpha1.RDSReadOnlyInstanceObservation{})
+	o, err := e.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("e.Observe(...): %s", err)
+	}
+
+	want := managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: false,
+		ConnectionDetails: managed.ConnectionDetails{
+			xpv1.ResourceCredentialsSecretEndpointKey: []byte("replica.pg.rds.aliyuncs.com"),
+			xpv1.ResourceCredentialsSecretPortKey:     []byte("5432"),
+		},
+	}
+	if diff := cmp.Diff(want, o); diff != "" {
+		t.Errorf("e.Observe(...): -want, +got:\n%s", diff)
+	}
+	wantStatus := v1alpha1.RDSReadOnlyInstanceObservation{
+		DBInstanceStatus:        v1alpha1.RDSInstanceStateRunning,
+		DBInstanceID:            testReplica,
+		PrimaryDBInstanceID:     testName,
+		EngineVersion:           "10.0",
+		DBInstanceClass:         "rds.pg.s1.small",
+		DBInstanceStorageInGB:   20,
+		ReplicationDelaySeconds: 3,
+		Endpoint:                &v1alpha1.Endpoint{Address: "replica.pg.rds.aliyuncs.com", Port: "5432"},
+	}
+	if diff := cmp.Diff(wantStatus, cr.Status.AtProvider); diff != "" {
+		t.Errorf("e.Observe(...): -want status, +got status:\n%s", diff)
+	}
+}
+
+func TestRDSReadOnlyInstanceCreate(t *testing.T) {
+	type want struct {
+		created  *rds.CreateReadOnlyDBInstanceRequest
+		id       string
+		err      error
+		terminal bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		params v1alpha1.RDSReadOnlyInstanceParameters
+		want   want
+	}{
+		"NoPrimary": {
+			reason: "Replicas without a primary instance should not be created",
+			params: v1alpha1.RDSReadOnlyInstanceParameters{DBInstanceStorageInGB: 20},
+			want:   want{err: clients.NewTerminalError(errNoPrimary), terminal: true},
+		},
+		"StorageIncrement": {
+			reason: "Replicas whose storage is not a multiple of the increment should not be created",
+			params: v1alpha1.RDSReadOnlyInstanceParameters{PrimaryDBInstanceID: testName, DBInstanceStorageInGB: 22},
+			want:   want{err: clients.NewTerminalErrorf(errFmtStorageIncrement, 22, rds.StorageIncrementGB), terminal: true},
+		},
+		"DescribePrimaryFailed": {
+			reason: "Errors describing the primary instance should be returned",
+			params: v1alpha1.RDSReadOnlyInstanceParameters{PrimaryDBInstanceID: "unknown", DBInstanceStorageInGB: 20},
+			want:   want{err: errors.Wrap(errors.New("DescribeDBInstance: client doesn't work"), errDescribePrimaryFailed)},
+		},
+		"Create": {
+			reason: "Replicas should be created with the engine version of their primary instance",
+			params: v1alpha1.RDSReadOnlyInstanceParameters{PrimaryDBInstanceID: testName, DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 20, ZoneID: "cn-hangzhou-h"},
+			want: want{
+				created: &rds.CreateReadOnlyDBInstanceRequest{
+					Name:                  testReplica,
+					PrimaryDBInstanceID:   testName,
+					EngineVersion:         "10.0",
+					DBInstanceClass:       "rds.pg.s1.small",
+					DBInstanceStorageInGB: 20,
+					ZoneID:                "cn-hangzhou-h",
+				},
+				id: testReplica,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &fakeRDSClient{}
+			cr := rdsReadOnlyInstance(tc.params, v1alpha1.RDSReadOnlyInstanceObservation{})
+			_, err := createRDSReadOnlyInstance(context.Background(), c, cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ncreateRDSReadOnlyInstance(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.terminal, clients.IsTerminalError(err)); diff != "" {
+				t.Errorf("\n%s\ncreateRDSReadOnlyInstance(...): -want terminal error, +got terminal error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.created, c.createdReplica); diff != "" {
+				t.Errorf("\n%s\ncreateRDSReadOnlyInstance(...): -want request, +got request:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.id, cr.Status.AtProvider.DBInstanceID); diff != "" {
+				t.Errorf("\n%s\ncreateRDSReadOnlyInstance(...): -want instance ID, +got instance ID:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRDSReadOnlyInstanceUpdateAndDelete(t *testing.T) {
+	c := &fakeRDSClient{}
+	cr := rdsReadOnlyInstance(
+		v1alpha1.RDSReadOnlyInstanceParameters{DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 40},
+		v1alpha1.RDSReadOnlyInstanceObservation{DBInstanceStatus: v1alpha1.RDSInstanceStateRunning, DBInstanceID: testReplica, DBInstanceClass: "rds.pg.s1.small", DBInstanceStorageInGB: 20},
+	)
+	if _, err := updateRDSReadOnlyInstance(context.Background(), c, cr); err != nil {
+		t.Fatalf("updateRDSReadOnlyInstance(...): %s", err)
+	}
+	want := &rds.ModifyDBInstanceSpecRequest{PayType: v1alpha1.PayTypePostpaid, DBInstanceStorageInGB: 40}
+	if diff := cmp.Diff(want, c.modified); diff != "" {
+		t.Errorf("updateRDSReadOnlyInstance(...): -want, +got:\n%s", diff)
+	}
+
+	if err := deleteRDSReadOnlyInstance(context.Background(), c, cr); err != nil {
+		t.Fatalf("deleteRDSReadOnlyInstance(...): %s", err)
+	}
+}
+
+func (c *fakeRDSClient) CreateReadOnlyDBInstance(ctx context.Context, req *rds.CreateReadOnlyDBInstanceRequest) (*rds.DBInstance, error) {
+	if req.PrimaryDBInstanceID != testName {
+		return nil, errors.New("CreateReadOnlyDBInstance: client doesn't work")
+	}
+	c.createdReplica = req
+	return &rds.DBInstance{
+		ID:               testReplica,
+		MasterInstanceID: req.PrimaryDBInstanceID,
+		Endpoint:         &v1alpha1.Endpoint{Address: "replica.pg.rds.aliyuncs.com", Port: "5432"},
+	}, nil
+}
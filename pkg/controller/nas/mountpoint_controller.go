@@ -19,147 +19,64 @@ package nas
 import (
 	"context"
 
-	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
-	"github.com/crossplane/crossplane-runtime/pkg/event"
+	sdk "github.com/alibabacloud-go/nas-20170626/v2/client"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/provider-alibaba/apis/nas/v1alpha1"
-	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
 	nasclient "github.com/crossplane/provider-alibaba/pkg/clients/nas"
-	"github.com/crossplane/provider-alibaba/pkg/util"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
 )
 
 const (
 	errFailedToCreateNASMountTarget   = "failed to create NAS filesystem"
 	errFailedToDeleteNASMountTarget   = "failed to delete NAS filesystem"
 	errFailedToDescribeNASMountTarget = "failed to describe NAS filesystem"
-	errNotNASMountTarget              = "managed resource is not a NASMountTarget custom resource"
 )
 
 // SetupNASMountTarget adds a controller that reconciles NASMountTarget.
-func SetupNASMountTarget(mgr ctrl.Manager, l logging.Logger) error {
-	name := managed.ControllerName(v1alpha1.NASMountTargetGroupKind)
-	return ctrl.NewControllerManagedBy(mgr).
-		Named(name).
-		For(&v1alpha1.NASMountTarget{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(v1alpha1.NASMountTargetGroupVersionKind),
-			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-			managed.WithExternalConnecter(&mtConnector{
-				Client:      mgr.GetClient(),
-				Usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &aliv1alpha1.ProviderConfigUsage{}),
-				NewClientFn: nasclient.NewClient,
-			})))
+func SetupNASMountTarget(mgr ctrl.Manager, l logging.Logger, o adapter.Options) error {
+	return adapter.Setup(mgr, l, mountTargetKind, o)
 }
 
-// mtConnector stores Kubernetes client and NAS client
-type mtConnector struct {
-	Client      client.Client
-	Usage       resource.Tracker
-	NewClientFn func(ctx context.Context, endpoint, accessKeyID, accessKeySecret, stsToken string) (*nasclient.SDKClient, error)
+// Managed resource `NASMountTarget` is special, the identifier of if `name` is
+// different to the cloud resource identifier `MountTargetDomain`.
+var mountTargetKind = adapter.Kind{
+	Type:              &v1alpha1.NASMountTarget{},
+	GroupVersionKind:  v1alpha1.NASMountTargetGroupVersionKind,
+	NewClient:         newNASClient,
+	Describe:          describeMountTarget,
+	IsNotFound:        nasclient.IsMountTargetNotFoundError,
+	IsUpToDate:        isMountTargetUpToDate,
+	ConnectionDetails: getMountTargetObservedConnectionDetails,
+	Create:            createMountTarget,
+	Delete:            deleteMountTarget,
 }
 
-// Connect initials cloud resource client
-func (c *mtConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
-	cr, ok := mg.(*v1alpha1.NASMountTarget)
-	if !ok {
-		return nil, errors.New(errNotNASMountTarget)
+func describeMountTarget(ctx context.Context, c interface{}, mg resource.Managed) (interface{}, error) {
+	cr := mg.(*v1alpha1.NASMountTarget)
+	if meta.GetExternalName(cr) == "" {
+		return nil, adapter.ErrNotFound
 	}
-
-	var (
-		secretKeySelector *xpv1.SecretKeySelector
-		region            string
-	)
-
-	if err := c.Usage.Track(ctx, mg); err != nil {
-		return nil, errors.Wrap(err, errTrackUsage)
-	}
-
-	providerConfig, err := util.GetProviderConfig(ctx, c.Client, cr.Spec.ProviderConfigReference.Name)
-	if err != nil {
-		return nil, err
-	}
-
-	if s := providerConfig.Spec.Credentials.Source; s != xpv1.CredentialsSourceSecret {
-		return nil, errors.Errorf(errFmtUnsupportedCredSource, s)
-	}
-	secretKeySelector = providerConfig.Spec.Credentials.SecretRef
-	region = providerConfig.Spec.Region
-
-	if secretKeySelector == nil {
-		return nil, errors.New(errNoConnectionSecret)
-	}
-	s := &corev1.Secret{}
-	nn := types.NamespacedName{Namespace: secretKeySelector.Namespace, Name: secretKeySelector.Name}
-	if err := c.Client.Get(ctx, nn, s); err != nil {
-		return nil, errors.Wrap(err, errGetConnectionSecret)
-	}
-
-	endpoint, err := util.GetEndpoint(cr.DeepCopyObject(), region)
-	if err != nil {
-		return nil, err
-	}
-
-	client, err := c.NewClientFn(ctx, endpoint, string(s.Data[util.AccessKeyID]), string(s.Data[util.AccessKeySecret]), string(s.Data[util.SecurityToken]))
-	return &mountTargetExternal{ExternalClient: client}, errors.Wrap(err, errCreateClient)
+	mountTarget, err := c.(nasclient.ClientInterface).DescribeMountTargets(ctx, cr.Spec.ForProvider.FileSystemID, cr.Status.AtProvider.MountTargetDomain)
+	return mountTarget, errors.Wrap(err, errFailedToDescribeNASMountTarget)
 }
 
-// mountTargetExternal includes external NAS client
-type mountTargetExternal struct {
-	ExternalClient nasclient.ClientInterface
+func isMountTargetUpToDate(mg resource.Managed, observed interface{}) bool {
+	return nasclient.IsMountTargetUpdateToDate(mg.(*v1alpha1.NASMountTarget), observed.(*sdk.DescribeMountTargetsResponse))
 }
 
-// Observe managed resource NAS filesystem
-func (e *mountTargetExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
-	cr, ok := mg.(*v1alpha1.NASMountTarget)
-	if !ok {
-		return managed.ExternalObservation{}, errors.New(errNotNASMountTarget)
-	}
-
-	if meta.GetExternalName(mg) == "" {
-		return managed.ExternalObservation{
-			ResourceExists: false,
-		}, nil
-	}
-
-	mountTarget, err := e.ExternalClient.DescribeMountTargets(cr.Spec.ForProvider.FileSystemID, cr.Status.AtProvider.MountTargetDomain)
-	if err != nil {
-		// Managed resource `NASMountTarget` is special, the identifier of if `name` is different to the cloud resource identifier `MountTargetDomain`
-		if nasclient.IsMountTargetNotFoundError(err) {
-			return managed.ExternalObservation{ResourceExists: false, ResourceUpToDate: true}, nil
-		}
-		return managed.ExternalObservation{ResourceExists: false}, errors.Wrap(err, errFailedToDescribeNASMountTarget)
-	}
-
-	var upToDate = nasclient.IsMountTargetUpdateToDate(cr, mountTarget)
-	if upToDate {
-		cr.SetConditions(xpv1.Available())
-	}
-
-	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  upToDate,
-		ConnectionDetails: GetMountTargetConnectionDetails(cr),
-	}, nil
+func getMountTargetObservedConnectionDetails(ctx context.Context, _ interface{}, mg resource.Managed, _ interface{}) (managed.ConnectionDetails, error) {
+	return GetMountTargetConnectionDetails(mg.(*v1alpha1.NASMountTarget)), nil
 }
 
-// Create managed resource NASFilesystem
-func (e *mountTargetExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
-	cr, ok := mg.(*v1alpha1.NASMountTarget)
-	if !ok {
-		return managed.ExternalCreation{}, errors.New(errNotNASMountTarget)
-	}
-	cr.SetConditions(xpv1.Creating())
-	res, err := e.ExternalClient.CreateMountTarget(cr.Spec.ForProvider)
+func createMountTarget(ctx context.Context, c interface{}, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr := mg.(*v1alpha1.NASMountTarget)
+	res, err := c.(nasclient.ClientInterface).CreateMountTarget(ctx, cr.Spec.ForProvider)
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errFailedToCreateNASMountTarget)
 	}
@@ -167,22 +84,10 @@ func (e *mountTargetExternal) Create(ctx context.Context, mg resource.Managed) (
 	return managed.ExternalCreation{ConnectionDetails: GetMountTargetConnectionDetails(cr)}, nil
 }
 
-// Update managed resource NASFilesystem
-func (e *mountTargetExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	return managed.ExternalUpdate{}, nil
-}
-
-// Delete managed resource NASFilesystem
-func (e *mountTargetExternal) Delete(ctx context.Context, mg resource.Managed) error {
-	cr, ok := mg.(*v1alpha1.NASMountTarget)
-	if !ok {
-		return errors.New(errNotNASMountTarget)
-	}
-	cr.SetConditions(xpv1.Deleting())
-	if err := e.ExternalClient.DeleteMountTarget(cr.Spec.ForProvider.FileSystemID, cr.Status.AtProvider.MountTargetDomain); err != nil {
-		return errors.Wrap(err, errFailedToDeleteNASMountTarget)
-	}
-	return nil
+func deleteMountTarget(ctx context.Context, c interface{}, mg resource.Managed) error {
+	cr := mg.(*v1alpha1.NASMountTarget)
+	err := c.(nasclient.ClientInterface).DeleteMountTarget(ctx, cr.Spec.ForProvider.FileSystemID, cr.Status.AtProvider.MountTargetDomain)
+	return errors.Wrap(err, errFailedToDeleteNASMountTarget)
 }
 
 // GetMountTargetConnectionDetails generates connection details
@@ -31,9 +31,10 @@ import (
 	"k8s.io/utils/pointer"
 
 	"github.com/crossplane/provider-alibaba/apis/nas/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
 )
 
-func (c *fakeSDKClient) DescribeMountTargets(fileSystemID, mountTargetDomain *string) (*sdk.DescribeMountTargetsResponse, error) {
+func (c *fakeSDKClient) DescribeMountTargets(ctx context.Context, fileSystemID, mountTargetDomain *string) (*sdk.DescribeMountTargetsResponse, error) {
 	switch *fileSystemID {
 	case "123":
 		return nil, errors.New("unknown error")
@@ -48,12 +49,12 @@ func (c *fakeSDKClient) DescribeMountTargets(fileSystemID, mountTargetDomain *st
 	}
 }
 
-func (c *fakeSDKClient) CreateMountTarget(fs v1alpha1.NASMountTargetParameter) (*sdk.CreateMountTargetResponse, error) {
+func (c *fakeSDKClient) CreateMountTarget(ctx context.Context, fs v1alpha1.NASMountTargetParameter) (*sdk.CreateMountTargetResponse, error) {
 	res := &sdk.CreateMountTargetResponse{Body: &sdk.CreateMountTargetResponseBody{MountTargetDomain: pointer.StringPtr("abc.com")}}
 	return res, nil
 }
 
-func (c *fakeSDKClient) DeleteMountTarget(fileSystemID, mountTargetDomain *string) error {
+func (c *fakeSDKClient) DeleteMountTarget(ctx context.Context, fileSystemID, mountTargetDomain *string) error {
 	return nil
 }
 
@@ -84,14 +85,6 @@ func TestMountTargetObserve(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotNASMountTarget": {
-			reason: "We should return an error if the supplied managed resource is not NASMountTarget",
-			mg:     nil,
-			want: want{
-				o:   managed.ExternalObservation{},
-				err: errors.New(errNotNASMountTarget),
-			},
-		},
 		"NASMountTargetNotFound": {
 			reason: "We should report a NotFound error",
 			mg:     &v1alpha1.NASMountTarget{},
@@ -125,7 +118,7 @@ func TestMountTargetObserve(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			external := &mountTargetExternal{ExternalClient: &fakeSDKClient{}}
+			external := adapter.NewExternalClient(mountTargetKind, nil, &fakeSDKClient{})
 			got, err := external.Observe(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -153,14 +146,6 @@ func TestMountTargetCreate(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotNASMountTarget": {
-			reason: "Not NASMountTarget object",
-			mg:     nil,
-			want: want{
-				o:   managed.ExternalCreation{},
-				err: errors.New(errNotNASMountTarget),
-			},
-		},
 		"Success": {
 			reason: "Creating NASMountTarget successfully",
 			mg:     validCR,
@@ -174,7 +159,7 @@ func TestMountTargetCreate(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			external := &mountTargetExternal{ExternalClient: &fakeSDKClient{}}
+			external := adapter.NewExternalClient(mountTargetKind, nil, &fakeSDKClient{})
 			got, err := external.Create(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -203,13 +188,6 @@ func TestMountTargetDelete(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotNASMountTarget": {
-			reason: "Not NASMountTarget object",
-			mg:     nil,
-			want: want{
-				err: errors.New(errNotNASMountTarget),
-			},
-		},
 		"Success": {
 			reason: "Deleting NASMountTarget successfully",
 			mg:     validCR,
@@ -221,7 +199,7 @@ func TestMountTargetDelete(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			external := &mountTargetExternal{ExternalClient: &fakeSDKClient{}}
+			external := adapter.NewExternalClient(mountTargetKind, nil, &fakeSDKClient{})
 			err := external.Delete(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -19,156 +19,143 @@ package nas
 import (
 	"context"
 
-	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
-	"github.com/crossplane/crossplane-runtime/pkg/event"
+	sdk "github.com/alibabacloud-go/nas-20170626/v2/client"
+	"github.com/alibabacloud-go/tea/tea"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/provider-alibaba/apis/nas/v1alpha1"
 	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
 	nasclient "github.com/crossplane/provider-alibaba/pkg/clients/nas"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
 	"github.com/crossplane/provider-alibaba/pkg/util"
 )
 
-const (
-	errCreateClient             = "cannot create NAS client"
-	errTrackUsage               = "cannot track provider config usage"
-	errNoConnectionSecret       = "no connection secret specified"
-	errGetConnectionSecret      = "cannot get connection secret"
-	errFmtUnsupportedCredSource = "credentials source %q is not currently supported"
-)
-
 const (
 	errFailedToCreateNASFileSystem   = "failed to create NAS filesystem"
 	errFailedToDeleteNASFileSystem   = "failed to delete NAS filesystem"
 	errFailedToDescribeNASFileSystem = "failed to describe NAS filesystem"
-	errNotNASFileSystem              = "managed resource is not a NASFileSystem custom resource"
+	errDescribeZones                 = "failed to describe NAS zones"
+
+	// Statuses of file systems that are being created or extended.
+	fileSystemStatusPending   = "Pending"
+	fileSystemStatusExtending = "Extending"
+
+	offeringFileSystem = "NAS storage type/protocol"
 )
 
 // SetupNASFileSystem adds a controller that reconciles NASFileSystem.
-func SetupNASFileSystem(mgr ctrl.Manager, l logging.Logger) error {
-	name := managed.ControllerName(v1alpha1.NASFileSystemGroupKind)
-	return ctrl.NewControllerManagedBy(mgr).
-		Named(name).
-		For(&v1alpha1.NASFileSystem{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(v1alpha1.NASFileSystemGroupVersionKind),
-			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-			managed.WithExternalConnecter(&Connector{
-				Client:      mgr.GetClient(),
-				Usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &aliv1alpha1.ProviderConfigUsage{}),
-				NewClientFn: nasclient.NewClient,
-			})))
+func SetupNASFileSystem(mgr ctrl.Manager, l logging.Logger, o adapter.Options) error {
+	return adapter.Setup(mgr, l, fileSystemKind, o)
 }
 
-// Connector stores Kubernetes client and NAS client
-type Connector struct {
-	Client      client.Client
-	Usage       resource.Tracker
-	NewClientFn func(ctx context.Context, endpoint, accessKeyID, accessKeySecret, stsToken string) (*nasclient.SDKClient, error)
+// Managed resource `NASFileSystem` is special, the identifier of if `name` is
+// different to the cloud resource identifier `FileSystemID`.
+var fileSystemKind = adapter.Kind{
+	Type:              &v1alpha1.NASFileSystem{},
+	GroupVersionKind:  v1alpha1.NASFileSystemGroupVersionKind,
+	NewClient:         newNASClient,
+	Describe:          describeFileSystem,
+	IsNotFound:        nasclient.IsNotFoundError,
+	Observe:           observeFileSystem,
+	IsUpToDate:        isFileSystemUpToDate,
+	ConnectionDetails: getFileSystemObservedConnectionDetails,
+	Create:            createFileSystem,
+	Delete:            deleteFileSystem,
+	Dependents:        getFileSystemDependents,
+	CloudID: func(mg resource.Managed) string {
+		return mg.(*v1alpha1.NASFileSystem).Status.AtProvider.FileSystemID
+	},
+	Preflight: &adapter.Preflight{
+		Check:            checkFileSystemAvailable,
+		QuotaProductCode: nasclient.QuotaProductCode,
+	},
+	Operations: &adapter.Operations{
+		Pending: func(mg resource.Managed) *aliv1alpha1.PendingOperation {
+			return mg.(*v1alpha1.NASFileSystem).Status.AtProvider.PendingOperation
+		},
+		SetPending: func(mg resource.Managed, op *aliv1alpha1.PendingOperation) {
+			mg.(*v1alpha1.NASFileSystem).Status.AtProvider.PendingOperation = op
+		},
+		InProgress: isFileSystemInProgress,
+	},
 }
 
-// Connect initials cloud resource client
-func (c *Connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
-	cr, ok := mg.(*v1alpha1.NASFileSystem)
-	if !ok {
-		return nil, errors.New(errNotNASFileSystem)
-	}
-
-	var (
-		secretKeySelector *xpv1.SecretKeySelector
-		region            string
-	)
-
-	if err := c.Usage.Track(ctx, mg); err != nil {
-		return nil, errors.Wrap(err, errTrackUsage)
-	}
-
-	providerConfig, err := util.GetProviderConfig(ctx, c.Client, cr.Spec.ProviderConfigReference.Name)
+// newNASClient returns the NAS client shared by all NAS managed resources.
+func newNASClient(ctx context.Context, mg resource.Managed, creds util.Credentials) (interface{}, error) {
+	endpoint, err := util.GetEndpoint(mg.DeepCopyObject(), creds.Region)
 	if err != nil {
 		return nil, err
 	}
+	return nasclient.NewClient(ctx, endpoint, creds.AccessKeyID, creds.AccessKeySecret, creds.SecurityToken)
+}
 
-	if s := providerConfig.Spec.Credentials.Source; s != xpv1.CredentialsSourceSecret {
-		return nil, errors.Errorf(errFmtUnsupportedCredSource, s)
-	}
-	secretKeySelector = providerConfig.Spec.Credentials.SecretRef
-	region = providerConfig.Spec.Region
-
-	if secretKeySelector == nil {
-		return nil, errors.New(errNoConnectionSecret)
-	}
-	s := &corev1.Secret{}
-	nn := types.NamespacedName{Namespace: secretKeySelector.Namespace, Name: secretKeySelector.Name}
-	if err := c.Client.Get(ctx, nn, s); err != nil {
-		return nil, errors.Wrap(err, errGetConnectionSecret)
-	}
-
-	endpoint, err := util.GetEndpoint(cr.DeepCopyObject(), region)
-	if err != nil {
-		return nil, err
+func describeFileSystem(ctx context.Context, c interface{}, mg resource.Managed) (interface{}, error) {
+	cr := mg.(*v1alpha1.NASFileSystem)
+	if meta.GetExternalName(cr) == "" {
+		return nil, adapter.ErrNotFound
 	}
+	fsID := cr.Status.AtProvider.FileSystemID
+	filesystem, err := c.(nasclient.ClientInterface).DescribeFileSystems(ctx, &fsID, cr.Spec.FileSystemType, cr.Spec.VpcID)
+	return filesystem, errors.Wrap(err, errFailedToDescribeNASFileSystem)
+}
 
-	client, err := c.NewClientFn(ctx, endpoint, string(s.Data[util.AccessKeyID]), string(s.Data[util.AccessKeySecret]), string(s.Data[util.SecurityToken]))
-	return &External{ExternalClient: client}, errors.Wrap(err, errCreateClient)
+func observeFileSystem(mg resource.Managed, observed interface{}) {
+	cr := mg.(*v1alpha1.NASFileSystem)
+	fsID := cr.Status.AtProvider.FileSystemID
+	cr.Status.AtProvider = nasclient.GenerateObservation(&fsID, observed.(*sdk.DescribeFileSystemsResponse))
 }
 
-// External includes external NAS client
-type External struct {
-	ExternalClient nasclient.ClientInterface
+func isFileSystemUpToDate(mg resource.Managed, observed interface{}) bool {
+	return nasclient.IsUpdateToDate(mg.(*v1alpha1.NASFileSystem), observed.(*sdk.DescribeFileSystemsResponse))
 }
 
-// Observe managed resource NAS filesystem
-func (e *External) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
-	cr, ok := mg.(*v1alpha1.NASFileSystem)
-	if !ok {
-		return managed.ExternalObservation{}, errors.New(errNotNASFileSystem)
+// isFileSystemInProgress returns true while the file system is being created
+// or extended.
+func isFileSystemInProgress(observed interface{}) bool {
+	res := observed.(*sdk.DescribeFileSystemsResponse)
+	if res.Body == nil || res.Body.FileSystems == nil || len(res.Body.FileSystems.FileSystem) == 0 {
+		return false
 	}
+	switch tea.StringValue(res.Body.FileSystems.FileSystem[0].Status) {
+	case fileSystemStatusPending, fileSystemStatusExtending:
+		return true
+	}
+	return false
+}
 
+func getFileSystemObservedConnectionDetails(ctx context.Context, _ interface{}, mg resource.Managed, _ interface{}) (managed.ConnectionDetails, error) {
+	cr := mg.(*v1alpha1.NASFileSystem)
 	fsID := cr.Status.AtProvider.FileSystemID
-	if meta.GetExternalName(mg) == "" {
-		return managed.ExternalObservation{
-			ResourceExists: false,
-		}, nil
-	}
+	return GetConnectionDetails(&fsID, cr), nil
+}
 
-	filesystem, err := e.ExternalClient.DescribeFileSystems(&fsID, cr.Spec.FileSystemType, cr.Spec.VpcID)
-	if err != nil {
-		// Managed resource `NASFileSystem` is special, the identifier of if `name` is different to the cloud resource identifier `FileSystemID`
-		if nasclient.IsNotFoundError(err) {
-			return managed.ExternalObservation{ResourceExists: false, ResourceUpToDate: true}, nil
-		}
-		return managed.ExternalObservation{ResourceExists: false}, errors.Wrap(err, errFailedToDescribeNASFileSystem)
+// checkFileSystemAvailable returns a terminal error if the storage type and
+// protocol of the supplied General-purpose NASFileSystem are not available in
+// any zone of the region.
+func checkFileSystemAvailable(ctx context.Context, c interface{}, mg resource.Managed, region string) error {
+	p := mg.(*v1alpha1.NASFileSystem).Spec.NASFileSystemParameter
+	if t := tea.StringValue(p.FileSystemType); t != "" && t != nasclient.FileSystemTypeStandard {
+		return nil
 	}
-
-	cr.Status.AtProvider = nasclient.GenerateObservation(&fsID, filesystem)
-	var upToDate = nasclient.IsUpdateToDate(cr, filesystem)
-	if upToDate {
-		cr.SetConditions(xpv1.Available())
+	if p.StorageType == nil || p.ProtocolType == nil {
+		return nil
 	}
-
-	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  upToDate,
-		ConnectionDetails: GetConnectionDetails(&fsID, cr),
-	}, nil
+	a, err := c.(nasclient.ClientInterface).DescribeZones(ctx, region)
+	if err != nil {
+		return errors.Wrap(err, errDescribeZones)
+	}
+	return a.Check(offeringFileSystem, nasclient.Offering(*p.StorageType, *p.ProtocolType), region, "")
 }
 
-// Create managed resource NASFilesystem
-func (e *External) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
-	cr, ok := mg.(*v1alpha1.NASFileSystem)
-	if !ok {
-		return managed.ExternalCreation{}, errors.New(errNotNASFileSystem)
-	}
-	cr.SetConditions(xpv1.Creating())
+func createFileSystem(ctx context.Context, c interface{}, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr := mg.(*v1alpha1.NASFileSystem)
+	nas := c.(nasclient.ClientInterface)
 	filesystemParameter := v1alpha1.NASFileSystemParameter{
 		FileSystemType: cr.Spec.FileSystemType,
 		ChargeType:     cr.Spec.ChargeType,
@@ -177,11 +164,11 @@ func (e *External) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		VpcID:          cr.Spec.VpcID,
 		VSwitchID:      cr.Spec.VSwitchID,
 	}
-	res, err := e.ExternalClient.CreateFileSystem(filesystemParameter)
+	res, err := nas.CreateFileSystem(ctx, filesystemParameter)
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errFailedToCreateNASFileSystem)
 	}
-	fsRes, err := e.ExternalClient.DescribeFileSystems(res.Body.FileSystemId, cr.Spec.FileSystemType, cr.Spec.VpcID)
+	fsRes, err := nas.DescribeFileSystems(ctx, res.Body.FileSystemId, cr.Spec.FileSystemType, cr.Spec.VpcID)
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errFailedToDescribeNASFileSystem)
 	}
@@ -189,22 +176,30 @@ func (e *External) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	return managed.ExternalCreation{ConnectionDetails: GetConnectionDetails(res.Body.FileSystemId, cr)}, nil
 }
 
-// Update managed resource NASFilesystem
-func (e *External) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	return managed.ExternalUpdate{}, nil
+func deleteFileSystem(ctx context.Context, c interface{}, mg resource.Managed) error {
+	cr := mg.(*v1alpha1.NASFileSystem)
+	err := c.(nasclient.ClientInterface).DeleteFileSystem(ctx, cr.Status.AtProvider.FileSystemID)
+	return errors.Wrap(err, errFailedToDeleteNASFileSystem)
 }
 
-// Delete managed resource NASFilesystem
-func (e *External) Delete(ctx context.Context, mg resource.Managed) error {
-	cr, ok := mg.(*v1alpha1.NASFileSystem)
-	if !ok {
-		return errors.New(errNotNASFileSystem)
+// getFileSystemDependents lists the NASMountTargets that are attached to the
+// supplied file system and therefore have to be deleted before it.
+func getFileSystemDependents(ctx context.Context, c client.Reader, mg resource.Managed) ([]util.Dependent, error) {
+	fileSystemID := mg.(*v1alpha1.NASFileSystem).Status.AtProvider.FileSystemID
+	if fileSystemID == "" {
+		return nil, nil
+	}
+	mts := &v1alpha1.NASMountTargetList{}
+	if err := c.List(ctx, mts); err != nil {
+		return nil, err
 	}
-	cr.SetConditions(xpv1.Deleting())
-	if err := e.ExternalClient.DeleteFileSystem(cr.Status.AtProvider.FileSystemID); err != nil {
-		return errors.Wrap(err, errFailedToDeleteNASFileSystem)
+	var dependents []util.Dependent
+	for i := range mts.Items {
+		if id := mts.Items[i].Spec.ForProvider.FileSystemID; id != nil && *id == fileSystemID {
+			dependents = append(dependents, util.Dependent{Kind: v1alpha1.NASMountTargetKind, Managed: &mts.Items[i]})
+		}
 	}
-	return nil
+	return dependents, nil
 }
 
 // GetConnectionDetails generates connection details
@@ -28,15 +28,19 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/provider-alibaba/apis/nas/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
 )
 
 type fakeSDKClient struct {
 }
 
-func (c *fakeSDKClient) DescribeFileSystems(fileSystemID, fileSystemType, vpcID *string) (*sdk.DescribeFileSystemsResponse, error) {
+func (c *fakeSDKClient) DescribeFileSystems(ctx context.Context, fileSystemID, fileSystemType, vpcID *string) (*sdk.DescribeFileSystemsResponse, error) {
 	switch *fileSystemID {
 	case "123":
 		return nil, errors.New("unknown error")
@@ -51,15 +55,19 @@ func (c *fakeSDKClient) DescribeFileSystems(fileSystemID, fileSystemType, vpcID
 	}
 }
 
-func (c *fakeSDKClient) CreateFileSystem(fs v1alpha1.NASFileSystemParameter) (*sdk.CreateFileSystemResponse, error) {
+func (c *fakeSDKClient) CreateFileSystem(ctx context.Context, fs v1alpha1.NASFileSystemParameter) (*sdk.CreateFileSystemResponse, error) {
 	res := &sdk.CreateFileSystemResponse{Body: &sdk.CreateFileSystemResponseBody{FileSystemId: pointer.StringPtr("123456")}}
 	return res, nil
 }
 
-func (c *fakeSDKClient) DeleteFileSystem(fileSystemID string) error {
+func (c *fakeSDKClient) DeleteFileSystem(ctx context.Context, fileSystemID string) error {
 	return nil
 }
 
+func (c *fakeSDKClient) DescribeZones(ctx context.Context, region string) (clients.Availability, error) {
+	return nil, errors.New("DescribeZones: client doesn't work")
+}
+
 func TestObserve(t *testing.T) {
 	var ctx = context.Background()
 
@@ -82,14 +90,6 @@ func TestObserve(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotAnNASFileSystem": {
-			reason: "We should return an error if the supplied managed resource is not NASFileSystem",
-			mg:     nil,
-			want: want{
-				o:   managed.ExternalObservation{},
-				err: errors.New(errNotNASFileSystem),
-			},
-		},
 		"NASFileSystemNotFound": {
 			reason: "We should report a NotFound error",
 			mg:     &v1alpha1.NASFileSystem{},
@@ -123,7 +123,7 @@ func TestObserve(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			external := &External{ExternalClient: &fakeSDKClient{}}
+			external := adapter.NewExternalClient(fileSystemKind, nil, &fakeSDKClient{})
 			got, err := external.Observe(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -153,14 +153,6 @@ func TestCreate(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotAnNASFileSystem": {
-			reason: "Not NASFileSystem object",
-			mg:     nil,
-			want: want{
-				o:   managed.ExternalCreation{},
-				err: errors.New(errNotNASFileSystem),
-			},
-		},
 		"Success": {
 			reason: "Creating NASFileSystem successfully",
 			mg:     validCR,
@@ -174,7 +166,7 @@ func TestCreate(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			external := &External{ExternalClient: &fakeSDKClient{}}
+			external := adapter.NewExternalClient(fileSystemKind, nil, &fakeSDKClient{})
 			got, err := external.Create(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -198,20 +190,35 @@ func TestDelete(t *testing.T) {
 		err error
 	}
 
+	blockedCR := validCR.DeepCopy()
+	blockedCR.Status.AtProvider.FileSystemID = "456"
+
+	withMountTarget := test.NewMockListFn(nil, func(obj runtime.Object) error {
+		if l, ok := obj.(*v1alpha1.NASMountTargetList); ok {
+			mt := v1alpha1.NASMountTarget{ObjectMeta: metav1.ObjectMeta{Name: "mt"}}
+			mt.Spec.ForProvider.FileSystemID = pointer.StringPtr("456")
+			l.Items = []v1alpha1.NASMountTarget{mt}
+		}
+		return nil
+	})
+
 	cases := map[string]struct {
 		reason string
+		kube   client.Client
 		mg     resource.Managed
 		want   want
 	}{
-		"NotNASFileSystem": {
-			reason: "Not NASFileSystem object",
-			mg:     nil,
+		"WaitForDependents": {
+			reason: "Deleting a NASFileSystem with NASMountTargets should wait for them",
+			kube:   &test.MockClient{MockList: withMountTarget},
+			mg:     blockedCR.DeepCopy(),
 			want: want{
-				err: errors.New(errNotNASFileSystem),
+				err: nil,
 			},
 		},
 		"Success": {
 			reason: "Deleting NASFileSystem successfully",
+			kube:   &test.MockClient{MockList: test.NewMockListFn(nil)},
 			mg:     validCR,
 			want: want{
 				err: nil,
@@ -221,7 +228,7 @@ func TestDelete(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			external := &External{ExternalClient: &fakeSDKClient{}}
+			external := adapter.NewExternalClient(fileSystemKind, tc.kube, &fakeSDKClient{})
 			err := external.Delete(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
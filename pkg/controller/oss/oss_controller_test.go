@@ -30,12 +30,13 @@ import (
 
 	ossv1alpha1 "github.com/crossplane/provider-alibaba/apis/oss/v1alpha1"
 	ossclient "github.com/crossplane/provider-alibaba/pkg/clients/oss"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
 )
 
 type fakeSDKClient struct {
 }
 
-func (c *fakeSDKClient) Describe(name string) (*sdk.GetBucketInfoResult, error) {
+func (c *fakeSDKClient) Describe(ctx context.Context, name string) (*sdk.GetBucketInfoResult, error) {
 	switch name {
 	case "":
 		return nil, sdk.ServiceError{Code: ossclient.ErrCodeNoSuchBucket}
@@ -53,11 +54,22 @@ func (c *fakeSDKClient) Describe(name string) (*sdk.GetBucketInfoResult, error)
 	}
 }
 
-func (c *fakeSDKClient) Create(name string, bucket ossv1alpha1.BucketParameter) error {
+func (c *fakeSDKClient) List(ctx context.Context, prefix string) ([]sdk.BucketProperties, error) {
+	return []sdk.BucketProperties{
+		{Name: "def", Location: "oss-cn-beijing"},
+		{Name: "ghi", Location: "oss-cn-hangzhou"},
+	}, nil
+}
+
+func (c *fakeSDKClient) DescribeTags(ctx context.Context, name string) (map[string]string, error) {
+	return map[string]string{"team": name}, nil
+}
+
+func (c *fakeSDKClient) Create(ctx context.Context, name string, bucket ossv1alpha1.BucketParameter) error {
 	return nil
 }
 
-func (c *fakeSDKClient) Update(name string, aclStr string) error {
+func (c *fakeSDKClient) Update(ctx context.Context, name string, aclStr string) error {
 	_, err := ossclient.ValidateOSSAcl(aclStr)
 	if err != nil {
 		return err
@@ -65,7 +77,7 @@ func (c *fakeSDKClient) Update(name string, aclStr string) error {
 	return nil
 }
 
-func (c *fakeSDKClient) Delete(name string) error {
+func (c *fakeSDKClient) Delete(ctx context.Context, name string) error {
 	return nil
 }
 
@@ -88,14 +100,6 @@ func TestObserve(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotAnOSS": {
-			reason: "We should return an error if the supplied managed resource is not an Bucket bucket",
-			mg:     nil,
-			want: want{
-				o:   managed.ExternalObservation{},
-				err: errors.New(errNotBucket),
-			},
-		},
 		"OSSNotFound": {
 			reason: "We should report a NotFound error",
 			mg:     &ossv1alpha1.Bucket{},
@@ -129,7 +133,7 @@ func TestObserve(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			external := &External{ExternalClient: &fakeSDKClient{}}
+			external := adapter.NewExternalClient(bucketKind, nil, &fakeSDKClient{})
 			got, err := external.Observe(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -158,14 +162,6 @@ func TestCreate(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotAnOSS": {
-			reason: "Not an Bucket object",
-			mg:     nil,
-			want: want{
-				o:   managed.ExternalCreation{},
-				err: errors.New(errNotBucket),
-			},
-		},
 		"Success": {
 			reason: "Creating an Bucket bucket successfully",
 			mg:     validCR,
@@ -179,7 +175,7 @@ func TestCreate(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			external := &External{ExternalClient: &fakeSDKClient{}}
+			external := adapter.NewExternalClient(bucketKind, nil, &fakeSDKClient{})
 			got, err := external.Create(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -208,14 +204,6 @@ func TestUpdate(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotAnOSS": {
-			reason: "Not an Bucket object",
-			mg:     nil,
-			want: want{
-				o:   managed.ExternalUpdate{},
-				err: errors.New(errNotBucket),
-			},
-		},
 		"Success": {
 			reason: "Creating an Bucket bucket successfully",
 			mg:     validCR,
@@ -228,7 +216,7 @@ func TestUpdate(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			external := &External{ExternalClient: &fakeSDKClient{}}
+			external := adapter.NewExternalClient(bucketKind, nil, &fakeSDKClient{})
 			got, err := external.Update(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -256,13 +244,6 @@ func TestDelete(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotAnOSS": {
-			reason: "Not an Bucket object",
-			mg:     nil,
-			want: want{
-				err: errors.New(errNotBucket),
-			},
-		},
 		"Success": {
 			reason: "Creating an Bucket bucket successfully",
 			mg:     validCR,
@@ -274,7 +255,7 @@ func TestDelete(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			external := &External{ExternalClient: &fakeSDKClient{}}
+			external := adapter.NewExternalClient(bucketKind, nil, &fakeSDKClient{})
 			err := external.Delete(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -282,3 +263,56 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestDiscover(t *testing.T) {
+	var ctx = context.Background()
+
+	bucket := func(name string) *ossv1alpha1.Bucket {
+		cr := &ossv1alpha1.Bucket{}
+		cr.SetName(name)
+		meta.SetExternalName(cr, name)
+		cr.Spec.BucketParameter = ossv1alpha1.BucketParameter{ACL: "private", StorageClass: "Standard"}
+		return cr
+	}
+
+	type want struct {
+		mgs []resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		f      adapter.Filter
+		want   want
+	}{
+		"OtherRegion": {
+			reason: "Buckets of other regions should not be discovered",
+			f:      adapter.Filter{Region: "cn-shanghai"},
+			want:   want{},
+		},
+		"TagMismatch": {
+			reason: "Buckets without the filtered tags should not be discovered",
+			f:      adapter.Filter{Region: "cn-beijing", Tags: map[string]string{"team": "abc"}},
+			want:   want{},
+		},
+		"Success": {
+			reason: "Buckets of the filtered region and tags should be discovered",
+			f:      adapter.Filter{Region: "cn-beijing", Tags: map[string]string{"team": "def"}},
+			want: want{
+				mgs: []resource.Managed{bucket("def")},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := discoverBuckets(ctx, &fakeSDKClient{}, tc.f)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ndiscoverBuckets(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.mgs, got); diff != "" {
+				t.Errorf("\n%s\ndiscoverBuckets(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
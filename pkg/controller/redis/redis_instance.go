@@ -22,14 +22,10 @@ import (
 	"strconv"
 
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	sdkerror "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
-	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/password"
@@ -38,164 +34,163 @@ import (
 
 	"github.com/crossplane/provider-alibaba/apis/redis/v1alpha1"
 	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients/bss"
 	"github.com/crossplane/provider-alibaba/pkg/clients/redis"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
+	"github.com/crossplane/provider-alibaba/pkg/util"
 )
 
 const (
 	// Fall to connection instance error description
 	errCreateInstanceConnectionFailed = "cannot instance connection"
 
-	errNotInstance         = "managed resource is not an instance custom resource"
-	errNoProvider          = "no provider config or provider specified"
-	errCreateClient        = "cannot create redis client"
-	errGetProviderConfig   = "cannot get provider config"
-	errTrackUsage          = "cannot track provider config usage"
-	errNoConnectionSecret  = "no connection secret specified"
-	errGetConnectionSecret = "cannot get connection secret"
-
 	errCreateFailed        = "cannot create redis instance"
 	errCreateAccountFailed = "cannot create redis account"
+	errUpdateFailed        = "cannot update redis instance"
 	errDeleteFailed        = "cannot delete redis instance"
 	errDescribeFailed      = "cannot describe redis instance"
+	errListFailed          = "cannot list redis instances"
+
+	errDescribeAvailableClasses = "cannot describe available redis instance classes"
+
+	fmtInstanceClass = "%s %s instance class"
 
-	errFmtUnsupportedCredSource = "credentials source %q is not currently supported"
-	errDuplicateConnectionPort  = "InvalidConnectionStringOrPort.Duplicate"
-	errAccountNameDuplicate     = "InvalidAccountName.Duplicate"
+	errDuplicateConnectionPort = "InvalidConnectionStringOrPort.Duplicate"
+	errAccountNameDuplicate    = "InvalidAccountName.Duplicate"
 
 	// Default port of redis database
 	defaultRedisPort = "6379"
 )
 
 // SetupRedisInstance adds a controller that reconciles RedisInstances.
-func SetupRedisInstance(mgr ctrl.Manager, l logging.Logger) error {
-	name := managed.ControllerName(v1alpha1.RedisInstanceGroupKind)
-
-	return ctrl.NewControllerManagedBy(mgr).
-		Named(name).
-		For(&v1alpha1.RedisInstance{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(v1alpha1.RedisInstanceGroupVersionKind),
-			managed.WithExternalConnecter(&redisConnector{
-				client:         mgr.GetClient(),
-				usage:          resource.NewProviderConfigUsageTracker(mgr.GetClient(), &aliv1alpha1.ProviderConfigUsage{}),
-				newRedisClient: redis.NewClient,
-			}),
-			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+func SetupRedisInstance(mgr ctrl.Manager, l logging.Logger, o adapter.Options) error {
+	return adapter.Setup(mgr, l, redisInstanceKind, o)
 }
 
-type redisConnector struct {
-	client         client.Client
-	usage          resource.Tracker
-	newRedisClient func(ctx context.Context, accessKeyID, accessKeySecret, region string) (redis.Client, error)
+// RedisInstanceKind returns the adapter.Kind of RedisInstances.
+func RedisInstanceKind() adapter.Kind {
+	return redisInstanceKind
 }
 
-func (c *redisConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) { //nolint:gocyclo
-	// account for the deprecated Provider type.
-	cr, ok := mg.(*v1alpha1.RedisInstance)
-	if !ok {
-		return nil, errors.New(errNotInstance)
-	}
-
-	// provider has more than one kind of managed resource.
-	var (
-		sel    *xpv1.SecretKeySelector
-		region string
-	)
-	switch {
-	case cr.GetProviderConfigReference() != nil:
-		if err := c.usage.Track(ctx, mg); err != nil {
-			return nil, errors.Wrap(err, errTrackUsage)
-		}
+var redisInstanceKind = adapter.Kind{
+	Type:              &v1alpha1.RedisInstance{},
+	GroupVersionKind:  v1alpha1.RedisInstanceGroupVersionKind,
+	NewClient:         newRedisClient,
+	Describe:          describeRedisInstance,
+	IsNotFound:        redis.IsErrorNotFound,
+	Observe:           observeRedisInstance,
+	IsUpToDate:        isRedisInstanceUpToDate,
+	Condition:         getRedisInstanceCondition,
+	ConnectionDetails: getRedisInstanceConnectionDetails,
+	Create:            createRedisInstance,
+	Update:            updateRedisInstance,
+	Delete:            deleteRedisInstance,
+	Discover:          discoverRedisInstances,
+	Parameters:        redisInstanceParameters,
+	CloudID: func(mg resource.Managed) string {
+		return mg.(*v1alpha1.RedisInstance).Status.AtProvider.DBInstanceID
+	},
+	Preflight: &adapter.Preflight{
+		Check:            checkRedisInstanceAvailable,
+		QuotaProductCode: redis.QuotaProductCode,
+	},
+	Pricing: &adapter.Pricing{
+		Request: func(mg resource.Managed, region string) bss.PriceRequest {
+			return redis.MakePriceRequest(&mg.(*v1alpha1.RedisInstance).Spec.ForProvider, region)
+		},
+		Estimate: func(mg resource.Managed) *aliv1alpha1.CostEstimate {
+			return mg.(*v1alpha1.RedisInstance).Status.AtProvider.CostEstimate
+		},
+		SetEstimate: func(mg resource.Managed, e *aliv1alpha1.CostEstimate) {
+			mg.(*v1alpha1.RedisInstance).Status.AtProvider.CostEstimate = e
+		},
+	},
+	Operations: &adapter.Operations{
+		Pending: func(mg resource.Managed) *aliv1alpha1.PendingOperation {
+			return mg.(*v1alpha1.RedisInstance).Status.AtProvider.PendingOperation
+		},
+		SetPending: func(mg resource.Managed, op *aliv1alpha1.PendingOperation) {
+			mg.(*v1alpha1.RedisInstance).Status.AtProvider.PendingOperation = op
+		},
+		InProgress: func(observed interface{}) bool {
+			return observed.(*redis.DBInstance).Status != v1alpha1.RedisInstanceStateRunning
+		},
+	},
+}
 
-		pc := &aliv1alpha1.ProviderConfig{}
-		if err := c.client.Get(ctx, types.NamespacedName{Name: cr.Spec.ProviderConfigReference.Name}, pc); err != nil {
-			return nil, errors.Wrap(err, errGetProviderConfig)
-		}
-		if s := pc.Spec.Credentials.Source; s != xpv1.CredentialsSourceSecret {
-			return nil, errors.Errorf(errFmtUnsupportedCredSource, s)
-		}
-		sel = pc.Spec.Credentials.SecretRef
-		region = pc.Spec.Region
-	default:
-		return nil, errors.New(errNoProvider)
-	}
+func newRedisClient(ctx context.Context, _ resource.Managed, creds util.Credentials) (interface{}, error) {
+	return redis.NewClient(ctx, creds.AccessKeyID, creds.AccessKeySecret, creds.Region)
+}
 
-	if sel == nil {
-		return nil, errors.New(errNoConnectionSecret)
+func describeRedisInstance(ctx context.Context, c interface{}, mg resource.Managed) (interface{}, error) {
+	cr := mg.(*v1alpha1.RedisInstance)
+	// Imported instances are identified by their external name until the
+	// instance ID has been observed.
+	id := cr.Status.AtProvider.DBInstanceID
+	if id == "" {
+		id = meta.GetExternalName(cr)
 	}
-
-	s := &corev1.Secret{}
-	nn := types.NamespacedName{Namespace: sel.Namespace, Name: sel.Name}
-	if err := c.client.Get(ctx, nn, s); err != nil {
-		return nil, errors.Wrap(err, errGetConnectionSecret)
+	if id == "" {
+		return nil, adapter.ErrNotFound
 	}
-
-	redisClient, err := c.newRedisClient(ctx, string(s.Data["accessKeyId"]), string(s.Data["accessKeySecret"]), region)
-	return &external{client: redisClient}, errors.Wrap(err, errCreateClient)
+	instance, err := c.(redis.Client).DescribeDBInstance(ctx, id)
+	return instance, errors.Wrap(err, errDescribeFailed)
 }
 
-type external struct {
-	client redis.Client
+func observeRedisInstance(mg resource.Managed, observed interface{}) {
+	mg.(*v1alpha1.RedisInstance).Status.AtProvider = redis.GenerateObservation(observed.(*redis.DBInstance))
 }
 
-func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
-	cr, ok := mg.(*v1alpha1.RedisInstance)
-	if !ok {
-		return managed.ExternalObservation{}, errors.New(errNotInstance)
-	}
-
-	if cr.Status.AtProvider.DBInstanceID == "" {
-		return managed.ExternalObservation{}, nil
-	}
+func isRedisInstanceUpToDate(mg resource.Managed, observed interface{}) bool {
+	return redis.IsUpToDate(&mg.(*v1alpha1.RedisInstance).Spec.ForProvider, observed.(*redis.DBInstance))
+}
 
-	instance, err := e.client.DescribeDBInstance(cr.Status.AtProvider.DBInstanceID)
-	if err != nil {
-		fmt.Print(err.Error(), resource.Ignore(redis.IsErrorNotFound, err))
-		return managed.ExternalObservation{}, errors.Wrap(resource.Ignore(redis.IsErrorNotFound, err), errDescribeFailed)
+func getRedisInstanceCondition(mg resource.Managed, _ interface{}) xpv1.Condition {
+	switch mg.(*v1alpha1.RedisInstance).Status.AtProvider.DBInstanceStatus {
+	case v1alpha1.RedisInstanceStateRunning:
+		return xpv1.Available()
+	case v1alpha1.RedisInstanceStateCreating:
+		return xpv1.Creating()
+	case v1alpha1.RedisInstanceStateDeleting:
+		return xpv1.Deleting()
+	default:
+		return xpv1.Unavailable()
 	}
+}
 
-	cr.Status.AtProvider = redis.GenerateObservation(instance)
+// getRedisInstanceConnectionDetails creates the connection and the account
+// once the instance is running, and returns them as connection details.
+func getRedisInstanceConnectionDetails(ctx context.Context, c interface{}, mg resource.Managed, observed interface{}) (managed.ConnectionDetails, error) {
+	cr := mg.(*v1alpha1.RedisInstance)
+	client := c.(redis.Client)
+	instance := observed.(*redis.DBInstance)
 	var pw string
-	switch cr.Status.AtProvider.DBInstanceStatus {
-	case v1alpha1.RedisInstanceStateRunning:
-		cr.Status.SetConditions(xpv1.Available())
-		address, port, err := e.createConnectionIfNeeded(cr)
+	if cr.Status.AtProvider.DBInstanceStatus == v1alpha1.RedisInstanceStateRunning {
+		address, port, err := createConnectionIfNeeded(ctx, client, cr)
 		if err != nil {
-			return managed.ExternalObservation{}, errors.Wrap(err, errCreateInstanceConnectionFailed)
+			return nil, errors.Wrap(err, errCreateInstanceConnectionFailed)
 		}
 		instance.Endpoint = &v1alpha1.Endpoint{
 			Address: address,
 			Port:    port,
 		}
 
-		pw, err = e.createAccountIfNeeded(cr)
+		pw, err = createAccountIfNeeded(ctx, client, cr)
 		if err != nil {
-			return managed.ExternalObservation{}, errors.Wrap(err, errCreateAccountFailed)
+			return nil, errors.Wrap(err, errCreateAccountFailed)
 		}
-	case v1alpha1.RedisInstanceStateCreating:
-		cr.Status.SetConditions(xpv1.Creating())
-	case v1alpha1.RedisInstanceStateDeleting:
-		cr.Status.SetConditions(xpv1.Deleting())
-	default:
-		cr.Status.SetConditions(xpv1.Unavailable())
 	}
-
-	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  true,
-		ConnectionDetails: getConnectionDetails(pw, cr, instance),
-	}, nil
+	return getConnectionDetails(pw, cr, instance), nil
 }
 
-func (e *external) createConnectionIfNeeded(cr *v1alpha1.RedisInstance) (string, string, error) {
+func createConnectionIfNeeded(ctx context.Context, client redis.Client, cr *v1alpha1.RedisInstance) (string, string, error) {
 	if cr.Spec.ForProvider.PubliclyAccessible {
-		return e.createPublicConnectionIfNeeded(cr)
+		return createPublicConnectionIfNeeded(ctx, client, cr)
 	}
-	return e.createPrivateConnectionIfNeeded(cr)
+	return createPrivateConnectionIfNeeded(ctx, client, cr)
 }
 
-func (e *external) createPrivateConnectionIfNeeded(cr *v1alpha1.RedisInstance) (string, string, error) {
+func createPrivateConnectionIfNeeded(ctx context.Context, client redis.Client, cr *v1alpha1.RedisInstance) (string, string, error) {
 	domain := cr.Status.AtProvider.DBInstanceID + ".redis.rds.aliyuncs.com"
 	if cr.Spec.ForProvider.InstancePort == 0 {
 		return domain, defaultRedisPort, nil
@@ -204,7 +199,7 @@ func (e *external) createPrivateConnectionIfNeeded(cr *v1alpha1.RedisInstance) (
 	if cr.Status.AtProvider.ConnectionReady {
 		return domain, port, nil
 	}
-	connectionDomain, err := e.client.ModifyDBInstanceConnectionString(cr.Status.AtProvider.DBInstanceID, cr.Spec.ForProvider.InstancePort)
+	connectionDomain, err := client.ModifyDBInstanceConnectionString(ctx, cr.Status.AtProvider.DBInstanceID, cr.Spec.ForProvider.InstancePort)
 	if err != nil {
 		// The previous request might fail due to timeout. That's fine we will eventually reconcile it.
 		if sdkErr, ok := err.(sdkerror.Error); ok {
@@ -220,7 +215,7 @@ func (e *external) createPrivateConnectionIfNeeded(cr *v1alpha1.RedisInstance) (
 	return connectionDomain, port, nil
 }
 
-func (e *external) createPublicConnectionIfNeeded(cr *v1alpha1.RedisInstance) (string, string, error) {
+func createPublicConnectionIfNeeded(ctx context.Context, client redis.Client, cr *v1alpha1.RedisInstance) (string, string, error) {
 	domain := cr.Status.AtProvider.DBInstanceID + redis.PubilConnectionDomain
 	if cr.Status.AtProvider.ConnectionReady {
 		return domain, "", nil
@@ -229,7 +224,7 @@ func (e *external) createPublicConnectionIfNeeded(cr *v1alpha1.RedisInstance) (s
 	if cr.Spec.ForProvider.InstancePort != 0 {
 		port = strconv.Itoa(cr.Spec.ForProvider.InstancePort)
 	}
-	_, err := e.client.AllocateInstancePublicConnection(cr.Status.AtProvider.DBInstanceID, cr.Spec.ForProvider.InstancePort)
+	_, err := client.AllocateInstancePublicConnection(ctx, cr.Status.AtProvider.DBInstanceID, cr.Spec.ForProvider.InstancePort)
 	if err != nil {
 		// The previous request might fail due to timeout. That's fine we will eventually reconcile it.
 		if sdkErr, ok := err.(sdkerror.Error); ok {
@@ -245,7 +240,7 @@ func (e *external) createPublicConnectionIfNeeded(cr *v1alpha1.RedisInstance) (s
 	return domain, port, nil
 }
 
-func (e *external) createAccountIfNeeded(cr *v1alpha1.RedisInstance) (string, error) {
+func createAccountIfNeeded(ctx context.Context, client redis.Client, cr *v1alpha1.RedisInstance) (string, error) {
 	if cr.Status.AtProvider.AccountReady {
 		return "", nil
 	}
@@ -259,7 +254,7 @@ func (e *external) createAccountIfNeeded(cr *v1alpha1.RedisInstance) (string, er
 		return pw, nil
 	}
 
-	err = e.client.CreateAccount(cr.Status.AtProvider.DBInstanceID, cr.Spec.ForProvider.MasterUsername, pw)
+	err = client.CreateAccount(ctx, cr.Status.AtProvider.DBInstanceID, cr.Spec.ForProvider.MasterUsername, pw)
 	if err != nil {
 		// The previous request might fail due to timeout. That's fine we will eventually reconcile it.
 		if sdkErr, ok := err.(sdkerror.Error); ok {
@@ -275,19 +270,25 @@ func (e *external) createAccountIfNeeded(cr *v1alpha1.RedisInstance) (string, er
 	return pw, nil
 }
 
-func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
-	cr, ok := mg.(*v1alpha1.RedisInstance)
-	if !ok {
-		return managed.ExternalCreation{}, errors.New(errNotInstance)
+// checkRedisInstanceAvailable returns a terminal error if the instance class
+// of the supplied RedisInstance is not available in any zone of the region.
+func checkRedisInstanceAvailable(ctx context.Context, c interface{}, mg resource.Managed, region string) error {
+	p := mg.(*v1alpha1.RedisInstance).Spec.ForProvider
+	a, err := c.(redis.Client).DescribeAvailableClasses(ctx, p.InstanceType, p.EngineVersion, p.ChargeType)
+	if err != nil {
+		return errors.Wrap(err, errDescribeAvailableClasses)
 	}
+	return a.Check(fmt.Sprintf(fmtInstanceClass, p.InstanceType, p.EngineVersion), p.InstanceClass, region, "")
+}
 
-	cr.SetConditions(xpv1.Creating())
+func createRedisInstance(ctx context.Context, c interface{}, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr := mg.(*v1alpha1.RedisInstance)
 	if cr.Status.AtProvider.DBInstanceStatus == v1alpha1.RedisInstanceStateCreating {
 		return managed.ExternalCreation{}, nil
 	}
 
 	req := redis.MakeCreateDBInstanceRequest(meta.GetExternalName(cr), &cr.Spec.ForProvider)
-	instance, err := e.client.CreateDBInstance(req)
+	instance, err := c.(redis.Client).CreateDBInstance(ctx, req)
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreateFailed)
 	}
@@ -299,33 +300,48 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	return managed.ExternalCreation{ConnectionDetails: getConnectionDetails("", cr, instance)}, nil
 }
 
-func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	cr, ok := mg.(*v1alpha1.RedisInstance)
-	if !ok {
-		return managed.ExternalUpdate{}, errors.New(errNotInstance)
-	}
-	name := meta.GetExternalName(cr)
-	description := cr.Spec.ForProvider
+// updateRedisInstance changes the instance class of the instance to that of
+// its spec.
+func updateRedisInstance(ctx context.Context, c interface{}, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr := mg.(*v1alpha1.RedisInstance)
 	modifyReq := &redis.ModifyRedisInstanceRequest{
-		InstanceClass: description.InstanceClass,
+		InstanceClass: cr.Spec.ForProvider.InstanceClass,
 	}
-	cr.Status.SetConditions(xpv1.Creating())
-	err := e.client.Update(name, modifyReq)
-	return managed.ExternalUpdate{}, err
+	err := c.(redis.Client).Update(ctx, cr.Status.AtProvider.DBInstanceID, modifyReq)
+	return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateFailed)
 }
 
-func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
-	cr, ok := mg.(*v1alpha1.RedisInstance)
-	if !ok {
-		return errors.New(errNotInstance)
-	}
-	cr.SetConditions(xpv1.Deleting())
+func deleteRedisInstance(ctx context.Context, c interface{}, mg resource.Managed) error {
+	cr := mg.(*v1alpha1.RedisInstance)
 	if cr.Status.AtProvider.DBInstanceStatus == v1alpha1.RedisInstanceStateDeleting {
 		return nil
 	}
 
-	err := e.client.DeleteDBInstance(cr.Status.AtProvider.DBInstanceID)
-	return errors.Wrap(resource.Ignore(redis.IsErrorNotFound, err), errDeleteFailed)
+	err := c.(redis.Client).DeleteDBInstance(ctx, cr.Status.AtProvider.DBInstanceID)
+	return errors.Wrap(err, errDeleteFailed)
+}
+
+func redisInstanceParameters(observed interface{}) interface{} {
+	return redis.GenerateParameters(observed.(*redis.DBInstance))
+}
+
+func discoverRedisInstances(ctx context.Context, c interface{}, f adapter.Filter) ([]resource.Managed, error) {
+	instances, err := c.(redis.Client).ListDBInstances(ctx, f.Tags)
+	if err != nil {
+		return nil, errors.Wrap(err, errListFailed)
+	}
+	var mgs []resource.Managed
+	for i := range instances {
+		if !f.MatchesName(instances[i].Name) {
+			continue
+		}
+		cr := &v1alpha1.RedisInstance{}
+		cr.SetName(instances[i].Name)
+		meta.SetExternalName(cr, instances[i].ID)
+		cr.Spec.ForProvider = redis.GenerateParameters(&instances[i])
+		mgs = append(mgs, cr)
+	}
+	return mgs, nil
 }
 
 func getConnectionDetails(password string, cr *v1alpha1.RedisInstance, instance *redis.DBInstance) managed.ConnectionDetails {
@@ -7,10 +7,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	crossplanemeta "github.com/crossplane/crossplane-runtime/pkg/meta"
@@ -19,250 +16,15 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/crossplane/provider-alibaba/apis/redis/v1alpha1"
-	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients"
 	"github.com/crossplane/provider-alibaba/pkg/clients/redis"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
 )
 
 const testName = "test"
 
-func TestConnector(t *testing.T) {
-	errBoom := errors.New("boom")
-
-	type fields struct {
-		client         client.Client
-		usage          resource.Tracker
-		newRedisClient func(ctx context.Context, accessKeyID, accessKeySecret, region string) (redis.Client, error)
-	}
-
-	type args struct {
-		ctx context.Context
-		mg  resource.Managed
-	}
-
-	cases := map[string]struct {
-		reason string
-		fields fields
-		args   args
-		want   error
-	}{
-		"NotRedisInstance": {
-			reason: "Should return an error if the supplied managed resource is not an RedisInstance",
-			args: args{
-				mg: nil,
-			},
-			want: errors.New(errNotInstance),
-		},
-		"TrackProviderConfigUsageError": {
-			reason: "Errors tracking a ProviderConfigUsage should be returned",
-			fields: fields{
-				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return errBoom }),
-			},
-			args: args{
-				mg: &v1alpha1.RedisInstance{
-					Spec: v1alpha1.RedisInstanceSpec{
-						ResourceSpec: xpv1.ResourceSpec{
-							ProviderConfigReference: &xpv1.Reference{},
-						},
-					},
-				},
-			},
-			want: errors.Wrap(errBoom, errTrackUsage),
-		},
-		"GetProviderConfigError": {
-			reason: "Errors getting a ProviderConfig should be returned",
-			fields: fields{
-				client: &test.MockClient{
-					MockGet: test.NewMockGetFn(errBoom),
-				},
-				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
-			},
-			args: args{
-				mg: &v1alpha1.RedisInstance{
-					Spec: v1alpha1.RedisInstanceSpec{
-						ResourceSpec: xpv1.ResourceSpec{
-							ProviderConfigReference: &xpv1.Reference{},
-						},
-					},
-				},
-			},
-			want: errors.Wrap(errBoom, errGetProviderConfig),
-		},
-		"UnsupportedCredentialsError": {
-			reason: "An error should be returned if the selected credentials source is unsupported",
-			fields: fields{
-				client: &test.MockClient{
-					MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
-						t := obj.(*aliv1alpha1.ProviderConfig)
-						*t = aliv1alpha1.ProviderConfig{
-							Spec: aliv1alpha1.ProviderConfigSpec{
-								ProviderConfigSpec: xpv1.ProviderConfigSpec{
-									Credentials: xpv1.ProviderCredentials{
-										Source: xpv1.CredentialsSource("wat"),
-									},
-								},
-							},
-						}
-						return nil
-					}),
-				},
-				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
-			},
-			args: args{
-				mg: &v1alpha1.RedisInstance{
-					Spec: v1alpha1.RedisInstanceSpec{
-						ResourceSpec: xpv1.ResourceSpec{
-							ProviderConfigReference: &xpv1.Reference{},
-						},
-					},
-				},
-			},
-			want: errors.Errorf(errFmtUnsupportedCredSource, "wat"),
-		},
-		"GetProviderError": {
-			reason: "Errors getting a Provider should be returned",
-			fields: fields{
-				client: &test.MockClient{
-					MockGet: test.NewMockGetFn(errBoom),
-				},
-				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
-			},
-			args: args{
-				mg: &v1alpha1.RedisInstance{
-					Spec: v1alpha1.RedisInstanceSpec{
-						ResourceSpec: xpv1.ResourceSpec{
-							ProviderReference: &xpv1.Reference{},
-						},
-					},
-				},
-			},
-			want: errors.New(errNoProvider),
-		},
-		"NoConnectionSecretError": {
-			reason: "An error should be returned if no connection secret was specified",
-			fields: fields{
-				client: &test.MockClient{
-					MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
-						t := obj.(*aliv1alpha1.ProviderConfig)
-						*t = aliv1alpha1.ProviderConfig{
-							Spec: aliv1alpha1.ProviderConfigSpec{
-								ProviderConfigSpec: xpv1.ProviderConfigSpec{
-									Credentials: xpv1.ProviderCredentials{
-										Source: xpv1.CredentialsSourceSecret,
-									},
-								},
-							},
-						}
-						return nil
-					}),
-				},
-				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
-			},
-			args: args{
-				mg: &v1alpha1.RedisInstance{
-					Spec: v1alpha1.RedisInstanceSpec{
-						ResourceSpec: xpv1.ResourceSpec{
-							ProviderConfigReference: &xpv1.Reference{},
-						},
-					},
-				},
-			},
-			want: errors.New(errNoConnectionSecret),
-		},
-		"GetConnectionSecretError": {
-			reason: "Errors getting a secret should be returned",
-			fields: fields{
-				client: &test.MockClient{
-					MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
-						switch t := obj.(type) {
-						case *corev1.Secret:
-							return errBoom
-						case *aliv1alpha1.ProviderConfig:
-							*t = aliv1alpha1.ProviderConfig{
-								Spec: aliv1alpha1.ProviderConfigSpec{
-									ProviderConfigSpec: xpv1.ProviderConfigSpec{
-										Credentials: xpv1.ProviderCredentials{
-											Source: xpv1.CredentialsSourceSecret,
-											SecretRef: &xpv1.SecretKeySelector{
-												SecretReference: xpv1.SecretReference{
-													Name: "coolsecret",
-												},
-											},
-										},
-									},
-								},
-							}
-						}
-						return nil
-					}),
-				},
-				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
-			},
-			args: args{
-				mg: &v1alpha1.RedisInstance{
-					Spec: v1alpha1.RedisInstanceSpec{
-						ResourceSpec: xpv1.ResourceSpec{
-							ProviderConfigReference: &xpv1.Reference{},
-						},
-					},
-				},
-			},
-			want: errors.Wrap(errBoom, errGetConnectionSecret),
-		},
-		"NewRedisClientError": {
-			reason: "Errors getting a secret should be returned",
-			fields: fields{
-				client: &test.MockClient{
-					MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
-						if t, ok := obj.(*aliv1alpha1.ProviderConfig); ok {
-							*t = aliv1alpha1.ProviderConfig{
-								Spec: aliv1alpha1.ProviderConfigSpec{
-									ProviderConfigSpec: xpv1.ProviderConfigSpec{
-										Credentials: xpv1.ProviderCredentials{
-											Source: xpv1.CredentialsSourceSecret,
-											SecretRef: &xpv1.SecretKeySelector{
-												SecretReference: xpv1.SecretReference{
-													Name: "coolsecret",
-												},
-											},
-										},
-									},
-								},
-							}
-						}
-						return nil
-					}),
-				},
-				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
-				newRedisClient: func(ctx context.Context, accessKeyID, accessKeySecret, region string) (redis.Client, error) {
-					return nil, errBoom
-				},
-			},
-			args: args{
-				mg: &v1alpha1.RedisInstance{
-					Spec: v1alpha1.RedisInstanceSpec{
-						ResourceSpec: xpv1.ResourceSpec{
-							ProviderConfigReference: &xpv1.Reference{},
-						},
-					},
-				},
-			},
-			want: errors.Wrap(errBoom, errCreateClient),
-		},
-	}
-
-	for name, tc := range cases {
-		t.Run(name, func(t *testing.T) {
-			c := &redisConnector{client: tc.fields.client, usage: tc.fields.usage, newRedisClient: tc.fields.newRedisClient}
-			_, err := c.Connect(tc.args.ctx, tc.args.mg)
-			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
-				t.Errorf("\n%s\nc.Connect(...) -want error, +got error:\n%s\n", tc.reason, diff)
-			}
-		})
-	}
-}
-
 func TestObserve(t *testing.T) {
-	e := &external{client: &fakeRedisClient{}}
+	e := adapter.NewExternalClient(redisInstanceKind, nil, &fakeRedisClient{})
 	type want struct {
 		ResourceExists   bool
 		ResourceUpToDate bool
@@ -327,6 +89,24 @@ func TestObserve(t *testing.T) {
 				ResourceExists: true, ResourceUpToDate: true, err: nil,
 			},
 		},
+		"InstanceClass is changed": {
+			mg: &v1alpha1.RedisInstance{
+				Spec: v1alpha1.RedisInstanceSpec{
+					ForProvider: v1alpha1.RedisInstanceParameters{
+						MasterUsername: testName,
+						InstanceClass:  "class-test",
+					},
+				},
+				Status: v1alpha1.RedisInstanceStatus{
+					AtProvider: v1alpha1.RedisInstanceObservation{
+						DBInstanceID: testName,
+					},
+				},
+			},
+			want: want{
+				ResourceExists: true, ResourceUpToDate: false, err: nil,
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -346,7 +126,7 @@ func TestObserve(t *testing.T) {
 }
 
 func TestCreate(t *testing.T) {
-	e := &external{client: &fakeRedisClient{}}
+	e := adapter.NewExternalClient(redisInstanceKind, nil, &fakeRedisClient{})
 	type want struct {
 		u   managed.ExternalCreation
 		err error
@@ -356,12 +136,6 @@ func TestCreate(t *testing.T) {
 		mg   resource.Managed
 		want want
 	}{
-		"No a valid managed resource": {
-			mg: nil,
-			want: want{
-				u: managed.ExternalCreation{}, err: errors.New(errNotInstance),
-			},
-		},
 		"DBInstanceStatus is creating": {
 			mg: &v1alpha1.RedisInstance{
 				Status: v1alpha1.RedisInstanceStatus{
@@ -418,7 +192,7 @@ func TestCreate(t *testing.T) {
 }
 
 func TestUpdate(t *testing.T) {
-	e := &external{client: &fakeRedisClient{}}
+	e := adapter.NewExternalClient(redisInstanceKind, nil, &fakeRedisClient{})
 	type want struct {
 		u   managed.ExternalUpdate
 		err error
@@ -428,22 +202,21 @@ func TestUpdate(t *testing.T) {
 		mg   resource.Managed
 		want want
 	}{
-		"No a valid managed resource": {
-			mg: nil,
-			want: want{
-				u: managed.ExternalUpdate{}, err: errors.New(errNotInstance),
-			},
-		},
 		"Successfully update a managed resource": {
 			mg: &v1alpha1.RedisInstance{
 				ObjectMeta: metav1.ObjectMeta{
-					Annotations: map[string]string{crossplanemeta.AnnotationKeyExternalName: testName},
+					Annotations: map[string]string{crossplanemeta.AnnotationKeyExternalName: "my-redis"},
 				},
 				Spec: v1alpha1.RedisInstanceSpec{
 					ForProvider: v1alpha1.RedisInstanceParameters{
 						InstanceClass: "class-test",
 					},
 				},
+				Status: v1alpha1.RedisInstanceStatus{
+					AtProvider: v1alpha1.RedisInstanceObservation{
+						DBInstanceID: testName,
+					},
+				},
 			},
 			want: want{
 				u: managed.ExternalUpdate{}, err: nil,
@@ -465,7 +238,7 @@ func TestUpdate(t *testing.T) {
 }
 
 func TestDelete(t *testing.T) {
-	e := &external{client: &fakeRedisClient{}}
+	e := adapter.NewExternalClient(redisInstanceKind, nil, &fakeRedisClient{})
 	type want struct {
 		err error
 	}
@@ -474,12 +247,6 @@ func TestDelete(t *testing.T) {
 		mg   resource.Managed
 		want want
 	}{
-		"No a valid managed resource": {
-			mg: nil,
-			want: want{
-				err: errors.New(errNotInstance),
-			},
-		},
 		"Managed resource is already in a delete state": {
 			mg: &v1alpha1.RedisInstance{
 				Status: v1alpha1.RedisInstanceStatus{
@@ -636,7 +403,7 @@ func TestGetConnectionDetails(t *testing.T) {
 
 type fakeRedisClient struct{}
 
-func (c *fakeRedisClient) DescribeDBInstance(id string) (*redis.DBInstance, error) {
+func (c *fakeRedisClient) DescribeDBInstance(ctx context.Context, id string) (*redis.DBInstance, error) {
 	if id != testName {
 		return nil, errors.New("DescribeRedisInstance: client doesn't work")
 	}
@@ -646,7 +413,11 @@ func (c *fakeRedisClient) DescribeDBInstance(id string) (*redis.DBInstance, erro
 	}, nil
 }
 
-func (c *fakeRedisClient) CreateDBInstance(req *redis.CreateRedisInstanceRequest) (*redis.DBInstance, error) {
+func (c *fakeRedisClient) ListDBInstances(ctx context.Context, tags map[string]string) ([]redis.DBInstance, error) {
+	return nil, nil
+}
+
+func (c *fakeRedisClient) CreateDBInstance(ctx context.Context, req *redis.CreateRedisInstanceRequest) (*redis.DBInstance, error) {
 	if req.Name != testName {
 		return nil, errors.New("CreateRedisInstance: client doesn't work")
 	}
@@ -659,35 +430,39 @@ func (c *fakeRedisClient) CreateDBInstance(req *redis.CreateRedisInstanceRequest
 	}, nil
 }
 
-func (c *fakeRedisClient) CreateAccount(id, user, pw string) error {
+func (c *fakeRedisClient) CreateAccount(ctx context.Context, id, user, pw string) error {
 	if id != testName {
 		return errors.New("CreateAccount: client doesn't work")
 	}
 	return nil
 }
 
-func (c *fakeRedisClient) DeleteDBInstance(id string) error {
+func (c *fakeRedisClient) DeleteDBInstance(ctx context.Context, id string) error {
 	if id != testName {
 		return errors.New("DeleteRedisInstance: client doesn't work")
 	}
 	return nil
 }
 
-func (c *fakeRedisClient) AllocateInstancePublicConnection(id string, port int) (string, error) {
+func (c *fakeRedisClient) DescribeAvailableClasses(ctx context.Context, engine, engineVersion, chargeType string) (clients.Availability, error) {
+	return clients.Availability{"cn-beijing-a": {"redis.logic.sharding.2g.8db.0rodb.8proxy.default", "class-test"}}, nil
+}
+
+func (c *fakeRedisClient) AllocateInstancePublicConnection(ctx context.Context, id string, port int) (string, error) {
 	if id != testName {
 		return "nil", errors.New("AllocateInstancePublicConnection: client doesn't work")
 	}
 	return "", nil
 }
 
-func (c *fakeRedisClient) ModifyDBInstanceConnectionString(id string, port int) (string, error) {
+func (c *fakeRedisClient) ModifyDBInstanceConnectionString(ctx context.Context, id string, port int) (string, error) {
 	if id != testName {
 		return "nil", errors.New("ModifyDBInstanceConnectionString: client doesn't work")
 	}
 	return "", nil
 }
 
-func (c *fakeRedisClient) Update(id string, req *redis.ModifyRedisInstanceRequest) error {
+func (c *fakeRedisClient) Update(ctx context.Context, id string, req *redis.ModifyRedisInstanceRequest) error {
 	if id != testName {
 		return errors.New("Update: client doesn't work")
 	}
@@ -18,156 +18,132 @@ package slb
 
 import (
 	"context"
+	"strconv"
 
-	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
-	"github.com/crossplane/crossplane-runtime/pkg/event"
+	sdk "github.com/alibabacloud-go/slb-20140515/v2/client"
+	"github.com/alibabacloud-go/tea/tea"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/provider-alibaba/apis/slb/v1alpha1"
 	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/clients/bss"
 	slbclient "github.com/crossplane/provider-alibaba/pkg/clients/slb"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
 	"github.com/crossplane/provider-alibaba/pkg/util"
 )
 
-const (
-	errCreateClient             = "cannot create SLB client"
-	errTrackUsage               = "cannot track provider config usage"
-	errNoConnectionSecret       = "no connection secret specified"
-	errGetConnectionSecret      = "cannot get connection secret"
-	errFmtUnsupportedCredSource = "credentials source %q is not currently supported"
-)
-
 const (
 	errFailedToCreateSLB   = "failed to create SLB"
 	errFailedToDeleteSLB   = "failed to delete SLB"
 	errFailedToDescribeSLB = "failed to describe SLB"
-	errNotCLB              = "managed resource is not a CLB custom resource"
+	errFailedToListSLB     = "failed to list SLB"
 )
 
 // SetupCLB adds a controller that reconciles CLB
-func SetupCLB(mgr ctrl.Manager, l logging.Logger) error {
-	name := managed.ControllerName(v1alpha1.CLBGroupKind)
-	return ctrl.NewControllerManagedBy(mgr).
-		Named(name).
-		For(&v1alpha1.CLB{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(v1alpha1.CLBGroupVersionKind),
-			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-			managed.WithExternalConnecter(&Connector{
-				Client:      mgr.GetClient(),
-				Usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &aliv1alpha1.ProviderConfigUsage{}),
-				NewClientFn: slbclient.NewClient,
-			})))
+func SetupCLB(mgr ctrl.Manager, l logging.Logger, o adapter.Options) error {
+	return adapter.Setup(mgr, l, clbKind, o)
 }
 
-// Connector stores Kubernetes client and SLB client
-type Connector struct {
-	Client      client.Client
-	Usage       resource.Tracker
-	NewClientFn func(ctx context.Context, endpoint, accessKeyID, accessKeySecret, stsToken string) (*slbclient.SDKClient, error)
+// CLBKind returns the adapter.Kind of CLBs.
+func CLBKind() adapter.Kind {
+	return clbKind
 }
 
-// Connect initials cloud resource client
-func (c *Connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
-	cr, ok := mg.(*v1alpha1.CLB)
-	if !ok {
-		return nil, errors.New(errNotCLB)
-	}
-
-	var secretKeySelector *xpv1.SecretKeySelector
-	if err := c.Usage.Track(ctx, mg); err != nil {
-		return nil, errors.Wrap(err, errTrackUsage)
-	}
-
-	providerConfig, err := util.GetProviderConfig(ctx, c.Client, cr.Spec.ProviderConfigReference.Name)
-	if err != nil {
-		return nil, err
-	}
-
-	if s := providerConfig.Spec.Credentials.Source; s != xpv1.CredentialsSourceSecret {
-		return nil, errors.Errorf(errFmtUnsupportedCredSource, s)
-	}
-	secretKeySelector = providerConfig.Spec.Credentials.SecretRef
-
-	if secretKeySelector == nil {
-		return nil, errors.New(errNoConnectionSecret)
-	}
-	s := &corev1.Secret{}
-	nn := types.NamespacedName{Namespace: secretKeySelector.Namespace, Name: secretKeySelector.Name}
-	if err := c.Client.Get(ctx, nn, s); err != nil {
-		return nil, errors.Wrap(err, errGetConnectionSecret)
-	}
+var clbKind = adapter.Kind{
+	Type:              &v1alpha1.CLB{},
+	GroupVersionKind:  v1alpha1.CLBGroupVersionKind,
+	NewClient:         newSLBClient,
+	Describe:          describeCLB,
+	IsNotFound:        slbclient.IsNotFoundError,
+	Observe:           observeCLB,
+	IsUpToDate:        isCLBUpToDate,
+	ConnectionDetails: getCLBConnectionDetails,
+	Create:            createCLB,
+	Delete:            deleteCLB,
+	Discover:          discoverCLBs,
+	Parameters:        clbParameters,
+	CloudID: func(mg resource.Managed) string {
+		return tea.StringValue(mg.(*v1alpha1.CLB).Status.AtProvider.LoadBalancerID)
+	},
+	Pricing: &adapter.Pricing{
+		Request: func(mg resource.Managed, region string) bss.PriceRequest {
+			return slbclient.MakePriceRequest(&mg.(*v1alpha1.CLB).Spec.ForProvider, region)
+		},
+		Estimate: func(mg resource.Managed) *aliv1alpha1.CostEstimate {
+			return mg.(*v1alpha1.CLB).Status.AtProvider.CostEstimate
+		},
+		SetEstimate: func(mg resource.Managed, e *aliv1alpha1.CostEstimate) {
+			mg.(*v1alpha1.CLB).Status.AtProvider.CostEstimate = e
+		},
+	},
+	Operations: &adapter.Operations{
+		Pending: func(mg resource.Managed) *aliv1alpha1.PendingOperation {
+			return mg.(*v1alpha1.CLB).Status.AtProvider.PendingOperation
+		},
+		SetPending: func(mg resource.Managed, op *aliv1alpha1.PendingOperation) {
+			mg.(*v1alpha1.CLB).Status.AtProvider.PendingOperation = op
+		},
+	},
+}
 
-	endpoint, err := util.GetEndpoint(cr.DeepCopyObject(), "")
+func newSLBClient(ctx context.Context, mg resource.Managed, creds util.Credentials) (interface{}, error) {
+	endpoint, err := util.GetEndpoint(mg.DeepCopyObject(), "")
 	if err != nil {
 		return nil, err
 	}
-
-	client, err := c.NewClientFn(ctx, endpoint, string(s.Data[util.AccessKeyID]), string(s.Data[util.AccessKeySecret]), string(s.Data[util.SecurityToken]))
-	return &External{ExternalClient: client}, errors.Wrap(err, errCreateClient)
-}
-
-// External includes external SLB client
-type External struct {
-	ExternalClient slbclient.ClientInterface
+	return slbclient.NewClient(ctx, endpoint, creds.AccessKeyID, creds.AccessKeySecret, creds.SecurityToken)
 }
 
-// Observe managed resource CLB
-func (e *External) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
-	cr, ok := mg.(*v1alpha1.CLB)
-	if !ok {
-		return managed.ExternalObservation{}, errors.New(errNotCLB)
+func describeCLB(ctx context.Context, c interface{}, mg resource.Managed) (interface{}, error) {
+	cr := mg.(*v1alpha1.CLB)
+	if meta.GetExternalName(cr) == "" {
+		return nil, adapter.ErrNotFound
 	}
-
-	if meta.GetExternalName(mg) == "" {
-		return managed.ExternalObservation{
-			ResourceExists: false,
-		}, nil
+	// Imported load balancers are identified by their external name until
+	// the load balancer ID has been observed.
+	id := cr.Status.AtProvider.LoadBalancerID
+	if id == nil {
+		id = tea.String(meta.GetExternalName(cr))
 	}
-
-	slb, err := e.ExternalClient.DescribeLoadBalancers(cr.Spec.ForProvider.Region, cr.Status.AtProvider.LoadBalancerID, cr.Spec.ForProvider.VpcID,
+	slb, err := c.(slbclient.ClientInterface).DescribeLoadBalancers(ctx, cr.Spec.ForProvider.Region, id, cr.Spec.ForProvider.VpcID,
 		cr.Spec.ForProvider.VSwitchID)
 	if err != nil {
-		return managed.ExternalObservation{ResourceExists: false}, errors.Wrap(err, errFailedToDescribeSLB)
+		return nil, errors.Wrap(err, errFailedToDescribeSLB)
 	}
 	if *slb.Body.TotalCount == 0 {
-		return managed.ExternalObservation{ResourceExists: false, ResourceUpToDate: true}, nil
+		return nil, adapter.ErrNotFound
 	}
+	return slb, nil
+}
 
-	cr.Status.AtProvider = slbclient.GenerateObservation(slb)
-	var upToDate = slbclient.IsUpdateToDate(cr, slb)
-	if upToDate {
-		cr.SetConditions(xpv1.Available())
-	}
+func observeCLB(mg resource.Managed, observed interface{}) {
+	mg.(*v1alpha1.CLB).Status.AtProvider = slbclient.GenerateObservation(observed.(*sdk.DescribeLoadBalancersResponse))
+}
 
-	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  upToDate,
-		ConnectionDetails: GetConnectionDetails(cr),
-	}, nil
+func isCLBUpToDate(mg resource.Managed, observed interface{}) bool {
+	return slbclient.IsUpdateToDate(mg.(*v1alpha1.CLB), observed.(*sdk.DescribeLoadBalancersResponse))
 }
 
-// Create managed resource CLB
-func (e *External) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
-	cr, ok := mg.(*v1alpha1.CLB)
-	if !ok {
-		return managed.ExternalCreation{}, errors.New(errNotCLB)
-	}
-	cr.SetConditions(xpv1.Creating())
-	res, err := e.ExternalClient.CreateLoadBalancer(cr.Name, cr.Spec.ForProvider)
+func getCLBConnectionDetails(ctx context.Context, _ interface{}, mg resource.Managed, _ interface{}) (managed.ConnectionDetails, error) {
+	return GetConnectionDetails(mg.(*v1alpha1.CLB)), nil
+}
+
+func createCLB(ctx context.Context, c interface{}, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr := mg.(*v1alpha1.CLB)
+	slb := c.(slbclient.ClientInterface)
+	res, err := slb.CreateLoadBalancer(ctx, cr.Name, cr.Spec.ForProvider)
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errFailedToCreateSLB)
 	}
-	lb, err := e.ExternalClient.DescribeLoadBalancers(cr.Spec.ForProvider.Region, res.Body.LoadBalancerId,
+	if res.Body.OrderId != nil {
+		adapter.SetTaskID(ctx, strconv.FormatInt(*res.Body.OrderId, 10))
+	}
+	lb, err := slb.DescribeLoadBalancers(ctx, cr.Spec.ForProvider.Region, res.Body.LoadBalancerId,
 		cr.Spec.ForProvider.VpcID, cr.Spec.ForProvider.VSwitchID)
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errFailedToDescribeSLB)
@@ -176,22 +152,37 @@ func (e *External) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	return managed.ExternalCreation{ConnectionDetails: GetConnectionDetails(cr)}, nil
 }
 
-// Update managed resource CLB
-func (e *External) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	return managed.ExternalUpdate{}, nil
+func deleteCLB(ctx context.Context, c interface{}, mg resource.Managed) error {
+	cr := mg.(*v1alpha1.CLB)
+	err := c.(slbclient.ClientInterface).DeleteLoadBalancer(ctx, cr.Spec.ForProvider.Region, cr.Status.AtProvider.LoadBalancerID)
+	return errors.Wrap(err, errFailedToDeleteSLB)
 }
 
-// Delete managed resource CLB
-func (e *External) Delete(ctx context.Context, mg resource.Managed) error {
-	cr, ok := mg.(*v1alpha1.CLB)
-	if !ok {
-		return errors.New(errNotCLB)
+func clbParameters(observed interface{}) interface{} {
+	res := observed.(*sdk.DescribeLoadBalancersResponse)
+	if res.Body.LoadBalancers == nil || len(res.Body.LoadBalancers.LoadBalancer) == 0 {
+		return nil
 	}
-	cr.SetConditions(xpv1.Deleting())
-	if err := e.ExternalClient.DeleteLoadBalancer(cr.Spec.ForProvider.Region, cr.Status.AtProvider.LoadBalancerID); err != nil {
-		return errors.Wrap(err, errFailedToDeleteSLB)
-	}
-	return nil
+	return slbclient.GenerateParameters(res.Body.LoadBalancers.LoadBalancer[0])
+}
+
+func discoverCLBs(ctx context.Context, c interface{}, f adapter.Filter) ([]resource.Managed, error) {
+	lbs, err := c.(slbclient.ClientInterface).ListLoadBalancers(ctx, f.Region, f.Tags)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToListSLB)
+	}
+	var mgs []resource.Managed
+	for _, lb := range lbs {
+		if lb.LoadBalancerId == nil || !f.MatchesName(tea.StringValue(lb.LoadBalancerName)) {
+			continue
+		}
+		cr := &v1alpha1.CLB{}
+		cr.SetName(tea.StringValue(lb.LoadBalancerName))
+		meta.SetExternalName(cr, *lb.LoadBalancerId)
+		cr.Spec.ForProvider = slbclient.GenerateParameters(lb)
+		mgs = append(mgs, cr)
+	}
+	return mgs, nil
 }
 
 // GetConnectionDetails generates connection details
@@ -19,168 +19,75 @@ package sls
 import (
 	"context"
 
-	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
-	"github.com/crossplane/crossplane-runtime/pkg/event"
+	sdk "github.com/aliyun/aliyun-log-go-sdk"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/sls/v1alpha1"
-	"github.com/crossplane/provider-alibaba/apis/v1alpha1"
 	slsclient "github.com/crossplane/provider-alibaba/pkg/clients/sls"
-	"github.com/crossplane/provider-alibaba/pkg/util"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
 )
 
 const (
 	errCreateIndex   = "failed to create Index"
 	errDeleteIndex   = "failed to delete Index"
 	errDescribeIndex = "failed to describe Index"
-	errNotIndex      = "managed resource is not an Index custom resource"
 )
 
 // SetupIndex adds a controller that reconciles Index.
-func SetupIndex(mgr ctrl.Manager, l logging.Logger) error {
-	name := managed.ControllerName(aliv1alpha1.IndexGroupKind)
-	return ctrl.NewControllerManagedBy(mgr).
-		Named(name).
-		For(&aliv1alpha1.LogstoreIndex{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(aliv1alpha1.IndexGroupVersionKind),
-			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-			managed.WithExternalConnecter(&indexConnector{
-				client:      mgr.GetClient(),
-				usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &v1alpha1.ProviderConfigUsage{}),
-				NewClientFn: slsclient.NewClient,
-			})))
+func SetupIndex(mgr ctrl.Manager, l logging.Logger, o adapter.Options) error {
+	return adapter.Setup(mgr, l, indexKind, o)
 }
 
-// indexConnector stores Kubernetes client and SLS client
-type indexConnector struct {
-	client      client.Client
-	usage       resource.Tracker
-	NewClientFn func(accessKeyID, accessKeySecret, securityToken, region string) *slsclient.LogClient
+var indexKind = adapter.Kind{
+	Type:              &aliv1alpha1.LogstoreIndex{},
+	GroupVersionKind:  aliv1alpha1.IndexGroupVersionKind,
+	NewClient:         newSLSClient,
+	Describe:          describeIndex,
+	IsNotFound:        slsclient.IsIndexNotFoundError,
+	Observe:           observeIndex,
+	IsUpToDate:        isIndexUpToDate,
+	ConnectionDetails: getIndexObservedConnectionDetails,
+	Create:            createIndex,
+	Delete:            deleteIndex,
+	// TODO(zzxwll) need to add Update logic here
 }
 
-// Connect initials cloud resource client
-func (c *indexConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
-	cr, ok := mg.(*aliv1alpha1.LogstoreIndex)
-	if !ok {
-		return nil, errors.New(errNotIndex)
-	}
-
-	var (
-		sel    *xpv1.SecretKeySelector
-		region string
-	)
-
-	switch {
-	case cr.GetProviderConfigReference() != nil:
-		if err := c.usage.Track(ctx, mg); err != nil {
-			return nil, errors.Wrap(err, errTrackUsage)
-		}
-
-		pc := &v1alpha1.ProviderConfig{}
-		if err := c.client.Get(ctx, types.NamespacedName{Name: cr.Spec.ProviderConfigReference.Name}, pc); err != nil {
-			return nil, errors.Wrap(err, errGetProviderConfig)
-		}
-		if s := pc.Spec.Credentials.Source; s != xpv1.CredentialsSourceSecret {
-			return nil, errors.Errorf(errFmtUnsupportedCredSource, s)
-		}
-		sel = pc.Spec.Credentials.SecretRef
-		region = pc.Spec.Region
-	default:
-		return nil, errors.New(errNoProvider)
-	}
-
-	if sel == nil {
-		return nil, errors.New(errNoConnectionSecret)
-	}
-
-	s := &corev1.Secret{}
-	nn := types.NamespacedName{Namespace: sel.Namespace, Name: sel.Name}
-	if err := c.client.Get(ctx, nn, s); err != nil {
-		return nil, errors.Wrap(err, errGetConnectionSecret)
+func describeIndex(ctx context.Context, c interface{}, mg resource.Managed) (interface{}, error) {
+	cr := mg.(*aliv1alpha1.LogstoreIndex)
+	if meta.GetExternalName(cr) == "" {
+		return nil, adapter.ErrNotFound
 	}
-
-	slsClient := c.NewClientFn(string(s.Data[util.AccessKeyID]), string(s.Data[util.AccessKeySecret]), string(s.Data[util.SecurityToken]), region)
-	return &indexExternal{client: slsClient}, nil
+	index, err := c.(slsclient.LogClientInterface).DescribeIndex(ctx, cr.Spec.ForProvider.ProjectName, cr.Spec.ForProvider.LogstoreName)
+	return index, errors.Wrap(err, errDescribeIndex)
 }
 
-// indexExternal includes external SLS client
-type indexExternal struct {
-	client slsclient.LogClientInterface
+func observeIndex(mg resource.Managed, observed interface{}) {
+	mg.(*aliv1alpha1.LogstoreIndex).Status.AtProvider = slsclient.GenerateIndexObservation(observed.(*sdk.Index))
 }
 
-// Observe managed resource LogstoreIndex
-func (e *indexExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
-	cr, ok := mg.(*aliv1alpha1.LogstoreIndex)
-	if !ok {
-		return managed.ExternalObservation{}, errors.New(errNotIndex)
-	}
-
-	if meta.GetExternalName(mg) == "" {
-		return managed.ExternalObservation{
-			ResourceExists: false,
-		}, nil
-	}
-
-	index, err := e.client.DescribeIndex(cr.Spec.ForProvider.ProjectName, cr.Spec.ForProvider.LogstoreName)
-	if err != nil {
-		if slsclient.IsIndexNotFoundError(err) {
-			return managed.ExternalObservation{ResourceExists: false, ResourceUpToDate: true}, nil
-		}
-		return managed.ExternalObservation{ResourceExists: false}, errors.Wrap(err, errDescribeIndex)
-	}
-	cr.Status.AtProvider = slsclient.GenerateIndexObservation(index)
-
-	var upToDate = slsclient.IsIndexUpdateToDate(cr, index)
-	if upToDate {
-		cr.SetConditions(xpv1.Available())
-	}
-
-	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  upToDate,
-		ConnectionDetails: GetIndexConnectionDetails(cr),
-	}, nil
+func isIndexUpToDate(mg resource.Managed, observed interface{}) bool {
+	return slsclient.IsIndexUpdateToDate(mg.(*aliv1alpha1.LogstoreIndex), observed.(*sdk.Index))
 }
 
-// Create managed resource LogstoreIndex
-func (e *indexExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
-	cr, ok := mg.(*aliv1alpha1.LogstoreIndex)
-	if !ok {
-		return managed.ExternalCreation{}, errors.New(errNotIndex)
-	}
-	cr.SetConditions(xpv1.Creating())
-
-	err := e.client.CreateIndex(cr.Spec.ForProvider)
-	return managed.ExternalCreation{}, errors.Wrap(err, errCreateIndex)
+func getIndexObservedConnectionDetails(ctx context.Context, _ interface{}, mg resource.Managed, _ interface{}) (managed.ConnectionDetails, error) {
+	return GetIndexConnectionDetails(mg.(*aliv1alpha1.LogstoreIndex)), nil
 }
 
-// Update managed resource LogstoreIndex
-func (e *indexExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	// TODO(zzxwll) need to add Update logic here
-	return managed.ExternalUpdate{}, nil
+func createIndex(ctx context.Context, c interface{}, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr := mg.(*aliv1alpha1.LogstoreIndex)
+	err := c.(slsclient.LogClientInterface).CreateIndex(ctx, cr.Spec.ForProvider)
+	return managed.ExternalCreation{}, errors.Wrap(err, errCreateIndex)
 }
 
-// Delete managed resource LogstoreIndex
-func (e *indexExternal) Delete(ctx context.Context, mg resource.Managed) error {
-	cr, ok := mg.(*aliv1alpha1.LogstoreIndex)
-	if !ok {
-		return errors.New(errNotIndex)
-	}
-	cr.SetConditions(xpv1.Deleting())
-	if err := e.client.DeleteIndex(cr.Spec.ForProvider.ProjectName, cr.Spec.ForProvider.LogstoreName); err != nil {
-		return errors.Wrap(err, errDeleteIndex)
-	}
-	return nil
+func deleteIndex(ctx context.Context, c interface{}, mg resource.Managed) error {
+	cr := mg.(*aliv1alpha1.LogstoreIndex)
+	err := c.(slsclient.LogClientInterface).DeleteIndex(ctx, cr.Spec.ForProvider.ProjectName, cr.Spec.ForProvider.LogstoreName)
+	return errors.Wrap(err, errDeleteIndex)
 }
 
 // GetIndexConnectionDetails generates connection details
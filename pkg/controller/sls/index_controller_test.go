@@ -33,6 +33,7 @@ import (
 
 	slsv1alpha1 "github.com/crossplane/provider-alibaba/apis/sls/v1alpha1"
 	slsclient "github.com/crossplane/provider-alibaba/pkg/clients/sls"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
 )
 
 var (
@@ -51,7 +52,7 @@ var validIndexCR = &slsv1alpha1.LogstoreIndex{
 	},
 }
 
-func (c *fakeSDKClient) DescribeIndex(project, logstore *string) (*sdk.Index, error) {
+func (c *fakeSDKClient) DescribeIndex(ctx context.Context, project, logstore *string) (*sdk.Index, error) {
 	switch *project {
 	case "":
 		return nil, sdk.Error{Code: slsclient.ErrCodeLogstoreIndexNotExist, HTTPCode: int32(0)}
@@ -62,15 +63,15 @@ func (c *fakeSDKClient) DescribeIndex(project, logstore *string) (*sdk.Index, er
 	}
 }
 
-func (c *fakeSDKClient) CreateIndex(param slsv1alpha1.LogstoreIndexParameters) error {
+func (c *fakeSDKClient) CreateIndex(ctx context.Context, param slsv1alpha1.LogstoreIndexParameters) error {
 	return nil
 }
 
-func (c *fakeSDKClient) UpdateIndex(project, logstore *string, index *sdk.Index) error {
+func (c *fakeSDKClient) UpdateIndex(ctx context.Context, project, logstore *string, index *sdk.Index) error {
 	return nil
 }
 
-func (c *fakeSDKClient) DeleteIndex(project, logstore *string) error {
+func (c *fakeSDKClient) DeleteIndex(ctx context.Context, project, logstore *string) error {
 	return nil
 }
 
@@ -89,14 +90,6 @@ func TestIndexObserve(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotIndex": {
-			reason: "We should return an error if the supplied managed resource is not anIndex",
-			mg:     nil,
-			want: want{
-				o:   managed.ExternalObservation{},
-				err: errors.New(errNotIndex),
-			},
-		},
 		"IndexNotFound": {
 			reason: "Index name could not be found",
 			mg:     &slsv1alpha1.LogstoreIndex{},
@@ -135,7 +128,7 @@ func TestIndexObserve(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			indexExternal := &indexExternal{client: &fakeSDKClient{}}
+			indexExternal := adapter.NewExternalClient(indexKind, nil, &fakeSDKClient{})
 			got, err := indexExternal.Observe(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -162,14 +155,6 @@ func TestIndexCreate(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotIndex": {
-			reason: "Not an Index object",
-			mg:     nil,
-			want: want{
-				o:   managed.ExternalCreation{},
-				err: errors.New(errNotIndex),
-			},
-		},
 		"Success": {
 			reason: "Creating anIndex successfully",
 			mg:     validIndexCR,
@@ -184,7 +169,7 @@ func TestIndexCreate(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			indexExternal := &indexExternal{client: &fakeSDKClient{}}
+			indexExternal := adapter.NewExternalClient(indexKind, nil, &fakeSDKClient{})
 			got, err := indexExternal.Create(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -211,14 +196,6 @@ func TestIndexUpdate(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotIndex": {
-			reason: "Not an Index object",
-			mg:     nil,
-			want: want{
-				o:   managed.ExternalUpdate{},
-				err: nil,
-			},
-		},
 		"Success": {
 			reason: "Creating anIndex successfully",
 			mg:     validIndexCR,
@@ -231,7 +208,7 @@ func TestIndexUpdate(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			indexExternal := &indexExternal{client: &fakeSDKClient{}}
+			indexExternal := adapter.NewExternalClient(indexKind, nil, &fakeSDKClient{})
 			got, err := indexExternal.Update(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Update(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -257,13 +234,6 @@ func TestIndexDelete(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotIndex": {
-			reason: "Not an Index object",
-			mg:     nil,
-			want: want{
-				err: errors.New(errNotIndex),
-			},
-		},
 		"Success": {
 			reason: "Creating anIndex successfully",
 			mg:     validIndexCR,
@@ -275,7 +245,7 @@ func TestIndexDelete(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			indexExternal := &indexExternal{client: &fakeSDKClient{}}
+			indexExternal := adapter.NewExternalClient(indexKind, nil, &fakeSDKClient{})
 			err := indexExternal.Delete(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
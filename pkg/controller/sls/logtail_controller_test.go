@@ -33,6 +33,7 @@ import (
 
 	slsv1alpha1 "github.com/crossplane/provider-alibaba/apis/sls/v1alpha1"
 	slsclient "github.com/crossplane/provider-alibaba/pkg/clients/sls"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
 )
 
 const (
@@ -49,7 +50,7 @@ var validLogtailCR = &slsv1alpha1.Logtail{
 	},
 }
 
-func (c *fakeSDKClient) DescribeConfig(logtail string, config string) (*sdk.LogConfig, error) {
+func (c *fakeSDKClient) DescribeConfig(ctx context.Context, logtail string, config string) (*sdk.LogConfig, error) {
 	switch config {
 	case "":
 		return nil, sdk.Error{Code: slsclient.ErrCodeLogtailNotExist, HTTPCode: int32(0)}
@@ -63,15 +64,15 @@ func (c *fakeSDKClient) DescribeConfig(logtail string, config string) (*sdk.LogC
 	}
 }
 
-func (c *fakeSDKClient) CreateConfig(name string, config slsv1alpha1.LogtailParameters) error {
+func (c *fakeSDKClient) CreateConfig(ctx context.Context, name string, config slsv1alpha1.LogtailParameters) error {
 	return nil
 }
 
-func (c *fakeSDKClient) UpdateConfig(logtail string, config *sdk.LogConfig) error {
+func (c *fakeSDKClient) UpdateConfig(ctx context.Context, logtail string, config *sdk.LogConfig) error {
 	return nil
 }
 
-func (c *fakeSDKClient) DeleteConfig(logtail string, config string) error {
+func (c *fakeSDKClient) DeleteConfig(ctx context.Context, logtail string, config string) error {
 	return nil
 }
 
@@ -90,14 +91,6 @@ func TestLogtailObserve(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotLogtail": {
-			reason: "We should return an error if the supplied managed resource is not anLogtail",
-			mg:     nil,
-			want: want{
-				o:   managed.ExternalObservation{},
-				err: errors.New(errNotLogtail),
-			},
-		},
 		"LogtailNotFound": {
 			reason: "Logtail name could not be found",
 			mg:     &slsv1alpha1.Logtail{},
@@ -136,7 +129,7 @@ func TestLogtailObserve(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			logtailExternal := &logtailExternal{client: &fakeSDKClient{}}
+			logtailExternal := adapter.NewExternalClient(logtailKind, nil, &fakeSDKClient{})
 			got, err := logtailExternal.Observe(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -163,14 +156,6 @@ func TestLogtailCreate(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotLogtail": {
-			reason: "Not an Logtail object",
-			mg:     nil,
-			want: want{
-				o:   managed.ExternalCreation{},
-				err: errors.New(errNotLogtail),
-			},
-		},
 		"Success": {
 			reason: "Creating anLogtail successfully",
 			mg:     validLogtailCR,
@@ -185,7 +170,7 @@ func TestLogtailCreate(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			logtailExternal := &logtailExternal{client: &fakeSDKClient{}}
+			logtailExternal := adapter.NewExternalClient(logtailKind, nil, &fakeSDKClient{})
 			got, err := logtailExternal.Create(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -212,14 +197,6 @@ func TestLogtailUpdate(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotLogtail": {
-			reason: "Not an Logtail object",
-			mg:     nil,
-			want: want{
-				o:   managed.ExternalUpdate{},
-				err: nil,
-			},
-		},
 		"Success": {
 			reason: "Creating anLogtail successfully",
 			mg:     validLogtailCR,
@@ -232,7 +209,7 @@ func TestLogtailUpdate(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			logtailExternal := &logtailExternal{client: &fakeSDKClient{}}
+			logtailExternal := adapter.NewExternalClient(logtailKind, nil, &fakeSDKClient{})
 			got, err := logtailExternal.Update(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Update(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -258,13 +235,6 @@ func TestLogtailDelete(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotLogtail": {
-			reason: "Not an Logtail object",
-			mg:     nil,
-			want: want{
-				err: errors.New(errNotLogtail),
-			},
-		},
 		"Success": {
 			reason: "Creating anLogtail successfully",
 			mg:     validLogtailCR,
@@ -276,7 +246,7 @@ func TestLogtailDelete(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			logtailExternal := &logtailExternal{client: &fakeSDKClient{}}
+			logtailExternal := adapter.NewExternalClient(logtailKind, nil, &fakeSDKClient{})
 			err := logtailExternal.Delete(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
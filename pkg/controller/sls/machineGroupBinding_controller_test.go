@@ -31,6 +31,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	slsv1alpha1 "github.com/crossplane/provider-alibaba/apis/sls/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
 )
 
 var (
@@ -60,7 +61,7 @@ var (
 	}
 )
 
-func (c *fakeSDKClient) GetAppliedConfigs(projectName *string, groupName *string) ([]string, error) {
+func (c *fakeSDKClient) GetAppliedConfigs(ctx context.Context, projectName *string, groupName *string) ([]string, error) {
 	switch *projectName {
 	case mgbProject:
 		return []string{mgbConfig}, nil
@@ -73,11 +74,11 @@ func (c *fakeSDKClient) GetAppliedConfigs(projectName *string, groupName *string
 	}
 }
 
-func (c *fakeSDKClient) ApplyConfigToMachineGroup(projectName, groupName, confName *string) error {
+func (c *fakeSDKClient) ApplyConfigToMachineGroup(ctx context.Context, projectName, groupName, confName *string) error {
 	return nil
 }
 
-func (c *fakeSDKClient) RemoveConfigFromMachineGroup(projectName, groupName, confName *string) error {
+func (c *fakeSDKClient) RemoveConfigFromMachineGroup(ctx context.Context, projectName, groupName, confName *string) error {
 	return nil
 }
 
@@ -96,14 +97,6 @@ func TestMachineGroupBindingObserve(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotMachineGroupBinding": {
-			reason: "We should return an error if the supplied managed resource is not MachineGroupBinding",
-			mg:     nil,
-			want: want{
-				o:   managed.ExternalObservation{},
-				err: errors.New(errNotMachineGroupBinding),
-			},
-		},
 		"ExternalNameIsNotSet": {
 			reason: "MachineGroupBinding's external name is not set",
 			mg:     &slsv1alpha1.MachineGroupBinding{},
@@ -147,8 +140,7 @@ func TestMachineGroupBindingObserve(t *testing.T) {
 			},
 			want: want{
 				o: managed.ExternalObservation{
-					ResourceExists:   false,
-					ResourceUpToDate: true,
+					ResourceExists: false,
 				},
 				err: nil,
 			},
@@ -168,7 +160,7 @@ func TestMachineGroupBindingObserve(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			external := &machineGroupBindingExternal{client: &fakeSDKClient{}}
+			external := adapter.NewExternalClient(machineGroupBindingKind, nil, &fakeSDKClient{})
 			got, err := external.Observe(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -195,14 +187,6 @@ func TestApplyConfigToMachineGroup(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotMachineGroupBinding": {
-			reason: "Not MachineGroupBinding object",
-			mg:     nil,
-			want: want{
-				o:   managed.ExternalCreation{},
-				err: errors.New(errNotMachineGroupBinding),
-			},
-		},
 		"Success": {
 			reason: "Creating MachineGroupBinding successfully",
 			mg:     validMachineGroupBindingCR,
@@ -215,7 +199,7 @@ func TestApplyConfigToMachineGroup(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			external := &machineGroupBindingExternal{client: &fakeSDKClient{}}
+			external := adapter.NewExternalClient(machineGroupBindingKind, nil, &fakeSDKClient{})
 			got, err := external.Create(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -241,13 +225,6 @@ func TestRemoveConfigFromMachineGroup(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotMachineGroupBinding": {
-			reason: "Not MachineGroupBinding object",
-			mg:     nil,
-			want: want{
-				err: errors.New(errNotMachineGroupBinding),
-			},
-		},
 		"Success": {
 			reason: "Creating MachineGroupBinding successfully",
 			mg:     validMachineGroupBindingCR,
@@ -259,7 +236,7 @@ func TestRemoveConfigFromMachineGroup(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			external := &machineGroupBindingExternal{client: &fakeSDKClient{}}
+			external := adapter.NewExternalClient(machineGroupBindingKind, nil, &fakeSDKClient{})
 			err := external.Delete(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -19,168 +19,75 @@ package sls
 import (
 	"context"
 
-	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
-	"github.com/crossplane/crossplane-runtime/pkg/event"
+	sdk "github.com/aliyun/aliyun-log-go-sdk"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/sls/v1alpha1"
-	"github.com/crossplane/provider-alibaba/apis/v1alpha1"
 	slsclient "github.com/crossplane/provider-alibaba/pkg/clients/sls"
-	"github.com/crossplane/provider-alibaba/pkg/util"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
 )
 
 const (
 	errCreateMachineGroup   = "failed to create MachineGroup"
 	errDeleteMachineGroup   = "failed to delete MachineGroup"
 	errDescribeMachineGroup = "failed to describe MachineGroup"
-	errNotMachineGroup      = "managed resource is not an MachineGroup custom resource"
 )
 
 // SetupMachineGroup adds a controller that reconciles MachineGroup.
-func SetupMachineGroup(mgr ctrl.Manager, l logging.Logger) error {
-	name := managed.ControllerName(aliv1alpha1.MachineGroupKind)
-	return ctrl.NewControllerManagedBy(mgr).
-		Named(name).
-		For(&aliv1alpha1.MachineGroup{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(aliv1alpha1.MachineGroupVersionKind),
-			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-			managed.WithExternalConnecter(&machineGroupConnector{
-				client:      mgr.GetClient(),
-				usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &v1alpha1.ProviderConfigUsage{}),
-				NewClientFn: slsclient.NewClient,
-			})))
+func SetupMachineGroup(mgr ctrl.Manager, l logging.Logger, o adapter.Options) error {
+	return adapter.Setup(mgr, l, machineGroupKind, o)
 }
 
-// machineGroupConnector stores Kubernetes client and SLS client
-type machineGroupConnector struct {
-	client      client.Client
-	usage       resource.Tracker
-	NewClientFn func(accessKeyID, accessKeySecret, securityToken, region string) *slsclient.LogClient
+var machineGroupKind = adapter.Kind{
+	Type:              &aliv1alpha1.MachineGroup{},
+	GroupVersionKind:  aliv1alpha1.MachineGroupVersionKind,
+	NewClient:         newSLSClient,
+	Describe:          describeMachineGroup,
+	IsNotFound:        slsclient.IsMachineGroupNotFoundError,
+	Observe:           observeMachineGroup,
+	IsUpToDate:        isMachineGroupUpToDate,
+	ConnectionDetails: getMachineGroupObservedConnectionDetails,
+	Create:            createMachineGroup,
+	Delete:            deleteMachineGroup,
+	// TODO(zzxwill) need to add Update logic here
 }
 
-// Connect initials cloud resource client
-func (c *machineGroupConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
-	cr, ok := mg.(*aliv1alpha1.MachineGroup)
-	if !ok {
-		return nil, errors.New(errNotMachineGroup)
-	}
-
-	var (
-		sel    *xpv1.SecretKeySelector
-		region string
-	)
-
-	switch {
-	case cr.GetProviderConfigReference() != nil:
-		if err := c.usage.Track(ctx, mg); err != nil {
-			return nil, errors.Wrap(err, errTrackUsage)
-		}
-
-		pc := &v1alpha1.ProviderConfig{}
-		if err := c.client.Get(ctx, types.NamespacedName{Name: cr.Spec.ProviderConfigReference.Name}, pc); err != nil {
-			return nil, errors.Wrap(err, errGetProviderConfig)
-		}
-		if s := pc.Spec.Credentials.Source; s != xpv1.CredentialsSourceSecret {
-			return nil, errors.Errorf(errFmtUnsupportedCredSource, s)
-		}
-		sel = pc.Spec.Credentials.SecretRef
-		region = pc.Spec.Region
-	default:
-		return nil, errors.New(errNoProvider)
-	}
-
-	if sel == nil {
-		return nil, errors.New(errNoConnectionSecret)
-	}
-
-	s := &corev1.Secret{}
-	nn := types.NamespacedName{Namespace: sel.Namespace, Name: sel.Name}
-	if err := c.client.Get(ctx, nn, s); err != nil {
-		return nil, errors.Wrap(err, errGetConnectionSecret)
+func describeMachineGroup(ctx context.Context, c interface{}, mg resource.Managed) (interface{}, error) {
+	cr := mg.(*aliv1alpha1.MachineGroup)
+	if meta.GetExternalName(cr) == "" {
+		return nil, adapter.ErrNotFound
 	}
-
-	slsClient := c.NewClientFn(string(s.Data[util.AccessKeyID]), string(s.Data[util.AccessKeySecret]), string(s.Data[util.SecurityToken]), region)
-	return &machineGroupExternal{client: slsClient}, nil
+	machineGroup, err := c.(slsclient.LogClientInterface).DescribeMachineGroup(ctx, cr.Spec.ForProvider.Project, meta.GetExternalName(cr))
+	return machineGroup, errors.Wrap(err, errDescribeMachineGroup)
 }
 
-// machineGroupExternal includes external SLS client
-type machineGroupExternal struct {
-	client slsclient.LogClientInterface
+func observeMachineGroup(mg resource.Managed, observed interface{}) {
+	mg.(*aliv1alpha1.MachineGroup).Status.AtProvider = slsclient.GenerateMachineGroupObservation(observed.(*sdk.MachineGroup))
 }
 
-// Observe managed resource LogstoreMachineGroup
-func (e *machineGroupExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
-	cr, ok := mg.(*aliv1alpha1.MachineGroup)
-	if !ok {
-		return managed.ExternalObservation{}, errors.New(errNotMachineGroup)
-	}
-
-	if meta.GetExternalName(mg) == "" {
-		return managed.ExternalObservation{
-			ResourceExists: false,
-		}, nil
-	}
-
-	machineGroup, err := e.client.DescribeMachineGroup(cr.Spec.ForProvider.Project, meta.GetExternalName(mg))
-	if err != nil {
-		if slsclient.IsMachineGroupNotFoundError(err) {
-			return managed.ExternalObservation{ResourceExists: false, ResourceUpToDate: true}, nil
-		}
-		return managed.ExternalObservation{ResourceExists: false}, errors.Wrap(err, errDescribeMachineGroup)
-	}
-	cr.Status.AtProvider = slsclient.GenerateMachineGroupObservation(machineGroup)
-
-	var upToDate = slsclient.IsMachineGroupUpdateToDate(cr, machineGroup)
-	if upToDate {
-		cr.SetConditions(xpv1.Available())
-	}
-
-	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  upToDate,
-		ConnectionDetails: GetMachineGroupConnectionDetails(cr),
-	}, nil
+func isMachineGroupUpToDate(mg resource.Managed, observed interface{}) bool {
+	return slsclient.IsMachineGroupUpdateToDate(mg.(*aliv1alpha1.MachineGroup), observed.(*sdk.MachineGroup))
 }
 
-// Create managed resource LogstoreMachineGroup
-func (e *machineGroupExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
-	cr, ok := mg.(*aliv1alpha1.MachineGroup)
-	if !ok {
-		return managed.ExternalCreation{}, errors.New(errNotMachineGroup)
-	}
-	cr.SetConditions(xpv1.Creating())
-
-	err := e.client.CreateMachineGroup(meta.GetExternalName(mg), cr.Spec.ForProvider)
-	return managed.ExternalCreation{}, errors.Wrap(err, errCreateMachineGroup)
+func getMachineGroupObservedConnectionDetails(ctx context.Context, _ interface{}, mg resource.Managed, _ interface{}) (managed.ConnectionDetails, error) {
+	return GetMachineGroupConnectionDetails(mg.(*aliv1alpha1.MachineGroup)), nil
 }
 
-// Update managed resource LogstoreMachineGroup
-func (e *machineGroupExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	// TODO(zzxwill) need to add Update logic here
-	return managed.ExternalUpdate{}, nil
+func createMachineGroup(ctx context.Context, c interface{}, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr := mg.(*aliv1alpha1.MachineGroup)
+	err := c.(slsclient.LogClientInterface).CreateMachineGroup(ctx, meta.GetExternalName(cr), cr.Spec.ForProvider)
+	return managed.ExternalCreation{}, errors.Wrap(err, errCreateMachineGroup)
 }
 
-// Delete managed resource LogstoreMachineGroup
-func (e *machineGroupExternal) Delete(ctx context.Context, mg resource.Managed) error {
-	cr, ok := mg.(*aliv1alpha1.MachineGroup)
-	if !ok {
-		return errors.New(errNotMachineGroup)
-	}
-	cr.SetConditions(xpv1.Deleting())
-	if err := e.client.DeleteMachineGroup(cr.Spec.ForProvider.Project, meta.GetExternalName(mg)); err != nil {
-		return errors.Wrap(err, errDeleteMachineGroup)
-	}
-	return nil
+func deleteMachineGroup(ctx context.Context, c interface{}, mg resource.Managed) error {
+	cr := mg.(*aliv1alpha1.MachineGroup)
+	err := c.(slsclient.LogClientInterface).DeleteMachineGroup(ctx, cr.Spec.ForProvider.Project, meta.GetExternalName(cr))
+	return errors.Wrap(err, errDeleteMachineGroup)
 }
 
 // GetMachineGroupConnectionDetails generates connection details
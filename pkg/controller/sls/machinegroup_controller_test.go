@@ -34,6 +34,7 @@ import (
 
 	slsv1alpha1 "github.com/crossplane/provider-alibaba/apis/sls/v1alpha1"
 	slsclient "github.com/crossplane/provider-alibaba/pkg/clients/sls"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
 )
 
 var (
@@ -58,7 +59,7 @@ var validMachineGroupCR = &slsv1alpha1.MachineGroup{
 	}},
 }
 
-func (c *fakeSDKClient) DescribeMachineGroup(project *string, name string) (*sdk.MachineGroup, error) {
+func (c *fakeSDKClient) DescribeMachineGroup(ctx context.Context, project *string, name string) (*sdk.MachineGroup, error) {
 	switch name {
 	case "":
 		return nil, sdk.Error{Code: slsclient.ErrCodeMachineGroupNotExist, HTTPCode: int32(0)}
@@ -78,15 +79,15 @@ func (c *fakeSDKClient) DescribeMachineGroup(project *string, name string) (*sdk
 	}
 }
 
-func (c *fakeSDKClient) CreateMachineGroup(name string, param slsv1alpha1.MachineGroupParameters) error {
+func (c *fakeSDKClient) CreateMachineGroup(ctx context.Context, name string, param slsv1alpha1.MachineGroupParameters) error {
 	return nil
 }
 
-func (c *fakeSDKClient) UpdateMachineGroup(project, logstore *string, machineGroup *sdk.MachineGroup) error {
+func (c *fakeSDKClient) UpdateMachineGroup(ctx context.Context, project, logstore *string, machineGroup *sdk.MachineGroup) error {
 	return nil
 }
 
-func (c *fakeSDKClient) DeleteMachineGroup(project *string, logstore string) error {
+func (c *fakeSDKClient) DeleteMachineGroup(ctx context.Context, project *string, logstore string) error {
 	return nil
 }
 
@@ -105,14 +106,6 @@ func TestMachineGroupObserve(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotMachineGroup": {
-			reason: "We should return an error if the supplied managed resource is not anMachineGroup",
-			mg:     nil,
-			want: want{
-				o:   managed.ExternalObservation{},
-				err: errors.New(errNotMachineGroup),
-			},
-		},
 		"MachineGroupNotFound": {
 			reason: "MachineGroup name could not be found",
 			mg: &slsv1alpha1.MachineGroup{
@@ -154,7 +147,7 @@ func TestMachineGroupObserve(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			machineGroupExternal := &machineGroupExternal{client: &fakeSDKClient{}}
+			machineGroupExternal := adapter.NewExternalClient(machineGroupKind, nil, &fakeSDKClient{})
 			got, err := machineGroupExternal.Observe(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -181,14 +174,6 @@ func TestMachineGroupCreate(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotMachineGroup": {
-			reason: "Not an MachineGroup object",
-			mg:     nil,
-			want: want{
-				o:   managed.ExternalCreation{},
-				err: errors.New(errNotMachineGroup),
-			},
-		},
 		"Success": {
 			reason: "Creating anMachineGroup successfully",
 			mg:     validMachineGroupCR,
@@ -203,7 +188,7 @@ func TestMachineGroupCreate(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			machineGroupExternal := &machineGroupExternal{client: &fakeSDKClient{}}
+			machineGroupExternal := adapter.NewExternalClient(machineGroupKind, nil, &fakeSDKClient{})
 			got, err := machineGroupExternal.Create(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -230,14 +215,6 @@ func TestMachineGroupUpdate(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotMachineGroup": {
-			reason: "Not an MachineGroup object",
-			mg:     nil,
-			want: want{
-				o:   managed.ExternalUpdate{},
-				err: nil,
-			},
-		},
 		"Success": {
 			reason: "Creating anMachineGroup successfully",
 			mg:     validMachineGroupCR,
@@ -250,7 +227,7 @@ func TestMachineGroupUpdate(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			machineGroupExternal := &machineGroupExternal{client: &fakeSDKClient{}}
+			machineGroupExternal := adapter.NewExternalClient(machineGroupKind, nil, &fakeSDKClient{})
 			got, err := machineGroupExternal.Update(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Update(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -276,13 +253,6 @@ func TestMachineGroupDelete(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotMachineGroup": {
-			reason: "Not an MachineGroup object",
-			mg:     nil,
-			want: want{
-				err: errors.New(errNotMachineGroup),
-			},
-		},
 		"Success": {
 			reason: "Creating anMachineGroup successfully",
 			mg:     validMachineGroupCR,
@@ -294,7 +264,7 @@ func TestMachineGroupDelete(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			machineGroupExternal := &machineGroupExternal{client: &fakeSDKClient{}}
+			machineGroupExternal := adapter.NewExternalClient(machineGroupKind, nil, &fakeSDKClient{})
 			err := machineGroupExternal.Delete(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
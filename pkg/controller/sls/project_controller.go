@@ -20,185 +20,237 @@ package sls
 
 import (
 	"context"
+	"fmt"
 
 	sdk "github.com/aliyun/aliyun-log-go-sdk"
-	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
-	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	slsv1alpha1 "github.com/crossplane/provider-alibaba/apis/sls/v1alpha1"
-	"github.com/crossplane/provider-alibaba/apis/v1alpha1"
+	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
 	slsclient "github.com/crossplane/provider-alibaba/pkg/clients/sls"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
 	"github.com/crossplane/provider-alibaba/pkg/util"
 )
 
-const (
-	errNotProject               = "managed resource is not a SLS project custom resource"
-	errNoProvider               = "no provider config or provider specified"
-	errGetProviderConfig        = "cannot get provider config"
-	errTrackUsage               = "cannot track provider config usage"
-	errNoConnectionSecret       = "no connection secret specified"
-	errGetConnectionSecret      = "cannot get connection secret"
-	errFmtUnsupportedCredSource = "credentials source %q is not currently supported"
-)
+const errFmtGetProviderConfig = "cannot get ProviderConfig %s"
 
 // SetupProject adds a controller that reconciles SLSProjects.
-func SetupProject(mgr ctrl.Manager, l logging.Logger) error {
-	name := managed.ControllerName(slsv1alpha1.ProjectGroupKind)
-	options := []managed.ReconcilerOption{
-		managed.WithExternalConnecter(&connector{
-			client:      mgr.GetClient(),
-			usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &v1alpha1.ProviderConfigUsage{}),
-			NewClientFn: slsclient.NewClient,
-		}),
-		managed.WithLogger(l.WithValues("controller", name)),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-	}
-	return ctrl.NewControllerManagedBy(mgr).
-		Named(name).
-		For(&slsv1alpha1.Project{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(slsv1alpha1.ProjectGroupVersionKind), options...))
-}
-
-type connector struct {
-	client      client.Client
-	usage       resource.Tracker
-	NewClientFn func(accessKeyID, accessKeySecret, securityToken, region string) *slsclient.LogClient
-}
-
-func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) { //nolint:gocyclo
-	cr, ok := mg.(*slsv1alpha1.Project)
-	if !ok {
-		return nil, errors.New(errNotProject)
-	}
-
-	var (
-		sel    *xpv1.SecretKeySelector
-		region string
-	)
-
-	switch {
-	case cr.GetProviderConfigReference() != nil:
-		if err := c.usage.Track(ctx, mg); err != nil {
-			return nil, errors.Wrap(err, errTrackUsage)
-		}
+func SetupProject(mgr ctrl.Manager, l logging.Logger, o adapter.Options) error {
+	return adapter.Setup(mgr, l, projectKind, o)
+}
 
-		pc := &v1alpha1.ProviderConfig{}
-		if err := c.client.Get(ctx, types.NamespacedName{Name: cr.Spec.ProviderConfigReference.Name}, pc); err != nil {
-			return nil, errors.Wrap(err, errGetProviderConfig)
-		}
-		if s := pc.Spec.Credentials.Source; s != xpv1.CredentialsSourceSecret {
-			return nil, errors.Errorf(errFmtUnsupportedCredSource, s)
-		}
-		sel = pc.Spec.Credentials.SecretRef
-		region = pc.Spec.Region
-	default:
-		return nil, errors.New(errNoProvider)
-	}
+// ProjectKind returns the adapter.Kind of SLS Projects.
+func ProjectKind() adapter.Kind {
+	return projectKind
+}
 
-	if sel == nil {
-		return nil, errors.New(errNoConnectionSecret)
-	}
+var projectKind = adapter.Kind{
+	Type:              &slsv1alpha1.Project{},
+	GroupVersionKind:  slsv1alpha1.ProjectGroupVersionKind,
+	NewClient:         newSLSClient,
+	Describe:          describeProject,
+	IsNotFound:        slsclient.IsNotFoundError,
+	Observe:           observeProject,
+	IsUpToDate:        isProjectUpToDate,
+	ConnectionDetails: getProjectConnectionDetails,
+	Create:            createProject,
+	Update:            updateProject,
+	Delete:            deleteProject,
+	Dependents:        getProjectDependents,
+	Discover:          discoverProjects,
+	Parameters:        projectParameters,
+}
+
+// newSLSClient returns the SLS client shared by all SLS managed resources.
+func newSLSClient(ctx context.Context, _ resource.Managed, creds util.Credentials) (interface{}, error) {
+	return slsclient.NewClient(creds.AccessKeyID, creds.AccessKeySecret, creds.SecurityToken, creds.Region), nil
+}
+
+func describeProject(ctx context.Context, c interface{}, mg resource.Managed) (interface{}, error) {
+	return c.(slsclient.LogClientInterface).Describe(ctx, meta.GetExternalName(mg))
+}
+
+func observeProject(mg resource.Managed, observed interface{}) {
+	mg.(*slsv1alpha1.Project).Status.AtProvider = slsclient.GenerateObservation(observed.(*sdk.LogProject))
+}
+
+func isProjectUpToDate(mg resource.Managed, observed interface{}) bool {
+	cr := mg.(*slsv1alpha1.Project)
+	project := observed.(*sdk.LogProject)
+	return meta.GetExternalName(cr) == project.Name && cr.Spec.ForProvider.Description == project.Description
+}
 
-	s := &corev1.Secret{}
-	nn := types.NamespacedName{Namespace: sel.Namespace, Name: sel.Name}
-	if err := c.client.Get(ctx, nn, s); err != nil {
-		return nil, errors.Wrap(err, errGetConnectionSecret)
+func getProjectConnectionDetails(ctx context.Context, _ interface{}, _ resource.Managed, observed interface{}) (managed.ConnectionDetails, error) {
+	return getConnectionDetails(observed.(*sdk.LogProject)), nil
+}
+
+func createProject(ctx context.Context, c interface{}, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr := mg.(*slsv1alpha1.Project)
+	project, err := c.(slsclient.LogClientInterface).Create(ctx, meta.GetExternalName(cr), cr.Spec.ForProvider.Description)
+	if err != nil {
+		return managed.ExternalCreation{}, err
 	}
+	return managed.ExternalCreation{ConnectionDetails: getConnectionDetails(project)}, nil
+}
+
+func updateProject(ctx context.Context, c interface{}, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr := mg.(*slsv1alpha1.Project)
+	_, err := c.(slsclient.LogClientInterface).Update(ctx, meta.GetExternalName(cr), cr.Spec.ForProvider.Description)
+	return managed.ExternalUpdate{}, err
+}
 
-	slsClient := c.NewClientFn(string(s.Data[util.AccessKeyID]), string(s.Data[util.AccessKeySecret]), string(s.Data[util.SecurityToken]), region)
-	return &external{client: slsClient}, nil
+func deleteProject(ctx context.Context, c interface{}, mg resource.Managed) error {
+	return c.(slsclient.LogClientInterface).Delete(ctx, meta.GetExternalName(mg))
 }
 
-type external struct {
-	client slsclient.LogClientInterface
+func projectParameters(observed interface{}) interface{} {
+	return slsclient.GenerateParameters(observed.(*sdk.LogProject))
+}
+
+func discoverProjects(ctx context.Context, c interface{}, f adapter.Filter) ([]resource.Managed, error) {
+	sls := c.(slsclient.LogClientInterface)
+	projects, err := sls.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var tagged map[string]map[string]string
+	if len(f.Tags) > 0 {
+		if tagged, err = sls.ListTags(ctx, f.Tags); err != nil {
+			return nil, err
+		}
+	}
+	var mgs []resource.Managed
+	for i := range projects {
+		p := &projects[i]
+		if !f.MatchesName(p.Name) || (len(f.Tags) > 0 && !f.MatchesTags(tagged[p.Name])) {
+			continue
+		}
+		cr := &slsv1alpha1.Project{}
+		cr.SetName(p.Name)
+		meta.SetExternalName(cr, p.Name)
+		cr.Spec.ForProvider = slsclient.GenerateParameters(p)
+		mgs = append(mgs, cr)
+	}
+	return mgs, nil
 }
 
-func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
-	cr, ok := mg.(*slsv1alpha1.Project)
-	if !ok {
-		return managed.ExternalObservation{}, errors.New(errNotProject)
+// getProjectDependents lists the SLS managed resources that live in the
+// supplied project and therefore have to be deleted before it. Managed
+// resources only live in the project if they reach its cloud account and
+// region, since projects of other accounts and regions may have its name.
+func getProjectDependents(ctx context.Context, c client.Reader, mg resource.Managed) ([]util.Dependent, error) { //nolint:gocyclo
+	project := meta.GetExternalName(mg)
+	var dependents []util.Dependent
+
+	stores := &slsv1alpha1.LogStoreList{}
+	if err := c.List(ctx, stores); err != nil {
+		return nil, err
 	}
-	projectName := meta.GetExternalName(cr)
-	project, err := e.client.Describe(projectName)
-	if slsclient.IsNotFoundError(err) {
-		return managed.ExternalObservation{
-			ResourceExists: false,
-		}, nil
+	for i := range stores.Items {
+		if stores.Items[i].Spec.ForProvider.ProjectName == project {
+			dependents = append(dependents, util.Dependent{Kind: slsv1alpha1.StoreKind, Managed: &stores.Items[i]})
+		}
 	}
 
-	if err != nil {
-		return managed.ExternalObservation{}, err
+	indexes := &slsv1alpha1.LogstoreIndexList{}
+	if err := c.List(ctx, indexes); err != nil {
+		return nil, err
+	}
+	for i := range indexes.Items {
+		if p := indexes.Items[i].Spec.ForProvider.ProjectName; p != nil && *p == project {
+			dependents = append(dependents, util.Dependent{Kind: slsv1alpha1.IndexKind, Managed: &indexes.Items[i]})
+		}
 	}
 
-	cr.Status.AtProvider = slsclient.GenerateObservation(project)
-	var upToDate bool
-	if (projectName == project.Name) && (cr.Spec.ForProvider.Description == project.Description) {
-		upToDate = true
-		cr.SetConditions(xpv1.Available())
+	logtails := &slsv1alpha1.LogtailList{}
+	if err := c.List(ctx, logtails); err != nil {
+		return nil, err
+	}
+	for i := range logtails.Items {
+		if logtails.Items[i].Spec.ForProvider.OutputDetail.ProjectName == project {
+			dependents = append(dependents, util.Dependent{Kind: slsv1alpha1.LogtailKind, Managed: &logtails.Items[i]})
+		}
 	}
 
-	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  upToDate,
-		ConnectionDetails: getConnectionDetails(project),
-	}, nil
-}
+	groups := &slsv1alpha1.MachineGroupList{}
+	if err := c.List(ctx, groups); err != nil {
+		return nil, err
+	}
+	for i := range groups.Items {
+		if p := groups.Items[i].Spec.ForProvider.Project; p != nil && *p == project {
+			dependents = append(dependents, util.Dependent{Kind: slsv1alpha1.MachineGroupKind, Managed: &groups.Items[i]})
+		}
+	}
 
-func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
-	cr, ok := mg.(*slsv1alpha1.Project)
-	if !ok {
-		return managed.ExternalCreation{}, errors.New(errNotProject)
+	bindings := &slsv1alpha1.MachineGroupBindingList{}
+	if err := c.List(ctx, bindings); err != nil {
+		return nil, err
 	}
-	name := meta.GetExternalName(cr)
-	description := cr.Spec.ForProvider.Description
-	cr.SetConditions(xpv1.Creating())
-	project, err := e.client.Create(name, description)
-	if err != nil {
-		return managed.ExternalCreation{}, err
+	for i := range bindings.Items {
+		if p := bindings.Items[i].Spec.ForProvider.ProjectName; p != nil && *p == project {
+			dependents = append(dependents, util.Dependent{Kind: slsv1alpha1.MachineGroupBindingKind, Managed: &bindings.Items[i]})
+		}
 	}
-	return managed.ExternalCreation{ConnectionDetails: getConnectionDetails(project)}, nil
+
+	return inSameCloud(ctx, c, mg, dependents)
 }
 
-func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	cr, ok := mg.(*slsv1alpha1.Project)
-	if !ok {
-		return managed.ExternalUpdate{}, errors.New(errNotProject)
-	}
-	name := meta.GetExternalName(cr)
-	description := cr.Spec.ForProvider.Description
-	cr.Status.SetConditions(xpv1.Creating())
-	got, err := e.client.Update(name, description)
+// inSameCloud returns the supplied dependents that reach the cloud account and
+// region of the supplied managed resource, i.e. that use its ProviderConfig or
+// a ProviderConfig with the same credentials and region.
+func inSameCloud(ctx context.Context, c client.Reader, mg resource.Managed, dependents []util.Dependent) ([]util.Dependent, error) {
+	clouds := map[string]string{}
+	want, err := cloudOf(ctx, c, mg, clouds)
 	if err != nil {
-		return managed.ExternalUpdate{}, err
+		return nil, err
 	}
-
-	if got.Description != description {
-		return managed.ExternalUpdate{}, err
+	var same []util.Dependent
+	for _, d := range dependents {
+		got, err := cloudOf(ctx, c, d.Managed, clouds)
+		if err != nil {
+			return nil, err
+		}
+		if got == want {
+			same = append(same, d)
+		}
 	}
-	return managed.ExternalUpdate{}, nil
+	return same, nil
 }
 
-func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
-	cr, ok := mg.(*slsv1alpha1.Project)
-	if !ok {
-		return errors.New(errNotProject)
+// cloudOf returns a key that identifies the cloud account and region of the
+// supplied managed resource, i.e. the credentials Secret and region of its
+// ProviderConfig. The keys of ProviderConfigs are cached in the supplied map.
+// Managed resources whose ProviderConfig does not exist are identified by its
+// name.
+func cloudOf(ctx context.Context, c client.Reader, mg resource.Managed, clouds map[string]string) (string, error) {
+	ref := mg.GetProviderConfigReference()
+	if ref == nil {
+		if p := mg.GetProviderReference(); p != nil {
+			return "provider/" + p.Name, nil
+		}
+		return "", nil
+	}
+	if k, ok := clouds[ref.Name]; ok {
+		return k, nil
+	}
+	pc := &aliv1alpha1.ProviderConfig{}
+	err := c.Get(ctx, types.NamespacedName{Name: ref.Name}, pc)
+	if resource.IgnoreNotFound(err) != nil {
+		return "", errors.Wrapf(err, errFmtGetProviderConfig, ref.Name)
 	}
-	name := meta.GetExternalName(cr)
-	cr.SetConditions(xpv1.Deleting())
-	if err := e.client.Delete(name); err != nil && !slsclient.IsNotFoundError(err) {
-		return err
+	k := "providerconfig/" + ref.Name
+	if s := pc.Spec.Credentials.SecretRef; err == nil && s != nil {
+		k = fmt.Sprintf("secret/%s/%s/%s/%s", s.Namespace, s.Name, s.Key, pc.Spec.Region)
 	}
-	return nil
+	clouds[ref.Name] = k
+	return k, nil
 }
 
 func getConnectionDetails(project *sdk.LogProject) managed.ConnectionDetails {
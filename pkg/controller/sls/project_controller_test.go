@@ -23,16 +23,23 @@ import (
 	"testing"
 
 	sdk "github.com/aliyun/aliyun-log-go-sdk"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	slsv1alpha1 "github.com/crossplane/provider-alibaba/apis/sls/v1alpha1"
+	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
 	slsclient "github.com/crossplane/provider-alibaba/pkg/clients/sls"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
 )
 
 var (
@@ -56,7 +63,7 @@ type fakeSDKClient struct {
 }
 
 // Describe describes SLS project
-func (c *fakeSDKClient) Describe(name string) (*sdk.LogProject, error) {
+func (c *fakeSDKClient) Describe(ctx context.Context, name string) (*sdk.LogProject, error) {
 	switch name {
 	case "":
 		return nil, sdk.Error{Code: slsclient.ErrCodeProjectNotExist, HTTPCode: int32(0)}
@@ -72,18 +79,28 @@ func (c *fakeSDKClient) Describe(name string) (*sdk.LogProject, error) {
 	}
 }
 
+// List lists SLS projects
+func (c *fakeSDKClient) List(ctx context.Context) ([]sdk.LogProject, error) {
+	return nil, nil
+}
+
+// ListTags lists the tags of SLS projects
+func (c *fakeSDKClient) ListTags(ctx context.Context, tags map[string]string) (map[string]map[string]string, error) {
+	return nil, nil
+}
+
 // Create creates SLS project
-func (c *fakeSDKClient) Create(name, description string) (*sdk.LogProject, error) {
+func (c *fakeSDKClient) Create(ctx context.Context, name, description string) (*sdk.LogProject, error) {
 	return validProject, nil
 }
 
 // Update sets SLS project description
-func (c *fakeSDKClient) Update(name, description string) (*sdk.LogProject, error) {
+func (c *fakeSDKClient) Update(ctx context.Context, name, description string) (*sdk.LogProject, error) {
 	return validProject, nil
 }
 
 // Delete deletes SLS project
-func (c *fakeSDKClient) Delete(name string) error {
+func (c *fakeSDKClient) Delete(ctx context.Context, name string) error {
 	return nil
 }
 
@@ -102,14 +119,6 @@ func TestObserve(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotSLSProject": {
-			reason: "We should return an error if the supplied managed resource is not an SLS project",
-			mg:     nil,
-			want: want{
-				o:   managed.ExternalObservation{},
-				err: errors.New(errNotProject),
-			},
-		},
 		"SLSProjectNotFound": {
 			reason: "SLS Project name could not be found",
 			mg:     &slsv1alpha1.Project{},
@@ -150,7 +159,7 @@ func TestObserve(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			external := &external{client: &fakeSDKClient{}}
+			external := adapter.NewExternalClient(projectKind, nil, &fakeSDKClient{})
 			got, err := external.Observe(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -177,14 +186,6 @@ func TestCreate(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotSLSProject": {
-			reason: "Not an Project object",
-			mg:     nil,
-			want: want{
-				o:   managed.ExternalCreation{},
-				err: errors.New(errNotProject),
-			},
-		},
 		"Success": {
 			reason: "Creating an SLS project successfully",
 			mg:     validCR,
@@ -198,7 +199,7 @@ func TestCreate(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			external := &external{client: &fakeSDKClient{}}
+			external := adapter.NewExternalClient(projectKind, nil, &fakeSDKClient{})
 			got, err := external.Create(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -225,14 +226,6 @@ func TestUpdate(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotSLSProject": {
-			reason: "Not an Project object",
-			mg:     nil,
-			want: want{
-				o:   managed.ExternalUpdate{},
-				err: errors.New(errNotProject),
-			},
-		},
 		"Success": {
 			reason: "Creating an SLS project successfully",
 			mg:     validCR,
@@ -245,7 +238,7 @@ func TestUpdate(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			external := &external{client: &fakeSDKClient{}}
+			external := adapter.NewExternalClient(projectKind, nil, &fakeSDKClient{})
 			got, err := external.Update(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Update(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -262,24 +255,36 @@ func TestDelete(t *testing.T) {
 		ctx = context.Background()
 	)
 
+	withLogStore := test.NewMockListFn(nil, func(obj runtime.Object) error {
+		if l, ok := obj.(*slsv1alpha1.LogStoreList); ok {
+			store := slsv1alpha1.LogStore{ObjectMeta: metav1.ObjectMeta{Name: "store"}}
+			store.Spec.ForProvider.ProjectName = "def"
+			l.Items = []slsv1alpha1.LogStore{store}
+		}
+		return nil
+	})
+
 	type want struct {
 		err error
 	}
 
 	cases := map[string]struct {
 		reason string
+		kube   client.Client
 		mg     resource.Managed
 		want   want
 	}{
-		"NotSLSProject": {
-			reason: "Not an Project object",
-			mg:     nil,
+		"WaitForDependents": {
+			reason: "Deleting an SLS project with LogStores should wait for them",
+			kube:   &test.MockClient{MockList: withLogStore},
+			mg:     validCR.DeepCopy(),
 			want: want{
-				err: errors.New(errNotProject),
+				err: nil,
 			},
 		},
 		"Success": {
 			reason: "Creating an SLS project successfully",
+			kube:   &test.MockClient{MockList: test.NewMockListFn(nil)},
 			mg:     validCR,
 			want: want{
 				err: nil,
@@ -289,7 +294,7 @@ func TestDelete(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			external := &external{client: &fakeSDKClient{}}
+			external := adapter.NewExternalClient(projectKind, tc.kube, &fakeSDKClient{})
 			err := external.Delete(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -297,3 +302,95 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestGetProjectDependents(t *testing.T) {
+	secret := &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Namespace: "crossplane-system", Name: "creds"}, Key: "credentials"}
+	pcs := map[string]aliv1alpha1.ProviderConfigSpec{
+		"a":        {Region: "cn-beijing", ProviderConfigSpec: xpv1.ProviderConfigSpec{Credentials: xpv1.ProviderCredentials{SecretRef: secret}}},
+		"b":        {Region: "cn-beijing", ProviderConfigSpec: xpv1.ProviderConfigSpec{Credentials: xpv1.ProviderCredentials{SecretRef: secret}}},
+		"hangzhou": {Region: "cn-hangzhou", ProviderConfigSpec: xpv1.ProviderConfigSpec{Credentials: xpv1.ProviderCredentials{SecretRef: secret}}},
+	}
+	getProviderConfig := func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+		spec, ok := pcs[key.Name]
+		if !ok {
+			return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+		}
+		obj.(*aliv1alpha1.ProviderConfig).Spec = spec
+		return nil
+	}
+	store := func(project, pc string) slsv1alpha1.LogStore {
+		s := slsv1alpha1.LogStore{ObjectMeta: metav1.ObjectMeta{Name: "store"}}
+		s.Spec.ForProvider.ProjectName = project
+		s.SetProviderConfigReference(&xpv1.Reference{Name: pc})
+		return s
+	}
+	withLogStore := func(s slsv1alpha1.LogStore) test.MockListFn {
+		return test.NewMockListFn(nil, func(obj runtime.Object) error {
+			if l, ok := obj.(*slsv1alpha1.LogStoreList); ok {
+				l.Items = []slsv1alpha1.LogStore{s}
+			}
+			return nil
+		})
+	}
+
+	type want struct {
+		dependents int
+		err        error
+	}
+
+	cases := map[string]struct {
+		reason string
+		get    test.MockGetFn
+		store  slsv1alpha1.LogStore
+		want   want
+	}{
+		"SameProviderConfig": {
+			reason: "LogStores of the project that use its ProviderConfig should be dependents",
+			get:    getProviderConfig,
+			store:  store("def", "a"),
+			want:   want{dependents: 1},
+		},
+		"SameCloud": {
+			reason: "LogStores of the project that use a ProviderConfig with its credentials and region should be dependents",
+			get:    getProviderConfig,
+			store:  store("def", "b"),
+			want:   want{dependents: 1},
+		},
+		"OtherRegion": {
+			reason: "LogStores of a project of the same name in another region should not be dependents",
+			get:    getProviderConfig,
+			store:  store("def", "hangzhou"),
+		},
+		"MissingProviderConfig": {
+			reason: "LogStores whose ProviderConfig does not exist should not be dependents",
+			get:    getProviderConfig,
+			store:  store("def", "deleted"),
+		},
+		"OtherProject": {
+			reason: "LogStores of other projects should not be dependents",
+			get:    getProviderConfig,
+			store:  store("abc", "a"),
+		},
+		"GetProviderConfigFailed": {
+			reason: "Errors getting a ProviderConfig should be returned",
+			get:    test.NewMockGetFn(errors.New("boom")),
+			store:  store("def", "a"),
+			want:   want{err: errors.Wrapf(errors.New("boom"), errFmtGetProviderConfig, "a")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kube := &test.MockClient{MockGet: tc.get, MockList: withLogStore(tc.store)}
+			cr := validCR.DeepCopy()
+			cr.SetProviderConfigReference(&xpv1.Reference{Name: "a"})
+			got, err := getProjectDependents(context.Background(), kube, cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ngetProjectDependents(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.dependents, len(got)); diff != "" {
+				t.Errorf("\n%s\ngetProjectDependents(...): -want dependents, +got dependents:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
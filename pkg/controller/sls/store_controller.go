@@ -22,139 +22,61 @@ import (
 	"context"
 
 	sdk "github.com/aliyun/aliyun-log-go-sdk"
-	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
-	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	slsv1alpha1 "github.com/crossplane/provider-alibaba/apis/sls/v1alpha1"
-	"github.com/crossplane/provider-alibaba/apis/v1alpha1"
 	slsclient "github.com/crossplane/provider-alibaba/pkg/clients/sls"
-	"github.com/crossplane/provider-alibaba/pkg/util"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
 )
 
 const (
-	errNotStore               = "managed resource is not a SLS store custom resource"
 	errMaxSplitShardMustBeSet = "maxSplitShard must be set if autoSplit is true"
 )
 
 // SetupStore adds a controller that reconciles SLSStores.
-func SetupStore(mgr ctrl.Manager, l logging.Logger) error {
-	name := managed.ControllerName(slsv1alpha1.StoreGroupKind)
-	options := []managed.ReconcilerOption{
-		managed.WithExternalConnecter(&logStoreConnector{
-			client:      mgr.GetClient(),
-			usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &v1alpha1.ProviderConfigUsage{}),
-			NewClientFn: slsclient.NewClient,
-		}),
-		managed.WithLogger(l.WithValues("controller", name)),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-	}
-	return ctrl.NewControllerManagedBy(mgr).
-		Named(name).
-		For(&slsv1alpha1.LogStore{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(slsv1alpha1.StoreGroupVersionKind), options...))
+func SetupStore(mgr ctrl.Manager, l logging.Logger, o adapter.Options) error {
+	return adapter.Setup(mgr, l, storeKind, o)
 }
 
-type logStoreConnector struct {
-	client      client.Client
-	usage       resource.Tracker
-	NewClientFn func(accessKeyID, accessKeySecret, securityToken, region string) *slsclient.LogClient
+var storeKind = adapter.Kind{
+	Type:              &slsv1alpha1.LogStore{},
+	GroupVersionKind:  slsv1alpha1.StoreGroupVersionKind,
+	NewClient:         newSLSClient,
+	Describe:          describeStore,
+	IsNotFound:        slsclient.IsStoreNotFoundError,
+	Observe:           observeStore,
+	IsUpToDate:        isStoreUpToDate,
+	ConnectionDetails: getStoreObservedConnectionDetails,
+	Create:            createStore,
+	Update:            updateStore,
+	Delete:            deleteStore,
 }
 
-func (c *logStoreConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) { //nolint:gocyclo
-	cr, ok := mg.(*slsv1alpha1.LogStore)
-	if !ok {
-		return nil, errors.New(errNotStore)
-	}
-
-	var (
-		sel    *xpv1.SecretKeySelector
-		region string
-	)
-
-	switch {
-	case cr.GetProviderConfigReference() != nil:
-		if err := c.usage.Track(ctx, mg); err != nil {
-			return nil, errors.Wrap(err, errTrackUsage)
-		}
-
-		pc := &v1alpha1.ProviderConfig{}
-		if err := c.client.Get(ctx, types.NamespacedName{Name: cr.Spec.ProviderConfigReference.Name}, pc); err != nil {
-			return nil, errors.Wrap(err, errGetProviderConfig)
-		}
-		if s := pc.Spec.Credentials.Source; s != xpv1.CredentialsSourceSecret {
-			return nil, errors.Errorf(errFmtUnsupportedCredSource, s)
-		}
-		sel = pc.Spec.Credentials.SecretRef
-		region = pc.Spec.Region
-	default:
-		return nil, errors.New(errNoProvider)
-	}
-
-	if sel == nil {
-		return nil, errors.New(errNoConnectionSecret)
-	}
-
-	s := &corev1.Secret{}
-	nn := types.NamespacedName{Namespace: sel.Namespace, Name: sel.Name}
-	if err := c.client.Get(ctx, nn, s); err != nil {
-		return nil, errors.Wrap(err, errGetConnectionSecret)
-	}
-
-	slsClient := c.NewClientFn(string(s.Data[util.AccessKeyID]), string(s.Data[util.AccessKeySecret]), string(s.Data[util.SecurityToken]), region)
-	return &storeExternal{client: slsClient}, nil
+func describeStore(ctx context.Context, c interface{}, mg resource.Managed) (interface{}, error) {
+	cr := mg.(*slsv1alpha1.LogStore)
+	return c.(slsclient.LogClientInterface).DescribeStore(ctx, cr.Spec.ForProvider.ProjectName, meta.GetExternalName(cr))
 }
 
-type storeExternal struct {
-	client slsclient.LogClientInterface
+func observeStore(mg resource.Managed, observed interface{}) {
+	mg.(*slsv1alpha1.LogStore).Status.AtProvider = slsclient.GenerateStoreObservation(observed.(*sdk.LogStore))
 }
 
-func (e *storeExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
-	cr, ok := mg.(*slsv1alpha1.LogStore)
-	if !ok {
-		return managed.ExternalObservation{}, errors.New(errNotStore)
-	}
-
-	storeName := meta.GetExternalName(cr)
-	project := cr.Spec.ForProvider.ProjectName
-
-	store, err := e.client.DescribeStore(project, storeName)
-	if slsclient.IsStoreNotFoundError(err) {
-		return managed.ExternalObservation{
-			ResourceExists: false,
-		}, nil
-	}
-	if err != nil {
-		return managed.ExternalObservation{}, err
-	}
-
-	cr.Status.AtProvider = slsclient.GenerateStoreObservation(store)
-	upToDate := slsclient.IsStoreUpdateToDate(cr, store)
-	if upToDate {
-		cr.SetConditions(xpv1.Available())
-	}
+func isStoreUpToDate(mg resource.Managed, observed interface{}) bool {
+	return slsclient.IsStoreUpdateToDate(mg.(*slsv1alpha1.LogStore), observed.(*sdk.LogStore))
+}
 
-	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  upToDate,
-		ConnectionDetails: getStoreConnectionDetails(project, storeName),
-	}, nil
+func getStoreObservedConnectionDetails(ctx context.Context, _ interface{}, mg resource.Managed, _ interface{}) (managed.ConnectionDetails, error) {
+	cr := mg.(*slsv1alpha1.LogStore)
+	return getStoreConnectionDetails(cr.Spec.ForProvider.ProjectName, meta.GetExternalName(cr)), nil
 }
 
-func (e *storeExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
-	cr, ok := mg.(*slsv1alpha1.LogStore)
-	if !ok {
-		return managed.ExternalCreation{}, errors.New(errNotStore)
-	}
+func createStore(ctx context.Context, c interface{}, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr := mg.(*slsv1alpha1.LogStore)
 	name := meta.GetExternalName(cr)
 	store := &sdk.LogStore{
 		Name:       name,
@@ -170,31 +92,21 @@ func (e *storeExternal) Create(ctx context.Context, mg resource.Managed) (manage
 	if cr.Spec.ForProvider.MaxSplitShard != nil {
 		store.MaxSplitShard = *cr.Spec.ForProvider.MaxSplitShard
 	}
-	cr.SetConditions(xpv1.Creating())
-	err := e.client.CreateStore(cr.Spec.ForProvider.ProjectName, store)
-	if err != nil {
+	if err := c.(slsclient.LogClientInterface).CreateStore(ctx, cr.Spec.ForProvider.ProjectName, store); err != nil {
 		return managed.ExternalCreation{}, err
 	}
 	return managed.ExternalCreation{ConnectionDetails: getStoreConnectionDetails(cr.Spec.ForProvider.ProjectName, name)}, nil
 }
 
-func (e *storeExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	cr, ok := mg.(*slsv1alpha1.LogStore)
-	if !ok {
-		return managed.ExternalUpdate{}, errors.New(errNotStore)
-	}
-	cr.Status.SetConditions(xpv1.Creating())
-	err := e.client.UpdateStore(cr.Spec.ForProvider.ProjectName, meta.GetExternalName(cr), cr.Spec.ForProvider.TTL)
+func updateStore(ctx context.Context, c interface{}, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr := mg.(*slsv1alpha1.LogStore)
+	err := c.(slsclient.LogClientInterface).UpdateStore(ctx, cr.Spec.ForProvider.ProjectName, meta.GetExternalName(cr), cr.Spec.ForProvider.TTL)
 	return managed.ExternalUpdate{}, err
 }
 
-func (e *storeExternal) Delete(ctx context.Context, mg resource.Managed) error {
-	cr, ok := mg.(*slsv1alpha1.LogStore)
-	if !ok {
-		return errors.New(errNotStore)
-	}
-	cr.SetConditions(xpv1.Deleting())
-	return e.client.DeleteStore(cr.Spec.ForProvider.ProjectName, meta.GetExternalName(cr))
+func deleteStore(ctx context.Context, c interface{}, mg resource.Managed) error {
+	cr := mg.(*slsv1alpha1.LogStore)
+	return c.(slsclient.LogClientInterface).DeleteStore(ctx, cr.Spec.ForProvider.ProjectName, meta.GetExternalName(cr))
 }
 
 func getStoreConnectionDetails(project, store string) managed.ConnectionDetails {
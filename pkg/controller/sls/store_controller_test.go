@@ -33,6 +33,7 @@ import (
 
 	slsv1alpha1 "github.com/crossplane/provider-alibaba/apis/sls/v1alpha1"
 	slsclient "github.com/crossplane/provider-alibaba/pkg/clients/sls"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
 )
 
 var (
@@ -62,7 +63,7 @@ var (
 	validStore = &sdk.LogStore{Name: store, TTL: 1, ShardCount: 2}
 )
 
-func (c *fakeSDKClient) DescribeStore(project string, logstore string) (*sdk.LogStore, error) {
+func (c *fakeSDKClient) DescribeStore(ctx context.Context, project string, logstore string) (*sdk.LogStore, error) {
 	switch logstore {
 	case "":
 		return nil, errors.Wrap(&sdk.Error{Code: slsclient.ErrCodeStoreNotExist}, "xxx")
@@ -73,15 +74,15 @@ func (c *fakeSDKClient) DescribeStore(project string, logstore string) (*sdk.Log
 	}
 }
 
-func (c *fakeSDKClient) CreateStore(project string, logstore *sdk.LogStore) error {
+func (c *fakeSDKClient) CreateStore(ctx context.Context, project string, logstore *sdk.LogStore) error {
 	return nil
 }
 
-func (c *fakeSDKClient) UpdateStore(project string, logstore string, ttl int) error {
+func (c *fakeSDKClient) UpdateStore(ctx context.Context, project string, logstore string, ttl int) error {
 	return nil
 }
 
-func (c *fakeSDKClient) DeleteStore(project string, logstore string) error {
+func (c *fakeSDKClient) DeleteStore(ctx context.Context, project string, logstore string) error {
 	return nil
 }
 
@@ -100,14 +101,6 @@ func TestStoreObserve(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotSLSStore": {
-			reason: "We should return an error if the supplied managed resource is not SLS store",
-			mg:     nil,
-			want: want{
-				o:   managed.ExternalObservation{},
-				err: errors.New(errNotStore),
-			},
-		},
 		"SLSStoreNotFound": {
 			reason: "SLS LogStore name could not be found",
 			mg:     &slsv1alpha1.LogStore{},
@@ -150,7 +143,7 @@ func TestStoreObserve(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			external := &storeExternal{client: &fakeSDKClient{}}
+			external := adapter.NewExternalClient(storeKind, nil, &fakeSDKClient{})
 			got, err := external.Observe(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -177,14 +170,6 @@ func TestStoreCreate(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotSLSStore": {
-			reason: "Not LogStore object",
-			mg:     nil,
-			want: want{
-				o:   managed.ExternalCreation{},
-				err: errors.New(errNotStore),
-			},
-		},
 		"Success": {
 			reason: "Creating SLS store successfully",
 			mg:     validStoreCR,
@@ -198,7 +183,7 @@ func TestStoreCreate(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			external := &storeExternal{client: &fakeSDKClient{}}
+			external := adapter.NewExternalClient(storeKind, nil, &fakeSDKClient{})
 			got, err := external.Create(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -225,14 +210,6 @@ func TestStoreUpdate(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotSLSStore": {
-			reason: "Not LogStore object",
-			mg:     nil,
-			want: want{
-				o:   managed.ExternalUpdate{},
-				err: errors.New(errNotStore),
-			},
-		},
 		"Success": {
 			reason: "Creating SLS store successfully",
 			mg:     validStoreCR,
@@ -245,7 +222,7 @@ func TestStoreUpdate(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			external := &storeExternal{client: &fakeSDKClient{}}
+			external := adapter.NewExternalClient(storeKind, nil, &fakeSDKClient{})
 			got, err := external.Update(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Update(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -271,13 +248,6 @@ func TestStoreDelete(t *testing.T) {
 		mg     resource.Managed
 		want   want
 	}{
-		"NotSLSStore": {
-			reason: "Not LogStore object",
-			mg:     nil,
-			want: want{
-				err: errors.New(errNotStore),
-			},
-		},
 		"Success": {
 			reason: "Creating SLS store successfully",
 			mg:     validStoreCR,
@@ -289,7 +259,7 @@ func TestStoreDelete(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			external := &storeExternal{client: &fakeSDKClient{}}
+			external := adapter.NewExternalClient(storeKind, nil, &fakeSDKClient{})
 			err := external.Delete(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -0,0 +1,211 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package discovery generates managed resource manifests for the existing
+// cloud resources of an Alibaba Cloud account, so that they can be imported.
+package discovery
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
+	"github.com/crossplane/provider-alibaba/pkg/util"
+)
+
+const (
+	errGetProviderConfig        = "cannot get referenced ProviderConfig"
+	errFmtUnsupportedCredSource = "unsupported credentials source %q"
+	errFmtUnknownKind           = "unknown kind %q"
+	errFmtDiscover              = "cannot discover %s resources"
+	errConvert                  = "cannot convert managed resource"
+	errMarshal                  = "cannot marshal managed resource"
+	errWrite                    = "cannot write managed resource"
+)
+
+// Options configure discovery.
+type Options struct {
+	// ProviderConfig whose credentials are used to discover cloud resources,
+	// and which the discovered managed resources reference.
+	ProviderConfig string
+
+	// Region to discover cloud resources in. Defaults to the region of the
+	// ProviderConfig.
+	Region string
+
+	// Kinds of managed resources to discover, e.g. RDSInstance. All
+	// discoverable kinds are discovered if empty.
+	Kinds []string
+
+	// NamePrefix selects cloud resources whose name starts with it.
+	NamePrefix string
+
+	// Tags selects cloud resources that have all of these tags.
+	Tags map[string]string
+
+	// DeletionPolicy of the discovered managed resources. Defaults to Orphan
+	// so that deleting an imported resource does not delete the cloud
+	// resource.
+	DeletionPolicy xpv1.DeletionPolicy
+}
+
+// Discover returns managed resources for the existing cloud resources of the
+// supplied kinds, using the credentials of the ProviderConfig configured by
+// the supplied options.
+func Discover(ctx context.Context, kube client.Client, kinds []adapter.Kind, o Options) ([]resource.Managed, error) {
+	selected, err := selectKinds(kinds, o.Kinds)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := &aliv1alpha1.ProviderConfig{}
+	if err := kube.Get(ctx, types.NamespacedName{Name: o.ProviderConfig}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetProviderConfig)
+	}
+	if s := pc.Spec.Credentials.Source; s != xpv1.CredentialsSourceSecret {
+		return nil, errors.Errorf(errFmtUnsupportedCredSource, s)
+	}
+	region := pc.Spec.Region
+	if o.Region != "" {
+		region = o.Region
+	}
+	creds, err := util.GetCredentials(ctx, kube, pc.Spec.Credentials.SecretRef, region)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := o.DeletionPolicy
+	if policy == "" {
+		policy = xpv1.DeletionOrphan
+	}
+
+	var discovered []resource.Managed
+	for _, k := range selected {
+		mgs, err := adapter.Discover(ctx, k, creds, adapter.Filter{NamePrefix: o.NamePrefix, Tags: o.Tags})
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtDiscover, k.GroupVersionKind.Kind)
+		}
+		names := map[string]bool{}
+		for _, mg := range mgs {
+			mg.GetObjectKind().SetGroupVersionKind(k.GroupVersionKind)
+			mg.SetName(uniqueName(names, mg.GetName(), meta.GetExternalName(mg)))
+			mg.SetProviderConfigReference(&xpv1.Reference{Name: o.ProviderConfig})
+			mg.SetDeletionPolicy(policy)
+			discovered = append(discovered, mg)
+		}
+	}
+	return discovered, nil
+}
+
+func selectKinds(kinds []adapter.Kind, names []string) ([]adapter.Kind, error) {
+	if len(names) == 0 {
+		return kinds, nil
+	}
+	selected := make([]adapter.Kind, 0, len(names))
+	for _, n := range names {
+		found := false
+		for _, k := range kinds {
+			if strings.EqualFold(k.GroupVersionKind.Kind, n) {
+				selected = append(selected, k)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.Errorf(errFmtUnknownKind, n)
+		}
+	}
+	return selected, nil
+}
+
+// uniqueName returns a valid object name derived from the supplied cloud
+// resource name, or from its external name if the cloud resource name can't
+// be used, that is not yet in names.
+func uniqueName(names map[string]bool, name, externalName string) string {
+	n := objectName(name)
+	if n == "" {
+		n = objectName(externalName)
+	}
+	if names[n] {
+		if e := objectName(externalName); e != "" && !names[e] {
+			n = e
+		}
+	}
+	for i, base := 2, n; names[n]; i++ {
+		n = base + "-" + strconv.Itoa(i)
+	}
+	names[n] = true
+	return n
+}
+
+// objectName converts the supplied cloud resource name into a valid object
+// name by lower casing it and replacing invalid characters. It returns an
+// empty string if no valid name can be derived.
+func objectName(name string) string {
+	n := []rune(strings.ToLower(name))
+	for i, r := range n {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '.' && r != '-' {
+			n[i] = '-'
+		}
+	}
+	s := string(n)
+	if len(s) > validation.DNS1123SubdomainMaxLength {
+		s = s[:validation.DNS1123SubdomainMaxLength]
+	}
+	s = strings.Trim(s, "-.")
+	if len(validation.IsDNS1123Subdomain(s)) > 0 {
+		return ""
+	}
+	return s
+}
+
+// WriteYAML writes the supplied managed resources to w as a stream of YAML
+// documents, omitting their status.
+func WriteYAML(w io.Writer, mgs []resource.Managed) error {
+	for i, mg := range mgs {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(mg)
+		if err != nil {
+			return errors.Wrap(err, errConvert)
+		}
+		delete(u, "status")
+		unstructured.RemoveNestedField(u, "metadata", "creationTimestamp")
+		b, err := yaml.Marshal(u)
+		if err != nil {
+			return errors.Wrap(err, errMarshal)
+		}
+		if i > 0 {
+			b = append([]byte("---\n"), b...)
+		}
+		if _, err := w.Write(b); err != nil {
+			return errors.Wrap(err, errWrite)
+		}
+	}
+	return nil
+}
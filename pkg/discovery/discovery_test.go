@@ -0,0 +1,219 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/provider-alibaba/apis/oss/v1alpha1"
+	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
+	"github.com/crossplane/provider-alibaba/pkg/util"
+)
+
+func bucket(name, externalName string) *v1alpha1.Bucket {
+	cr := &v1alpha1.Bucket{}
+	cr.SetName(name)
+	meta.SetExternalName(cr, externalName)
+	return cr
+}
+
+func bucketKind(buckets ...*v1alpha1.Bucket) adapter.Kind {
+	return adapter.Kind{
+		Type:             &v1alpha1.Bucket{},
+		GroupVersionKind: v1alpha1.BucketGroupVersionKind,
+		NewClient: func(_ context.Context, _ resource.Managed, creds util.Credentials) (interface{}, error) {
+			return creds.Region, nil
+		},
+		Discover: func(_ context.Context, c interface{}, f adapter.Filter) ([]resource.Managed, error) {
+			if c.(string) != f.Region {
+				return nil, errors.New("region not passed to discovery")
+			}
+			mgs := make([]resource.Managed, 0, len(buckets))
+			for _, b := range buckets {
+				mgs = append(mgs, b.DeepCopy())
+			}
+			return mgs, nil
+		},
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	pc := func(obj runtime.Object) error {
+		switch t := obj.(type) {
+		case *aliv1alpha1.ProviderConfig:
+			*t = aliv1alpha1.ProviderConfig{
+				Spec: aliv1alpha1.ProviderConfigSpec{
+					ProviderConfigSpec: xpv1.ProviderConfigSpec{
+						Credentials: xpv1.ProviderCredentials{
+							Source:    xpv1.CredentialsSourceSecret,
+							SecretRef: &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: "coolsecret"}},
+						},
+					},
+					Region: "cn-hangzhou",
+				},
+			}
+		case *corev1.Secret:
+		}
+		return nil
+	}
+
+	discovered := func(name, externalName string, p xpv1.DeletionPolicy) *v1alpha1.Bucket {
+		cr := bucket(name, externalName)
+		cr.SetGroupVersionKind(v1alpha1.BucketGroupVersionKind)
+		cr.SetProviderConfigReference(&xpv1.Reference{Name: "default"})
+		cr.SetDeletionPolicy(p)
+		return cr
+	}
+
+	type args struct {
+		kube  *test.MockClient
+		kinds []adapter.Kind
+		o     Options
+	}
+	type want struct {
+		mgs []resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UnknownKind": {
+			reason: "Discovering an unknown kind should return an error",
+			args: args{
+				kinds: []adapter.Kind{bucketKind()},
+				o:     Options{Kinds: []string{"Widget"}},
+			},
+			want: want{err: errors.Errorf(errFmtUnknownKind, "Widget")},
+		},
+		"GetProviderConfigError": {
+			reason: "Errors getting the ProviderConfig should be returned",
+			args: args{
+				kube:  &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				kinds: []adapter.Kind{bucketKind()},
+				o:     Options{ProviderConfig: "default"},
+			},
+			want: want{err: errors.Wrap(errBoom, errGetProviderConfig)},
+		},
+		"DiscoverError": {
+			reason: "Errors discovering a kind should be returned",
+			args: args{
+				kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, pc)},
+				kinds: []adapter.Kind{func() adapter.Kind {
+					k := bucketKind()
+					k.Discover = func(_ context.Context, _ interface{}, _ adapter.Filter) ([]resource.Managed, error) {
+						return nil, errBoom
+					}
+					return k
+				}()},
+				o: Options{ProviderConfig: "default"},
+			},
+			want: want{err: errors.Wrapf(errBoom, errFmtDiscover, v1alpha1.BucketKind)},
+		},
+		"Success": {
+			reason: "Discovered resources should get valid unique names and reference the ProviderConfig",
+			args: args{
+				kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, pc)},
+				kinds: []adapter.Kind{bucketKind(
+					bucket("My_Bucket", "b-1"),
+					bucket("my-bucket", "b-2"),
+					bucket("--", "b-3"),
+				)},
+				o: Options{ProviderConfig: "default", Kinds: []string{"bucket"}, Region: "cn-beijing"},
+			},
+			want: want{mgs: []resource.Managed{
+				discovered("my-bucket", "b-1", xpv1.DeletionOrphan),
+				discovered("b-2", "b-2", xpv1.DeletionOrphan),
+				discovered("b-3", "b-3", xpv1.DeletionOrphan),
+			}},
+		},
+		"DeletionPolicy": {
+			reason: "Discovered resources should use the supplied deletion policy",
+			args: args{
+				kube:  &test.MockClient{MockGet: test.NewMockGetFn(nil, pc)},
+				kinds: []adapter.Kind{bucketKind(bucket("a", "a"))},
+				o:     Options{ProviderConfig: "default", DeletionPolicy: xpv1.DeletionDelete},
+			},
+			want: want{mgs: []resource.Managed{
+				discovered("a", "a", xpv1.DeletionDelete),
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Discover(context.Background(), tc.args.kube, tc.args.kinds, tc.args.o)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDiscover(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.mgs, got); diff != "" {
+				t.Errorf("\n%s\nDiscover(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWriteYAML(t *testing.T) {
+	a := bucket("a", "a")
+	a.SetGroupVersionKind(v1alpha1.BucketGroupVersionKind)
+	a.Spec.ACL = "private"
+	a.Status.AtProvider.ExtranetEndpoint = "oss-cn-hangzhou.aliyuncs.com"
+	b := bucket("b", "b")
+	b.SetGroupVersionKind(v1alpha1.BucketGroupVersionKind)
+
+	want := `apiVersion: oss.alibaba.crossplane.io/v1alpha1
+kind: Bucket
+metadata:
+  annotations:
+    crossplane.io/external-name: a
+  name: a
+spec:
+  acl: private
+---
+apiVersion: oss.alibaba.crossplane.io/v1alpha1
+kind: Bucket
+metadata:
+  annotations:
+    crossplane.io/external-name: b
+  name: b
+spec: {}
+`
+
+	buf := &bytes.Buffer{}
+	if err := WriteYAML(buf, []resource.Managed{a, b}); err != nil {
+		t.Fatalf("WriteYAML(...): %s", err)
+	}
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("WriteYAML(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plan aggregates the pending changes reported by the managed
+// resources in dry run mode across a cluster.
+package plan
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
+)
+
+const (
+	errFmtList = "cannot list %s resources"
+	errWrite   = "cannot write plan"
+)
+
+// A Change is the pending change of a managed resource in dry run mode.
+type Change struct {
+	Kind   string
+	Name   string
+	Reason xpv1.ConditionReason
+
+	// Message names the pending cloud API call and the fields it would
+	// change, one per line.
+	Message string
+}
+
+// List returns the changes pending for the managed resources in dry run
+// mode, of all managed resource kinds known to the supplied scheme, ordered
+// by kind and name.
+func List(ctx context.Context, kube client.Reader, s *runtime.Scheme) ([]Change, error) {
+	var changes []Change
+	for _, gvk := range sortedKinds(s) {
+		if !strings.HasSuffix(gvk.Kind, "List") {
+			continue
+		}
+		item := gvk.GroupVersion().WithKind(strings.TrimSuffix(gvk.Kind, "List"))
+		o, err := s.New(item)
+		if err != nil {
+			continue
+		}
+		if _, ok := o.(resource.Managed); !ok {
+			continue
+		}
+
+		l, err := s.New(gvk)
+		if err != nil {
+			continue
+		}
+		if err := kube.List(ctx, l); err != nil {
+			return nil, errors.Wrapf(err, errFmtList, item.Kind)
+		}
+		items, err := meta.ExtractList(l)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtList, item.Kind)
+		}
+		for _, o := range items {
+			mg := o.(resource.Managed)
+			c := mg.GetCondition(adapter.TypeDryRun)
+			if c.Status != corev1.ConditionTrue {
+				continue
+			}
+			changes = append(changes, Change{Kind: item.Kind, Name: mg.GetName(), Reason: c.Reason, Message: c.Message})
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Kind != changes[j].Kind {
+			return changes[i].Kind < changes[j].Kind
+		}
+		return changes[i].Name < changes[j].Name
+	})
+	return changes, nil
+}
+
+func sortedKinds(s *runtime.Scheme) []schema.GroupVersionKind {
+	kinds := make([]schema.GroupVersionKind, 0, len(s.AllKnownTypes()))
+	for gvk := range s.AllKnownTypes() {
+		kinds = append(kinds, gvk)
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i].String() < kinds[j].String() })
+	return kinds
+}
+
+// Write writes the supplied pending changes to w, followed by a summary that
+// also counts the managed resources without pending changes.
+func Write(w io.Writer, changes []Change) error {
+	counts := map[xpv1.ConditionReason]int{}
+	b := &strings.Builder{}
+	for _, c := range changes {
+		counts[c.Reason]++
+		if c.Reason == adapter.ReasonNoPendingChanges {
+			continue
+		}
+		fmt.Fprintf(b, "%s/%s: %s\n", c.Kind, c.Name, c.Reason)
+		for _, l := range strings.Split(c.Message, "\n") {
+			fmt.Fprintf(b, "    %s\n", l)
+		}
+	}
+	fmt.Fprintf(b, "Plan: %d to create, %d to update, %d to delete, %d unchanged.\n",
+		counts[adapter.ReasonPendingCreate], counts[adapter.ReasonPendingUpdate],
+		counts[adapter.ReasonPendingDelete], counts[adapter.ReasonNoPendingChanges])
+	_, err := io.WriteString(w, b.String())
+	return errors.Wrap(err, errWrite)
+}
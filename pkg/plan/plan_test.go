@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-alibaba/apis/oss/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/controller/adapter"
+)
+
+func bucket(name string, c ...xpv1.Condition) v1alpha1.Bucket {
+	b := v1alpha1.Bucket{}
+	b.SetName(name)
+	b.SetConditions(c...)
+	return b
+}
+
+func TestList(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	s := runtime.NewScheme()
+	if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	update := xpv1.Condition{Type: adapter.TypeDryRun, Status: corev1.ConditionTrue, Reason: adapter.ReasonPendingUpdate, Message: "Would call Update"}
+	disabled := xpv1.Condition{Type: adapter.TypeDryRun, Status: corev1.ConditionFalse, Reason: adapter.ReasonDryRunDisabled}
+
+	type want struct {
+		changes []Change
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Reader
+		want   want
+	}{
+		"ListError": {
+			reason: "Errors listing managed resources should be returned",
+			kube:   &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			want:   want{err: errors.Wrapf(errBoom, errFmtList, v1alpha1.BucketKind)},
+		},
+		"Success": {
+			reason: "Only managed resources in dry run mode should be listed, ordered by name",
+			kube: &test.MockClient{MockList: func(_ context.Context, l runtime.Object, _ ...client.ListOption) error {
+				if bl, ok := l.(*v1alpha1.BucketList); ok {
+					bl.Items = []v1alpha1.Bucket{bucket("b", update), bucket("c", disabled), bucket("d"), bucket("a", update)}
+				}
+				return nil
+			}},
+			want: want{changes: []Change{
+				{Kind: v1alpha1.BucketKind, Name: "a", Reason: adapter.ReasonPendingUpdate, Message: "Would call Update"},
+				{Kind: v1alpha1.BucketKind, Name: "b", Reason: adapter.ReasonPendingUpdate, Message: "Would call Update"},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := List(context.Background(), tc.kube, s)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nList(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.changes, got); diff != "" {
+				t.Errorf("\n%s\nList(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	changes := []Change{
+		{Kind: "Bucket", Name: "a", Reason: adapter.ReasonPendingCreate, Message: "Would call Create\nacl: <unset> -> \"private\""},
+		{Kind: "Bucket", Name: "b", Reason: adapter.ReasonNoPendingChanges},
+		{Kind: "RedisInstance", Name: "c", Reason: adapter.ReasonPendingUpdate, Message: "Would call Update\ninstanceClass: \"small\" -> \"large\""},
+	}
+	want := `Bucket/a: PendingCreate
+    Would call Create
+    acl: <unset> -> "private"
+RedisInstance/c: PendingUpdate
+    Would call Update
+    instanceClass: "small" -> "large"
+Plan: 1 to create, 1 to update, 0 to delete, 1 unchanged.
+`
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, changes); err != nil {
+		t.Fatalf("Write(...): %s", err)
+	}
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("Write(...): -want, +got:\n%s", diff)
+	}
+}
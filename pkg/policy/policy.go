@@ -0,0 +1,180 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy evaluates the ProvisioningPolicies that constrain the
+// parameters of managed resources.
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
+	"github.com/crossplane/provider-alibaba/pkg/util"
+)
+
+const (
+	errListPolicies      = "cannot list provisioning policies"
+	errFlattenParameters = "cannot flatten managed resource parameters"
+
+	errFmtNotNumber       = "policy %s rule %s: parameter %s is not a number"
+	errFmtInvalidBound    = "policy %s rule %s: operator %s takes a single numeric value"
+	errFmtUnknownOperator = "policy %s rule %s: unknown operator %q"
+)
+
+// A Violation is a rule of a ProvisioningPolicy that the parameters of a
+// managed resource do not satisfy.
+type Violation struct {
+	Policy  string
+	Rule    string
+	Field   string
+	Value   string
+	Message string
+}
+
+func (v Violation) String() string {
+	s := fmt.Sprintf("policy %s rule %s: %s %s", v.Policy, v.Rule, v.Field, v.Value)
+	if v.Message != "" {
+		s += ": " + v.Message
+	}
+	return s
+}
+
+// Message returns a single message describing all of the supplied violations.
+func Message(vs []Violation) string {
+	msg := make([]string, len(vs))
+	for i, v := range vs {
+		msg[i] = v.String()
+	}
+	return strings.Join(msg, "; ")
+}
+
+// Check returns the violations of all ProvisioningPolicies by the supplied
+// parameters of a managed resource of the supplied kind.
+func Check(ctx context.Context, kube client.Reader, kind string, params map[string]interface{}) ([]Violation, error) {
+	l := &aliv1alpha1.ProvisioningPolicyList{}
+	if err := kube.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListPolicies)
+	}
+	return Evaluate(l.Items, kind, params)
+}
+
+// Evaluate returns the violations of the supplied policies by the supplied
+// parameters of a managed resource of the supplied kind. Rules of other kinds,
+// and rules of parameters that are not set, are not evaluated.
+func Evaluate(ps []aliv1alpha1.ProvisioningPolicy, kind string, params map[string]interface{}) ([]Violation, error) {
+	flat, err := util.Flatten(params)
+	if err != nil {
+		return nil, errors.Wrap(err, errFlattenParameters)
+	}
+
+	sort.Slice(ps, func(i, j int) bool { return ps[i].GetName() < ps[j].GetName() })
+
+	var vs []Violation
+	for _, p := range ps {
+		for _, r := range p.Spec.Rules {
+			if r.Kind != kind {
+				continue
+			}
+			raw, ok := flat[r.Field]
+			if !ok {
+				continue
+			}
+			ok, err := satisfies(p.GetName(), r, value(raw))
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				vs = append(vs, Violation{
+					Policy:  p.GetName(),
+					Rule:    r.Name,
+					Field:   r.Field,
+					Value:   describe(r, raw),
+					Message: r.Message,
+				})
+			}
+		}
+	}
+	return vs, nil
+}
+
+// value returns the supplied JSON encoded parameter as it would be written in
+// the values of a rule, i.e. strings without quotes.
+func value(raw string) string {
+	var s string
+	if err := json.Unmarshal([]byte(raw), &s); err == nil {
+		return s
+	}
+	return raw
+}
+
+// satisfies returns true if the supplied parameter value satisfies the
+// supplied rule of the named policy.
+func satisfies(policy string, r aliv1alpha1.PolicyRule, v string) (bool, error) {
+	switch r.Operator {
+	case aliv1alpha1.PolicyOperatorIn:
+		return contains(r.Values, v), nil
+	case aliv1alpha1.PolicyOperatorNotIn:
+		return !contains(r.Values, v), nil
+	case aliv1alpha1.PolicyOperatorMax, aliv1alpha1.PolicyOperatorMin:
+		if len(r.Values) != 1 {
+			return false, errors.Errorf(errFmtInvalidBound, policy, r.Name, r.Operator)
+		}
+		bound, err := strconv.ParseFloat(r.Values[0], 64)
+		if err != nil {
+			return false, errors.Errorf(errFmtInvalidBound, policy, r.Name, r.Operator)
+		}
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return false, errors.Errorf(errFmtNotNumber, policy, r.Name, r.Field)
+		}
+		if r.Operator == aliv1alpha1.PolicyOperatorMax {
+			return n <= bound, nil
+		}
+		return n >= bound, nil
+	}
+	return false, errors.Errorf(errFmtUnknownOperator, policy, r.Name, r.Operator)
+}
+
+// describe returns why the supplied parameter violates the supplied rule.
+func describe(r aliv1alpha1.PolicyRule, raw string) string {
+	switch r.Operator {
+	case aliv1alpha1.PolicyOperatorIn:
+		return fmt.Sprintf("is %s, must be one of %s", raw, strings.Join(r.Values, ", "))
+	case aliv1alpha1.PolicyOperatorNotIn:
+		return fmt.Sprintf("is %s, must not be one of %s", raw, strings.Join(r.Values, ", "))
+	case aliv1alpha1.PolicyOperatorMax:
+		return fmt.Sprintf("is %s, must be at most %s", raw, r.Values[0])
+	default:
+		return fmt.Sprintf("is %s, must be at least %s", raw, r.Values[0])
+	}
+}
+
+func contains(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
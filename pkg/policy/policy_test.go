@@ -0,0 +1,235 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	aliv1alpha1 "github.com/crossplane/provider-alibaba/apis/v1alpha1"
+)
+
+func policyWith(name string, rules ...aliv1alpha1.PolicyRule) aliv1alpha1.ProvisioningPolicy {
+	p := aliv1alpha1.ProvisioningPolicy{Spec: aliv1alpha1.ProvisioningPolicySpec{Rules: rules}}
+	p.SetName(name)
+	return p
+}
+
+func TestEvaluate(t *testing.T) {
+	noPublicBuckets := aliv1alpha1.PolicyRule{
+		Name:     "no-public-buckets",
+		Kind:     "Bucket",
+		Field:    "acl",
+		Operator: aliv1alpha1.PolicyOperatorNotIn,
+		Values:   []string{"public-read-write"},
+		Message:  "buckets must not be writable by everyone",
+	}
+	redisClasses := aliv1alpha1.PolicyRule{
+		Name:     "redis-classes",
+		Kind:     "RedisInstance",
+		Field:    "instanceClass",
+		Operator: aliv1alpha1.PolicyOperatorIn,
+		Values:   []string{"redis.master.small.default"},
+	}
+	maxBandwidth := aliv1alpha1.PolicyRule{
+		Name:     "max-bandwidth",
+		Kind:     "CLB",
+		Field:    "bandwidth",
+		Operator: aliv1alpha1.PolicyOperatorMax,
+		Values:   []string{"100"},
+	}
+
+	type args struct {
+		ps     []aliv1alpha1.ProvisioningPolicy
+		kind   string
+		params map[string]interface{}
+	}
+	type want struct {
+		vs  []Violation
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotIn": {
+			reason: "Parameters with a value that is not allowed violate NotIn rules",
+			args: args{
+				ps:     []aliv1alpha1.ProvisioningPolicy{policyWith("buckets", noPublicBuckets)},
+				kind:   "Bucket",
+				params: map[string]interface{}{"acl": "public-read-write"},
+			},
+			want: want{vs: []Violation{{
+				Policy:  "buckets",
+				Rule:    "no-public-buckets",
+				Field:   "acl",
+				Value:   `is "public-read-write", must not be one of public-read-write`,
+				Message: "buckets must not be writable by everyone",
+			}}},
+		},
+		"In": {
+			reason: "Parameters with a value that is allowed satisfy In rules",
+			args: args{
+				ps:     []aliv1alpha1.ProvisioningPolicy{policyWith("redis", redisClasses)},
+				kind:   "RedisInstance",
+				params: map[string]interface{}{"instanceClass": "redis.master.small.default"},
+			},
+		},
+		"Max": {
+			reason: "Numeric parameters above the bound violate Max rules",
+			args: args{
+				ps:     []aliv1alpha1.ProvisioningPolicy{policyWith("clb", maxBandwidth)},
+				kind:   "CLB",
+				params: map[string]interface{}{"bandwidth": 200},
+			},
+			want: want{vs: []Violation{{
+				Policy: "clb",
+				Rule:   "max-bandwidth",
+				Field:  "bandwidth",
+				Value:  "is 200, must be at most 100",
+			}}},
+		},
+		"OtherKind": {
+			reason: "Rules of other kinds are not evaluated",
+			args: args{
+				ps:     []aliv1alpha1.ProvisioningPolicy{policyWith("buckets", noPublicBuckets)},
+				kind:   "RedisInstance",
+				params: map[string]interface{}{"acl": "public-read-write"},
+			},
+		},
+		"Unset": {
+			reason: "Rules of parameters that are not set are not evaluated",
+			args: args{
+				ps:     []aliv1alpha1.ProvisioningPolicy{policyWith("redis", redisClasses)},
+				kind:   "RedisInstance",
+				params: map[string]interface{}{},
+			},
+		},
+		"NotANumber": {
+			reason: "Max rules of parameters that are not numbers are invalid",
+			args: args{
+				ps:     []aliv1alpha1.ProvisioningPolicy{policyWith("clb", maxBandwidth)},
+				kind:   "CLB",
+				params: map[string]interface{}{"bandwidth": "lots"},
+			},
+			want: want{err: errors.Errorf(errFmtNotNumber, "clb", "max-bandwidth", "bandwidth")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			vs, err := Evaluate(tc.args.ps, tc.args.kind, tc.args.params)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nEvaluate(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.vs, vs); diff != "" {
+				t.Errorf("\n%s\nEvaluate(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidator(t *testing.T) {
+	p := policyWith("buckets", aliv1alpha1.PolicyRule{
+		Name:     "no-public-buckets",
+		Kind:     "Bucket",
+		Field:    "acl",
+		Operator: aliv1alpha1.PolicyOperatorNotIn,
+		Values:   []string{"public-read-write"},
+	})
+	kube := &test.MockClient{MockList: test.NewMockListFn(nil, func(obj runtime.Object) error {
+		obj.(*aliv1alpha1.ProvisioningPolicyList).Items = []aliv1alpha1.ProvisioningPolicy{p}
+		return nil
+	})}
+
+	bucket := func(acl string, meta map[string]interface{}) []byte {
+		raw, _ := json.Marshal(map[string]interface{}{"metadata": meta, "spec": map[string]interface{}{"acl": acl}})
+		return raw
+	}
+	request := func(op admissionv1beta1.Operation, acl string) admission.Request {
+		return admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Operation: op,
+			Kind:      metav1.GroupVersionKind{Kind: "Bucket"},
+			Object:    runtime.RawExtension{Raw: bucket(acl, nil)},
+		}}
+	}
+	update := func(oldACL, acl string, meta map[string]interface{}) admission.Request {
+		return admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Operation: admissionv1beta1.Update,
+			Kind:      metav1.GroupVersionKind{Kind: "Bucket"},
+			Object:    runtime.RawExtension{Raw: bucket(acl, meta)},
+			OldObject: runtime.RawExtension{Raw: bucket(oldACL, nil)},
+		}}
+	}
+
+	cases := map[string]struct {
+		reason string
+		req    admission.Request
+		want   bool
+	}{
+		"Violated": {
+			reason: "Managed resources violating a policy are denied",
+			req:    request(admissionv1beta1.Create, "public-read-write"),
+			want:   false,
+		},
+		"Satisfied": {
+			reason: "Managed resources satisfying all policies are allowed",
+			req:    update("public-read-write", "private", nil),
+			want:   true,
+		},
+		"UpdateViolated": {
+			reason: "Updates changing a parameter to a violating value are denied",
+			req:    update("private", "public-read-write", nil),
+			want:   false,
+		},
+		"UpdateUnchanged": {
+			reason: "Updates that do not change a violating parameter are allowed, e.g. those of controllers after a policy was tightened",
+			req:    update("public-read-write", "public-read-write", map[string]interface{}{"annotations": map[string]interface{}{"crossplane.io/external-name": "bucket"}}),
+			want:   true,
+		},
+		"Deleting": {
+			reason: "Managed resources being deleted are allowed, so that their finalizers can be removed",
+			req:    update("private", "public-read-write", map[string]interface{}{"deletionTimestamp": "2021-01-01T00:00:00Z"}),
+			want:   true,
+		},
+		"Delete": {
+			reason: "Deleting managed resources is always allowed",
+			req:    admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{Operation: admissionv1beta1.Delete}},
+			want:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewValidator(kube).Handle(context.Background(), tc.req)
+			if diff := cmp.Diff(tc.want, got.Allowed); diff != "" {
+				t.Errorf("\n%s\nHandle(...): -want allowed, +got allowed:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/provider-alibaba/pkg/util"
+)
+
+// WebhookPath is the path the validating webhook is served at.
+const WebhookPath = "/validate-managed-resources"
+
+const (
+	errDecodeObject    = "cannot decode managed resource"
+	errDecodeOldObject = "cannot decode old managed resource"
+)
+
+// NewValidator returns an admission handler that denies the creation and
+// update of managed resources violating a ProvisioningPolicy. Updates are only
+// denied if they change a parameter to a violating value, so that managed
+// resources created before a policy was tightened can still be updated by
+// their controllers, and managed resources being deleted are always allowed.
+func NewValidator(kube client.Reader) admission.Handler {
+	return &validator{kube: kube}
+}
+
+// RegisterWebhook serves the validating webhook on the supplied server.
+func RegisterWebhook(s *webhook.Server, kube client.Reader) {
+	s.Register(WebhookPath, &webhook.Admission{Handler: NewValidator(kube)})
+}
+
+type validator struct {
+	kube client.Reader
+}
+
+func (v *validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1beta1.Create && req.Operation != admissionv1beta1.Update {
+		return admission.Allowed("")
+	}
+
+	u := &unstructured.Unstructured{}
+	if err := json.Unmarshal(req.Object.Raw, &u.Object); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeObject))
+	}
+	if u.GetDeletionTimestamp() != nil {
+		return admission.Allowed("")
+	}
+	params, err := util.ParametersOf(u.Object)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeObject))
+	}
+
+	vs, err := Check(ctx, v.kube, req.Kind.Kind, params)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if req.Operation == admissionv1beta1.Update && len(vs) > 0 {
+		old := map[string]interface{}{}
+		if err := json.Unmarshal(req.OldObject.Raw, &old); err != nil {
+			return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeOldObject))
+		}
+		oldParams, err := util.ParametersOf(old)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeOldObject))
+		}
+		if vs, err = changed(vs, oldParams, params); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+	if len(vs) > 0 {
+		return admission.Denied(Message(vs))
+	}
+	return admission.Allowed("")
+}
+
+// changed returns the supplied violations of the supplied parameters whose
+// fields have a different value in the supplied old parameters.
+func changed(vs []Violation, old, params map[string]interface{}) ([]Violation, error) {
+	o, err := util.Flatten(old)
+	if err != nil {
+		return nil, errors.Wrap(err, errFlattenParameters)
+	}
+	n, err := util.Flatten(params)
+	if err != nil {
+		return nil, errors.Wrap(err, errFlattenParameters)
+	}
+	var c []Violation
+	for _, v := range vs {
+		if ov, ok := o[v.Field]; !ok || ov != n[v.Field] {
+			c = append(c, v)
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sdkgen generates the forProvider and atProvider types of managed
+// resources from the request and response structs of tea-style Alibaba Cloud
+// SDKs (github.com/alibabacloud-go/*), together with the functions converting
+// them to and from the SDK structs and the up-to-date comparison helpers.
+//
+// Generation is driven by a ConfigFileName file in an API version directory,
+// for example apis/slb/v1alpha1/sdkgen.yaml:
+//
+//   resources:
+//   - kind: CLB
+//     sdkPackage: github.com/alibabacloud-go/slb-20140515/v2/client
+//     clientDir: ../../../pkg/clients/slb
+//     parameters:
+//       struct: CreateLoadBalancerRequest
+//       ignore: [OwnerId, ResourceOwnerId]
+//       rename: {RegionId: Region}
+//       required: [RegionId]
+//     observation:
+//       struct: DescribeLoadBalancersResponseBodyLoadBalancersLoadBalancer
+//       name: LoadBalancerObservation
+//       fields: [LoadBalancerId, LoadBalancerStatus, Address]
+//     upToDate: [LoadBalancerSpec]
+//
+// The types are written to TypesFileName next to the configuration file and
+// the functions to TypesFileName in clientDir.
+package sdkgen
+
+import (
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ConfigFileName is the name of the files configuring generation.
+	ConfigFileName = "sdkgen.yaml"
+
+	// TypesFileName is the name of the generated files.
+	TypesFileName = "zz_generated.sdkgen.go"
+)
+
+const (
+	errReadConfig     = "cannot read configuration"
+	errParseConfig    = "cannot parse configuration"
+	errFmtNoKind      = "resource %d has no kind"
+	errFmtNoSDK       = "resource %s has no sdkPackage"
+	errFmtNoClientDir = "resource %s has no clientDir"
+	errFmtNoStruct    = "resource %s has no %s struct"
+)
+
+// A Config configures the generation of the resources of one API version.
+type Config struct {
+	Resources []Resource `json:"resources"`
+}
+
+// A Resource configures the generation of one managed resource kind.
+type Resource struct {
+	// Kind of the managed resource, e.g. CLB. Generated types are named
+	// <Kind>Parameters and <Kind>Observation.
+	Kind string `json:"kind"`
+
+	// SDKPackage is the import path of the tea-style SDK client package.
+	SDKPackage string `json:"sdkPackage"`
+
+	// ClientDir is the directory, relative to the configuration file, of the
+	// client package the conversion functions are written to.
+	ClientDir string `json:"clientDir"`
+
+	// Parameters configures the forProvider type.
+	Parameters Struct `json:"parameters"`
+
+	// Observation configures the atProvider type.
+	Observation Struct `json:"observation"`
+
+	// UpToDate lists the SDK fields that are compared between forProvider and
+	// the observed SDK struct to decide whether the resource is up to date.
+	// Both structs must have these fields.
+	UpToDate []string `json:"upToDate,omitempty"`
+}
+
+// A Struct configures how a generated type is derived from an SDK struct.
+type Struct struct {
+	// Struct is the name of the SDK struct, e.g. CreateLoadBalancerRequest.
+	Struct string `json:"struct"`
+
+	// Name of the generated type, e.g. to embed it in a hand-written type
+	// that adds fields the SDK struct lacks. <Kind>Parameters or
+	// <Kind>Observation if empty.
+	Name string `json:"name,omitempty"`
+
+	// Fields lists the SDK fields to include. All fields are included if
+	// empty.
+	Fields []string `json:"fields,omitempty"`
+
+	// Ignore lists the SDK fields to omit.
+	Ignore []string `json:"ignore,omitempty"`
+
+	// Rename maps SDK field names to the Go field names to use instead.
+	Rename map[string]string `json:"rename,omitempty"`
+
+	// Required lists the SDK fields that may not be omitted.
+	Required []string `json:"required,omitempty"`
+
+	// Comments maps SDK field names to the doc comments of their fields.
+	Comments map[string]string `json:"comments,omitempty"`
+}
+
+// ReadConfig reads and validates the configuration file at path.
+func ReadConfig(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return nil, errors.Wrap(err, errReadConfig)
+	}
+	c := &Config{}
+	if err := yaml.UnmarshalStrict(b, c); err != nil {
+		return nil, errors.Wrap(err, errParseConfig)
+	}
+	for i, r := range c.Resources {
+		switch {
+		case r.Kind == "":
+			return nil, errors.Errorf(errFmtNoKind, i)
+		case r.SDKPackage == "":
+			return nil, errors.Errorf(errFmtNoSDK, r.Kind)
+		case r.ClientDir == "":
+			return nil, errors.Errorf(errFmtNoClientDir, r.Kind)
+		case r.Parameters.Struct == "":
+			return nil, errors.Errorf(errFmtNoStruct, r.Kind, "parameters")
+		case r.Observation.Struct == "":
+			return nil, errors.Errorf(errFmtNoStruct, r.Kind, "observation")
+		}
+	}
+	return c, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
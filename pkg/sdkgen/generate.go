@@ -0,0 +1,398 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sdkgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errFindModule      = "cannot find go.mod"
+	errReadModule      = "cannot read go.mod"
+	errFmtNoModulePath = "no module path in %s"
+	errFmtPackageName  = "cannot determine package name of %s"
+	errFmtSharedClient = "resources %s and %s write to client directory %s but use different SDK packages"
+	errFormat          = "cannot format generated code"
+	errWrite           = "cannot write generated code"
+	errFmtResource     = "cannot generate resource %s"
+
+	generatedBy = "// Code generated by sdkgen. DO NOT EDIT.\n\n"
+
+	sdkAlias = "sdk"
+)
+
+var modulePath = regexp.MustCompile(`(?m)^module\s+(\S+)`)
+
+// A Generator generates code from configuration files.
+type Generator struct {
+	// Header is prepended to all generated files, typically a license.
+	Header string
+
+	// Load finds SDK packages.
+	Load Loader
+
+	sdks map[string]*sdkPackage
+}
+
+// NewGenerator returns a Generator that prepends the supplied header and
+// finds SDK packages using `go list`.
+func NewGenerator(header string) *Generator {
+	return &Generator{Header: header, Load: GoListLoader}
+}
+
+// Generate the types and functions configured by the configuration file at
+// path.
+func (g *Generator) Generate(path string) error {
+	c, err := ReadConfig(path)
+	if err != nil {
+		return err
+	}
+	apiDir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return errors.Wrap(err, errFindModule)
+	}
+	apiImport, err := importPath(apiDir)
+	if err != nil {
+		return err
+	}
+	apiPkg, err := packageName(apiDir)
+	if err != nil {
+		return err
+	}
+
+	m := newModel()
+	clients := map[string][]*resourceModel{}
+	var dirs []string
+	for _, r := range c.Resources {
+		sdk, err := g.loadSDK(r.SDKPackage)
+		if err != nil {
+			return errors.Wrapf(err, errFmtResource, r.Kind)
+		}
+		rm, err := m.addResource(r, sdk)
+		if err != nil {
+			return errors.Wrapf(err, errFmtResource, r.Kind)
+		}
+		dir := filepath.Clean(filepath.Join(apiDir, r.ClientDir))
+		if existing := clients[dir]; len(existing) > 0 && existing[0].SDKPackage != r.SDKPackage {
+			return errors.Errorf(errFmtSharedClient, existing[0].Kind, r.Kind, r.ClientDir)
+		}
+		if _, ok := clients[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		clients[dir] = append(clients[dir], rm)
+	}
+
+	if err := g.write(filepath.Join(apiDir, TypesFileName), g.types(apiPkg, m)); err != nil {
+		return err
+	}
+	for _, dir := range dirs {
+		pkg, err := packageName(dir)
+		if err != nil {
+			return err
+		}
+		if err := g.write(filepath.Join(dir, TypesFileName), g.functions(pkg, apiPkg, apiImport, clients[dir])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *Generator) loadSDK(importPath string) (*sdkPackage, error) {
+	if s, ok := g.sdks[importPath]; ok {
+		return s, nil
+	}
+	dir, err := g.Load(importPath)
+	if err != nil {
+		return nil, err
+	}
+	s, err := parseSDK(dir)
+	if err != nil {
+		return nil, err
+	}
+	if g.sdks == nil {
+		g.sdks = map[string]*sdkPackage{}
+	}
+	g.sdks[importPath] = s
+	return s, nil
+}
+
+func (g *Generator) write(path string, src []byte) error {
+	h := ""
+	if g.Header != "" {
+		h = strings.TrimSpace(g.Header) + "\n\n"
+	}
+	b := append([]byte(h+generatedBy), src...)
+	out, err := format.Source(b)
+	if err != nil {
+		return errors.Wrap(err, errFormat)
+	}
+	return errors.Wrap(ioutil.WriteFile(path, out, 0644), errWrite) //nolint:gosec
+}
+
+// types renders the API types of the supplied model.
+func (g *Generator) types(pkg string, m *model) []byte {
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "package %s\n\n", pkg)
+	for _, t := range m.types {
+		fmt.Fprintf(b, "// %s\n", t.comment)
+		fmt.Fprintf(b, "type %s struct {\n", t.name)
+		for i, f := range t.fields {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			if f.comment != "" {
+				for _, l := range strings.Split(strings.TrimSpace(f.comment), "\n") {
+					fmt.Fprintf(b, "// %s\n", l)
+				}
+			}
+			if f.required {
+				b.WriteString("// +kubebuilder:validation:Required\n")
+				fmt.Fprintf(b, "%s %s `json:\"%s\"`\n", f.name, apiType(f), f.json)
+				continue
+			}
+			b.WriteString("// +optional\n")
+			fmt.Fprintf(b, "%s %s `json:\"%s,omitempty\"`\n", f.name, apiType(f), f.json)
+		}
+		b.WriteString("}\n\n")
+	}
+	return b.Bytes()
+}
+
+func apiType(f field) string {
+	switch f.typ.shape {
+	case shapeScalarSlice:
+		return "[]" + f.typ.name
+	case shapeStruct:
+		return "*" + f.nested.name
+	case shapeStructSlice:
+		return "[]" + f.nested.name
+	case shapeStringMap:
+		return "map[string]string"
+	default:
+		return "*" + f.typ.name
+	}
+}
+
+// direction is the set of conversion functions needed for a type.
+type direction struct{ to, from bool }
+
+// functions renders the conversion and comparison functions of the supplied
+// resources.
+func (g *Generator) functions(pkg, apiPkg, apiImport string, rms []*resourceModel) []byte {
+	// Only generate the nested conversions that are actually used, walking
+	// from the parameters in both directions and from the observations in
+	// one.
+	needed := map[*genType]*direction{}
+	var walk func(t *genType, to bool)
+	walk = func(t *genType, to bool) {
+		d, ok := needed[t]
+		if !ok {
+			d = &direction{}
+			needed[t] = d
+		}
+		if d.from && (d.to || !to) {
+			return
+		}
+		d.from = true
+		d.to = d.to || to
+		for _, f := range t.fields {
+			if f.nested != nil {
+				walk(f.nested, to)
+			}
+		}
+	}
+	for _, rm := range rms {
+		walk(rm.parameters, true)
+		walk(rm.observation, false)
+	}
+	order := make([]*genType, 0, len(needed))
+	for t := range needed {
+		order = append(order, t)
+	}
+	sort.Slice(order, func(i, j int) bool { return order[i].name < order[j].name })
+
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "package %s\n\n", pkg)
+	fmt.Fprintf(b, "import (\n%s %q\n\n%q\n)\n\n", sdkAlias, rms[0].SDKPackage, apiImport)
+
+	for _, rm := range rms {
+		p, o := rm.parameters, rm.observation
+		fmt.Fprintf(b, "// %s returns the %s of the supplied %s.\n", p.to, p.sdkName, p.name)
+		fmt.Fprintf(b, "func %s(in *%s.%s) *%s.%s {\n", p.to, apiPkg, p.name, sdkAlias, p.sdkName)
+		writeTo(b, p)
+		b.WriteString("}\n\n")
+
+		fmt.Fprintf(b, "// %s returns the %s of the supplied %s.\n", p.from, p.name, p.sdkName)
+		fmt.Fprintf(b, "func %s(in *%s.%s) %s.%s {\n", p.from, sdkAlias, p.sdkName, apiPkg, p.name)
+		writeFrom(b, apiPkg, p, false)
+		b.WriteString("}\n\n")
+
+		fmt.Fprintf(b, "// %s returns the %s of the supplied %s.\n", o.from, o.name, o.sdkName)
+		fmt.Fprintf(b, "func %s(in *%s.%s) %s.%s {\n", o.from, sdkAlias, o.sdkName, apiPkg, o.name)
+		writeFrom(b, apiPkg, o, false)
+		b.WriteString("}\n\n")
+
+		fmt.Fprintf(b, "// Is%sUpToDate returns true if the supplied %s matches the\n", rm.Kind, o.sdkName)
+		fmt.Fprintf(b, "// supplied %s.\n", p.name)
+		fmt.Fprintf(b, "func Is%sUpToDate(p %s.%s, o *%s.%s) bool {\n", rm.Kind, apiPkg, p.name, sdkAlias, o.sdkName)
+		b.WriteString("if o == nil {\nreturn false\n}\n")
+		for _, f := range rm.upToDate {
+			fmt.Fprintf(b, "if p.%s != nil && (o.%s == nil || *p.%s != *o.%s) {\nreturn false\n}\n", f.name, f.sdkName, f.name, f.sdkName)
+		}
+		b.WriteString("return true\n}\n\n")
+	}
+
+	for _, t := range order {
+		if isTopLevel(rms, t) {
+			continue
+		}
+		d := needed[t]
+		if d.to {
+			fmt.Fprintf(b, "func %s(in *%s.%s) *%s.%s {\n", t.to, apiPkg, t.name, sdkAlias, t.sdkName)
+			writeTo(b, t)
+			b.WriteString("}\n\n")
+		}
+		if d.from {
+			fmt.Fprintf(b, "func %s(in *%s.%s) *%s.%s {\n", t.from, sdkAlias, t.sdkName, apiPkg, t.name)
+			writeFrom(b, apiPkg, t, true)
+			b.WriteString("}\n\n")
+		}
+	}
+	return b.Bytes()
+}
+
+func isTopLevel(rms []*resourceModel, t *genType) bool {
+	for _, rm := range rms {
+		if t == rm.parameters || t == rm.observation {
+			return true
+		}
+	}
+	return false
+}
+
+func writeTo(b *bytes.Buffer, t *genType) {
+	fmt.Fprintf(b, "if in == nil {\nreturn nil\n}\nout := &%s.%s{}\n", sdkAlias, t.sdkName)
+	for _, f := range t.fields {
+		switch f.typ.shape {
+		case shapeScalar:
+			fmt.Fprintf(b, "out.%s = in.%s\n", f.sdkName, f.name)
+		case shapeScalarSlice:
+			fmt.Fprintf(b, "if in.%s != nil {\n", f.name)
+			fmt.Fprintf(b, "out.%s = make([]*%s, len(in.%s))\n", f.sdkName, f.typ.name, f.name)
+			fmt.Fprintf(b, "for i := range in.%s {\nout.%s[i] = &in.%s[i]\n}\n}\n", f.name, f.sdkName, f.name)
+		case shapeStruct:
+			fmt.Fprintf(b, "out.%s = %s(in.%s)\n", f.sdkName, f.nested.to, f.name)
+		case shapeStructSlice:
+			fmt.Fprintf(b, "if in.%s != nil {\n", f.name)
+			fmt.Fprintf(b, "out.%s = make([]*%s.%s, len(in.%s))\n", f.sdkName, sdkAlias, f.typ.name, f.name)
+			fmt.Fprintf(b, "for i := range in.%s {\nout.%s[i] = %s(&in.%s[i])\n}\n}\n", f.name, f.sdkName, f.nested.to, f.name)
+		case shapeStringMap:
+			fmt.Fprintf(b, "if in.%s != nil {\n", f.name)
+			fmt.Fprintf(b, "out.%s = make(map[string]*string, len(in.%s))\n", f.sdkName, f.name)
+			fmt.Fprintf(b, "for k, v := range in.%s {\nv := v\nout.%s[k] = &v\n}\n}\n", f.name, f.sdkName)
+		}
+	}
+	b.WriteString("return out\n")
+}
+
+func writeFrom(b *bytes.Buffer, apiPkg string, t *genType, pointer bool) {
+	if pointer {
+		fmt.Fprintf(b, "if in == nil {\nreturn nil\n}\nout := &%s.%s{}\n", apiPkg, t.name)
+	} else {
+		fmt.Fprintf(b, "out := %s.%s{}\nif in == nil {\nreturn out\n}\n", apiPkg, t.name)
+	}
+	for _, f := range t.fields {
+		switch f.typ.shape {
+		case shapeScalar:
+			fmt.Fprintf(b, "out.%s = in.%s\n", f.name, f.sdkName)
+		case shapeScalarSlice:
+			fmt.Fprintf(b, "if in.%s != nil {\n", f.sdkName)
+			fmt.Fprintf(b, "out.%s = make([]%s, 0, len(in.%s))\n", f.name, f.typ.name, f.sdkName)
+			fmt.Fprintf(b, "for _, v := range in.%s {\nif v != nil {\nout.%s = append(out.%s, *v)\n}\n}\n}\n", f.sdkName, f.name, f.name)
+		case shapeStruct:
+			fmt.Fprintf(b, "out.%s = %s(in.%s)\n", f.name, f.nested.from, f.sdkName)
+		case shapeStructSlice:
+			fmt.Fprintf(b, "if in.%s != nil {\n", f.sdkName)
+			fmt.Fprintf(b, "out.%s = make([]%s.%s, 0, len(in.%s))\n", f.name, apiPkg, f.nested.name, f.sdkName)
+			fmt.Fprintf(b, "for _, v := range in.%s {\nif v != nil {\nout.%s = append(out.%s, *%s(v))\n}\n}\n}\n", f.sdkName, f.name, f.name, f.nested.from)
+		case shapeStringMap:
+			fmt.Fprintf(b, "if in.%s != nil {\n", f.sdkName)
+			fmt.Fprintf(b, "out.%s = make(map[string]string, len(in.%s))\n", f.name, f.sdkName)
+			fmt.Fprintf(b, "for k, v := range in.%s {\nif v != nil {\nout.%s[k] = *v\n}\n}\n}\n", f.sdkName, f.name)
+		}
+	}
+	b.WriteString("return out\n")
+}
+
+// importPath returns the Go import path of the supplied directory, derived
+// from the nearest go.mod.
+func importPath(dir string) (string, error) {
+	root := dir
+	for {
+		if _, err := os.Stat(filepath.Join(root, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(root)
+		if parent == root {
+			return "", errors.New(errFindModule)
+		}
+		root = parent
+	}
+	b, err := ioutil.ReadFile(filepath.Join(root, "go.mod")) //nolint:gosec
+	if err != nil {
+		return "", errors.Wrap(err, errReadModule)
+	}
+	m := modulePath.FindSubmatch(b)
+	if m == nil {
+		return "", errors.Errorf(errFmtNoModulePath, filepath.Join(root, "go.mod"))
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return "", errors.Wrap(err, errFindModule)
+	}
+	if rel == "." {
+		return string(m[1]), nil
+	}
+	return string(m[1]) + "/" + filepath.ToSlash(rel), nil
+}
+
+// packageName returns the name of the Go package in the supplied directory,
+// ignoring generated and test files.
+func packageName(dir string) (string, error) {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, func(fi os.FileInfo) bool {
+		return fi.Name() != TypesFileName && !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.PackageClauseOnly)
+	if err != nil || len(pkgs) != 1 {
+		return "", errors.Errorf(errFmtPackageName, dir)
+	}
+	for name := range pkgs {
+		return name, nil
+	}
+	return "", errors.Errorf(errFmtPackageName, dir)
+}
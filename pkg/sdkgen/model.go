@@ -0,0 +1,267 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sdkgen
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// initialisms maps the words of SDK field names to their Go spelling.
+var initialisms = map[string]string{
+	"Acl":  "ACL",
+	"Api":  "API",
+	"Cpu":  "CPU",
+	"Dns":  "DNS",
+	"Http": "HTTP",
+	"Id":   "ID",
+	"Ids":  "IDs",
+	"Ip":   "IP",
+	"Ips":  "IPs",
+	"Ssl":  "SSL",
+	"Ttl":  "TTL",
+	"Url":  "URL",
+}
+
+// goName returns the Go spelling of an SDK field name, e.g. VSwitchID for
+// VSwitchId.
+func goName(sdkName string) string {
+	var words []string
+	start := 0
+	r := []rune(sdkName)
+	for i := 1; i < len(r); i++ {
+		if unicode.IsUpper(r[i]) && !unicode.IsUpper(r[i-1]) {
+			words = append(words, string(r[start:i]))
+			start = i
+		}
+	}
+	words = append(words, string(r[start:]))
+	for i, w := range words {
+		if s, ok := initialisms[w]; ok {
+			words[i] = s
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// jsonName returns the JSON name of a field, which is its SDK name with a
+// lower case first letter, e.g. vSwitchId for VSwitchId.
+func jsonName(name string) string {
+	if name == "" {
+		return name
+	}
+	r := []rune(name)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+// A field of a generated type.
+type field struct {
+	sdkName  string
+	name     string
+	json     string
+	required bool
+	comment  string
+	typ      fieldType
+
+	// nested is the generated type of struct shaped fields.
+	nested *genType
+}
+
+// A generated type derived from an SDK struct.
+type genType struct {
+	name    string
+	sdkName string
+	comment string
+	fields  []field
+
+	// to and from are the names of the functions converting this type to and
+	// from its SDK struct.
+	to, from string
+}
+
+// model is the set of types generated for one configuration file.
+type model struct {
+	types []*genType
+
+	// nested types keyed by SDK struct name, shared by all resources.
+	nested map[string]*genType
+}
+
+// resourceModel holds the generated types of one resource.
+type resourceModel struct {
+	Resource
+	parameters  *genType
+	observation *genType
+	upToDate    []field
+}
+
+func newModel() *model {
+	return &model{nested: map[string]*genType{}}
+}
+
+func (m *model) addResource(r Resource, sdk *sdkPackage) (*resourceModel, error) {
+	rm := &resourceModel{Resource: r}
+	var err error
+	rm.parameters, err = m.addType(typeName(r.Parameters, r.Kind+"Parameters"), r.Parameters, sdk, r.Kind)
+	if err != nil {
+		return nil, err
+	}
+	rm.parameters.comment = rm.parameters.name + " are the configurable fields of a " + r.Kind + "."
+	rm.parameters.to = "Generate" + r.Kind + "Request"
+	rm.parameters.from = "Generate" + r.Kind + "Parameters"
+
+	rm.observation, err = m.addType(typeName(r.Observation, r.Kind+"Observation"), r.Observation, sdk, r.Kind)
+	if err != nil {
+		return nil, err
+	}
+	rm.observation.comment = rm.observation.name + " are the observable fields of a " + r.Kind + "."
+	rm.observation.from = "Generate" + r.Kind + "Observation"
+
+	observed, err := sdk.fields(r.Observation.Struct)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range r.UpToDate {
+		p, ok := findField(rm.parameters, name)
+		if !ok {
+			return nil, errors.Errorf(errFmtNoSDKField, r.Parameters.Struct, name)
+		}
+		o, ok := findSDKField(observed, name)
+		if !ok {
+			return nil, errors.Errorf(errFmtNoSDKField, r.Observation.Struct, name)
+		}
+		if ot, ok := sdk.typeOf(o.typ); !ok || p.typ.shape != shapeScalar || ot != p.typ {
+			return nil, errors.Errorf(errFmtNotComparable, name)
+		}
+		rm.upToDate = append(rm.upToDate, p)
+	}
+	return rm, nil
+}
+
+// typeName returns the configured name of the type generated from the
+// supplied struct, or name if none is configured.
+func typeName(s Struct, name string) string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return name
+}
+
+func findField(t *genType, sdkName string) (field, bool) {
+	for _, f := range t.fields {
+		if f.sdkName == sdkName {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+// addType adds a top-level type named name derived from the configured SDK
+// struct, and any nested types it references.
+func (m *model) addType(name string, s Struct, sdk *sdkPackage, prefix string) (*genType, error) {
+	// Reserve a place so the type precedes the nested types it references.
+	i := len(m.types)
+	m.types = append(m.types, nil)
+	t, err := m.newType(name, s, sdk, prefix)
+	if err != nil {
+		return nil, err
+	}
+	m.types[i] = t
+	return t, nil
+}
+
+func (m *model) newType(name string, s Struct, sdk *sdkPackage, prefix string) (*genType, error) {
+	sf, err := sdk.fields(s.Struct)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range s.Fields {
+		if _, ok := findSDKField(sf, n); !ok {
+			return nil, errors.Errorf(errFmtNoSDKField, s.Struct, n)
+		}
+	}
+
+	t := &genType{name: name, sdkName: s.Struct}
+	for _, f := range sf {
+		if contains(s.Ignore, f.name) || (len(s.Fields) > 0 && !contains(s.Fields, f.name)) {
+			continue
+		}
+		typ, ok := sdk.typeOf(f.typ)
+		if !ok {
+			return nil, errors.Errorf(errFmtUnsupported, s.Struct, f.name, exprString(f.typ))
+		}
+		n := f.name
+		if rn, ok := s.Rename[f.name]; ok {
+			n = rn
+		}
+		gf := field{
+			sdkName:  f.name,
+			name:     goName(n),
+			json:     jsonName(n),
+			required: contains(s.Required, f.name),
+			comment:  s.Comments[f.name],
+			typ:      typ,
+		}
+		if typ.shape == shapeStruct || typ.shape == shapeStructSlice {
+			nt, err := m.nestedType(typ.name, sdk, prefix, gf.name)
+			if err != nil {
+				return nil, err
+			}
+			gf.nested = nt
+		}
+		t.fields = append(t.fields, gf)
+	}
+	return t, nil
+}
+
+// nestedType returns the generated type of the supplied nested SDK struct,
+// adding it to the model if necessary. Nested types are named after the
+// resource kind and the field first referencing them.
+func (m *model) nestedType(sdkName string, sdk *sdkPackage, prefix, fieldName string) (*genType, error) {
+	if t, ok := m.nested[sdkName]; ok {
+		return t, nil
+	}
+	name := prefix + fieldName
+	t := &genType{
+		name:    name,
+		sdkName: sdkName,
+		comment: name + " is generated from " + sdkName + ".",
+		to:      "toSDK" + name,
+		from:    "fromSDK" + name,
+	}
+	// Register before recursing so self-referencing structs terminate.
+	m.nested[sdkName] = t
+	nt, err := m.newType(name, Struct{Struct: sdkName}, sdk, prefix)
+	if err != nil {
+		return nil, err
+	}
+	t.fields = nt.fields
+	m.types = append(m.types, t)
+	return t, nil
+}
+
+func findSDKField(fields []sdkField, name string) (sdkField, bool) {
+	for _, f := range fields {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return sdkField{}, false
+}
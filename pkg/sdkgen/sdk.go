@@ -0,0 +1,204 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sdkgen
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errLoadSDK          = "cannot find SDK package"
+	errParseSDK         = "cannot parse SDK package"
+	errFmtNoSDKPackage  = "no Go package in %s"
+	errFmtNoSDKStruct   = "SDK package has no struct %s"
+	errFmtUnsupported   = "field %s.%s has unsupported type %s; add it to ignore"
+	errFmtNoSDKField    = "SDK struct %s has no field %s"
+	errFmtNotComparable = "field %s cannot be compared for up to date checks; only scalar fields can"
+)
+
+// A Loader returns the directory of the Go package with the supplied import
+// path.
+type Loader func(importPath string) (string, error)
+
+// GoListLoader finds packages using `go list`, i.e. in the module cache of
+// the current module.
+func GoListLoader(importPath string) (string, error) {
+	out, err := exec.Command("go", "list", "-f", "{{.Dir}}", importPath).Output() //nolint:gosec
+	if err != nil {
+		return "", errors.Wrap(err, errLoadSDK)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// sdkPackage holds the structs of a tea-style SDK package.
+type sdkPackage struct {
+	structs map[string]*ast.StructType
+}
+
+func parseSDK(dir string) (*sdkPackage, error) {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseSDK)
+	}
+	if len(pkgs) == 0 {
+		return nil, errors.Errorf(errFmtNoSDKPackage, dir)
+	}
+
+	p := &sdkPackage{structs: map[string]*ast.StructType{}}
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			for _, d := range f.Decls {
+				gd, ok := d.(*ast.GenDecl)
+				if !ok || gd.Tok != token.TYPE {
+					continue
+				}
+				for _, s := range gd.Specs {
+					ts := s.(*ast.TypeSpec)
+					if st, ok := ts.Type.(*ast.StructType); ok {
+						p.structs[ts.Name.Name] = st
+					}
+				}
+			}
+		}
+	}
+	return p, nil
+}
+
+// sdkField is a named field of an SDK struct.
+type sdkField struct {
+	name string
+	typ  ast.Expr
+}
+
+func (p *sdkPackage) fields(name string) ([]sdkField, error) {
+	st, ok := p.structs[name]
+	if !ok {
+		return nil, errors.Errorf(errFmtNoSDKStruct, name)
+	}
+	var fields []sdkField
+	for _, f := range st.Fields.List {
+		for _, n := range f.Names {
+			if n.IsExported() {
+				fields = append(fields, sdkField{name: n.Name, typ: f.Type})
+			}
+		}
+	}
+	return fields, nil
+}
+
+// A shape is the kind of an SDK field type that sdkgen knows how to convert.
+type shape int
+
+const (
+	// *string, *int64, etc.
+	shapeScalar shape = iota
+	// []*string, []*int64, etc.
+	shapeScalarSlice
+	// *Struct
+	shapeStruct
+	// []*Struct
+	shapeStructSlice
+	// map[string]*string
+	shapeStringMap
+)
+
+// scalars are the SDK scalar types that may be used in CRDs. Floats are
+// deliberately absent.
+var scalars = map[string]bool{
+	"string": true,
+	"bool":   true,
+	"int":    true,
+	"int32":  true,
+	"int64":  true,
+}
+
+// fieldType is the supported type of an SDK field.
+type fieldType struct {
+	shape shape
+
+	// name is the scalar type for scalar shapes and the SDK struct name for
+	// struct shapes.
+	name string
+}
+
+func (p *sdkPackage) typeOf(e ast.Expr) (fieldType, bool) {
+	switch t := e.(type) {
+	case *ast.StarExpr:
+		id, ok := t.X.(*ast.Ident)
+		if !ok {
+			return fieldType{}, false
+		}
+		if scalars[id.Name] {
+			return fieldType{shape: shapeScalar, name: id.Name}, true
+		}
+		if _, ok := p.structs[id.Name]; ok {
+			return fieldType{shape: shapeStruct, name: id.Name}, true
+		}
+	case *ast.ArrayType:
+		if t.Len != nil {
+			return fieldType{}, false
+		}
+		elem, ok := p.typeOf(t.Elt)
+		if !ok {
+			return fieldType{}, false
+		}
+		switch elem.shape {
+		case shapeScalar:
+			return fieldType{shape: shapeScalarSlice, name: elem.name}, true
+		case shapeStruct:
+			return fieldType{shape: shapeStructSlice, name: elem.name}, true
+		}
+	case *ast.MapType:
+		k, ok := t.Key.(*ast.Ident)
+		if !ok || k.Name != "string" {
+			return fieldType{}, false
+		}
+		if v, ok := p.typeOf(t.Value); ok && v.shape == shapeScalar && v.name == "string" {
+			return fieldType{shape: shapeStringMap, name: "string"}, true
+		}
+	}
+	return fieldType{}, false
+}
+
+// exprString renders simple type expressions for error messages.
+func exprString(e ast.Expr) string {
+	switch t := e.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return "*" + exprString(t.X)
+	case *ast.ArrayType:
+		return "[]" + exprString(t.Elt)
+	case *ast.MapType:
+		return "map[" + exprString(t.Key) + "]" + exprString(t.Value)
+	case *ast.SelectorExpr:
+		return exprString(t.X) + "." + t.Sel.Name
+	case *ast.InterfaceType:
+		return "interface{}"
+	default:
+		return "?"
+	}
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sdkgen
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+const header = "/* Header */"
+
+const widgetConfig = `resources:
+- kind: Widget
+  sdkPackage: example.com/sdk/client
+  clientDir: ../../client
+  parameters:
+    struct: CreateWidgetRequest
+    ignore: [OwnerId, Body]
+    rename: {RegionId: Region}
+    required: [RegionId]
+    comments:
+      RegionId: Region is the region of the widget.
+  observation:
+    struct: DescribeWidgetResponseBodyWidget
+    fields: [WidgetId, Status, Tags]
+  upToDate: [WidgetName, Size]
+`
+
+func sdkLoader(importPath string) (string, error) {
+	return filepath.Abs(filepath.Join("testdata", "sdk"))
+}
+
+// setup writes a module containing an API and a client package and returns
+// the path of its configuration file.
+func setup(t *testing.T, config string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "sdkgen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	files := map[string]string{
+		"go.mod":                          "module example.com/provider\n",
+		"apis/v1alpha1/doc.go":            "package v1alpha1\n",
+		"apis/v1alpha1/" + ConfigFileName: config,
+		"client/client.go":                "package widget\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(dir, "apis", "v1alpha1", ConfigFileName)
+}
+
+func TestGenerate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		err    error
+		types  string
+		client string
+	}
+
+	cases := map[string]struct {
+		reason string
+		config string
+		load   Loader
+		want   want
+	}{
+		"LoadFailed": {
+			reason: "Errors finding the SDK package should be returned",
+			config: widgetConfig,
+			load:   func(string) (string, error) { return "", errBoom },
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtResource, "Widget"),
+			},
+		},
+		"NoSDKStruct": {
+			reason: "Referencing a struct the SDK does not have should return an error",
+			config: "resources:\n- {kind: Widget, sdkPackage: example.com/sdk/client, clientDir: ../../client, parameters: {struct: CreateWidget}, observation: {struct: DescribeWidgetResponseBodyWidget}}\n",
+			load:   sdkLoader,
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtNoSDKStruct, "CreateWidget"), errFmtResource, "Widget"),
+			},
+		},
+		"UnsupportedType": {
+			reason: "Fields of unsupported types that are not ignored should return an error",
+			config: "resources:\n- {kind: Widget, sdkPackage: example.com/sdk/client, clientDir: ../../client, parameters: {struct: CreateWidgetRequest, ignore: [OwnerId]}, observation: {struct: DescribeWidgetResponseBodyWidget}}\n",
+			load:   sdkLoader,
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtUnsupported, "CreateWidgetRequest", "Body", "io.Reader"), errFmtResource, "Widget"),
+			},
+		},
+		"NoObservedField": {
+			reason: "Fields compared for up to date checks must exist in the observed struct",
+			config: "resources:\n- {kind: Widget, sdkPackage: example.com/sdk/client, clientDir: ../../client, parameters: {struct: CreateWidgetRequest, ignore: [Body]}, observation: {struct: DescribeWidgetResponseBodyWidget, ignore: [Price]}, upToDate: [VSwitchIds]}\n",
+			load:   sdkLoader,
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtNoSDKField, "DescribeWidgetResponseBodyWidget", "VSwitchIds"), errFmtResource, "Widget"),
+			},
+		},
+		"Named": {
+			reason: "Generated types should use the configured names",
+			config: strings.Replace(widgetConfig, "    fields: [WidgetId", "    name: WidgetStatus\n    fields: [WidgetId", 1),
+			load:   sdkLoader,
+			want: want{
+				types:  "widget_named_types.golden",
+				client: "widget_named_client.golden",
+			},
+		},
+		"Success": {
+			reason: "Types and conversion functions should be generated from the configured SDK structs",
+			config: widgetConfig,
+			load:   sdkLoader,
+			want: want{
+				types:  "widget_types.golden",
+				client: "widget_client.golden",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := setup(t, tc.config)
+			g := &Generator{Header: header, Load: tc.load}
+			err := g.Generate(path)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\ng.Generate(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			golden(t, tc.reason, filepath.Join(filepath.Dir(path), TypesFileName), tc.want.types)
+			golden(t, tc.reason, filepath.Join(filepath.Dir(path), "..", "..", "client", TypesFileName), tc.want.client)
+		})
+	}
+}
+
+func golden(t *testing.T, reason, path, name string) {
+	t.Helper()
+	got, err := ioutil.ReadFile(path) //nolint:gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := filepath.Join("testdata", name)
+	if *update {
+		if err := ioutil.WriteFile(g, got, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := ioutil.ReadFile(g) //nolint:gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(string(want), string(got)); diff != "" {
+		t.Errorf("\n%s\n%s: -want, +got:\n%s\n", reason, name, diff)
+	}
+}
+
+func TestGoName(t *testing.T) {
+	cases := map[string]string{
+		"RegionId":           "RegionID",
+		"VSwitchId":          "VSwitchID",
+		"LoadBalancerIds":    "LoadBalancerIDs",
+		"SecurityIpList":     "SecurityIPList",
+		"Identity":           "Identity",
+		"InternetChargeType": "InternetChargeType",
+		"AclStatus":          "ACLStatus",
+	}
+	for in, want := range cases {
+		t.Run(in, func(t *testing.T) {
+			if diff := cmp.Diff(want, goName(in)); diff != "" {
+				t.Errorf("goName(%q): -want, +got:\n%s\n", in, diff)
+			}
+		})
+	}
+}
+
+func TestReadConfig(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		config string
+		want   error
+	}{
+		"NoKind": {
+			reason: "Resources must have a kind",
+			config: "resources:\n- {sdkPackage: p}\n",
+			want:   errors.Errorf(errFmtNoKind, 0),
+		},
+		"NoObservation": {
+			reason: "Resources must have an observation struct",
+			config: "resources:\n- {kind: Widget, sdkPackage: p, clientDir: c, parameters: {struct: S}}\n",
+			want:   errors.Errorf(errFmtNoStruct, "Widget", "observation"),
+		},
+		"Valid": {
+			reason: "Valid configurations should be read without error",
+			config: widgetConfig,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ReadConfig(setup(t, tc.config))
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nReadConfig(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+// Package client is a stand-in for a tea-style Alibaba Cloud SDK package.
+package client
+
+import "io"
+
+type CreateWidgetRequest struct {
+	RegionId   *string                   `json:"RegionId,omitempty" xml:"RegionId,omitempty" require:"true"`
+	WidgetName *string                   `json:"WidgetName,omitempty" xml:"WidgetName,omitempty"`
+	Size       *int32                    `json:"Size,omitempty" xml:"Size,omitempty"`
+	VSwitchIds []*string                 `json:"VSwitchIds,omitempty" xml:"VSwitchIds,omitempty" type:"Repeated"`
+	Tag        []*CreateWidgetRequestTag `json:"Tag,omitempty" xml:"Tag,omitempty" type:"Repeated"`
+	Labels     map[string]*string        `json:"Labels,omitempty" xml:"Labels,omitempty"`
+	OwnerId    *int64                    `json:"OwnerId,omitempty" xml:"OwnerId,omitempty"`
+	Body       io.Reader                 `json:"Body,omitempty" xml:"Body,omitempty"`
+}
+
+type CreateWidgetRequestTag struct {
+	Key   *string `json:"Key,omitempty" xml:"Key,omitempty"`
+	Value *string `json:"Value,omitempty" xml:"Value,omitempty"`
+}
+
+type DescribeWidgetResponseBodyWidget struct {
+	WidgetId   *string                   `json:"WidgetId,omitempty" xml:"WidgetId,omitempty"`
+	WidgetName *string                   `json:"WidgetName,omitempty" xml:"WidgetName,omitempty"`
+	Size       *int32                    `json:"Size,omitempty" xml:"Size,omitempty"`
+	Status     *string                   `json:"Status,omitempty" xml:"Status,omitempty"`
+	Price      *float64                  `json:"Price,omitempty" xml:"Price,omitempty"`
+	Tags       []*CreateWidgetRequestTag `json:"Tags,omitempty" xml:"Tags,omitempty" type:"Repeated"`
+}
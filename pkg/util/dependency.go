@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// AnnotationKeyCascadeDelete is the annotation which, when set to "true" on a
+// parent managed resource, makes its deletion also delete the managed
+// resources that depend on it.
+const AnnotationKeyCascadeDelete = "alibaba.crossplane.io/cascade-delete"
+
+const (
+	errDeleteDependent = "cannot delete dependent resource"
+
+	msgFmtWaitForDependents = "waiting for dependent resources to be deleted: %s"
+)
+
+// A Dependent is a managed resource that must be deleted before the managed
+// resource it refers to.
+type Dependent struct {
+	// Kind of the dependent managed resource, e.g. LogStore.
+	Kind string
+
+	resource.Managed
+}
+
+// String returns the Dependent as Kind/name.
+func (d Dependent) String() string {
+	return fmt.Sprintf("%s/%s", d.Kind, d.GetName())
+}
+
+// CascadeDelete returns true if the supplied object is annotated for cascade
+// deletion of its dependents.
+func CascadeDelete(o metav1.Object) bool {
+	return o.GetAnnotations()[AnnotationKeyCascadeDelete] == "true"
+}
+
+// WaitForDependents returns true if the deletion of mg must wait for the
+// supplied dependents to be gone. In that case mg is marked as Deleting with a
+// message listing the blocking dependents, and, if cascade deletion is
+// enabled on mg, the dependents are deleted.
+func WaitForDependents(ctx context.Context, c client.Client, mg resource.Managed, dependents []Dependent) (bool, error) {
+	if len(dependents) == 0 {
+		return false, nil
+	}
+
+	names := make([]string, len(dependents))
+	for i, d := range dependents {
+		names[i] = d.String()
+		if !CascadeDelete(mg) || meta.WasDeleted(d) {
+			continue
+		}
+		if err := c.Delete(ctx, d); resource.IgnoreNotFound(err) != nil {
+			return true, errors.Wrapf(err, "%s %s", errDeleteDependent, d)
+		}
+	}
+
+	mg.SetConditions(xpv1.Deleting().WithMessage(fmt.Sprintf(msgFmtWaitForDependents, strings.Join(names, ", "))))
+	return true, nil
+}
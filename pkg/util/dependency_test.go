@@ -0,0 +1,103 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-alibaba/apis/oss/v1alpha1"
+)
+
+func TestWaitForDependents(t *testing.T) {
+	errBoom := errors.New("boom")
+	dependent := Dependent{Kind: v1alpha1.BucketKind, Managed: &v1alpha1.Bucket{ObjectMeta: metav1.ObjectMeta{Name: "child"}}}
+	cascade := map[string]string{AnnotationKeyCascadeDelete: "true"}
+
+	type want struct {
+		wait bool
+		err  error
+		cond xpv1.Condition
+	}
+
+	cases := map[string]struct {
+		kube       client.Client
+		mg         resource.Managed
+		dependents []Dependent
+		want       want
+	}{
+		"NoDependents": {
+			mg: &v1alpha1.Bucket{},
+			want: want{
+				wait: false,
+				cond: xpv1.Condition{Type: xpv1.TypeReady, Status: corev1.ConditionUnknown},
+			},
+		},
+		"WaitForDependents": {
+			mg:         &v1alpha1.Bucket{},
+			dependents: []Dependent{dependent},
+			want: want{
+				wait: true,
+				cond: xpv1.Deleting().WithMessage(fmt.Sprintf(msgFmtWaitForDependents, "Bucket/child")),
+			},
+		},
+		"CascadeDelete": {
+			kube:       &test.MockClient{MockDelete: test.NewMockDeleteFn(nil)},
+			mg:         &v1alpha1.Bucket{ObjectMeta: metav1.ObjectMeta{Annotations: cascade}},
+			dependents: []Dependent{dependent},
+			want: want{
+				wait: true,
+				cond: xpv1.Deleting().WithMessage(fmt.Sprintf(msgFmtWaitForDependents, "Bucket/child")),
+			},
+		},
+		"CascadeDeleteFailed": {
+			kube:       &test.MockClient{MockDelete: test.NewMockDeleteFn(errBoom)},
+			mg:         &v1alpha1.Bucket{ObjectMeta: metav1.ObjectMeta{Annotations: cascade}},
+			dependents: []Dependent{dependent},
+			want: want{
+				wait: true,
+				err:  errors.Wrapf(errBoom, "%s %s", errDeleteDependent, "Bucket/child"),
+				cond: xpv1.Condition{Type: xpv1.TypeReady, Status: corev1.ConditionUnknown},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			wait, err := WaitForDependents(context.Background(), tc.kube, tc.mg, tc.dependents)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nWaitForDependents(...) -want error, +got error:\n%s\n", diff)
+			}
+			if diff := cmp.Diff(tc.want.wait, wait); diff != "" {
+				t.Errorf("\nWaitForDependents(...) -want, +got:\n%s\n", diff)
+			}
+			if diff := cmp.Diff(tc.want.cond, tc.mg.GetCondition(xpv1.TypeReady), test.EquateConditions()); diff != "" {
+				t.Errorf("\nWaitForDependents(...) -want condition, +got condition:\n%s\n", diff)
+			}
+		})
+	}
+}
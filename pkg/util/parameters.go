@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const errNoParameters = "managed resource has no spec"
+
+// resourceSpecFields are the fields of a managed resource spec that are not
+// parameters of its cloud resource.
+var resourceSpecFields = []string{"writeConnectionSecretToRef", "providerConfigRef", "providerRef", "deletionPolicy"}
+
+// Parameters returns the JSON representation of the desired parameters of
+// the supplied managed resource. See ParametersOf.
+func Parameters(mg resource.Managed) (map[string]interface{}, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(mg)
+	if err != nil {
+		return nil, err
+	}
+	return ParametersOf(u)
+}
+
+// ParametersOf returns spec.forProvider of the supplied unstructured managed
+// resource, or its spec without the common managed resource fields if it has
+// no spec.forProvider.
+func ParametersOf(u map[string]interface{}) (map[string]interface{}, error) {
+	spec, ok := u["spec"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New(errNoParameters)
+	}
+	if fp, ok := spec["forProvider"].(map[string]interface{}); ok {
+		return fp, nil
+	}
+	params := make(map[string]interface{}, len(spec))
+	for k, v := range spec {
+		params[k] = v
+	}
+	for _, f := range resourceSpecFields {
+		delete(params, f)
+	}
+	return params, nil
+}
+
+// Flatten returns the JSON encoded leaves of the JSON representation of the
+// supplied value, keyed by their dot separated paths. Arrays are leaves and
+// null values are omitted.
+func Flatten(v interface{}) (map[string]string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	flat := map[string]string{}
+	return flat, flattenInto(flat, "", m)
+}
+
+func flattenInto(flat map[string]string, prefix string, m map[string]interface{}) error {
+	for k, v := range m {
+		p := k
+		if prefix != "" {
+			p = prefix + "." + k
+		}
+		if nested, ok := v.(map[string]interface{}); ok {
+			if err := flattenInto(flat, p, nested); err != nil {
+				return err
+			}
+			continue
+		}
+		if v == nil {
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		flat[p] = string(b)
+	}
+	return nil
+}
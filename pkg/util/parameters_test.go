@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestParametersOf(t *testing.T) {
+	type want struct {
+		params map[string]interface{}
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		u      map[string]interface{}
+		want   want
+	}{
+		"NoSpec": {
+			reason: "Objects without a spec have no parameters",
+			u:      map[string]interface{}{},
+			want:   want{err: errors.New(errNoParameters)},
+		},
+		"ForProvider": {
+			reason: "The parameters of managed resources with spec.forProvider are spec.forProvider",
+			u: map[string]interface{}{"spec": map[string]interface{}{
+				"forProvider":       map[string]interface{}{"engine": "MySQL"},
+				"providerConfigRef": map[string]interface{}{"name": "default"},
+			}},
+			want: want{params: map[string]interface{}{"engine": "MySQL"}},
+		},
+		"InlineSpec": {
+			reason: "The parameters of managed resources without spec.forProvider are the other fields of spec",
+			u: map[string]interface{}{"spec": map[string]interface{}{
+				"acl":               "private",
+				"providerConfigRef": map[string]interface{}{"name": "default"},
+				"deletionPolicy":    "Delete",
+			}},
+			want: want{params: map[string]interface{}{"acl": "private"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParametersOf(tc.u)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParametersOf(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.params, got); diff != "" {
+				t.Errorf("\n%s\nParametersOf(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	type nested struct {
+		Port *int `json:"port,omitempty"`
+	}
+	type params struct {
+		Name   string            `json:"name"`
+		Tags   []string          `json:"tags"`
+		Nested nested            `json:"nested"`
+		Labels map[string]string `json:"labels"`
+		Unset  *string           `json:"unset"`
+	}
+	port := 3306
+
+	want := map[string]string{
+		"name":        `"db"`,
+		"tags":        `["a","b"]`,
+		"nested.port": "3306",
+		"labels.team": `"a"`,
+	}
+	got, err := Flatten(params{Name: "db", Tags: []string{"a", "b"}, Nested: nested{Port: &port}, Labels: map[string]string{"team": "a"}})
+	if err != nil {
+		t.Fatalf("Flatten(...): %s", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Flatten(...): -want, +got:\n%s", diff)
+	}
+}
@@ -20,6 +20,9 @@ import (
 	"context"
 	"fmt"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -35,6 +38,20 @@ const (
 	SecurityToken = "securityToken"
 )
 
+const (
+	errNoConnectionSecret  = "no connection secret specified"
+	errGetConnectionSecret = "cannot get connection secret"
+)
+
+// Credentials are the Alibaba Cloud credentials and region used to reach the
+// cloud API.
+type Credentials struct {
+	AccessKeyID     string
+	AccessKeySecret string
+	SecurityToken   string
+	Region          string
+}
+
 // GetProviderConfig gets ProviderConfig
 func GetProviderConfig(ctx context.Context, k8sClient client.Client, providerConfigName string) (*crossplanev1alpha1.ProviderConfig, error) {
 	providerConfig := &crossplanev1alpha1.ProviderConfig{}
@@ -43,3 +60,21 @@ func GetProviderConfig(ctx context.Context, k8sClient client.Client, providerCon
 	}
 	return providerConfig, nil
 }
+
+// GetCredentials reads the Alibaba Cloud credentials from the secret selected
+// by sel.
+func GetCredentials(ctx context.Context, k8sClient client.Client, sel *xpv1.SecretKeySelector, region string) (Credentials, error) {
+	if sel == nil {
+		return Credentials{}, errors.New(errNoConnectionSecret)
+	}
+	s := &corev1.Secret{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: sel.Namespace, Name: sel.Name}, s); err != nil {
+		return Credentials{}, errors.Wrap(err, errGetConnectionSecret)
+	}
+	return Credentials{
+		AccessKeyID:     string(s.Data[AccessKeyID]),
+		AccessKeySecret: string(s.Data[AccessKeySecret]),
+		SecurityToken:   string(s.Data[SecurityToken]),
+		Region:          region,
+	}, nil
+}
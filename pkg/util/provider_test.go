@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestGetCredentials(t *testing.T) {
+	errBoom := errors.New("boom")
+	sel := &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: "coolsecret"}}
+
+	type want struct {
+		creds Credentials
+		err   error
+	}
+
+	cases := map[string]struct {
+		kube client.Client
+		sel  *xpv1.SecretKeySelector
+		want want
+	}{
+		"NoConnectionSecret": {
+			sel: nil,
+			want: want{
+				err: errors.New(errNoConnectionSecret),
+			},
+		},
+		"GetConnectionSecretFailed": {
+			kube: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			sel:  sel,
+			want: want{
+				err: errors.Wrap(errBoom, errGetConnectionSecret),
+			},
+		},
+		"Success": {
+			kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+				obj.(*corev1.Secret).Data = map[string][]byte{
+					AccessKeyID:     []byte("id"),
+					AccessKeySecret: []byte("secret"),
+					SecurityToken:   []byte("token"),
+				}
+				return nil
+			})},
+			sel: sel,
+			want: want{
+				creds: Credentials{AccessKeyID: "id", AccessKeySecret: "secret", SecurityToken: "token", Region: "cn-beijing"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := GetCredentials(context.Background(), tc.kube, tc.sel, "cn-beijing")
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nGetCredentials(...) -want error, +got error:\n%s\n", diff)
+			}
+			if diff := cmp.Diff(tc.want.creds, got); diff != "" {
+				t.Errorf("\nGetCredentials(...) -want, +got:\n%s\n", diff)
+			}
+		})
+	}
+}